@@ -3,16 +3,66 @@ package analyzer
 import (
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
-	"walrus/compiler/internal/parser"
+	"strings"
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/bytecode"
+	"walrus/compiler/internal/codegen/c"
+	"walrus/compiler/internal/codegen/golang"
+	"walrus/compiler/internal/interpreter"
+	"walrus/compiler/internal/ir"
+	"walrus/compiler/internal/modules"
 	"walrus/compiler/internal/typechecker"
+	"walrus/compiler/internal/vm"
 	"walrus/compiler/report"
 	"walrus/compiler/wio"
 )
 
 const HALTED = "compilation halted"
 
+// check parses the file with every file it imports and type checks them. It returns the
+// modules with their type info, and the program linked from all the modules for the
+// backends. Nothing is type checked when the files could not be loaded.
+func check(filePath string, debug bool) (file checked, e error) {
+
+	file.modules, e = modules.Load(filePath, debug)
+	if e != nil {
+		return file, e
+	}
+
+	file.entry = file.modules[len(file.modules)-1]
+
+	if report.GetReports().HasErrors() {
+		return file, nil
+	}
+
+	file.infos = typechecker.AnalyzeProgram(file.modules)
+	file.info = file.infos[len(file.infos)-1]
+	file.program = modules.Link(file.modules)
+
+	return file, nil
+}
+
 func Analyze(filePath string, displayErrors, debug, save2Json bool) (reports report.Reports, e error) {
+	return AnalyzeTo(filePath, "", displayErrors, debug, save2Json, false)
+}
+
+// checked is a checked file: its module with its type info, every module of its program in
+// the order they are linked with their type info, and the program linked from them for the
+// backends.
+type checked struct {
+	entry   modules.Module
+	info    *typechecker.TypeInfo
+	modules []modules.Module
+	infos   []*typechecker.TypeInfo
+	program ast.Node
+}
+
+// collect calls fn and returns the reports it made, with its error or the error of a
+// critical report when one stopped it.
+func collect(fn func() error) (reports report.Reports, e error) {
 
 	defer func() {
 		if r := recover(); r != nil {
@@ -22,31 +72,150 @@ func Analyze(filePath string, displayErrors, debug, save2Json bool) (reports rep
 		report.ClearReports()
 	}()
 
+	e = fn()
+	return report.GetReports(), e
+}
+
+// analyze checks a .wal file and calls use with the result. When halt is set, use is not
+// called if errors were found. It returns the reports of the file, and the error of a
+// critical report when one stopped the checker.
+func analyze(filePath string, debug, halt bool, use func(file checked) error) (reports report.Reports, e error) {
+
 	//must have .wal file
 	if len(filePath) < 5 || filePath[len(filePath)-4:] != ".wal" {
 		e = errors.New("error: file must have .wal extension")
 		return nil, e
 	}
 
-	//get the folder and file name
-	folder, fileName := filepath.Split(filePath)
+	return collect(func() error {
+		file, e := check(filePath, debug)
+		if e != nil {
+			return e
+		}
 
-	tree, e := parser.NewParser(filePath, debug).Parse()
-	if e != nil {
-		return report.GetReports(), e
-	}
+		if halt && report.GetReports().HasErrors() {
+			return errors.New(HALTED)
+		}
+
+		return use(file)
+	})
+}
+
+// AnalyzeTo is Analyze writing the saved files in the 'ast' and 'bytecode' folders of the
+// output folder. They are written next to the analyzed file when the output folder is empty.
+// The bytecode is only compiled and saved when saveBytecode is set and no errors were found.
+func AnalyzeTo(filePath, outputDir string, displayErrors, debug, save2Json, saveBytecode bool) (reports report.Reports, e error) {
+
+	return analyze(filePath, debug, false, func(file checked) error {
 
-	if save2Json {
-		//write the tree to a file named 'expressions.json' in 'code/ast' folder
-		e = wio.Serialize(&tree, folder, fileName)
-		if reports != nil {
-			return report.GetReports(), e
+		//get the folder and file name
+		folder, fileName := filepath.Split(filePath)
+		if folder == "" {
+			//the file is in the working directory
+			folder = "."
+		}
+		if outputDir != "" {
+			folder = outputDir
+			if save2Json || saveBytecode {
+				if e := os.MkdirAll(folder, os.ModePerm); e != nil {
+					return e
+				}
+			}
+		}
+
+		if save2Json {
+			//write the tree and its types to a file named '<fileName>.json' in the 'ast' folder
+			if e := wio.Serialize(&file.entry.Tree, file.info, folder, fileName); e != nil {
+				return e
+			}
 		}
-	}
 
-	typechecker.Analyze(tree, filePath)
+		if saveBytecode && !report.GetReports().HasErrors() {
+			//write the compiled program next to the tree, in the 'bytecode' folder
+			program := bytecode.Compile(file.program, filePath)
+			return wio.SerializeBytecode(program, folder, fileName)
+		}
+
+		return nil
+	})
+}
 
-	reports = report.GetReports()
+// Run analyzes the file and executes it when no errors were found. The program runs on the
+// bytecode vm when useVM is set and on the tree-walking interpreter otherwise.
+// The output of the program is written to out.
+func Run(filePath string, out io.Writer, debug, useVM bool) (reports report.Reports, e error) {
+
+	return analyze(filePath, debug, true, func(file checked) error {
+		if useVM {
+			vm.Run(bytecode.Compile(file.program, filePath), filePath, out)
+		} else {
+			interpreter.Run(file.program, filePath, out)
+		}
+		return nil
+	})
+}
+
+// RunBytecode executes a program built to a .wbc file on the bytecode vm. The runtime errors
+// of the program are reported against the file it was compiled from.
+// The output of the program is written to out.
+func RunBytecode(filePath string, out io.Writer) (reports report.Reports, e error) {
+
+	return collect(func() error {
+		file, e := os.Open(filePath)
+		if e != nil {
+			return e
+		}
+		defer file.Close()
 
-	return reports, nil
+		program, e := bytecode.Decode(file)
+		if e != nil {
+			return fmt.Errorf("%s: %v", filePath, e)
+		}
+
+		vm.Run(program, program.Source, out)
+		return nil
+	})
+}
+
+// Lower analyzes the file and, when no errors were found, lowers the program into the IR.
+func Lower(filePath string, debug bool) (program *ir.Program, reports report.Reports, e error) {
+
+	reports, e = analyze(filePath, debug, true, func(file checked) error {
+		program = ir.Lower(file.modules, file.infos)
+		return nil
+	})
+
+	return program, reports, e
+}
+
+// Transpile analyzes the file and, when no errors were found, generates the source of the
+// program in the target language. The "go" target writes a 'main' package named after the
+// file in the 'golang' folder, so every program builds on its own. The "c" target writes the
+// source and the runtime header it includes in a folder named after the file in the 'c' folder.
+func Transpile(filePath string, debug bool, target string) (reports report.Reports, e error) {
+
+	folder, fileName := filepath.Split(filePath)
+	name := strings.TrimSuffix(fileName, ".wal")
+
+	return analyze(filePath, debug, true, func(file checked) error {
+		switch target {
+		case "go":
+			source, err := golang.Generate(file.modules, file.infos)
+			if err != nil {
+				return err
+			}
+			return wio.SerializeSource(source, folder, "golang/"+name, "main.go")
+		case "c":
+			source, err := c.Generate(file.modules, file.infos)
+			if err != nil {
+				return err
+			}
+			if err := wio.SerializeSource(c.Runtime, folder, "c/"+name, "walrus.h"); err != nil {
+				return err
+			}
+			return wio.SerializeSource(source, folder, "c/"+name, "main.c")
+		default:
+			return fmt.Errorf("unknown target '%s'", target)
+		}
+	})
 }
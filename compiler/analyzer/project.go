@@ -0,0 +1,71 @@
+package analyzer
+
+import (
+	//Standard packages
+	"bytes"
+	"errors"
+
+	//Walrus packages
+	"walrus/compiler/internal/bytecode"
+	"walrus/compiler/internal/modules"
+	"walrus/compiler/internal/typechecker"
+	"walrus/compiler/project"
+	"walrus/compiler/report"
+	"walrus/compiler/wio"
+)
+
+// Check parses every file with the files they import and type checks them as one program,
+// so a file imported by several others is checked once.
+func Check(files []string, debug bool) (report.Reports, error) {
+
+	reports, e := collect(func() error {
+		loaded, e := modules.LoadAll(files, debug)
+		if e != nil || len(loaded) == 0 || report.GetReports().HasErrors() {
+			return e
+		}
+
+		typechecker.AnalyzeProgram(loaded)
+		modules.Link(loaded)
+		return nil
+	})
+
+	if e != nil && reports.HasErrors() {
+		// the error stopped the check, its reports say why
+		e = nil
+	}
+
+	return reports, e
+}
+
+// Build checks every file of the project and, when no errors were found, compiles the entry
+// file to '<name>.wbc' in the output folder of the project.
+func Build(manifest *project.Manifest, debug bool) (reports report.Reports, e error) {
+
+	entry, e := manifest.EntryPath()
+	if e != nil {
+		return nil, e
+	}
+
+	files, e := manifest.Files()
+	if e != nil {
+		return nil, e
+	}
+
+	reports, e = Check(files, debug)
+	if e != nil {
+		return reports, e
+	}
+	if reports.HasErrors() {
+		return reports, errors.New(HALTED)
+	}
+
+	_, e = analyze(entry, debug, true, func(file checked) error {
+		var program bytes.Buffer
+		if e := bytecode.Encode(&program, bytecode.Compile(file.program, entry)); e != nil {
+			return e
+		}
+		return wio.SerializeSource(program.Bytes(), manifest.Path(manifest.Output), "", manifest.Name+".wbc")
+	})
+
+	return reports, e
+}
@@ -2,34 +2,66 @@ package colors
 
 import (
 	"fmt"
+	"io"
+	"os"
 )
 
+// enabled reports whether the printers write color escape codes.
+var enabled = true
+
+// output is where the printers write, the standard output unless SetOutput changed it.
+var output io.Writer = os.Stdout
+
+// SetEnabled turns the color escape codes of every printer on or off.
+func SetEnabled(on bool) {
+	enabled = on
+}
+
+// SetOutput makes every printer, and the diagnostics of the compiler printed without a color,
+// write to w.
+func SetOutput(w io.Writer) {
+	output = w
+}
+
+// Output returns where the printers write, for the diagnostics printed without a color.
+func Output() io.Writer {
+	return output
+}
+
+// code returns the escape code of the color, or nothing when colors are off.
+func (c COLOR) code() string {
+	if !enabled {
+		return ""
+	}
+	return string(c)
+}
+
 func (c COLOR) Printf(format string, args ...interface{}) {
-	fmt.Printf(string(c)+format+string(RESET), args...)
+	fmt.Fprintf(output, c.code()+format+RESET.code(), args...)
 }
 
 func (c COLOR) Println(args ...interface{}) {
-	fmt.Print(string(c))
-	fmt.Println(args...)
-	fmt.Print(string(RESET))
+	fmt.Fprint(output, c.code())
+	fmt.Fprintln(output, args...)
+	fmt.Fprint(output, RESET.code())
 }
 
 func (c COLOR) Print(args ...interface{}) {
-	fmt.Print(string(c))
-	fmt.Print(args...)
-	fmt.Print(string(RESET))
+	fmt.Fprint(output, c.code())
+	fmt.Fprint(output, args...)
+	fmt.Fprint(output, RESET.code())
 }
 
 func (c COLOR) Sprintf(format string, args ...interface{}) string {
-	return string(c) + fmt.Sprintf(format, args...) + string(RESET)
+	return c.code() + fmt.Sprintf(format, args...) + RESET.code()
 }
 
 func (c COLOR) Sprintln(args ...interface{}) string {
-	return string(c) + fmt.Sprintln(args...) + string(RESET)
+	return c.code() + fmt.Sprintln(args...) + RESET.code()
 }
 
 func (c COLOR) Sprint(args ...interface{}) string {
-	return string(c) + fmt.Sprint(args...) + string(RESET)
+	return c.code() + fmt.Sprint(args...) + RESET.code()
 }
 
 func PrintWithColor(color COLOR, args ...interface{}) {
@@ -1,6 +1,7 @@
 package colors
 
 import (
+	"os"
 	"strings"
 	"testing"
 )
@@ -78,3 +79,27 @@ func TestMultipleColors(t *testing.T) {
 		t.Errorf("Expected RESET escape code in combined text, got: %s", combined)
 	}
 }
+
+func TestColorsDisabled(t *testing.T) {
+	SetEnabled(false)
+	defer SetEnabled(true)
+
+	if output := RED.Sprintf("Hello %s", "World"); output != "Hello World" {
+		t.Errorf("Expected no escape codes, got: %q", output)
+	}
+}
+
+func TestSetOutput(t *testing.T) {
+	var out strings.Builder
+	SetOutput(&out)
+	defer SetOutput(os.Stdout)
+
+	RED.Printf("Hello %s", "World")
+	GREEN.Println("Line")
+	if !strings.Contains(out.String(), "Hello World") || !strings.Contains(out.String(), "Line\n") {
+		t.Errorf("Expected the printers to write to the output, got: %q", out.String())
+	}
+	if Output() != &out {
+		t.Errorf("Expected Output to return the writer set with SetOutput")
+	}
+}
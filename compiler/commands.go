@@ -0,0 +1,383 @@
+package main
+
+import (
+	//Standard packages
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/analyzer"
+	"walrus/compiler/colors"
+	"walrus/compiler/internal/formatter"
+	"walrus/compiler/internal/lexer"
+	"walrus/compiler/project"
+	"walrus/compiler/report"
+)
+
+// commands maps the name of every command to the function running it with its arguments.
+var commands map[string]func(args []string) error
+
+func init() {
+	commands = map[string]func(args []string) error{
+		"check":  check,
+		"build":  build,
+		"run":    run,
+		"tokens": tokens,
+		"ast":    tree,
+		"ir":     lower,
+		"go":     transpiler("go"),
+		"c":      transpiler("c"),
+		"fmt":    format,
+		"lsp":    lsp,
+	}
+}
+
+// stdout is the standard output, where the problems, the output of a command and the output of
+// the programs it runs are written. The json and sarif formats keep it for them, everything
+// else the compiler prints goes to the standard error.
+var stdout io.Writer = os.Stdout
+
+// options holds the flags shared by the commands.
+type options struct {
+	debug  bool
+	color  bool
+	format string
+}
+
+// newFlags returns the flag set of a command with the shared flags registered.
+func newFlags(name, arguments string, opts *options) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.BoolVar(&opts.debug, "debug", false, "print the tokens of every file")
+	flags.BoolVar(&opts.color, "color", true, "color the output")
+	flags.StringVar(&opts.format, "format", "text", "format of the problems: text, short, json or sarif")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: walrus %s [flags] %s\n", name, arguments)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parse parses the arguments of a command and applies the shared flags.
+func parse(flags *flag.FlagSet, opts *options, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	switch opts.format {
+	case "text", "short":
+	case "json", "sarif":
+		colors.SetOutput(os.Stderr)
+	default:
+		return fmt.Errorf("unknown format '%s'", opts.format)
+	}
+	colors.SetEnabled(opts.color && os.Getenv("NO_COLOR") == "")
+	return nil
+}
+
+// file returns the only file argument of a command.
+func file(flags *flag.FlagSet) (string, error) {
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return "", errors.New("expected a file")
+	}
+	return flags.Arg(0), nil
+}
+
+// display prints the problems in the format chosen with -format. The text format ends with
+// the status of the compilation when there are problems, or always when status is set.
+// The json and sarif formats are always written, even without problems.
+func display(r report.Reports, opts *options, status bool) {
+	var err error
+	switch opts.format {
+	case "short":
+		r.DisplayShort()
+	case "json":
+		err = r.WriteJSON(stdout)
+	case "sarif":
+		err = r.WriteSARIF(stdout)
+	default:
+		if len(r) > 0 || status {
+			r.DisplayAll()
+		}
+	}
+	if err != nil {
+		colors.RED.Println("Error writing the problems: ", err)
+	}
+}
+
+func check(args []string) error {
+
+	var opts options
+	flags := newFlags("check", "[file|folder]", &opts)
+	save := flags.Bool("json", false, "save the tree of the file as JSON")
+	saveBytecode := flags.Bool("bytecode", false, "save the bytecode of the file when there are no errors")
+	output := flags.String("out", "", "folder the saved files are written to, the folder of the file by default")
+	if err := parse(flags, &opts, args); err != nil {
+		return err
+	}
+
+	target := "."
+	if flags.NArg() > 0 {
+		target = flags.Arg(0)
+	}
+
+	if strings.HasSuffix(target, ".wal") {
+		r, err := analyzer.AnalyzeTo(target, *output, true, opts.debug, *save, *saveBytecode)
+		display(r, &opts, false)
+		return err
+	}
+
+	manifest, err := loadProject(target, false)
+	if err != nil {
+		return err
+	}
+	files, err := manifest.Files()
+	if err != nil {
+		return err
+	}
+
+	r, err := analyzer.Check(files, opts.debug)
+	display(r, &opts, true)
+	if err == nil && r.HasErrors() {
+		err = errors.New(analyzer.HALTED)
+	}
+	return err
+}
+
+func build(args []string) error {
+
+	var opts options
+	flags := newFlags("build", "[folder]", &opts)
+	if err := parse(flags, &opts, args); err != nil {
+		return err
+	}
+
+	target := "."
+	if flags.NArg() > 0 {
+		target = flags.Arg(0)
+	}
+
+	manifest, err := loadProject(target, true)
+	if err != nil {
+		return err
+	}
+
+	r, err := analyzer.Build(manifest, opts.debug)
+	display(r, &opts, true)
+	return err
+}
+
+// loadProject returns the manifest of the project the folder belongs to. Without a project
+// file, the folder is checked as a project of its own, but it cannot be built.
+func loadProject(dir string, build bool) (*project.Manifest, error) {
+	path, err := project.Find(dir)
+	if err != nil {
+		if build {
+			return nil, err
+		}
+		return project.Default(dir), nil
+	}
+	return project.Load(path)
+}
+
+func run(args []string) error {
+
+	var opts options
+	flags := newFlags("run", "<file.wal|file.wbc>", &opts)
+	useVM := flags.Bool("vm", false, "run the program on the bytecode vm, a .wbc file always runs on it")
+	if err := parse(flags, &opts, args); err != nil {
+		return err
+	}
+
+	filePath, err := file(flags)
+	if err != nil {
+		return err
+	}
+
+	var r report.Reports
+	if strings.HasSuffix(filePath, ".wbc") {
+		r, err = analyzer.RunBytecode(filePath, stdout)
+	} else {
+		r, err = analyzer.Run(filePath, stdout, opts.debug, *useVM)
+	}
+	display(r, &opts, false)
+	return err
+}
+
+func tokens(args []string) (err error) {
+
+	var opts options
+	flags := newFlags("tokens", "<file>", &opts)
+	if err := parse(flags, &opts, args); err != nil {
+		return err
+	}
+
+	filePath, err := file(flags)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			display(report.GetReports(), &opts, false)
+			err = fmt.Errorf("%v", r)
+		}
+		report.ClearReports()
+	}()
+
+	lexer.Tokenize(filePath, true)
+
+	return nil
+}
+
+func tree(args []string) error {
+
+	var opts options
+	flags := newFlags("ast", "<file>", &opts)
+	output := flags.String("out", "", "folder the tree is written to, the folder of the file by default")
+	if err := parse(flags, &opts, args); err != nil {
+		return err
+	}
+
+	filePath, err := file(flags)
+	if err != nil {
+		return err
+	}
+
+	r, err := analyzer.AnalyzeTo(filePath, *output, true, opts.debug, true, false)
+	display(r, &opts, false)
+	if err != nil {
+		return err
+	}
+
+	folder := *output
+	if folder == "" {
+		folder = filepath.Dir(filePath)
+	}
+	colors.GREEN.Printf("Tree written to %s\n", filepath.Join(folder, "ast", filepath.Base(filePath)+".json"))
+
+	return nil
+}
+
+func lower(args []string) error {
+
+	var opts options
+	flags := newFlags("ir", "<file>", &opts)
+	if err := parse(flags, &opts, args); err != nil {
+		return err
+	}
+
+	filePath, err := file(flags)
+	if err != nil {
+		return err
+	}
+
+	program, r, err := analyzer.Lower(filePath, opts.debug)
+	display(r, &opts, false)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(stdout, program)
+	return nil
+}
+
+// transpiler returns the command generating the source of a file in the target language.
+func transpiler(target string) func(args []string) error {
+	return func(args []string) error {
+
+		var opts options
+		flags := newFlags(target, "<file>", &opts)
+		if err := parse(flags, &opts, args); err != nil {
+			return err
+		}
+
+		filePath, err := file(flags)
+		if err != nil {
+			return err
+		}
+
+		r, err := analyzer.Transpile(filePath, opts.debug, target)
+		display(r, &opts, false)
+		return err
+	}
+}
+
+func format(args []string) error {
+
+	flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+	write := flags.Bool("w", false, "write the result to the files instead of printing it")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: walrus fmt [flags] <files>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("expected a file")
+	}
+
+	for _, filePath := range flags.Args() {
+		src, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+
+		formatted, err := formatter.Format(src)
+		if err != nil {
+			return fmt.Errorf("%s:%v", filePath, err)
+		}
+
+		if !*write {
+			fmt.Fprint(stdout, string(formatted))
+			continue
+		}
+
+		if string(formatted) != string(src) {
+			if err := os.WriteFile(filePath, formatted, 0644); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// lsp starts the language server, the 'walrus-lsp' program built from the lsp module. It is
+// looked for next to this program, then in the PATH.
+func lsp(args []string) error {
+
+	name := "walrus-lsp"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+
+	path, err := exec.LookPath(name)
+	if executable, e := os.Executable(); e == nil {
+		if local := filepath.Join(filepath.Dir(executable), name); fileExists(local) {
+			path, err = local, nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("cannot find the language server '%s'", name)
+	}
+
+	server := exec.Command(path, args...)
+	server.Stdin, server.Stdout, server.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	return server.Run()
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -20,9 +20,10 @@ func (a IdentifierExpr) EndPos() lexer.Position {
 
 // Literals or Raw values like: 1,2,3,4.6, "hello world", 'a' ...etc
 type IntegerLiteralExpr struct {
-	Value    string
-	BitSize  uint8
-	IsSigned bool
+	Value     string
+	BitSize   uint8
+	IsSigned  bool
+	IsUntyped bool // written without a type suffix, it takes the type of the place it is stored in
 	Location
 }
 
@@ -37,8 +38,9 @@ func (a IntegerLiteralExpr) EndPos() lexer.Position {
 }
 
 type FloatLiteralExpr struct {
-	Value   string
-	BitSize uint8
+	Value     string
+	BitSize   uint8
+	IsUntyped bool // written without a type suffix, it takes the type of the place it is stored in
 	Location
 }
 
@@ -67,6 +69,23 @@ func (a StringLiteralExpr) EndPos() lexer.Position {
 	return a.Location.End
 }
 
+// InterpolatedStringExpr is a string with values in it, like "hello {name}". Its parts are
+// the text around the values, as string literals, and the values, in order.
+type InterpolatedStringExpr struct {
+	Parts []Node
+	Location
+}
+
+func (a InterpolatedStringExpr) INode() {
+	//empty method implements Node interface
+}
+func (a InterpolatedStringExpr) StartPos() lexer.Position {
+	return a.Location.Start
+}
+func (a InterpolatedStringExpr) EndPos() lexer.Position {
+	return a.Location.End
+}
+
 type ByteLiteralExpr struct {
 	Value string
 	Location
@@ -112,6 +131,40 @@ func (a NullLiteralExpr) EndPos() lexer.Position {
 	return a.Location.End
 }
 
+// ResultExpr is 'ok(value)' or 'err(value)', a value or an error of a result type.
+type ResultExpr struct {
+	IsErr bool
+	Value Node
+	Location
+}
+
+func (a ResultExpr) INode() {
+	//empty method implements Node interface
+}
+func (a ResultExpr) StartPos() lexer.Position {
+	return a.Location.Start
+}
+func (a ResultExpr) EndPos() lexer.Position {
+	return a.Location.End
+}
+
+// PropagateExpr is 'value?'. It gives the value of a result, or returns its error from
+// the enclosing function.
+type PropagateExpr struct {
+	Value Node
+	Location
+}
+
+func (a PropagateExpr) INode() {
+	//empty method implements Node interface
+}
+func (a PropagateExpr) StartPos() lexer.Position {
+	return a.Location.Start
+}
+func (a PropagateExpr) EndPos() lexer.Position {
+	return a.Location.End
+}
+
 type MapProp struct {
 	Key   Node
 	Value Node
@@ -296,6 +349,22 @@ func (a ArrayLiteral) EndPos() lexer.Position {
 	return a.Location.End
 }
 
+// TupleLiteral is '(a, b, ...)', a tuple of two values or more.
+type TupleLiteral struct {
+	Values []Node
+	Location
+}
+
+func (a TupleLiteral) INode() {
+	//empty method implements Node interface
+}
+func (a TupleLiteral) StartPos() lexer.Position {
+	return a.Location.Start
+}
+func (a TupleLiteral) EndPos() lexer.Position {
+	return a.Location.End
+}
+
 type Indexable struct {
 	Index     Node
 	Container Node
@@ -319,6 +388,7 @@ type StructProp struct {
 
 type StructLiteral struct {
 	Identifier IdentifierExpr
+	TypeArgs   []DataType // set on literals of generic structs like '@Box<i32>{...}'
 	Properties []StructProp
 	Location
 }
@@ -336,6 +406,7 @@ func (a StructLiteral) EndPos() lexer.Position {
 type StructPropertyAccessExpr struct {
 	Object   Node
 	Property IdentifierExpr
+	Optional bool // accessed with '?.', which gives null when the object is null
 	Location
 }
 
@@ -366,6 +437,7 @@ func (a FunctionCallExpr) EndPos() lexer.Position {
 }
 
 type FunctionLiteral struct {
+	TypeParams []TypeParam // set on generic functions and methods
 	Params     []FunctionParam
 	Body       BlockStmt
 	ReturnType DataType
@@ -0,0 +1,70 @@
+package ast
+
+import (
+	//Standard packages
+	"reflect"
+)
+
+var nodeType = reflect.TypeOf((*Node)(nil)).Elem()
+
+// Rewrite replaces every node of a tree, children first, with the node fn returns for it.
+// Nodes held in slices are replaced in place, so a program, whose statements are held in a
+// slice, is rewritten for everything holding it.
+func Rewrite(node Node, fn func(Node) Node) Node {
+	if node == nil {
+		return nil
+	}
+	return fn(rewriteValue(reflect.ValueOf(node), fn).Interface().(Node))
+}
+
+// rewriteValue rewrites the nodes held by a value. Structs are copied, slices and the values
+// pointers point to are updated in place.
+func rewriteValue(value reflect.Value, fn func(Node) Node) reflect.Value {
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		if node, ok := value.Interface().(Node); ok {
+			return reflect.ValueOf(Rewrite(node, fn))
+		}
+		return value
+	case reflect.Struct:
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(value)
+		for i := 0; i < copied.NumField(); i++ {
+			if field := copied.Field(i); field.CanSet() {
+				setRewritten(field, fn)
+			}
+		}
+		return copied
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			setRewritten(value.Index(i), fn)
+		}
+		return value
+	case reflect.Pointer:
+		if !value.IsNil() {
+			setRewritten(value.Elem(), fn)
+		}
+		return value
+	}
+	return value
+}
+
+// setRewritten rewrites the nodes held by a settable value and stores the result in it. A
+// node held by a field of a node type, rather than of the Node interface, keeps its type.
+func setRewritten(value reflect.Value, fn func(Node) Node) {
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.Type() != nodeType {
+			// data types hold no expressions
+			return
+		}
+		if rewritten := rewriteValue(value, fn); rewritten.IsValid() {
+			value.Set(rewritten)
+		}
+	case reflect.Struct, reflect.Slice, reflect.Pointer:
+		value.Set(rewriteValue(value, fn))
+	}
+}
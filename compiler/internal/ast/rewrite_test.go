@@ -0,0 +1,41 @@
+package ast
+
+import (
+	"testing"
+)
+
+func TestRewrite(t *testing.T) {
+	program := ProgramStmt{
+		Contents: []Node{
+			VarDeclStmt{
+				Variables: []VarDeclStmtVar{{
+					Identifier:   IdentifierExpr{Name: "x"},
+					ExplicitType: FloatType{TypeName: "f64", BitSize: 64},
+					Value: BinaryExpr{
+						Left:  IntegerLiteralExpr{Value: "1", BitSize: 32, IsSigned: true},
+						Right: IntegerLiteralExpr{Value: "2", BitSize: 32, IsSigned: true},
+					},
+				}},
+			},
+		},
+	}
+
+	Rewrite(program, func(node Node) Node {
+		if literal, ok := node.(IntegerLiteralExpr); ok {
+			return FloatLiteralExpr{Value: literal.Value, BitSize: 64, Location: literal.Location}
+		}
+		return node
+	})
+
+	// the statements of the program are rewritten in place
+	decl := program.Contents[0].(VarDeclStmt)
+	value := decl.Variables[0].Value.(BinaryExpr)
+	for _, operand := range []Node{value.Left, value.Right} {
+		if literal, ok := operand.(FloatLiteralExpr); !ok || literal.BitSize != 64 {
+			t.Errorf("expected a f64 literal, got %#v", operand)
+		}
+	}
+	if _, ok := decl.Variables[0].ExplicitType.(FloatType); !ok {
+		t.Errorf("expected the explicit type to be kept, got %#v", decl.Variables[0].ExplicitType)
+	}
+}
@@ -21,12 +21,22 @@ type VarDeclStmtVar struct {
 	Identifier   IdentifierExpr
 	Value        Node
 	ExplicitType DataType
+	Destructure  *Destructuring // set instead of Identifier when the value is unpacked into names
+	Location
+}
+
+// Destructuring unpacks a value into names. 'let (q, r) := ...' unpacks a tuple by position,
+// where '_' skips an element, and 'let {name, age} := ...' unpacks a struct by field names.
+type Destructuring struct {
+	Names    []IdentifierExpr
+	IsStruct bool
 	Location
 }
 
 type VarDeclStmt struct {
-	Variables []VarDeclStmtVar
-	IsConst   bool
+	Variables  []VarDeclStmtVar
+	IsConst    bool
+	IsExported bool
 	Location
 }
 
@@ -43,6 +53,7 @@ func (a VarDeclStmt) EndPos() lexer.Position {
 type TypeDeclStmt struct {
 	UDTypeValue DataType
 	UDTypeName  IdentifierExpr
+	IsExported  bool
 	Location
 }
 
@@ -89,6 +100,7 @@ func (a IfStmt) EndPos() lexer.Position {
 }
 
 type ForStmt struct {
+	Label     IdentifierExpr // the label of the loop, an empty name if it has none
 	Init      Node
 	Condition Node
 	Increment Node
@@ -106,7 +118,30 @@ func (a ForStmt) EndPos() lexer.Position {
 	return a.Location.End
 }
 
+// WhileStmt is a 'while cond { }' loop, or a 'do { } while cond;' loop, which runs its
+// block once before checking the condition.
+type WhileStmt struct {
+	Label     IdentifierExpr // the label of the loop, an empty name if it has none
+	Condition Node
+	Block     BlockStmt
+	IsDo      bool
+	Location
+}
+
+func (a WhileStmt) INode() {
+	//empty method implements Node interface
+}
+
+func (a WhileStmt) StartPos() lexer.Position {
+	return a.Location.Start
+}
+
+func (a WhileStmt) EndPos() lexer.Position {
+	return a.Location.End
+}
+
 type ForEachStmt struct {
+	Label    IdentifierExpr // the label of the loop, an empty name if it has none
 	Key      Node
 	Value    Node
 	Iterable Node
@@ -126,6 +161,41 @@ func (a ForEachStmt) EndPos() lexer.Position {
 	return a.Location.End
 }
 
+// MatchStmt runs the block of the first arm with a pattern matching the value.
+type MatchStmt struct {
+	Value Node
+	Arms  []MatchArm
+	Location
+}
+
+func (a MatchStmt) INode() {
+	//empty method implements Node interface
+}
+
+func (a MatchStmt) StartPos() lexer.Position {
+	return a.Location.Start
+}
+
+func (a MatchStmt) EndPos() lexer.Position {
+	return a.Location.End
+}
+
+// MatchArm is one 'patterns => { ... }' arm of a match. A pattern is a literal, a range,
+// a type name or '_', which matches any value.
+type MatchArm struct {
+	Patterns []Node
+	Block    BlockStmt
+	Location
+}
+
+// TypeParam is a type parameter of a generic function or type, like 'T' or 'T: Shape'.
+// A constrained type parameter only takes types implementing the constraint interface.
+type TypeParam struct {
+	Identifier IdentifierExpr
+	Constraint DataType // nil when any type is accepted
+	Location
+}
+
 type FunctionParam struct {
 	Identifier   IdentifierExpr
 	Type         DataType
@@ -147,6 +217,7 @@ func (a FunctionParam) EndPos() lexer.Position {
 
 type FunctionDeclStmt struct {
 	Identifier IdentifierExpr
+	IsExported bool
 	FunctionLiteral
 }
 
@@ -179,14 +250,51 @@ func (a ReturnStmt) EndPos() lexer.Position {
 	return a.Location.End
 }
 
+// BreakStmt exits the innermost loop, or the loop with the label.
+type BreakStmt struct {
+	Label IdentifierExpr // an empty name if the statement has no label
+	Location
+}
+
+func (a BreakStmt) INode() {
+	//empty method implements Node interface
+}
+
+func (a BreakStmt) StartPos() lexer.Position {
+	return a.Location.Start
+}
+
+func (a BreakStmt) EndPos() lexer.Position {
+	return a.Location.End
+}
+
+// ContinueStmt skips to the next iteration of the innermost loop, or of the loop with the label.
+type ContinueStmt struct {
+	Label IdentifierExpr // an empty name if the statement has no label
+	Location
+}
+
+func (a ContinueStmt) INode() {
+	//empty method implements Node interface
+}
+
+func (a ContinueStmt) StartPos() lexer.Position {
+	return a.Location.Start
+}
+
+func (a ContinueStmt) EndPos() lexer.Position {
+	return a.Location.End
+}
+
 type MethodToImplement struct {
 	IsPrivate bool
 	FunctionDeclStmt
 }
 
 type ImplStmt struct {
-	ImplFor IdentifierExpr
-	Methods []MethodToImplement
+	ImplFor    IdentifierExpr
+	TypeParams []IdentifierExpr // the type parameters of a generic struct, like 'impl Box<T>'
+	Methods    []MethodToImplement
 	Location
 }
 
@@ -220,3 +328,22 @@ func (a SafeStmt) StartPos() lexer.Position {
 func (a SafeStmt) EndPos() lexer.Position {
 	return a.Location.End
 }
+
+// ImportStmt imports the exported declarations of another file. Path is written as in the
+// source, relative to the importing file.
+type ImportStmt struct {
+	Path string
+	Location
+}
+
+func (a ImportStmt) INode() {
+	//empty method implements Node interface
+}
+
+func (a ImportStmt) StartPos() lexer.Position {
+	return a.Location.Start
+}
+
+func (a ImportStmt) EndPos() lexer.Position {
+	return a.Location.End
+}
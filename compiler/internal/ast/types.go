@@ -105,6 +105,58 @@ func (a ArrayType) EndPos() lexer.Position {
 	return a.Location.End
 }
 
+// MaybeType is a type written 'T?'. Its values are the values of T and null.
+type MaybeType struct {
+	TypeName  builtins.PARSER_TYPE
+	MaybeType DataType
+	Location
+}
+
+func (a MaybeType) Type() builtins.PARSER_TYPE {
+	return a.TypeName
+}
+func (a MaybeType) StartPos() lexer.Position {
+	return a.Location.Start
+}
+func (a MaybeType) EndPos() lexer.Position {
+	return a.Location.End
+}
+
+// TupleType is a type written '(T, U, ...)'. Its values hold a value of each of the types, in order.
+type TupleType struct {
+	TypeName builtins.PARSER_TYPE
+	Elements []DataType
+	Location
+}
+
+func (a TupleType) Type() builtins.PARSER_TYPE {
+	return a.TypeName
+}
+func (a TupleType) StartPos() lexer.Position {
+	return a.Location.Start
+}
+func (a TupleType) EndPos() lexer.Position {
+	return a.Location.End
+}
+
+// ResultType is a type written 'T!E'. Its values are the values of T, or an error of type E.
+type ResultType struct {
+	TypeName builtins.PARSER_TYPE
+	OkType   DataType
+	ErrType  DataType
+	Location
+}
+
+func (a ResultType) Type() builtins.PARSER_TYPE {
+	return a.TypeName
+}
+func (a ResultType) StartPos() lexer.Position {
+	return a.Location.Start
+}
+func (a ResultType) EndPos() lexer.Position {
+	return a.Location.End
+}
+
 type StructPropType struct {
 	Prop      IdentifierExpr
 	PropType  DataType
@@ -212,9 +264,55 @@ func (a RangeType) EndPos() lexer.Position {
 	return a.Location.End
 }
 
+// EnumType is the definition of an enum, a closed set of variants like
+// 'enum { Circle(r: f64), Empty }'.
+type EnumType struct {
+	TypeName builtins.PARSER_TYPE
+	Variants []EnumVariant
+	Location
+}
+
+func (a EnumType) Type() builtins.PARSER_TYPE {
+	return a.TypeName
+}
+func (a EnumType) StartPos() lexer.Position {
+	return a.Location.Start
+}
+func (a EnumType) EndPos() lexer.Position {
+	return a.Location.End
+}
+
+// EnumVariant is one variant of an enum. Its fields are named like the parameters of a
+// function, and a variant without fields has none.
+type EnumVariant struct {
+	Name   IdentifierExpr
+	Fields []FunctionTypeParam
+	Location
+}
+
+// GenericType is the definition of a generic type like 'type Box<T> struct { ... }'. An
+// instance like 'Box<i32>' is the definition with its type parameters replaced.
+type GenericType struct {
+	TypeName   builtins.PARSER_TYPE
+	TypeParams []TypeParam
+	TypeDef    DataType
+	Location
+}
+
+func (a GenericType) Type() builtins.PARSER_TYPE {
+	return a.TypeName
+}
+func (a GenericType) StartPos() lexer.Position {
+	return a.Location.Start
+}
+func (a GenericType) EndPos() lexer.Position {
+	return a.Location.End
+}
+
 type UserDefinedType struct {
 	TypeName  builtins.PARSER_TYPE
 	AliasName string
+	TypeArgs  []DataType // set on instances of generic types like 'Box<i32>'
 	Location
 }
 
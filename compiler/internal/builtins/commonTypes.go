@@ -11,10 +11,12 @@ const (
 	INT16        = "i16"
 	INT32        = "i32"
 	INT64        = "i64"
+	INT128       = "i128"
 	UINT8        = "u8"
 	UINT16       = "u16"
 	UINT32       = "u32"
 	UINT64       = "u64"
+	UINT128      = "u128"
 	FLOAT32      = "f32"
 	FLOAT64      = "f64"
 	STRING       = "str"
@@ -23,10 +25,19 @@ const (
 	FUNCTION     = "fn"
 	STRUCT       = "struct"
 	INTERFACE    = "interface"
+	ENUM         = "enum"
 	ARRAY        = "array"
 	MAP          = "map"
 	VOID         = "void"
 	RANGE        = "range"
+	MAYBE        = "maybe"
+	NULL         = "null"
+	RESULT       = "result"
+	TUPLE        = "tuple"
+	OK           = "ok"
+	ERR          = "err"
+	GENERIC      = "generic"
+	TYPE_PARAM   = "type_param"
 	USER_DEFINED = "user_defined"
 )
 
@@ -44,6 +55,8 @@ func GetBitSize[T Searchable](kind T) uint8 {
 		return 32
 	case INT64, UINT64, FLOAT64:
 		return 64
+	case INT128, UINT128:
+		return 128
 	default:
 		return 0
 	}
@@ -51,7 +64,7 @@ func GetBitSize[T Searchable](kind T) uint8 {
 
 func IsSigned[T Searchable](kind T) bool {
 	switch kind {
-	case INT8, INT16, INT32, INT64:
+	case INT8, INT16, INT32, INT64, INT128:
 		return true
 	default:
 		return false
@@ -60,7 +73,7 @@ func IsSigned[T Searchable](kind T) bool {
 
 func IsUnsigned[T Searchable](kind T) bool {
 	switch kind {
-	case UINT8, UINT16, UINT32, UINT64, BYTE:
+	case UINT8, UINT16, UINT32, UINT64, UINT128, BYTE:
 		return true
 	default:
 		return false
@@ -20,6 +20,8 @@ func TestGetBitSize(t *testing.T) {
 		{INT64, 64},
 		{UINT64, 64},
 		{FLOAT64, 64},
+		{INT128, 128},
+		{UINT128, 128},
 		{STRING, 0},
 	}
 
@@ -41,6 +43,7 @@ func TestIsSigned(t *testing.T) {
 		{INT16, true},
 		{INT32, true},
 		{INT64, true},
+		{INT128, true},
 		{UINT8, false},
 		{UINT16, false},
 		{UINT32, false},
@@ -0,0 +1,228 @@
+package bytecode
+
+import (
+	//Standard packages
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/values"
+	"walrus/compiler/report"
+)
+
+// scope is the compile time view of a runtime scope. Every variable gets a slot,
+// so the vm finds variables by position instead of by name.
+type scope struct {
+	parent *scope
+	slots  map[string]int
+	size   int
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{parent: parent, slots: make(map[string]int)}
+}
+
+func (s *scope) declare(name string) int {
+	if slot, ok := s.slots[name]; ok {
+		return slot
+	}
+	slot := s.size
+	s.slots[name] = slot
+	s.size++
+	return slot
+}
+
+// resolve returns how many scopes up the variable is declared and its slot in that scope.
+func (s *scope) resolve(name string) (int, int, bool) {
+	depth := 0
+	for current := s; current != nil; current = current.parent {
+		if slot, ok := current.slots[name]; ok {
+			return depth, slot, true
+		}
+		depth++
+	}
+	return 0, 0, false
+}
+
+// loop is a loop being compiled. The jumps of its 'break' and 'continue' statements are
+// patched once the end of the loop and the start of its next iteration are known.
+type loop struct {
+	label     string
+	scope     *scope // the scope of the loop, the jumps leave the scopes nested in it
+	breaks    []int
+	continues []int
+}
+
+type Compiler struct {
+	filePath  string
+	program   *Program
+	types     values.TypeTable
+	constants map[string]int
+	methods   map[string]int // struct name -> index in program.Methods
+	variants  map[string]int // 'Enum.Variant' -> index of the function creating the variant
+	function  *Function
+	scope     *scope
+	loops     []*loop // the loops around the current statement, the innermost last
+}
+
+func NewCompiler(filePath string) *Compiler {
+	return &Compiler{
+		filePath:  filePath,
+		program:   &Program{Source: filePath},
+		types:     make(values.TypeTable),
+		constants: make(map[string]int),
+		methods:   make(map[string]int),
+		variants:  make(map[string]int),
+	}
+}
+
+// Compile compiles a type checked program to bytecode.
+// Nodes the compiler does not support are reported as critical errors.
+func Compile(program ast.Node, filePath string) *Program {
+	c := NewCompiler(filePath)
+
+	main := &Function{Name: "main"}
+	c.program.Functions = append(c.program.Functions, main)
+	c.function = main
+	c.scope = newScope(nil)
+	for _, name := range Globals {
+		c.scope.declare(name)
+	}
+
+	if prog, ok := program.(ast.ProgramStmt); ok {
+		c.declareMethods(prog)
+	}
+
+	c.compileStmt(program)
+	c.emit(program, OP_VOID)
+	c.emit(program, OP_RETURN)
+	main.Locals = c.scope.size
+
+	return c.program
+}
+
+func (c *Compiler) compileError(node ast.Node, msg string) {
+	report.Add(c.filePath, node.StartPos().Line, node.EndPos().Line, node.StartPos().Column, node.EndPos().Column, msg).SetLevel(report.CRITICAL_ERROR)
+}
+
+// emit appends an instruction and returns its offset.
+func (c *Compiler) emit(node ast.Node, op Opcode, operands ...int) int {
+	fn := c.function
+	offset := len(fn.Code)
+
+	span := Span{LineStart: node.StartPos().Line, LineEnd: node.EndPos().Line, ColStart: node.StartPos().Column, ColEnd: node.EndPos().Column}
+	if len(fn.Spans) == 0 || fn.Spans[len(fn.Spans)-1].Span != span {
+		fn.Spans = append(fn.Spans, SpanEntry{Offset: offset, Span: span})
+	}
+
+	fn.Code = append(fn.Code, byte(op))
+	for _, operand := range operands {
+		if operand < 0 || operand > math.MaxUint16 {
+			c.compileError(node, fmt.Sprintf("operand %d of %s is out of range", operand, op))
+		}
+		fn.Code = binary.BigEndian.AppendUint16(fn.Code, uint16(operand))
+	}
+	return offset
+}
+
+// emitJump emits a jump with an unknown target and returns the offset of the operand to patch.
+func (c *Compiler) emitJump(node ast.Node, op Opcode) int {
+	return c.emit(node, op, 0) + 1
+}
+
+// patch sets the operand at the given offset.
+func (c *Compiler) patch(node ast.Node, offset int, value int) {
+	if value > math.MaxUint16 {
+		c.compileError(node, "function body is too large")
+	}
+	binary.BigEndian.PutUint16(c.function.Code[offset:], uint16(value))
+}
+
+// constant adds a value to the constant pool, reusing an equal constant of the same type.
+func (c *Compiler) constant(node ast.Node, value values.Value) int {
+	key := values.TypeName(value) + ":" + value.String()
+	if index, ok := c.constants[key]; ok {
+		return index
+	}
+	index := len(c.program.Constants)
+	if index > math.MaxUint16 {
+		c.compileError(node, "too many constants in one program")
+	}
+	c.program.Constants = append(c.program.Constants, value)
+	c.constants[key] = index
+	return index
+}
+
+func (c *Compiler) layout(name string, fields []string) int {
+	c.program.Layouts = append(c.program.Layouts, StructLayout{Name: name, Fields: fields})
+	return len(c.program.Layouts) - 1
+}
+
+func (c *Compiler) enterScope() {
+	c.scope = newScope(c.scope)
+}
+
+func (c *Compiler) exitScope() {
+	c.scope = c.scope.parent
+}
+
+// declareMethods collects the methods of every struct before compiling, so a method can
+// call the other methods of its struct by name.
+func (c *Compiler) declareMethods(program ast.ProgramStmt) {
+	for _, node := range program.Contents {
+		switch t := node.(type) {
+		case ast.TypeDeclStmt:
+			c.types[t.UDTypeName.Name] = t.UDTypeValue
+		case ast.ImplStmt:
+			name := c.types.StructName(t.ImplFor.Name)
+			index, ok := c.methods[name]
+			if !ok {
+				index = len(c.program.Methods)
+				c.program.Methods = append(c.program.Methods, MethodTable{Struct: name})
+				c.methods[name] = index
+			}
+			table := &c.program.Methods[index]
+			for _, method := range t.Methods {
+				table.Names = append(table.Names, method.Identifier.Name)
+				table.Functions = append(table.Functions, -1)
+			}
+		}
+	}
+}
+
+// compileFunction compiles a function literal to a new function and returns its index.
+// The receiver scope holds 'this' and the struct methods when compiling a method.
+func (c *Compiler) compileFunction(name string, literal ast.FunctionLiteral, receiver *scope) int {
+	fn := &Function{Name: name, Arity: len(literal.Params)}
+	c.program.Functions = append(c.program.Functions, fn)
+	index := len(c.program.Functions) - 1
+
+	enclosingFunction, enclosingScope, enclosingLoops := c.function, c.scope, c.loops
+	c.function = fn
+	c.loops = nil
+	if receiver != nil {
+		c.scope = newScope(receiver)
+	} else {
+		c.scope = newScope(enclosingScope)
+	}
+
+	for _, param := range literal.Params {
+		c.scope.declare(param.Identifier.Name)
+	}
+
+	c.compileBlock(literal.Body)
+	c.emit(literal, OP_VOID)
+	c.emit(literal, OP_RETURN)
+	fn.Locals = c.scope.size
+
+	c.function, c.scope, c.loops = enclosingFunction, enclosingScope, enclosingLoops
+	return index
+}
+
+func (c *Compiler) compileBlock(block ast.BlockStmt) {
+	for _, stmt := range block.Contents {
+		c.compileStmt(stmt)
+	}
+}
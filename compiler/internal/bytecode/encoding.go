@@ -0,0 +1,275 @@
+package bytecode
+
+import (
+	//Standard packages
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+
+	//Walrus packages
+	"walrus/compiler/internal/values"
+)
+
+// The encoded program starts with MAGIC and VERSION, followed by its source file, the
+// constants, functions, struct layouts and method tables. Counts and integers are written as
+// varints and strings as their length followed by their bytes.
+const (
+	MAGIC   = "WBC"
+	VERSION = 7
+)
+
+const (
+	intConstant byte = iota
+	floatConstant
+	strConstant
+	boolConstant
+	nullConstant
+)
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *encoder) write(p []byte) {
+	if e.err == nil {
+		_, e.err = e.w.Write(p)
+	}
+}
+
+func (e *encoder) byte(b byte) {
+	e.write([]byte{b})
+}
+
+func (e *encoder) uint(n int) {
+	e.write(binary.AppendUvarint(nil, uint64(n)))
+}
+
+func (e *encoder) string(s string) {
+	e.uint(len(s))
+	e.write([]byte(s))
+}
+
+func (e *encoder) constant(value values.Value) {
+	switch v := value.(type) {
+	case values.Int:
+		e.byte(intConstant)
+		e.byte(v.BitSize)
+		e.bool(v.IsSigned)
+		e.string(v.Value.String())
+	case values.Float:
+		e.byte(floatConstant)
+		e.byte(v.BitSize)
+		e.write(binary.BigEndian.AppendUint64(nil, math.Float64bits(v.Value)))
+	case values.Str:
+		e.byte(strConstant)
+		e.string(v.Value)
+	case values.Bool:
+		e.byte(boolConstant)
+		e.bool(v.Value)
+	case values.Null:
+		e.byte(nullConstant)
+	default:
+		if e.err == nil {
+			e.err = fmt.Errorf("cannot encode constant of type '%s'", values.TypeName(value))
+		}
+	}
+}
+
+func (e *encoder) bool(b bool) {
+	if b {
+		e.byte(1)
+	} else {
+		e.byte(0)
+	}
+}
+
+// Encode writes the program in the walrus bytecode format.
+func Encode(w io.Writer, program *Program) error {
+	e := &encoder{w: bufio.NewWriter(w)}
+
+	e.write([]byte(MAGIC))
+	e.byte(VERSION)
+	e.string(program.Source)
+
+	e.uint(len(program.Constants))
+	for _, constant := range program.Constants {
+		e.constant(constant)
+	}
+
+	e.uint(len(program.Functions))
+	for _, fn := range program.Functions {
+		e.string(fn.Name)
+		e.uint(fn.Arity)
+		e.uint(fn.Locals)
+		e.uint(len(fn.Code))
+		e.write(fn.Code)
+		e.uint(len(fn.Spans))
+		for _, entry := range fn.Spans {
+			e.uint(entry.Offset)
+			e.uint(entry.LineStart)
+			e.uint(entry.LineEnd)
+			e.uint(entry.ColStart)
+			e.uint(entry.ColEnd)
+		}
+	}
+
+	e.uint(len(program.Layouts))
+	for _, layout := range program.Layouts {
+		e.string(layout.Name)
+		e.uint(len(layout.Fields))
+		for _, field := range layout.Fields {
+			e.string(field)
+		}
+	}
+
+	e.uint(len(program.Methods))
+	for _, table := range program.Methods {
+		e.string(table.Struct)
+		e.uint(len(table.Names))
+		for i, name := range table.Names {
+			e.string(name)
+			e.uint(table.Functions[i])
+		}
+	}
+
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+type decoder struct {
+	r   *bufio.Reader
+	err error
+}
+
+func (d *decoder) read(n int) []byte {
+	if d.err != nil {
+		return make([]byte, n)
+	}
+	p := make([]byte, n)
+	_, d.err = io.ReadFull(d.r, p)
+	return p
+}
+
+func (d *decoder) byte() byte {
+	return d.read(1)[0]
+}
+
+func (d *decoder) bool() bool {
+	return d.byte() != 0
+}
+
+func (d *decoder) uint() int {
+	if d.err != nil {
+		return 0
+	}
+	n, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		d.err = err
+		return 0
+	}
+	if n > math.MaxInt32 {
+		d.err = errors.New("invalid bytecode: count is too large")
+		return 0
+	}
+	return int(n)
+}
+
+func (d *decoder) string() string {
+	return string(d.read(d.uint()))
+}
+
+func (d *decoder) constant() values.Value {
+	switch kind := d.byte(); kind {
+	case intConstant:
+		bitSize := d.byte()
+		isSigned := d.bool()
+		value, ok := new(big.Int).SetString(d.string(), 10)
+		if !ok && d.err == nil {
+			d.err = errors.New("invalid bytecode: malformed integer constant")
+		}
+		return values.Int{Value: value, BitSize: bitSize, IsSigned: isSigned}
+	case floatConstant:
+		bitSize := d.byte()
+		return values.Float{Value: math.Float64frombits(binary.BigEndian.Uint64(d.read(8))), BitSize: bitSize}
+	case strConstant:
+		return values.NewStr(d.string())
+	case boolConstant:
+		return values.NewBool(d.bool())
+	case nullConstant:
+		return values.Null{}
+	default:
+		if d.err == nil {
+			d.err = fmt.Errorf("invalid bytecode: unknown constant kind %d", kind)
+		}
+		return values.NewVoid()
+	}
+}
+
+// Decode reads a program written by Encode.
+func Decode(r io.Reader) (*Program, error) {
+	d := &decoder{r: bufio.NewReader(r)}
+
+	if magic := string(d.read(len(MAGIC))); d.err == nil && magic != MAGIC {
+		return nil, errors.New("invalid bytecode: missing walrus bytecode header")
+	}
+	if version := d.byte(); d.err == nil && version != VERSION {
+		return nil, fmt.Errorf("invalid bytecode: unsupported version %d", version)
+	}
+
+	program := &Program{Source: d.string()}
+
+	count := d.uint()
+	for i := 0; i < count && d.err == nil; i++ {
+		program.Constants = append(program.Constants, d.constant())
+	}
+
+	count = d.uint()
+	for i := 0; i < count && d.err == nil; i++ {
+		fn := &Function{Name: d.string(), Arity: d.uint(), Locals: d.uint()}
+		fn.Code = d.read(d.uint())
+		spans := d.uint()
+		for j := 0; j < spans && d.err == nil; j++ {
+			fn.Spans = append(fn.Spans, SpanEntry{Offset: d.uint(), Span: Span{LineStart: d.uint(), LineEnd: d.uint(), ColStart: d.uint(), ColEnd: d.uint()}})
+		}
+		program.Functions = append(program.Functions, fn)
+	}
+
+	count = d.uint()
+	for i := 0; i < count && d.err == nil; i++ {
+		layout := StructLayout{Name: d.string()}
+		fields := d.uint()
+		for j := 0; j < fields && d.err == nil; j++ {
+			layout.Fields = append(layout.Fields, d.string())
+		}
+		program.Layouts = append(program.Layouts, layout)
+	}
+
+	count = d.uint()
+	for i := 0; i < count && d.err == nil; i++ {
+		table := MethodTable{Struct: d.string()}
+		methods := d.uint()
+		for j := 0; j < methods && d.err == nil; j++ {
+			table.Names = append(table.Names, d.string())
+			table.Functions = append(table.Functions, d.uint())
+		}
+		program.Methods = append(program.Methods, table)
+	}
+
+	if d.err != nil {
+		if errors.Is(d.err, io.EOF) || errors.Is(d.err, io.ErrUnexpectedEOF) {
+			return nil, errors.New("invalid bytecode: unexpected end of file")
+		}
+		return nil, d.err
+	}
+	if len(program.Functions) == 0 {
+		return nil, errors.New("invalid bytecode: program has no main function")
+	}
+	return program, nil
+}
@@ -0,0 +1,83 @@
+package bytecode
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"walrus/compiler/internal/values"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	program := &Program{
+		Source: "main.wal",
+		Constants: []values.Value{
+			values.Int{Value: big.NewInt(-42), BitSize: 64, IsSigned: true},
+			values.NewFloat(1.5, 32),
+			values.NewStr("hello"),
+			values.NewBool(true),
+			values.Null{},
+		},
+		Functions: []*Function{
+			{
+				Name:   "main",
+				Locals: 5,
+				Code:   []byte{byte(OP_CONSTANT), 0, 0, byte(OP_DEFINE_VAR), 0, 4, byte(OP_VOID), byte(OP_RETURN)},
+				Spans:  []SpanEntry{{Offset: 0, Span: Span{LineStart: 1, LineEnd: 1, ColStart: 1, ColEnd: 10}}},
+			},
+			{Name: "sum", Arity: 1, Locals: 1, Code: []byte{byte(OP_VOID), byte(OP_RETURN)}},
+		},
+		Layouts: []StructLayout{{Name: "Point", Fields: []string{"x", "y"}}},
+		Methods: []MethodTable{{Struct: "Point", Names: []string{"sum"}, Functions: []int{1}}},
+	}
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, program); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !bytes.HasPrefix(buf.Bytes(), []byte(MAGIC)) {
+		t.Errorf("Expected encoded program to start with %q", MAGIC)
+	}
+
+	decoded, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if decoded.String() != program.String() {
+		t.Errorf("Expected decoded program\n%s\ngot\n%s", program.String(), decoded.String())
+	}
+	if decoded.Source != program.Source {
+		t.Errorf("Expected source %q, got %q", program.Source, decoded.Source)
+	}
+	if decoded.Main().SpanAt(3) != program.Main().SpanAt(3) {
+		t.Errorf("Expected span %v, got %v", program.Main().SpanAt(3), decoded.Main().SpanAt(3))
+	}
+	if decoded.Layouts[0].Name != "Point" || len(decoded.Layouts[0].Fields) != 2 {
+		t.Errorf("Expected layout of 'Point' with 2 fields, got %v", decoded.Layouts[0])
+	}
+	if decoded.Methods[0].Functions[0] != 1 {
+		t.Errorf("Expected method 'sum' to be function 1, got %d", decoded.Methods[0].Functions[0])
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{name: "Empty input", input: []byte{}},
+		{name: "Wrong header", input: []byte("ELF\x01")},
+		{name: "Wrong version", input: []byte("WBC\x09")},
+		{name: "Truncated program", input: []byte("WBC\x01\x02\x00")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(bytes.NewReader(tt.input)); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
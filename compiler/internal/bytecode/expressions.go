@@ -0,0 +1,384 @@
+package bytecode
+
+import (
+	//Standard packages
+	"fmt"
+	"math/big"
+	"strconv"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/builtins"
+	"walrus/compiler/internal/lexer"
+	"walrus/compiler/internal/values"
+)
+
+var binaryOpcodes = map[builtins.TOKEN_KIND]Opcode{
+	lexer.PLUS_TOKEN:          OP_ADD,
+	lexer.MINUS_TOKEN:         OP_SUB,
+	lexer.MUL_TOKEN:           OP_MUL,
+	lexer.DIV_TOKEN:           OP_DIV,
+	lexer.MOD_TOKEN:           OP_MOD,
+	lexer.EXP_TOKEN:           OP_EXP,
+	lexer.BIT_AND_TOKEN:       OP_BIT_AND,
+	lexer.BIT_OR_TOKEN:        OP_BIT_OR,
+	lexer.BIT_XOR_TOKEN:       OP_BIT_XOR,
+	lexer.SHIFT_LEFT_TOKEN:    OP_SHIFT_LEFT,
+	lexer.SHIFT_RIGHT_TOKEN:   OP_SHIFT_RIGHT,
+	lexer.DOUBLE_EQUAL_TOKEN:  OP_EQUAL,
+	lexer.NOT_EQUAL_TOKEN:     OP_NOT_EQUAL,
+	lexer.LESS_TOKEN:          OP_LESS,
+	lexer.LESS_EQUAL_TOKEN:    OP_LESS_EQUAL,
+	lexer.GREATER_TOKEN:       OP_GREATER,
+	lexer.GREATER_EQUAL_TOKEN: OP_GREATER_EQUAL,
+}
+
+// compoundOpcodes maps assignment operators like += to the operation they apply.
+var compoundOpcodes = map[builtins.TOKEN_KIND]Opcode{
+	lexer.PLUS_EQUALS_TOKEN:        OP_ADD,
+	lexer.MINUS_EQUALS_TOKEN:       OP_SUB,
+	lexer.MUL_EQUALS_TOKEN:         OP_MUL,
+	lexer.DIV_EQUALS_TOKEN:         OP_DIV,
+	lexer.MOD_EQUALS_TOKEN:         OP_MOD,
+	lexer.EXP_EQUALS_TOKEN:         OP_EXP,
+	lexer.BIT_AND_EQUALS_TOKEN:     OP_BIT_AND,
+	lexer.BIT_OR_EQUALS_TOKEN:      OP_BIT_OR,
+	lexer.BIT_XOR_EQUALS_TOKEN:     OP_BIT_XOR,
+	lexer.SHIFT_LEFT_EQUALS_TOKEN:  OP_SHIFT_LEFT,
+	lexer.SHIFT_RIGHT_EQUALS_TOKEN: OP_SHIFT_RIGHT,
+}
+
+// zeroConstant returns the zero value of a primitive type.
+func zeroConstant(dtype ast.DataType) values.Value {
+	switch t := dtype.(type) {
+	case ast.IntegerType:
+		return values.NewInt(0, t.BitSize, t.IsSigned)
+	case ast.FloatType:
+		return values.NewFloat(0, t.BitSize)
+	case ast.StringType:
+		return values.NewStr("")
+	default:
+		return values.NewBool(false)
+	}
+}
+
+// compileExpr compiles an expression. Every expression leaves exactly one value on the stack.
+func (c *Compiler) compileExpr(node ast.Node) {
+	switch t := node.(type) {
+	case ast.VarAssignmentExpr:
+		c.compileAssignment(t)
+	case ast.TypeofExpr:
+		c.compileExpr(t.Expression)
+		c.emit(t, OP_TYPEOF)
+	case ast.TypeCastExpr:
+		c.compileTypeCast(t)
+	case ast.IdentifierExpr:
+		depth, slot := c.resolve(t)
+		c.emit(t, OP_GET_VAR, depth, slot)
+	case ast.IntegerLiteralExpr:
+		value, ok := new(big.Int).SetString(t.Value, 10)
+		if !ok {
+			c.compileError(t, fmt.Sprintf("invalid integer literal '%s'", t.Value))
+			return
+		}
+		constant := values.Int{Value: values.WrapInt(value, t.BitSize, t.IsSigned), BitSize: t.BitSize, IsSigned: t.IsSigned}
+		c.emit(t, OP_CONSTANT, c.constant(t, constant))
+	case ast.FloatLiteralExpr:
+		value, err := strconv.ParseFloat(t.Value, 64)
+		if err != nil {
+			c.compileError(t, fmt.Sprintf("invalid float literal '%s'", t.Value))
+			return
+		}
+		c.emit(t, OP_CONSTANT, c.constant(t, values.NewFloat(value, t.BitSize)))
+	case ast.StringLiteralExpr:
+		c.emit(t, OP_CONSTANT, c.constant(t, values.NewStr(t.Value)))
+	case ast.InterpolatedStringExpr:
+		// "a {x}" is "" + "a" + x, adding a value to a string writes it
+		c.emit(t, OP_CONSTANT, c.constant(t, values.NewStr("")))
+		for _, part := range t.Parts {
+			c.compileExpr(part)
+			c.emit(t, OP_ADD)
+		}
+	case ast.ByteLiteralExpr:
+		c.emit(t, OP_CONSTANT, c.constant(t, values.NewInt(int64(t.Value[0]), 8, false)))
+	case ast.NullLiteralExpr:
+		c.emit(t, OP_CONSTANT, c.constant(t, values.Null{}))
+	case ast.ResultExpr:
+		c.compileExpr(t.Value)
+		if t.IsErr {
+			c.emit(t, OP_ERR)
+		}
+	case ast.PropagateExpr:
+		c.compilePropagate(t)
+	case ast.BinaryExpr:
+		if t.Binop.Kind == lexer.AND_TOKEN || t.Binop.Kind == lexer.OR_TOKEN {
+			c.compileLogical(t)
+			return
+		}
+		op, ok := binaryOpcodes[t.Binop.Kind]
+		if !ok {
+			c.compileError(t, fmt.Sprintf("invalid operator '%s'", t.Binop.Value))
+			return
+		}
+		c.compileExpr(t.Left)
+		c.compileExpr(t.Right)
+		c.emit(t, op)
+	case ast.UnaryExpr:
+		c.compileExpr(t.Argument)
+		switch t.Operator.Kind {
+		case lexer.NOT_TOKEN:
+			c.emit(t, OP_NOT)
+		case lexer.BIT_NOT_TOKEN:
+			c.emit(t, OP_BIT_NOT)
+		default:
+			c.emit(t, OP_NEGATE)
+		}
+	case ast.IncrementalInterface:
+		c.compileIncrementalExpr(t)
+	case ast.RangeExpr:
+		c.compileExpr(t.Start)
+		c.compileExpr(t.End)
+		c.emit(t, OP_RANGE)
+	case ast.ArrayLiteral:
+		for _, value := range t.Values {
+			c.compileExpr(value)
+		}
+		// the element type is taken from the first element at runtime
+		c.emit(t, OP_ARRAY, c.constant(t, values.NewStr("")), len(t.Values))
+	case ast.TupleLiteral:
+		for _, value := range t.Values {
+			c.compileExpr(value)
+		}
+		c.emit(t, OP_TUPLE, len(t.Values))
+	case ast.Indexable:
+		c.compileExpr(t.Container)
+		c.compileExpr(t.Index)
+		c.emit(t.Index, OP_INDEX)
+	case ast.StructLiteral:
+		c.compileStructLiteral(t)
+	case ast.StructPropertyAccessExpr:
+		if enumType, name, ok := c.enumOf(t.Object); ok {
+			c.compileVariant(t, enumType, name)
+			return
+		}
+		if t.Optional {
+			c.compileOptionalProperty(t)
+			return
+		}
+		c.compileExpr(t.Object)
+		c.emit(t.Property, OP_GET_PROPERTY, c.constant(t, values.NewStr(t.Property.Name)))
+	case ast.MapLiteral:
+		c.compileMapLiteral(t)
+	case ast.FunctionLiteral:
+		c.emit(t, OP_CLOSURE, c.compileFunction("anonymous", t, nil))
+	case ast.FunctionCallExpr:
+		c.compileExpr(t.Caller)
+		for _, arg := range t.Arguments {
+			c.compileExpr(arg)
+		}
+		c.emit(t, OP_CALL, len(t.Arguments))
+	default:
+		c.compileError(node, fmt.Sprintf("<%T> node cannot be compiled yet", node))
+	}
+}
+
+// compileLogical compiles '&&' and '||'. The right operand is only evaluated when the
+// left one does not decide the result.
+func (c *Compiler) compileLogical(node ast.BinaryExpr) {
+	c.compileExpr(node.Left)
+	rightJump := c.emitJump(node, OP_JUMP_IF_FALSE)
+	if node.Binop.Kind == lexer.AND_TOKEN {
+		c.compileExpr(node.Right)
+	} else {
+		c.emit(node, OP_CONSTANT, c.constant(node, values.NewBool(true)))
+	}
+	endJump := c.emitJump(node, OP_JUMP)
+
+	c.patch(node, rightJump, len(c.function.Code))
+	if node.Binop.Kind == lexer.AND_TOKEN {
+		c.emit(node, OP_CONSTANT, c.constant(node, values.NewBool(false)))
+	} else {
+		c.compileExpr(node.Right)
+	}
+	c.patch(node, endJump, len(c.function.Code))
+}
+
+// compilePropagate compiles 'value?'. An error is returned from the function, a value is
+// left on the stack.
+func (c *Compiler) compilePropagate(node ast.PropagateExpr) {
+	c.compileExpr(node.Value)
+	c.emit(node, OP_DUP)
+	c.emit(node, OP_IS_ERR)
+	endJump := c.emitJump(node, OP_JUMP_IF_FALSE)
+	c.emit(node, OP_RETURN)
+	c.patch(node, endJump, len(c.function.Code))
+}
+
+// compileOptionalProperty compiles a property accessed with '?.'. A null object is left
+// on the stack as the result instead of reading the property.
+func (c *Compiler) compileOptionalProperty(node ast.StructPropertyAccessExpr) {
+	c.compileExpr(node.Object)
+	c.emit(node, OP_DUP)
+	c.emit(node, OP_CONSTANT, c.constant(node, values.Null{}))
+	c.emit(node, OP_NOT_EQUAL)
+	endJump := c.emitJump(node, OP_JUMP_IF_FALSE)
+	c.emit(node.Property, OP_GET_PROPERTY, c.constant(node, values.NewStr(node.Property.Name)))
+	c.patch(node, endJump, len(c.function.Code))
+}
+
+func (c *Compiler) resolve(node ast.IdentifierExpr) (int, int) {
+	depth, slot, ok := c.scope.resolve(node.Name)
+	if !ok {
+		c.compileError(node, fmt.Sprintf("'%s' was not declared in this scope", node.Name))
+	}
+	return depth, slot
+}
+
+// compileAssignment compiles an assignment to an identifier, an array or map element, or a
+// struct property. Compound operators like += first apply the operator to the current value.
+func (c *Compiler) compileAssignment(node ast.VarAssignmentExpr) {
+	op, isCompound := compoundOpcodes[node.Operator.Kind]
+
+	switch t := node.Assignee.(type) {
+	case ast.IdentifierExpr:
+		depth, slot := c.resolve(t)
+		if isCompound {
+			c.emit(t, OP_GET_VAR, depth, slot)
+		}
+		c.compileExpr(node.Value)
+		if isCompound {
+			c.emit(node, op)
+		}
+		c.emit(node, OP_SET_VAR, depth, slot)
+	case ast.Indexable:
+		c.compileExpr(t.Container)
+		c.compileExpr(t.Index)
+		if isCompound {
+			c.emit(t, OP_DUP2)
+			c.emit(t.Index, OP_INDEX)
+		}
+		c.compileExpr(node.Value)
+		if isCompound {
+			c.emit(node, op)
+		}
+		c.emit(t, OP_SET_INDEX)
+	case ast.StructPropertyAccessExpr:
+		name := c.constant(t, values.NewStr(t.Property.Name))
+		c.compileExpr(t.Object)
+		if isCompound {
+			c.emit(t, OP_DUP)
+			c.emit(t.Property, OP_GET_PROPERTY, name)
+		}
+		c.compileExpr(node.Value)
+		if isCompound {
+			c.emit(node, op)
+		}
+		c.emit(t, OP_SET_PROPERTY, name)
+	default:
+		c.compileError(node.Assignee, "invalid assignment target")
+	}
+}
+
+// compileIncrementalExpr compiles ++ and -- in prefix and postfix form.
+// The prefix form evaluates to the updated value and the postfix form to the old one.
+func (c *Compiler) compileIncrementalExpr(node ast.IncrementalInterface) {
+	arg := node.Arg()
+	depth, slot := c.resolve(arg)
+	_, isPrefix := node.(ast.PrefixExpr)
+
+	c.emit(arg, OP_GET_VAR, depth, slot)
+	if !isPrefix {
+		c.emit(arg, OP_DUP)
+	}
+	c.emit(arg, OP_CONSTANT, c.constant(arg, values.NewInt(1, 32, true)))
+	if node.Op().Kind == lexer.PLUS_PLUS_TOKEN {
+		c.emit(arg, OP_ADD)
+	} else {
+		c.emit(arg, OP_SUB)
+	}
+	c.emit(arg, OP_SET_VAR, depth, slot)
+	if !isPrefix {
+		c.emit(arg, OP_POP)
+	}
+}
+
+// compileTypeCast compiles the 'as' operator. Numbers are converted between sizes, structs
+// are copied into the target struct type and everything else is unchanged.
+func (c *Compiler) compileTypeCast(node ast.TypeCastExpr) {
+	c.compileExpr(node.Expression)
+
+	def, name := c.types.Underlying(node.ToCast)
+	switch t := def.(type) {
+	case ast.IntegerType:
+		signed := 0
+		if t.IsSigned {
+			signed = 1
+		}
+		c.emit(node, OP_CAST_INT, int(t.BitSize), signed)
+	case ast.FloatType:
+		c.emit(node, OP_CAST_FLOAT, int(t.BitSize))
+	case ast.StructType:
+		if name != "" {
+			name = c.types.StructName(name)
+		}
+		fields := make([]string, len(t.Properties))
+		for i, prop := range t.Properties {
+			fields[i] = prop.Prop.Name
+		}
+		c.emit(node, OP_CAST_STRUCT, c.layout(name, fields))
+	}
+}
+
+// compileStructLiteral compiles a struct literal. Named structs keep the field order of their
+// declaration and missing fields get their zero value; anonymous structs keep the order of the literal.
+func (c *Compiler) compileStructLiteral(node ast.StructLiteral) {
+	if node.Identifier.Name == "" {
+		fields := make([]string, len(node.Properties))
+		for i, prop := range node.Properties {
+			fields[i] = prop.Prop.Name
+			c.compileExpr(prop.Value)
+		}
+		c.emit(node, OP_STRUCT, c.layout("", fields))
+		return
+	}
+
+	def, _ := c.types.Underlying(ast.UserDefinedType{AliasName: node.Identifier.Name, Location: node.Identifier.Location})
+	structType, ok := def.(ast.StructType)
+	if !ok {
+		c.compileError(node.Identifier, fmt.Sprintf("'%s' is not a struct", node.Identifier.Name))
+		return
+	}
+
+	values := make(map[string]ast.Node, len(node.Properties))
+	for _, prop := range node.Properties {
+		values[prop.Prop.Name] = prop.Value
+	}
+
+	fields := make([]string, len(structType.Properties))
+	for i, prop := range structType.Properties {
+		fields[i] = prop.Prop.Name
+		if value, ok := values[prop.Prop.Name]; ok {
+			c.compileExpr(value)
+		} else {
+			c.compileZeroValue(prop.PropType, node)
+		}
+	}
+	c.emit(node, OP_STRUCT, c.layout(c.types.StructName(node.Identifier.Name), fields))
+}
+
+func (c *Compiler) compileMapLiteral(node ast.MapLiteral) {
+	def, _ := c.types.Underlying(node.MapType)
+	mapType, ok := def.(ast.MapType)
+	if !ok {
+		c.compileError(node, fmt.Sprintf("'%s' is not a map", node.MapType.Map.Name))
+		return
+	}
+
+	for _, prop := range node.Values {
+		c.compileExpr(prop.Key)
+		c.compileExpr(prop.Value)
+	}
+	keyType := c.constant(node, values.NewStr(c.types.Name(mapType.KeyType)))
+	valueType := c.constant(node, values.NewStr(c.types.Name(mapType.ValueType)))
+	c.emit(node, OP_MAP, keyType, valueType, len(node.Values))
+}
@@ -0,0 +1,198 @@
+package bytecode
+
+import (
+	//Standard packages
+	"fmt"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/values"
+)
+
+// compileMatchStmt compiles the arms of a match statement, tried in order. The matched value
+// lives in a hidden slot of the match scope, and every arm has its own scope holding the
+// fields bound by its patterns. The patterns of an arm share its block.
+func (c *Compiler) compileMatchStmt(node ast.MatchStmt) {
+	enter := c.emit(node, OP_ENTER_SCOPE, 0)
+	c.enterScope()
+
+	value := c.scope.declare("$match")
+	c.compileExpr(node.Value)
+	c.emit(node.Value, OP_DEFINE_VAR, value)
+
+	var endJumps []int
+	for _, arm := range node.Arms {
+		armEnter := c.emit(arm.Block, OP_ENTER_SCOPE, 0)
+		c.enterScope()
+
+		var blockJumps []int
+		for _, pattern := range arm.Patterns {
+			c.compilePatternTest(pattern, value)
+			nextJump := c.emitJump(pattern, OP_JUMP_IF_FALSE)
+			c.compilePatternBindings(pattern, value)
+			blockJumps = append(blockJumps, c.emitJump(pattern, OP_JUMP))
+			c.patch(pattern, nextJump, len(c.function.Code))
+		}
+		c.emit(arm.Block, OP_EXIT_SCOPE)
+		nextArm := c.emitJump(arm.Block, OP_JUMP)
+
+		for _, jump := range blockJumps {
+			c.patch(arm.Block, jump, len(c.function.Code))
+		}
+		c.compileBlock(arm.Block)
+		c.emit(arm.Block, OP_EXIT_SCOPE)
+		endJumps = append(endJumps, c.emitJump(arm.Block, OP_JUMP))
+
+		c.patch(arm.Block, armEnter+1, c.scope.size)
+		c.exitScope()
+		c.patch(arm.Block, nextArm, len(c.function.Code))
+	}
+
+	for _, jump := range endJumps {
+		c.patch(node, jump, len(c.function.Code))
+	}
+	c.emit(node, OP_EXIT_SCOPE)
+	c.patch(node, enter+1, c.scope.size)
+	c.exitScope()
+}
+
+// compilePatternTest leaves whether the matched value, in the given slot of the match scope,
+// matches the pattern. '_' matches every value, a struct type name matches the structs of
+// that type and a range matches the numbers from its start up to its end, which is left out.
+// A variant with fields matches the values of that variant, 'ok(name)' and 'err(name)' match
+// the values and the errors of a result. Other patterns match equal values.
+func (c *Compiler) compilePatternTest(pattern ast.Node, slot int) {
+	switch t := pattern.(type) {
+	case ast.ResultExpr:
+		c.emit(t, OP_GET_VAR, 1, slot)
+		c.emit(t, OP_IS_ERR)
+		if !t.IsErr {
+			c.emit(t, OP_NOT)
+		}
+		return
+	case ast.FunctionCallExpr:
+		if access, ok := t.Caller.(ast.StructPropertyAccessExpr); ok {
+			if _, enumName, ok := c.enumOf(access.Object); ok {
+				c.emit(t, OP_GET_VAR, 1, slot)
+				c.emit(t, OP_IS_VARIANT, c.constant(t, values.NewStr(enumName)), c.constant(t, values.NewStr(access.Property.Name)))
+				return
+			}
+		}
+	case ast.IdentifierExpr:
+		if t.Name == "_" {
+			c.emit(t, OP_CONSTANT, c.constant(t, values.NewBool(true)))
+			return
+		}
+		if _, ok := c.types[t.Name]; ok {
+			c.emit(t, OP_GET_VAR, 1, slot)
+			c.emit(t, OP_IS_STRUCT, c.constant(t, values.NewStr(c.types.StructName(t.Name))))
+			return
+		}
+	case ast.RangeExpr:
+		c.emit(t, OP_GET_VAR, 1, slot)
+		c.compileExpr(t.Start)
+		c.emit(t, OP_GREATER_EQUAL)
+		falseJump := c.emitJump(t, OP_JUMP_IF_FALSE)
+		c.emit(t, OP_GET_VAR, 1, slot)
+		c.compileExpr(t.End)
+		c.emit(t, OP_LESS)
+		endJump := c.emitJump(t, OP_JUMP)
+		c.patch(t, falseJump, len(c.function.Code))
+		c.emit(t, OP_CONSTANT, c.constant(t, values.NewBool(false)))
+		c.patch(t, endJump, len(c.function.Code))
+		return
+	}
+	c.emit(pattern, OP_GET_VAR, 1, slot)
+	c.compileExpr(pattern)
+	c.emit(pattern, OP_EQUAL)
+}
+
+// compilePatternBindings declares the names a matching pattern binds in the scope of its arm:
+// the value or the error of a result, or the fields of a variant.
+func (c *Compiler) compilePatternBindings(pattern ast.Node, slot int) {
+	switch t := pattern.(type) {
+	case ast.ResultExpr:
+		ident, ok := t.Value.(ast.IdentifierExpr)
+		if !ok || ident.Name == "_" {
+			return
+		}
+		c.emit(t, OP_GET_VAR, 1, slot)
+		if t.IsErr {
+			c.emit(t, OP_ERR_VALUE)
+		}
+		c.emit(ident, OP_DEFINE_VAR, c.scope.declare(ident.Name))
+	case ast.FunctionCallExpr:
+		access, ok := t.Caller.(ast.StructPropertyAccessExpr)
+		if !ok {
+			return
+		}
+		if _, _, ok := c.enumOf(access.Object); !ok {
+			return
+		}
+		for i, arg := range t.Arguments {
+			if ident, ok := arg.(ast.IdentifierExpr); ok && ident.Name != "_" {
+				c.emit(ident, OP_GET_VAR, 1, slot)
+				c.emit(ident, OP_VARIANT_FIELD, i)
+				c.emit(ident, OP_DEFINE_VAR, c.scope.declare(ident.Name))
+			}
+		}
+	}
+}
+
+// enumOf returns the enum named by the object of a property access, like 'Shape' in 'Shape.Circle',
+// with the name the enum was declared with.
+func (c *Compiler) enumOf(object ast.Node) (ast.EnumType, string, bool) {
+	ident, ok := object.(ast.IdentifierExpr)
+	if !ok {
+		return ast.EnumType{}, "", false
+	}
+	if _, ok := c.types[ident.Name]; !ok {
+		return ast.EnumType{}, "", false
+	}
+	def, name := c.types.Underlying(ast.UserDefinedType{AliasName: ident.Name, Location: ident.Location})
+	enumType, ok := def.(ast.EnumType)
+	return enumType, name, ok
+}
+
+// compileVariant compiles a variant of an enum. A variant without fields is a value, a
+// variant with fields is a function creating one, compiled once per variant.
+func (c *Compiler) compileVariant(node ast.StructPropertyAccessExpr, enumType ast.EnumType, enumName string) {
+	for _, variant := range enumType.Variants {
+		if variant.Name.Name != node.Property.Name {
+			continue
+		}
+		enumConstant := c.constant(node, values.NewStr(enumName))
+		nameConstant := c.constant(node, values.NewStr(variant.Name.Name))
+		if len(variant.Fields) == 0 {
+			c.emit(node, OP_VARIANT, enumConstant, nameConstant, 0)
+			return
+		}
+
+		key := enumName + "." + variant.Name.Name
+		index, ok := c.variants[key]
+		if !ok {
+			index = c.compileVariantFunction(variant, key, enumConstant, nameConstant)
+			c.variants[key] = index
+		}
+		c.emit(node, OP_CLOSURE, index)
+		return
+	}
+	c.compileError(node.Property, fmt.Sprintf("'%s' is not a variant of enum '%s'", node.Property.Name, enumName))
+}
+
+// compileVariantFunction compiles the function creating a variant from the values of its fields.
+func (c *Compiler) compileVariantFunction(variant ast.EnumVariant, name string, enumConstant, nameConstant int) int {
+	fn := &Function{Name: name, Arity: len(variant.Fields), Locals: len(variant.Fields)}
+	c.program.Functions = append(c.program.Functions, fn)
+
+	enclosing := c.function
+	c.function = fn
+	for i := range variant.Fields {
+		c.emit(variant.Name, OP_GET_VAR, 0, i)
+	}
+	c.emit(variant.Name, OP_VARIANT, enumConstant, nameConstant, len(variant.Fields))
+	c.emit(variant.Name, OP_RETURN)
+	c.function = enclosing
+
+	return len(c.program.Functions) - 1
+}
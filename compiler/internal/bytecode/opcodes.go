@@ -0,0 +1,142 @@
+package bytecode
+
+// Opcode is a single vm instruction. Operands follow the opcode in the code as
+// big endian uint16 values; the number of operands of each opcode is fixed.
+type Opcode byte
+
+const (
+	OP_CONSTANT Opcode = iota // constant index
+	OP_VOID
+	OP_POP
+	OP_DUP
+	OP_DUP2
+	OP_GET_VAR     // scope depth, slot
+	OP_SET_VAR     // scope depth, slot
+	OP_DEFINE_VAR  // slot
+	OP_ENTER_SCOPE // slot count
+	OP_EXIT_SCOPE
+	OP_ADD
+	OP_SUB
+	OP_MUL
+	OP_DIV
+	OP_MOD
+	OP_EXP
+	OP_BIT_AND
+	OP_BIT_OR
+	OP_BIT_XOR
+	OP_SHIFT_LEFT
+	OP_SHIFT_RIGHT
+	OP_EQUAL
+	OP_NOT_EQUAL
+	OP_LESS
+	OP_LESS_EQUAL
+	OP_GREATER
+	OP_GREATER_EQUAL
+	OP_NEGATE
+	OP_NOT
+	OP_BIT_NOT
+	OP_CAST_INT    // bit size, signed (0 or 1)
+	OP_CAST_FLOAT  // bit size
+	OP_CAST_STRUCT // layout index
+	OP_TYPEOF
+	OP_RANGE
+	OP_ARRAY         // element type constant, element count
+	OP_MAP           // key type constant, value type constant, entry count
+	OP_STRUCT        // layout index
+	OP_TUPLE         // element count
+	OP_VARIANT       // enum name constant, variant name constant, field count
+	OP_IS_VARIANT    // enum name constant, variant name constant
+	OP_VARIANT_FIELD // field index
+	OP_IS_STRUCT     // struct name constant
+	OP_INDEX
+	OP_SET_INDEX
+	OP_GET_PROPERTY // name constant
+	OP_SET_PROPERTY // name constant
+	OP_CLOSURE      // function index
+	OP_CALL         // argument count
+	OP_RETURN
+	OP_JUMP          // target offset
+	OP_JUMP_IF_FALSE // target offset
+	OP_ITER
+	OP_ITER_NEXT // target offset
+	OP_ERR
+	OP_IS_ERR
+	OP_ERR_VALUE
+)
+
+type opcodeInfo struct {
+	name     string
+	operands int
+}
+
+var opcodes = map[Opcode]opcodeInfo{
+	OP_CONSTANT:      {"CONSTANT", 1},
+	OP_VOID:          {"VOID", 0},
+	OP_POP:           {"POP", 0},
+	OP_DUP:           {"DUP", 0},
+	OP_DUP2:          {"DUP2", 0},
+	OP_GET_VAR:       {"GET_VAR", 2},
+	OP_SET_VAR:       {"SET_VAR", 2},
+	OP_DEFINE_VAR:    {"DEFINE_VAR", 1},
+	OP_ENTER_SCOPE:   {"ENTER_SCOPE", 1},
+	OP_EXIT_SCOPE:    {"EXIT_SCOPE", 0},
+	OP_ADD:           {"ADD", 0},
+	OP_SUB:           {"SUB", 0},
+	OP_MUL:           {"MUL", 0},
+	OP_DIV:           {"DIV", 0},
+	OP_MOD:           {"MOD", 0},
+	OP_EXP:           {"EXP", 0},
+	OP_BIT_AND:       {"BIT_AND", 0},
+	OP_BIT_OR:        {"BIT_OR", 0},
+	OP_BIT_XOR:       {"BIT_XOR", 0},
+	OP_SHIFT_LEFT:    {"SHIFT_LEFT", 0},
+	OP_SHIFT_RIGHT:   {"SHIFT_RIGHT", 0},
+	OP_EQUAL:         {"EQUAL", 0},
+	OP_NOT_EQUAL:     {"NOT_EQUAL", 0},
+	OP_LESS:          {"LESS", 0},
+	OP_LESS_EQUAL:    {"LESS_EQUAL", 0},
+	OP_GREATER:       {"GREATER", 0},
+	OP_GREATER_EQUAL: {"GREATER_EQUAL", 0},
+	OP_NEGATE:        {"NEGATE", 0},
+	OP_NOT:           {"NOT", 0},
+	OP_BIT_NOT:       {"BIT_NOT", 0},
+	OP_CAST_INT:      {"CAST_INT", 2},
+	OP_CAST_FLOAT:    {"CAST_FLOAT", 1},
+	OP_CAST_STRUCT:   {"CAST_STRUCT", 1},
+	OP_TYPEOF:        {"TYPEOF", 0},
+	OP_RANGE:         {"RANGE", 0},
+	OP_ARRAY:         {"ARRAY", 2},
+	OP_MAP:           {"MAP", 3},
+	OP_STRUCT:        {"STRUCT", 1},
+	OP_TUPLE:         {"TUPLE", 1},
+	OP_VARIANT:       {"VARIANT", 3},
+	OP_IS_VARIANT:    {"IS_VARIANT", 2},
+	OP_VARIANT_FIELD: {"VARIANT_FIELD", 1},
+	OP_IS_STRUCT:     {"IS_STRUCT", 1},
+	OP_INDEX:         {"INDEX", 0},
+	OP_SET_INDEX:     {"SET_INDEX", 0},
+	OP_GET_PROPERTY:  {"GET_PROPERTY", 1},
+	OP_SET_PROPERTY:  {"SET_PROPERTY", 1},
+	OP_CLOSURE:       {"CLOSURE", 1},
+	OP_CALL:          {"CALL", 1},
+	OP_RETURN:        {"RETURN", 0},
+	OP_JUMP:          {"JUMP", 1},
+	OP_JUMP_IF_FALSE: {"JUMP_IF_FALSE", 1},
+	OP_ITER:          {"ITER", 0},
+	OP_ITER_NEXT:     {"ITER_NEXT", 1},
+	OP_ERR:           {"ERR", 0},
+	OP_IS_ERR:        {"IS_ERR", 0},
+	OP_ERR_VALUE:     {"ERR_VALUE", 0},
+}
+
+func (op Opcode) String() string {
+	if info, ok := opcodes[op]; ok {
+		return info.name
+	}
+	return "UNKNOWN"
+}
+
+// Operands returns the number of uint16 operands that follow the opcode.
+func (op Opcode) Operands() int {
+	return opcodes[op].operands
+}
@@ -0,0 +1,107 @@
+package bytecode
+
+import (
+	//Standard packages
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/internal/values"
+)
+
+// Globals are the builtin values the vm declares in the first slots of the program scope,
+// in this order.
+var Globals = []string{"true", "false", "PI", "print"}
+
+// Span is the source location of an instruction, used to report runtime errors.
+type Span struct {
+	LineStart int
+	LineEnd   int
+	ColStart  int
+	ColEnd    int
+}
+
+// SpanEntry marks the span of the instructions starting at Offset, up to the next entry.
+type SpanEntry struct {
+	Offset int
+	Span
+}
+
+// Function is a compiled function body. Locals is the number of slots of its scope.
+type Function struct {
+	Name   string
+	Arity  int
+	Locals int
+	Code   []byte
+	Spans  []SpanEntry
+}
+
+// SpanAt returns the source location of the instruction at the given offset.
+func (fn *Function) SpanAt(offset int) Span {
+	var span Span
+	for _, entry := range fn.Spans {
+		if entry.Offset > offset {
+			break
+		}
+		span = entry.Span
+	}
+	return span
+}
+
+// ReadOperand reads the uint16 operand at the given offset.
+func (fn *Function) ReadOperand(offset int) int {
+	return int(binary.BigEndian.Uint16(fn.Code[offset:]))
+}
+
+// StructLayout describes the fields of the structs created by OP_STRUCT and OP_CAST_STRUCT.
+// Anonymous structs have an empty name.
+type StructLayout struct {
+	Name   string
+	Fields []string
+}
+
+// MethodTable holds the methods implemented for a struct. When a method is called, its scope
+// holds 'this' in slot 0 followed by the methods in the order of Names.
+type MethodTable struct {
+	Struct    string
+	Names     []string
+	Functions []int
+}
+
+// Program is a compiled walrus program. The first function is the program body. Source is
+// the file the program was compiled from, its runtime errors are reported against it.
+type Program struct {
+	Source    string
+	Constants []values.Value
+	Functions []*Function
+	Layouts   []StructLayout
+	Methods   []MethodTable
+}
+
+// Main returns the function holding the top level statements of the program.
+func (p *Program) Main() *Function {
+	return p.Functions[0]
+}
+
+// String disassembles the program into a readable listing.
+func (p *Program) String() string {
+	var sb strings.Builder
+	for i, fn := range p.Functions {
+		fmt.Fprintf(&sb, "fn #%d %s (arity %d, locals %d)\n", i, fn.Name, fn.Arity, fn.Locals)
+		for offset := 0; offset < len(fn.Code); {
+			op := Opcode(fn.Code[offset])
+			fmt.Fprintf(&sb, "  %04d %-14s", offset, op)
+			offset++
+			for j := 0; j < op.Operands(); j++ {
+				fmt.Fprintf(&sb, " %d", fn.ReadOperand(offset))
+				offset += 2
+			}
+			if op == OP_CONSTANT {
+				fmt.Fprintf(&sb, " (%s)", p.Constants[fn.ReadOperand(offset-2)].String())
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
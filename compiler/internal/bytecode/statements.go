@@ -0,0 +1,358 @@
+package bytecode
+
+import (
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/values"
+)
+
+func (c *Compiler) compileStmt(node ast.Node) {
+	switch t := node.(type) {
+	case ast.ProgramStmt:
+		for _, item := range t.Contents {
+			c.compileStmt(item)
+		}
+	case ast.VarDeclStmt:
+		c.compileVarDecl(t)
+	case ast.TypeDeclStmt:
+		c.types[t.UDTypeName.Name] = t.UDTypeValue
+	case ast.ImplStmt:
+		c.compileImplStmt(t)
+	case ast.FunctionDeclStmt:
+		// declare the name first so the function can call itself
+		slot := c.scope.declare(t.Identifier.Name)
+		index := c.compileFunction(t.Identifier.Name, t.FunctionLiteral, nil)
+		c.emit(t, OP_CLOSURE, index)
+		c.emit(t, OP_DEFINE_VAR, slot)
+	case ast.IfStmt:
+		c.compileIfStmt(t)
+	case ast.ForStmt:
+		c.compileForStmt(t)
+	case ast.ForEachStmt:
+		c.compileForEachStmt(t)
+	case ast.WhileStmt:
+		c.compileWhileStmt(t)
+	case ast.MatchStmt:
+		c.compileMatchStmt(t)
+	case ast.SafeStmt:
+		c.compileSafeStmt(t)
+	case ast.ReturnStmt:
+		if t.Value == nil {
+			c.emit(t, OP_VOID)
+		} else {
+			c.compileExpr(t.Value)
+		}
+		c.emit(t, OP_RETURN)
+	case ast.BreakStmt:
+		c.compileLoopJump(t, t.Label.Name, false)
+	case ast.ContinueStmt:
+		c.compileLoopJump(t, t.Label.Name, true)
+	default:
+		c.compileExpr(node)
+		c.emit(node, OP_POP)
+	}
+}
+
+// compileVarDecl compiles every variable of a let/const statement. A variable declared
+// with only a type starts with the zero value of that type.
+func (c *Compiler) compileVarDecl(node ast.VarDeclStmt) {
+	for _, variable := range node.Variables {
+		if variable.Value != nil {
+			c.compileExpr(variable.Value)
+		} else {
+			c.compileZeroValue(variable.ExplicitType, variable.Identifier)
+		}
+		if variable.Destructure != nil {
+			c.compileDestructuring(*variable.Destructure, node)
+			continue
+		}
+		slot := c.scope.declare(variable.Identifier.Name)
+		c.emit(variable.Identifier, OP_DEFINE_VAR, slot)
+	}
+}
+
+// compileDestructuring defines the names the value on top of the stack is unpacked into,
+// by position for a tuple or by field names for a struct, then pops the value.
+func (c *Compiler) compileDestructuring(pattern ast.Destructuring, node ast.Node) {
+	for i, name := range pattern.Names {
+		if name.Name == "_" {
+			continue
+		}
+		c.emit(name, OP_DUP)
+		if pattern.IsStruct {
+			c.emit(name, OP_GET_PROPERTY, c.constant(name, values.NewStr(name.Name)))
+		} else {
+			c.emit(name, OP_CONSTANT, c.constant(name, values.NewInt(int64(i), 32, true)))
+			c.emit(name, OP_INDEX)
+		}
+		slot := c.scope.declare(name.Name)
+		c.emit(name, OP_DEFINE_VAR, slot)
+	}
+	c.emit(node, OP_POP)
+}
+
+// compileImplStmt compiles the methods of a struct. Their scope is nested in a receiver
+// scope holding 'this' and the other methods of the struct.
+func (c *Compiler) compileImplStmt(node ast.ImplStmt) {
+	name := c.types.StructName(node.ImplFor.Name)
+	index, ok := c.methods[name]
+	if !ok {
+		c.compileError(node, "implement statement must be at global scope")
+		return
+	}
+	table := &c.program.Methods[index]
+
+	receiver := newScope(c.scope)
+	receiver.declare("this")
+	for _, method := range table.Names {
+		receiver.declare(method)
+	}
+
+	for _, method := range node.Methods {
+		fnIndex := c.compileFunction(method.Identifier.Name, method.FunctionLiteral, receiver)
+		// methods are stored in the order they were declared, so fill the first free entry
+		for i, methodName := range table.Names {
+			if methodName == method.Identifier.Name && table.Functions[i] == -1 {
+				table.Functions[i] = fnIndex
+				break
+			}
+		}
+	}
+}
+
+// compileIfStmt compiles the branches of an if statement. Like the typechecker,
+// the branches share the scope of the if statement.
+func (c *Compiler) compileIfStmt(node ast.IfStmt) {
+	c.compileExpr(node.Condition)
+	elseJump := c.emitJump(node, OP_JUMP_IF_FALSE)
+
+	c.compileBlock(node.Block)
+	endJump := c.emitJump(node, OP_JUMP)
+
+	c.patch(node, elseJump, len(c.function.Code))
+	switch t := node.AlternateBlock.(type) {
+	case ast.IfStmt:
+		c.compileIfStmt(t)
+	case ast.BlockStmt:
+		c.compileBlock(t)
+	}
+	c.patch(node, endJump, len(c.function.Code))
+}
+
+// compileSafeStmt compiles a safe statement like an if statement checking that the
+// variable is not null. The variable is not narrowed at runtime, it holds the value itself.
+func (c *Compiler) compileSafeStmt(node ast.SafeStmt) {
+	c.compileExpr(node.Value)
+	c.emit(node, OP_CONSTANT, c.constant(node, values.Null{}))
+	c.emit(node, OP_NOT_EQUAL)
+	otherwiseJump := c.emitJump(node, OP_JUMP_IF_FALSE)
+
+	c.compileBlock(node.SafeBlock)
+	endJump := c.emitJump(node, OP_JUMP)
+
+	c.patch(node, otherwiseJump, len(c.function.Code))
+	c.compileBlock(node.UnsafeBlock)
+	c.patch(node, endJump, len(c.function.Code))
+}
+
+// compileForStmt compiles a loop in its own scope. A loop without a condition runs until it returns.
+func (c *Compiler) compileForStmt(node ast.ForStmt) {
+	enter := c.emit(node, OP_ENTER_SCOPE, 0)
+	c.enterScope()
+
+	if node.Init != nil {
+		c.compileStmt(node.Init)
+	}
+
+	loopStart := len(c.function.Code)
+	exitJump := -1
+	if node.Condition != nil {
+		c.compileExpr(node.Condition)
+		exitJump = c.emitJump(node.Condition, OP_JUMP_IF_FALSE)
+	}
+
+	current := &loop{label: node.Label.Name, scope: c.scope}
+	c.loops = append(c.loops, current)
+	c.compileBlock(node.Block)
+	c.loops = c.loops[:len(c.loops)-1]
+
+	for _, jump := range current.continues {
+		c.patch(node, jump, len(c.function.Code))
+	}
+	if node.Increment != nil {
+		c.compileExpr(node.Increment)
+		c.emit(node.Increment, OP_POP)
+	}
+	c.emit(node, OP_JUMP, loopStart)
+
+	if exitJump != -1 {
+		c.patch(node, exitJump, len(c.function.Code))
+	}
+	for _, jump := range current.breaks {
+		c.patch(node, jump, len(c.function.Code))
+	}
+	c.emit(node, OP_EXIT_SCOPE)
+
+	c.patch(node, enter+1, c.scope.size)
+	c.exitScope()
+}
+
+// compileWhileStmt compiles a 'while' loop, or a 'do-while' loop, whose block runs before
+// the condition is checked, in its own scope like a for loop.
+func (c *Compiler) compileWhileStmt(node ast.WhileStmt) {
+	enter := c.emit(node, OP_ENTER_SCOPE, 0)
+	c.enterScope()
+
+	current := &loop{label: node.Label.Name, scope: c.scope}
+	c.loops = append(c.loops, current)
+
+	loopStart := len(c.function.Code)
+	exitJump := -1
+	if !node.IsDo {
+		c.compileExpr(node.Condition)
+		exitJump = c.emitJump(node.Condition, OP_JUMP_IF_FALSE)
+	}
+
+	c.compileBlock(node.Block)
+	c.loops = c.loops[:len(c.loops)-1]
+
+	if node.IsDo {
+		// 'continue' checks the condition, which jumps back to the block while it holds
+		for _, jump := range current.continues {
+			c.patch(node, jump, len(c.function.Code))
+		}
+		c.compileExpr(node.Condition)
+		c.emit(node.Condition, OP_NOT)
+		c.emit(node.Condition, OP_JUMP_IF_FALSE, loopStart)
+	} else {
+		for _, jump := range current.continues {
+			c.patch(node, jump, loopStart)
+		}
+		c.emit(node, OP_JUMP, loopStart)
+		c.patch(node, exitJump, len(c.function.Code))
+	}
+
+	for _, jump := range current.breaks {
+		c.patch(node, jump, len(c.function.Code))
+	}
+	c.emit(node, OP_EXIT_SCOPE)
+
+	c.patch(node, enter+1, c.scope.size)
+	c.exitScope()
+}
+
+// compileForEachStmt compiles a loop over the elements of an array, the entries of a map or
+// the numbers of a range. The iterator lives in a hidden slot of the loop scope, so 'break'
+// and 'continue' leave nothing on the stack, and every iteration has its own scope holding
+// the loop variables.
+func (c *Compiler) compileForEachStmt(node ast.ForEachStmt) {
+	enter := c.emit(node, OP_ENTER_SCOPE, 0)
+	c.enterScope()
+
+	iterator := c.scope.declare("$iterator")
+	c.compileExpr(node.Iterable)
+	c.emit(node.Iterable, OP_ITER)
+	c.emit(node.Iterable, OP_DEFINE_VAR, iterator)
+
+	loopStart := c.emit(node, OP_GET_VAR, 0, iterator)
+	exitJump := c.emitJump(node, OP_ITER_NEXT)
+
+	iteration := c.emit(node, OP_ENTER_SCOPE, 0)
+	c.enterScope()
+	value := node.Value.(ast.IdentifierExpr)
+	c.emit(value, OP_DEFINE_VAR, c.scope.declare(value.Name))
+	if key, ok := node.Key.(ast.IdentifierExpr); ok {
+		c.emit(key, OP_DEFINE_VAR, c.scope.declare(key.Name))
+	} else {
+		c.emit(node, OP_POP)
+	}
+
+	current := &loop{label: node.Label.Name, scope: c.scope}
+	c.loops = append(c.loops, current)
+	c.compileBlock(node.Block)
+	c.loops = c.loops[:len(c.loops)-1]
+
+	for _, jump := range current.continues {
+		c.patch(node, jump, len(c.function.Code))
+	}
+	c.emit(node, OP_EXIT_SCOPE)
+	c.emit(node, OP_JUMP, loopStart)
+
+	for _, jump := range current.breaks {
+		c.patch(node, jump, len(c.function.Code))
+	}
+	c.emit(node, OP_EXIT_SCOPE)
+	c.patch(node, iteration+1, c.scope.size)
+	c.exitScope()
+
+	c.patch(node, exitJump, len(c.function.Code))
+	c.emit(node, OP_EXIT_SCOPE)
+	c.patch(node, enter+1, c.scope.size)
+	c.exitScope()
+}
+
+// compileLoopJump compiles a 'break' or a 'continue' to a jump to the end of the loop, or to
+// its increment. The scopes of the loops nested in it are left first.
+func (c *Compiler) compileLoopJump(node ast.Node, label string, isContinue bool) {
+	var target *loop
+	for i := len(c.loops) - 1; i >= 0; i-- {
+		if label == "" || c.loops[i].label == label {
+			target = c.loops[i]
+			break
+		}
+	}
+	if target == nil {
+		c.compileError(node, "'break' and 'continue' must be inside a loop")
+		return
+	}
+
+	for s := c.scope; s != target.scope; s = s.parent {
+		c.emit(node, OP_EXIT_SCOPE)
+	}
+
+	jump := c.emitJump(node, OP_JUMP)
+	if isContinue {
+		target.continues = append(target.continues, jump)
+	} else {
+		target.breaks = append(target.breaks, jump)
+	}
+}
+
+// compileZeroValue emits the instructions creating the zero value of a type.
+func (c *Compiler) compileZeroValue(dtype ast.DataType, node ast.Node) {
+	def, name := c.types.Underlying(dtype)
+	switch t := def.(type) {
+	case ast.IntegerType, ast.FloatType, ast.StringType, ast.BooleanType:
+		c.emit(node, OP_CONSTANT, c.constant(node, zeroConstant(t)))
+	case ast.ArrayType:
+		c.emit(node, OP_ARRAY, c.constant(node, values.NewStr(c.types.Name(t.ArrayType))), 0)
+	case ast.MapType:
+		c.emit(node, OP_MAP, c.constant(node, values.NewStr(c.types.Name(t.KeyType))), c.constant(node, values.NewStr(c.types.Name(t.ValueType))), 0)
+	case ast.RangeType:
+		c.compileZeroValue(t.RangeStart, node)
+		c.compileZeroValue(t.RangeEnd, node)
+		c.emit(node, OP_RANGE)
+	case ast.MaybeType:
+		c.emit(node, OP_CONSTANT, c.constant(node, values.Null{}))
+	case ast.ResultType:
+		c.compileZeroValue(t.OkType, node)
+	case ast.TupleType:
+		for _, element := range t.Elements {
+			c.compileZeroValue(element, node)
+		}
+		c.emit(node, OP_TUPLE, len(t.Elements))
+	case ast.StructType:
+		if name != "" {
+			name = c.types.StructName(name)
+		}
+		fields := make([]string, len(t.Properties))
+		for i, prop := range t.Properties {
+			fields[i] = prop.Prop.Name
+			c.compileZeroValue(prop.PropType, node)
+		}
+		c.emit(node, OP_STRUCT, c.layout(name, fields))
+	default:
+		// functions and interfaces have no value until one is assigned
+		c.emit(node, OP_VOID)
+	}
+}
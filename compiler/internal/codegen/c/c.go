@@ -0,0 +1,316 @@
+package c
+
+import (
+	//Standard packages
+	_ "embed"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/builtins"
+	"walrus/compiler/internal/codegen"
+	"walrus/compiler/internal/modules"
+	"walrus/compiler/internal/typechecker"
+	"walrus/compiler/report"
+)
+
+// Runtime is the header every generated file includes. It is written next to the generated source.
+//
+//go:embed walrus.h
+var Runtime []byte
+
+// frame holds the local variables of a generated function. Closures keep a pointer to the
+// frame of the function they are declared in, so a frame whose function creates closures
+// is allocated on the heap and every other frame lives on the stack.
+type frame struct {
+	id      int
+	parent  *frame // frame of the enclosing function, nil for top level functions and methods
+	fields  strings.Builder
+	names   map[string]int
+	escapes bool
+}
+
+type symbolKind int
+
+const (
+	variable symbolKind = iota
+	function            // top level function, called directly
+	method              // method of the struct whose method is being generated
+	builtin
+)
+
+// symbol is the C counterpart of a walrus name.
+type symbol struct {
+	kind  symbolKind
+	cname string
+	owner *frame // frame holding a variable, nil for the variables of the program
+}
+
+// scope tracks the types of the variables and where they are stored.
+type scope struct {
+	*codegen.Scope
+	parent  *scope
+	symbols map[string]symbol
+}
+
+func newScope(parent *scope) *scope {
+	var types *codegen.Scope
+	if parent != nil {
+		types = parent.Scope
+	}
+	return &scope{Scope: codegen.NewScope(types), parent: parent, symbols: make(map[string]symbol)}
+}
+
+func (s *scope) declare(name string, dtype ast.DataType, sym symbol) {
+	s.Scope.Declare(name, dtype)
+	s.symbols[name] = sym
+}
+
+func (s *scope) lookup(name string) (symbol, bool) {
+	for current := s; current != nil; current = current.parent {
+		if sym, ok := current.symbols[name]; ok {
+			return sym, true
+		}
+	}
+	return symbol{}, false
+}
+
+// Generator lowers a type checked program into a C file. Every walrus function becomes a
+// C function taking the environment it captured as its first parameter, variables of the
+// program become C globals and every other statement runs in 'main'.
+type Generator struct {
+	filePath    string
+	info        *typechecker.TypeInfo
+	declared    *codegen.Declarations
+	builtins    *scope
+	receiver    string         // name of the struct whose method is being generated
+	frame       *frame         // frame of the function being generated, nil at the top level
+	returnType  ast.DataType   // return type of the function being generated
+	labels      map[string]int // label of a loop around the statement -> number of its C labels
+	count       int
+	generated   map[string]string // type name -> generated typedef, formatter, vtable or cast
+	globalNames map[string]int
+
+	typedefs, types, frames, protos, globals, data, funcs strings.Builder
+}
+
+// Generate returns the C source of a type checked program, from its modules in the order they
+// are linked and the type info of each of them. The source includes "walrus.h", see Runtime.
+func Generate(program []modules.Module, infos []*typechecker.TypeInfo) ([]byte, error) {
+	g := &Generator{
+		declared:    codegen.NewDeclarations(),
+		builtins:    newScope(nil),
+		generated:   make(map[string]string),
+		labels:      make(map[string]int),
+		globalNames: make(map[string]int),
+	}
+
+	g.builtins.declare("true", codegen.BoolType(), symbol{kind: builtin, cname: "true"})
+	g.builtins.declare("false", codegen.BoolType(), symbol{kind: builtin, cname: "false"})
+	g.builtins.declare("PI", codegen.FloatType(32), symbol{kind: builtin, cname: "((float)3.14159265358979323846)"})
+	g.builtins.declare("print", ast.FunctionType{
+		TypeName:   builtins.FUNCTION,
+		Parameters: []ast.FunctionTypeParam{{Identifier: ast.IdentifierExpr{Name: "value"}, Type: codegen.StrType()}},
+		ReturnType: codegen.VoidType(),
+	}, symbol{kind: builtin, cname: "wl_print"})
+
+	global := newScope(g.builtins)
+
+	// types, methods and functions are visible to the whole program
+	g.each(program, infos, func(node ast.Node) {
+		switch t := node.(type) {
+		case ast.TypeDeclStmt:
+			switch t.UDTypeValue.(type) {
+			case ast.GenericType:
+				g.unsupported(t, "generic types cannot be generated yet")
+			case ast.EnumType:
+				g.unsupported(t, "enum types cannot be generated yet")
+			}
+			g.declared.DeclareType(t)
+			if _, ok := t.UDTypeValue.(ast.StructType); ok {
+				fmt.Fprintf(&g.typedefs, "typedef struct w_%s w_%s;\n", t.UDTypeName.Name, t.UDTypeName.Name)
+			}
+		case ast.ImplStmt:
+			g.declared.DeclareMethods(t)
+		case ast.FunctionDeclStmt:
+			global.declare(t.Identifier.Name, codegen.FunctionTypeOf(t.FunctionLiteral), symbol{kind: function, cname: "fn_" + t.Identifier.Name})
+		}
+	})
+
+	var main strings.Builder
+	g.each(program, infos, func(node ast.Node) {
+		switch t := node.(type) {
+		case ast.TypeDeclStmt:
+			g.typeDecl(t)
+		case ast.ImplStmt, ast.FunctionDeclStmt:
+			// generated once every global variable is declared
+		default:
+			g.statement(node, global, &main)
+		}
+	})
+
+	g.each(program, infos, func(node ast.Node) {
+		switch t := node.(type) {
+		case ast.ImplStmt:
+			g.implStmt(t, global)
+		case ast.FunctionDeclStmt:
+			g.function("fn_"+t.Identifier.Name, t.FunctionLiteral, global, nil)
+		}
+	})
+
+	return g.source(program[len(program)-1].FilePath, &main), nil
+}
+
+// each calls fn for the top level statements of every module but its imports, with the type
+// info of the module.
+func (g *Generator) each(program []modules.Module, infos []*typechecker.TypeInfo, fn func(node ast.Node)) {
+	for i, module := range program {
+		g.filePath, g.info = module.FilePath, infos[i]
+		for _, node := range module.Tree.(ast.ProgramStmt).Contents {
+			if _, ok := node.(ast.ImportStmt); !ok {
+				fn(node)
+			}
+		}
+	}
+}
+
+// typeOf returns the type the typechecker resolved for an expression, or nil.
+func (g *Generator) typeOf(node ast.Node) ast.DataType {
+	dtype, _ := g.info.DataTypeOf(node)
+	return dtype
+}
+
+// source assembles the file generated from the entry file of the program.
+func (g *Generator) source(entry string, main *strings.Builder) []byte {
+	var file strings.Builder
+	fmt.Fprintf(&file, "/* Code generated by walrus from %s. DO NOT EDIT. */\n\n#include \"walrus.h\"\n\n", filepath.Base(entry))
+	for _, section := range []*strings.Builder{&g.typedefs, &g.types, &g.frames, &g.protos, &g.globals, &g.data} {
+		if section.Len() > 0 {
+			file.WriteString(section.String())
+			file.WriteString("\n")
+		}
+	}
+	file.WriteString(g.funcs.String())
+	fmt.Fprintf(&file, "int main(void) {\n%sreturn 0;\n}\n", main.String())
+	return []byte(indent(file.String()))
+}
+
+// indent indents the generated lines by the braces they open and close. Braces inside
+// string and character literals are skipped.
+func indent(source string) string {
+	var out strings.Builder
+	depth := 0
+	for _, line := range strings.Split(source, "\n") {
+		line = strings.TrimSpace(line)
+		level := depth
+		if strings.HasPrefix(line, "}") {
+			level--
+		}
+		if line != "" {
+			out.WriteString(strings.Repeat("    ", max(level, 0)))
+		}
+		out.WriteString(line + "\n")
+
+		quote := byte(0)
+		for i := 0; i < len(line); i++ {
+			switch ch := line[i]; {
+			case quote != 0 && ch == '\\':
+				i++
+			case quote != 0:
+				if ch == quote {
+					quote = 0
+				}
+			case ch == '"' || ch == '\'':
+				quote = ch
+			case ch == '{':
+				depth++
+			case ch == '}':
+				depth--
+			}
+		}
+	}
+	return strings.TrimSuffix(out.String(), "\n")
+}
+
+// unsupported reports a node the C backend cannot generate.
+func (g *Generator) unsupported(node ast.Node, msg string) {
+	report.Add(g.filePath, node.StartPos().Line, node.EndPos().Line, node.StartPos().Column, node.EndPos().Column, msg).SetLevel(report.CRITICAL_ERROR)
+}
+
+// next returns a number for a generated name.
+func (g *Generator) next() int {
+	g.count++
+	return g.count
+}
+
+// declareVariable declares a variable in the frame of the function being generated, or as
+// a C global at the top level. Shadowed names get a numbered C name.
+func (g *Generator) declareVariable(name string, dtype ast.DataType, s *scope) symbol {
+	names := g.globalNames
+	if g.frame != nil {
+		names = g.frame.names
+	}
+	names[name]++
+	cname := "w_" + name
+	if n := names[name]; n > 1 {
+		cname += "_" + strconv.Itoa(n)
+	}
+
+	if g.frame == nil {
+		fmt.Fprintf(&g.globals, "static %s;\n", g.declaration(dtype, cname))
+	} else {
+		fmt.Fprintf(&g.frame.fields, "%s;\n", g.declaration(dtype, cname))
+	}
+	sym := symbol{kind: variable, cname: cname, owner: g.frame}
+	s.declare(name, dtype, sym)
+	return sym
+}
+
+// access returns the C expression of a variable. Variables of enclosing functions are
+// reached through the frames the closures captured.
+func (g *Generator) access(sym symbol) string {
+	if sym.owner == nil {
+		return sym.cname
+	}
+	path := "f"
+	for current := g.frame; current != nil && current != sym.owner; current = current.parent {
+		path += "->up"
+	}
+	return path + "->" + sym.cname
+}
+
+// typeDecl generates the C struct of a named struct type. Other named types are used
+// through the types they name.
+func (g *Generator) typeDecl(node ast.TypeDeclStmt) {
+	g.declared.DeclareType(node)
+	if t, ok := node.UDTypeValue.(ast.StructType); ok {
+		g.structDef("w_"+node.UDTypeName.Name, t)
+	}
+}
+
+// implStmt generates the methods of a struct. A method receives the struct as its
+// environment, inside it 'this' and the other methods of the struct are in scope.
+func (g *Generator) implStmt(node ast.ImplStmt, s *scope) {
+	structName := g.declared.Types.StructName(node.ImplFor.Name)
+
+	receiver := newScope(s)
+	for _, m := range g.declared.Methods[structName] {
+		receiver.declare(m.Name, m.Type, symbol{kind: method, cname: methodName(structName, m.Name)})
+	}
+
+	previous := g.receiver
+	g.receiver = structName
+	defer func() { g.receiver = previous }()
+
+	this := ast.UserDefinedType{TypeName: builtins.USER_DEFINED, AliasName: structName, Location: node.ImplFor.Location}
+	for _, method := range node.Methods {
+		g.function(methodName(structName, method.Identifier.Name), method.FunctionLiteral, receiver, this)
+	}
+}
+
+func methodName(structName, name string) string {
+	return "m_" + structName + "_" + name
+}
@@ -0,0 +1,656 @@
+package c
+
+import (
+	//Standard packages
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/builtins"
+	"walrus/compiler/internal/codegen"
+	"walrus/compiler/internal/lexer"
+	"walrus/compiler/internal/values"
+)
+
+func (g *Generator) expr(node ast.Node, s *scope) string {
+	if value, ok := g.info.ConstantOf(node); ok {
+		return g.literal(value)
+	}
+
+	switch t := node.(type) {
+	case ast.IdentifierExpr:
+		return g.identifier(t, s)
+	case ast.IntegerLiteralExpr, ast.FloatLiteralExpr:
+		g.unsupported(t, "invalid numeric literal")
+		return ""
+	case ast.StringLiteralExpr:
+		return cString(t.Value)
+	case ast.InterpolatedStringExpr:
+		return g.interpolation(t, s)
+	case ast.ByteLiteralExpr:
+		return strconv.Itoa(int(t.Value[0]))
+	case ast.BinaryExpr:
+		return g.binary(t.Binop, t.Left, t.Right, s)
+	case ast.UnaryExpr:
+		return g.unary(t, s)
+	case ast.PrefixExpr:
+		return g.increment(t, true, s)
+	case ast.PostfixExpr:
+		return g.increment(t, false, s)
+	case ast.VarAssignmentExpr:
+		return g.assignment(t, s)
+	case ast.TypeCastExpr:
+		return g.convert(g.expr(t.Expression, s), g.typeOf(t.Expression), t.ToCast)
+	case ast.ResultExpr, ast.PropagateExpr:
+		g.unsupported(t, "results cannot be generated yet")
+		return ""
+	case ast.TupleLiteral:
+		g.unsupported(t, "tuples cannot be generated yet")
+		return ""
+	case ast.TypeofExpr:
+		return cString(g.declared.Types.Name(g.typeOf(t.Expression)))
+	case ast.RangeExpr:
+		return fmt.Sprintf("((%s){%s, %s})", g.ctype(g.typeOf(t)), g.expr(t.Start, s), g.expr(t.End, s))
+	case ast.ArrayLiteral:
+		return g.arrayLiteral(t, g.typeOf(t), s)
+	case ast.Indexable:
+		return g.index(t, s)
+	case ast.StructLiteral:
+		return g.structLiteral(t, s)
+	case ast.StructPropertyAccessExpr:
+		return g.property(t, s)
+	case ast.MapLiteral:
+		return g.mapLiteral(t, s)
+	case ast.FunctionLiteral:
+		return g.closure(t, "", s)
+	case ast.FunctionCallExpr:
+		return g.call(t, s)
+	default:
+		g.unsupported(node, fmt.Sprintf("<%T> node cannot be generated yet", node))
+		return ""
+	}
+}
+
+// exprAs generates an expression whose value is stored in a place of the given type.
+// Numbers are converted, structs stored in interfaces become interface values and empty
+// arrays take the element type of the place.
+func (g *Generator) exprAs(node ast.Node, dtype ast.DataType, s *scope) string {
+	if array, ok := node.(ast.ArrayLiteral); ok {
+		return g.arrayLiteral(array, dtype, s)
+	}
+	from := g.typeOf(node)
+	code := g.expr(node, s)
+	if g.isNumber(from) && g.isNumber(dtype) && g.ctype(from) != g.ctype(dtype) {
+		return g.convert(code, from, dtype)
+	}
+	if _, ok := g.underlying(dtype).(ast.InterfaceType); ok {
+		return g.convert(code, from, dtype)
+	}
+	return code
+}
+
+// literal generates a folded constant. Integers that do not fit in a C int are written
+// as their bits converted to their type, so they wrap around like in walrus.
+func (g *Generator) literal(value values.Value) string {
+	switch v := value.(type) {
+	case values.Int:
+		if v.Value.Sign() >= 0 && v.Value.Cmp(big.NewInt(math.MaxInt32)) <= 0 {
+			return v.Value.String()
+		}
+		ctype := g.ctype(codegen.IntType(v.BitSize, v.IsSigned))
+		bits := new(big.Int).Mod(v.Value, new(big.Int).Lsh(big.NewInt(1), 128))
+		low := new(big.Int).And(bits, new(big.Int).SetUint64(math.MaxUint64))
+		if v.BitSize <= 64 {
+			return fmt.Sprintf("((%s)UINT64_C(%s))", ctype, low)
+		}
+		return fmt.Sprintf("((%s)(((wl_u128)UINT64_C(%s) << 64) | UINT64_C(%s)))", ctype, new(big.Int).Rsh(bits, 64), low)
+	case values.Float:
+		ctype := g.ctype(codegen.FloatType(v.BitSize))
+		switch {
+		case math.IsNaN(v.Value):
+			return fmt.Sprintf("((%s)NAN)", ctype)
+		case math.IsInf(v.Value, 1):
+			return fmt.Sprintf("((%s)INFINITY)", ctype)
+		case math.IsInf(v.Value, -1):
+			return fmt.Sprintf("((%s)-INFINITY)", ctype)
+		}
+		text := strconv.FormatFloat(v.Value, 'g', -1, 64)
+		if !strings.ContainsAny(text, ".e") {
+			text += ".0"
+		}
+		if v.BitSize == 32 {
+			return fmt.Sprintf("((float)%s)", text)
+		}
+		return operand(text)
+	case values.Str:
+		return cString(v.Value)
+	default:
+		return value.String()
+	}
+}
+
+// identifier resolves a name. Functions and methods used as values become function values.
+func (g *Generator) identifier(node ast.IdentifierExpr, s *scope) string {
+	sym, ok := s.lookup(node.Name)
+	if !ok {
+		g.unsupported(node, fmt.Sprintf("'%s' is not declared", node.Name))
+		return ""
+	}
+	switch sym.kind {
+	case builtin:
+		if node.Name == "print" {
+			return "wl_fn_new((void *)wl_print_fn, NULL)"
+		}
+		return sym.cname
+	case function:
+		return fmt.Sprintf("wl_fn_new((void *)%s, NULL)", sym.cname)
+	case method:
+		return fmt.Sprintf("wl_fn_new((void *)%s, %s)", sym.cname, g.this(s))
+	}
+	return g.access(sym)
+}
+
+// this returns the struct whose method is being generated.
+func (g *Generator) this(s *scope) string {
+	sym, _ := s.lookup("this")
+	return g.access(sym)
+}
+
+// interpolation generates a string like "a {x} b" as the concatenations "" + "a" + x + " b",
+// a string plus any value writes the value.
+func (g *Generator) interpolation(node ast.InterpolatedStringExpr, s *scope) string {
+	text := cString("")
+	for _, part := range node.Parts {
+		text = fmt.Sprintf("wl_concat(%s, %s)", text, g.format(g.expr(part, s), g.typeOf(part), false))
+	}
+	return text
+}
+
+// binary generates a binary operation. Like in walrus, the right operand is converted to
+// the type of the left operand and the result has the type of the left operand, but an
+// integer and a float give the float type.
+func (g *Generator) binary(op lexer.Token, leftNode, rightNode ast.Node, s *scope) string {
+	leftType := g.typeOf(leftNode)
+	rightType := g.typeOf(rightNode)
+	left := g.expr(leftNode, s)
+	right := g.expr(rightNode, s)
+
+	if _, ok := g.underlying(leftType).(ast.StringType); ok {
+		switch op.Kind {
+		case lexer.PLUS_TOKEN:
+			return fmt.Sprintf("wl_concat(%s, %s)", left, g.format(right, rightType, false))
+		case lexer.DOUBLE_EQUAL_TOKEN:
+			return fmt.Sprintf("wl_str_eq(%s, %s)", left, right)
+		case lexer.NOT_EQUAL_TOKEN:
+			return fmt.Sprintf("(!wl_str_eq(%s, %s))", left, right)
+		case lexer.LESS_TOKEN, lexer.LESS_EQUAL_TOKEN, lexer.GREATER_TOKEN, lexer.GREATER_EQUAL_TOKEN:
+			return fmt.Sprintf("(wl_str_cmp(%s, %s) %s 0)", left, right, op.Kind)
+		}
+	}
+
+	if op.Kind == lexer.SHIFT_LEFT_TOKEN || op.Kind == lexer.SHIFT_RIGHT_TOKEN {
+		return g.shift(op.Kind, leftType, rightType, left, right)
+	}
+
+	if g.isNumber(leftType) && !g.isFloat(leftType) && g.isFloat(rightType) && !g.isComparison(op) {
+		// an integer and a float give a float
+		left = g.convert(left, leftType, rightType)
+		leftType = rightType
+	}
+
+	if g.isNumber(leftType) && g.isNumber(rightType) && g.ctype(leftType) != g.ctype(rightType) {
+		if g.isComparison(op) && (g.isFloat(leftType) || g.isFloat(rightType)) {
+			left = "(double)" + operand(left)
+			right = "(double)" + operand(right)
+		} else {
+			right = g.convert(right, rightType, leftType)
+		}
+	}
+
+	if _, ok := g.underlying(leftType).(ast.InterfaceType); ok {
+		left, right = operand(left)+".obj", operand(right)+".obj"
+	}
+
+	if g.isNumber(leftType) && !g.isComparison(op) {
+		return g.arithmetic(op.Kind, leftType, left, right)
+	}
+	return fmt.Sprintf("(%s %s %s)", operand(left), op.Kind, operand(right))
+}
+
+// arithmetic applies an arithmetic operator to two values of a numeric type. Integers
+// are added, subtracted and multiplied as unsigned integers, which wrap around, and the
+// result is converted back to the type. Division checks for a zero divisor.
+func (g *Generator) arithmetic(kind builtins.TOKEN_KIND, dtype ast.DataType, left, right string) string {
+	ctype := g.ctype(dtype)
+	left, right = operand(left), operand(right)
+
+	if g.isFloat(dtype) {
+		// like the interpreter, these are computed in double precision
+		switch kind {
+		case lexer.MOD_TOKEN:
+			return fmt.Sprintf("((%s)fmod(%s, %s))", ctype, left, right)
+		case lexer.EXP_TOKEN:
+			return fmt.Sprintf("((%s)pow(%s, %s))", ctype, left, right)
+		}
+		return fmt.Sprintf("(%s %s %s)", left, kind, right)
+	}
+
+	t, _ := g.underlying(dtype).(ast.IntegerType)
+	wide := "uint32_t"
+	switch {
+	case t.BitSize > 64:
+		wide = "wl_u128"
+	case t.BitSize > 32:
+		wide = "uint64_t"
+	}
+	sign := "u"
+	if t.IsSigned {
+		sign = "s"
+	}
+
+	switch kind {
+	case lexer.DIV_TOKEN:
+		return fmt.Sprintf("((%s)wl_%sdiv(%s, %s))", ctype, sign, left, right)
+	case lexer.MOD_TOKEN:
+		return fmt.Sprintf("((%s)wl_%smod(%s, %s))", ctype, sign, left, right)
+	case lexer.EXP_TOKEN:
+		return fmt.Sprintf("((%s)wl_pow((wl_u128)%s, %s))", ctype, left, right)
+	}
+	return fmt.Sprintf("((%s)((%s)%s %s (%s)%s))", ctype, wide, left, kind, wide, right)
+}
+
+// shift generates << and >>. The count keeps its own type, may be as large as it likes
+// and must not be negative. Signed values shift right arithmetically.
+func (g *Generator) shift(kind builtins.TOKEN_KIND, dtype, countType ast.DataType, left, right string) string {
+	ctype := g.ctype(dtype)
+	count := fmt.Sprintf("wl_ucount((wl_u128)%s)", operand(right))
+	if t, _ := g.underlying(countType).(ast.IntegerType); t.IsSigned {
+		count = fmt.Sprintf("wl_scount((wl_i128)%s)", operand(right))
+	}
+	if kind == lexer.SHIFT_LEFT_TOKEN {
+		return fmt.Sprintf("((%s)wl_shl((wl_u128)%s, %s))", ctype, operand(left), count)
+	}
+	if t, _ := g.underlying(dtype).(ast.IntegerType); t.IsSigned {
+		return fmt.Sprintf("((%s)wl_sshr((wl_i128)%s, %s))", ctype, operand(left), count)
+	}
+	return fmt.Sprintf("((%s)wl_ushr((wl_u128)%s, %s))", ctype, operand(left), count)
+}
+
+func (g *Generator) unary(node ast.UnaryExpr, s *scope) string {
+	argument := operand(g.expr(node.Argument, s))
+	dtype := g.typeOf(node.Argument)
+	if node.Operator.Kind == lexer.MINUS_TOKEN {
+		if _, ok := g.underlying(dtype).(ast.IntegerType); ok {
+			return g.arithmetic(lexer.MINUS_TOKEN, dtype, "0", argument)
+		}
+	}
+	if node.Operator.Kind == lexer.BIT_NOT_TOKEN {
+		// the complement of a promoted integer has to be cut back to its type
+		return fmt.Sprintf("((%s)~%s)", g.ctype(dtype), argument)
+	}
+	return fmt.Sprintf("(%s%s)", node.Operator.Kind, argument)
+}
+
+// increment generates ++ and --. The postfix form returns the value the variable had.
+func (g *Generator) increment(node ast.IncrementalInterface, prefix bool, s *scope) string {
+	target := g.identifier(node.Arg(), s)
+	dtype := g.typeOf(node.Arg())
+	updated := fmt.Sprintf("(%s = %s)", target, g.arithmetic(builtins.TOKEN_KIND(string(node.Op().Kind)[:1]), dtype, target, "1"))
+	if prefix {
+		return updated
+	}
+	return fmt.Sprintf("({ %s = %s; %s; wl_old; })", g.declaration(dtype, "wl_old"), target, updated)
+}
+
+// assignment generates an assignment. Compound operators are generated as the binary
+// operation they apply.
+func (g *Generator) assignment(node ast.VarAssignmentExpr, s *scope) string {
+	dtype := g.typeOf(node.Assignee)
+
+	op := node.Operator
+	switch op.Kind {
+	case lexer.PLUS_EQUALS_TOKEN:
+		op.Kind = lexer.PLUS_TOKEN
+	case lexer.MINUS_EQUALS_TOKEN:
+		op.Kind = lexer.MINUS_TOKEN
+	case lexer.MUL_EQUALS_TOKEN:
+		op.Kind = lexer.MUL_TOKEN
+	case lexer.DIV_EQUALS_TOKEN:
+		op.Kind = lexer.DIV_TOKEN
+	case lexer.MOD_EQUALS_TOKEN:
+		op.Kind = lexer.MOD_TOKEN
+	case lexer.EXP_EQUALS_TOKEN:
+		op.Kind = lexer.EXP_TOKEN
+	case lexer.BIT_AND_EQUALS_TOKEN:
+		op.Kind = lexer.BIT_AND_TOKEN
+	case lexer.BIT_OR_EQUALS_TOKEN:
+		op.Kind = lexer.BIT_OR_TOKEN
+	case lexer.BIT_XOR_EQUALS_TOKEN:
+		op.Kind = lexer.BIT_XOR_TOKEN
+	case lexer.SHIFT_LEFT_EQUALS_TOKEN:
+		op.Kind = lexer.SHIFT_LEFT_TOKEN
+	case lexer.SHIFT_RIGHT_EQUALS_TOKEN:
+		op.Kind = lexer.SHIFT_RIGHT_TOKEN
+	}
+	var value string
+	if op.Kind == lexer.EQUALS_TOKEN {
+		value = g.exprAs(node.Value, dtype, s)
+	} else {
+		value = g.binary(op, node.Assignee, node.Value, s)
+	}
+
+	switch t := node.Assignee.(type) {
+	case ast.IdentifierExpr:
+		return fmt.Sprintf("(%s = %s)", g.identifier(t, s), value)
+	case ast.Indexable:
+		container := g.expr(t.Container, s)
+		if mapType, ok := g.underlying(g.typeOf(t.Container)).(ast.MapType); ok {
+			key, valueType := g.ctype(mapType.KeyType), g.ctype(mapType.ValueType)
+			return fmt.Sprintf("(*(%s *)wl_map_set(%s, &(%s){%s}, &(%s){%s}))", valueType, container, key, g.exprAs(t.Index, mapType.KeyType, s), valueType, value)
+		}
+		return fmt.Sprintf("(*(%s *)wl_array_at(%s, %s) = %s)", g.ctype(dtype), container, g.expr(t.Index, s), value)
+	case ast.StructPropertyAccessExpr:
+		return fmt.Sprintf("(%s = %s)", g.property(t, s), value)
+	default:
+		g.unsupported(node.Assignee, "invalid assignment target")
+		return ""
+	}
+}
+
+// convert converts a value between numeric types with the wrapping semantics of walrus,
+// copies structs into a struct of the target type and stores structs in interfaces.
+func (g *Generator) convert(code string, from, to ast.DataType) string {
+	fromType, toType := g.ctype(from), g.ctype(to)
+
+	switch target := g.underlying(to).(type) {
+	case ast.IntegerType:
+		if g.isFloat(from) {
+			return fmt.Sprintf("((%s)wl_ftoi(%s))", toType, code)
+		}
+	case ast.FloatType:
+	case ast.StructType:
+		source, ok := g.underlying(from).(ast.StructType)
+		if !ok || fromType == toType {
+			return code
+		}
+		return fmt.Sprintf("%s(%s)", g.structCast(from, source, to, target), code)
+	case ast.InterfaceType:
+		if _, ok := g.underlying(from).(ast.StructType); !ok {
+			return code
+		}
+		_, structName := g.declared.Types.Underlying(from)
+		vtable := g.vtable(g.declared.Types.StructName(structName), target, g.declared.Types.Name(to))
+		return fmt.Sprintf("((wl_iface){%s, %s, %s})", code, vtable, g.formatter(from))
+	default:
+		return code
+	}
+
+	if fromType == toType {
+		return code
+	}
+	return fmt.Sprintf("((%s)%s)", toType, operand(code))
+}
+
+// structCast returns the name of the function copying a struct into a struct of another
+// type with the same fields.
+func (g *Generator) structCast(from ast.DataType, source ast.StructType, to ast.DataType, target ast.StructType) string {
+	key := "cast " + g.declared.Types.Name(from) + " " + g.declared.Types.Name(to)
+	if cname, ok := g.generated[key]; ok {
+		return cname
+	}
+	cname := fmt.Sprintf("wl_cast_%d", g.next())
+	g.generated[key] = cname
+
+	values := make(map[string]string, len(target.Properties))
+	for _, prop := range target.Properties {
+		for _, field := range source.Properties {
+			if field.Prop.Name == prop.Prop.Name {
+				values[prop.Prop.Name] = "v->w_" + field.Prop.Name
+			}
+		}
+	}
+	header := fmt.Sprintf("static %s(%s)", g.declaration(to, cname), g.declaration(from, "v"))
+	fmt.Fprintf(&g.protos, "%s;\n", header)
+	fmt.Fprintf(&g.funcs, "%s {\nreturn %s;\n}\n\n", header, g.newStruct(to, target, values))
+	return cname
+}
+
+func (g *Generator) arrayLiteral(node ast.ArrayLiteral, dtype ast.DataType, s *scope) string {
+	array, ok := g.underlying(dtype).(ast.ArrayType)
+	if !ok {
+		array, _ = g.typeOf(node).(ast.ArrayType)
+	}
+	element := g.ctype(array.ArrayType)
+	if len(node.Values) == 0 {
+		return fmt.Sprintf("wl_array_new(sizeof(%s), 0, NULL)", element)
+	}
+	values := make([]string, len(node.Values))
+	for i, value := range node.Values {
+		values[i] = g.exprAs(value, array.ArrayType, s)
+	}
+	return fmt.Sprintf("wl_array_new(sizeof(%s), %d, (%s[]){%s})", element, len(values), element, strings.Join(values, ", "))
+}
+
+// index reads an array element, a byte of a string or a map value.
+func (g *Generator) index(node ast.Indexable, s *scope) string {
+	container := g.expr(node.Container, s)
+	switch t := g.underlying(g.typeOf(node.Container)).(type) {
+	case ast.MapType:
+		return fmt.Sprintf("(*(%s *)wl_map_get(%s, &(%s){%s}))", g.ctype(t.ValueType), container, g.ctype(t.KeyType), g.exprAs(node.Index, t.KeyType, s))
+	case ast.StringType:
+		return fmt.Sprintf("wl_str_at(%s, %s)", container, g.expr(node.Index, s))
+	case ast.ArrayType:
+		return fmt.Sprintf("(*(%s *)wl_array_at(%s, %s))", g.ctype(t.ArrayType), container, g.expr(node.Index, s))
+	default:
+		g.unsupported(node, "value cannot be indexed")
+		return ""
+	}
+}
+
+func (g *Generator) structLiteral(node ast.StructLiteral, s *scope) string {
+	dtype := g.typeOf(node)
+	structType, ok := g.underlying(dtype).(ast.StructType)
+	if !ok {
+		g.unsupported(node.Identifier, fmt.Sprintf("'%s' is not a struct", node.Identifier.Name))
+	}
+
+	values := make(map[string]string, len(node.Properties))
+	for _, prop := range node.Properties {
+		propType := g.typeOf(prop.Value)
+		if field, ok := g.declared.FindProperty(structType, prop.Prop.Name); ok {
+			propType = field.PropType
+		}
+		values[prop.Prop.Name] = g.exprAs(prop.Value, propType, s)
+	}
+	return g.newStruct(dtype, structType, values)
+}
+
+// property reads a field, or creates a function value for a method bound to its receiver.
+func (g *Generator) property(node ast.StructPropertyAccessExpr, s *scope) string {
+	if node.Optional {
+		g.unsupported(node, "optional chaining cannot be generated yet")
+	}
+	object := g.expr(node.Object, s)
+	objectType := g.typeOf(node.Object)
+
+	if _, ok := g.declared.FindProperty(objectType, node.Property.Name); ok {
+		return operand(object) + "->w_" + node.Property.Name
+	}
+	def, structName := g.declared.Types.Underlying(objectType)
+	switch t := def.(type) {
+	case ast.StructType:
+		if _, ok := g.declared.FindMethod(structName, node.Property.Name); ok {
+			return fmt.Sprintf("wl_fn_new((void *)%s, %s)", methodName(g.declared.Types.StructName(structName), node.Property.Name), object)
+		}
+	case ast.InterfaceType:
+		if i := methodIndex(t, node.Property.Name); i >= 0 {
+			return fmt.Sprintf("({ wl_iface wl_i = %s; wl_fn_new((void *)wl_i.methods[%d], wl_i.obj); })", object, i)
+		}
+	}
+	g.unsupported(node.Property, fmt.Sprintf("'%s' is not a property", node.Property.Name))
+	return ""
+}
+
+func methodIndex(t ast.InterfaceType, name string) int {
+	for i, method := range t.Methods {
+		if method.Identifier.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (g *Generator) mapLiteral(node ast.MapLiteral, s *scope) string {
+	mapType, _ := g.underlying(node.MapType).(ast.MapType)
+	key, value := g.ctype(mapType.KeyType), g.ctype(mapType.ValueType)
+	if len(node.Values) == 0 {
+		return g.zero(mapType)
+	}
+	keys := make([]string, len(node.Values))
+	values := make([]string, len(node.Values))
+	for i, entry := range node.Values {
+		keys[i] = g.exprAs(entry.Key, mapType.KeyType, s)
+		values[i] = g.exprAs(entry.Value, mapType.ValueType, s)
+	}
+	return fmt.Sprintf("wl_map_from(%s, sizeof(%s), sizeof(%s), %d, (%s[]){%s}, (%s[]){%s})", g.mapKind(mapType), key, value, len(keys), key, strings.Join(keys, ", "), value, strings.Join(values, ", "))
+}
+
+// call generates a function call. Top level functions and methods are called directly,
+// interface methods through the table of the interface value and every other function
+// through the function value. Arguments are converted to the types of the parameters.
+func (g *Generator) call(node ast.FunctionCallExpr, s *scope) string {
+	fnType, _ := g.underlying(g.typeOf(node.Caller)).(ast.FunctionType)
+
+	args := make([]string, len(node.Arguments))
+	for i, arg := range node.Arguments {
+		if i < len(fnType.Parameters) {
+			args[i] = g.exprAs(arg, fnType.Parameters[i].Type, s)
+		} else {
+			args[i] = g.expr(arg, s)
+		}
+	}
+	withEnv := func(env string) string {
+		return strings.Join(append([]string{env}, args...), ", ")
+	}
+
+	switch caller := node.Caller.(type) {
+	case ast.IdentifierExpr:
+		sym, _ := s.lookup(caller.Name)
+		switch sym.kind {
+		case builtin:
+			return fmt.Sprintf("%s(%s)", sym.cname, strings.Join(args, ", "))
+		case function:
+			return fmt.Sprintf("%s(%s)", sym.cname, withEnv("NULL"))
+		case method:
+			return fmt.Sprintf("%s(%s)", sym.cname, withEnv(g.this(s)))
+		}
+	case ast.StructPropertyAccessExpr:
+		objectType := g.typeOf(caller.Object)
+		if _, ok := g.declared.FindProperty(objectType, caller.Property.Name); ok {
+			break
+		}
+		def, structName := g.declared.Types.Underlying(objectType)
+		switch t := def.(type) {
+		case ast.StructType:
+			if _, ok := g.declared.FindMethod(structName, caller.Property.Name); ok {
+				return fmt.Sprintf("%s(%s)", methodName(g.declared.Types.StructName(structName), caller.Property.Name), withEnv(g.expr(caller.Object, s)))
+			}
+		case ast.InterfaceType:
+			if i := methodIndex(t, caller.Property.Name); i >= 0 {
+				return fmt.Sprintf("({ wl_iface wl_i = %s; ((%s)wl_i.methods[%d])(%s); })", g.expr(caller.Object, s), g.signature(fnType), i, withEnv("wl_i.obj"))
+			}
+		}
+	}
+
+	return fmt.Sprintf("({ wl_fn *wl_c = %s; ((%s)wl_c->code)(%s); })", g.expr(node.Caller, s), g.signature(fnType), withEnv("wl_c->env"))
+}
+
+var simple = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*(->[A-Za-z_][A-Za-z0-9_]*)*|[0-9.]+|"([^"\\]|\\.)*")$`)
+
+// operand parenthesizes an expression unless it is a name, a number, a string or
+// already enclosed in parentheses.
+func operand(code string) string {
+	if simple.MatchString(code) || enclosed(code) {
+		return code
+	}
+	return "(" + code + ")"
+}
+
+// enclosed reports whether the parenthesis opening an expression closes at its end.
+func enclosed(code string) bool {
+	if !strings.HasPrefix(code, "(") {
+		return false
+	}
+	depth := 0
+	quote := byte(0)
+	for i := 0; i < len(code); i++ {
+		switch ch := code[i]; {
+		case quote != 0 && ch == '\\':
+			i++
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+			if depth == 0 {
+				return i == len(code)-1
+			}
+		}
+	}
+	return false
+}
+
+// cString returns a C string literal. Bytes outside printable ASCII are escaped in octal.
+func cString(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(value); i++ {
+		switch ch := value[i]; {
+		case ch == '"' || ch == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(ch)
+		case ch >= 0x20 && ch < 0x7f && ch != '?':
+			b.WriteByte(ch)
+		default:
+			fmt.Fprintf(&b, "\\%03o", ch)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func (g *Generator) underlying(dtype ast.DataType) ast.DataType {
+	def, _ := g.declared.Types.Underlying(dtype)
+	return def
+}
+
+func (g *Generator) isNumber(dtype ast.DataType) bool {
+	switch g.underlying(dtype).(type) {
+	case ast.IntegerType, ast.FloatType:
+		return true
+	}
+	return false
+}
+
+func (g *Generator) isFloat(dtype ast.DataType) bool {
+	_, ok := g.underlying(dtype).(ast.FloatType)
+	return ok
+}
+
+func (g *Generator) isComparison(op lexer.Token) bool {
+	switch op.Kind {
+	case lexer.DOUBLE_EQUAL_TOKEN, lexer.NOT_EQUAL_TOKEN, lexer.LESS_TOKEN, lexer.LESS_EQUAL_TOKEN, lexer.GREATER_TOKEN, lexer.GREATER_EQUAL_TOKEN:
+		return true
+	}
+	return false
+}
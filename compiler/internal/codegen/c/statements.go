@@ -0,0 +1,258 @@
+package c
+
+import (
+	//Standard packages
+	"fmt"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/codegen"
+)
+
+func (g *Generator) statement(node ast.Node, s *scope, out *strings.Builder) {
+	switch t := node.(type) {
+	case ast.VarDeclStmt:
+		for _, variable := range t.Variables {
+			fmt.Fprintf(out, "%s;\n", g.varDecl(variable, s))
+		}
+	case ast.TypeDeclStmt:
+		g.unsupported(t, "types can only be declared at the top level of a program")
+	case ast.FunctionDeclStmt:
+		g.functionDecl(t, s, out)
+	case ast.IfStmt:
+		g.ifStmt(t, s, out)
+	case ast.ForStmt:
+		g.forStmt(t, s, out)
+	case ast.WhileStmt:
+		g.whileStmt(t, s, out)
+	case ast.ReturnStmt:
+		switch {
+		case g.frame == nil:
+			out.WriteString("return 0;\n")
+		case t.Value == nil:
+			out.WriteString("return;\n")
+		default:
+			fmt.Fprintf(out, "return %s;\n", g.exprAs(t.Value, g.returnType, s))
+		}
+	case ast.BreakStmt:
+		out.WriteString(g.loopJump("break", t.Label))
+	case ast.ContinueStmt:
+		out.WriteString(g.loopJump("continue", t.Label))
+	case ast.ImplStmt:
+		g.unsupported(t, "methods can only be implemented at the top level of a program")
+	case ast.ForEachStmt:
+		g.unsupported(t, "foreach loops cannot be generated yet")
+	case ast.MatchStmt:
+		g.unsupported(t, "match statements cannot be generated yet")
+	case ast.SafeStmt:
+		g.unsupported(t, "safe statements cannot be generated yet")
+	default:
+		fmt.Fprintf(out, "%s;\n", g.simpleStatement(t, s))
+	}
+}
+
+// simpleStatement generates an expression used as a statement. Values nobody uses are
+// discarded explicitly.
+func (g *Generator) simpleStatement(node ast.Node, s *scope) string {
+	switch t := node.(type) {
+	case ast.FunctionCallExpr, ast.VarAssignmentExpr, ast.PrefixExpr:
+		return g.expr(t, s)
+	case ast.PostfixExpr:
+		// the old value is not needed, so it is incremented like the prefix form
+		return g.increment(t, true, s)
+	default:
+		return "(void)" + operand(g.expr(node, s))
+	}
+}
+
+// varDecl declares a variable and returns the assignment of its initial value.
+func (g *Generator) varDecl(variable ast.VarDeclStmtVar, s *scope) string {
+	if variable.Destructure != nil {
+		g.unsupported(variable.Value, "destructuring cannot be generated yet")
+		return ""
+	}
+	dtype := variable.ExplicitType
+	if dtype == nil {
+		dtype = g.typeOf(variable.Value)
+	}
+	if dtype == nil {
+		g.unsupported(variable.Identifier, fmt.Sprintf("cannot infer the type of '%s'", variable.Identifier.Name))
+	}
+
+	value := ""
+	if variable.Value == nil {
+		value = g.zero(dtype)
+	} else {
+		value = g.exprAs(variable.Value, dtype, s)
+	}
+
+	sym := g.declareVariable(variable.Identifier.Name, dtype, s)
+	return g.access(sym) + " = " + value
+}
+
+// functionDecl declares a nested function as a variable holding a closure. The variable is
+// declared before the closure is created so the function can call itself.
+func (g *Generator) functionDecl(node ast.FunctionDeclStmt, s *scope, out *strings.Builder) {
+	sym := g.declareVariable(node.Identifier.Name, codegen.FunctionTypeOf(node.FunctionLiteral), s)
+	fmt.Fprintf(out, "%s = %s;\n", g.access(sym), g.closure(node.FunctionLiteral, node.Identifier.Name, s))
+}
+
+// closure lifts a function literal into a C function and creates a function value that
+// captures the frame of the function being generated.
+func (g *Generator) closure(node ast.FunctionLiteral, name string, s *scope) string {
+	cname := fmt.Sprintf("fn_%d", g.next())
+	if name != "" {
+		cname += "_" + name
+	}
+	g.function(cname, node, s, nil)
+	if g.frame == nil {
+		return fmt.Sprintf("wl_fn_new((void *)%s, NULL)", cname)
+	}
+	g.frame.escapes = true
+	return fmt.Sprintf("wl_fn_new((void *)%s, f)", cname)
+}
+
+// function generates a C function. Its parameters and local variables live in its frame,
+// which also points to the frame of the enclosing function. A method receives the struct
+// it is called on as its environment. A function that returns a value ends with a panic
+// when its last statement is not a return, which the typechecker guarantees is never reached.
+func (g *Generator) function(cname string, node ast.FunctionLiteral, s *scope, this ast.DataType) {
+	if len(node.TypeParams) > 0 {
+		g.unsupported(node, "generic functions cannot be generated yet")
+	}
+
+	fnType := codegen.FunctionTypeOf(node)
+
+	previousFrame, previousReturn, previousLabels := g.frame, g.returnType, g.labels
+	g.frame = &frame{id: g.next(), parent: g.frame, names: make(map[string]int)}
+	g.returnType = codegen.ReturnType(fnType)
+	g.labels = make(map[string]int)
+	defer func() { g.frame, g.returnType, g.labels = previousFrame, previousReturn, previousLabels }()
+	if this != nil {
+		g.frame.parent = nil
+	}
+
+	fnScope := newScope(s)
+	var body strings.Builder
+	if this != nil {
+		sym := g.declareVariable("this", this, fnScope)
+		fmt.Fprintf(&body, "%s = env;\n", g.access(sym))
+	}
+	params := []string{"void *env"}
+	for i, param := range node.Params {
+		sym := g.declareVariable(param.Identifier.Name, param.Type, fnScope)
+		arg := fmt.Sprintf("a%d", i)
+		params = append(params, g.declaration(param.Type, arg))
+		fmt.Fprintf(&body, "%s = %s;\n", g.access(sym), arg)
+	}
+
+	g.block(node.Body, fnScope, &body)
+	if !codegen.IsVoid(g.returnType) {
+		contents := node.Body.Contents
+		if len(contents) == 0 {
+			body.WriteString("wl_panic(\"missing return\");\n")
+		} else if _, ok := contents[len(contents)-1].(ast.ReturnStmt); !ok {
+			body.WriteString("wl_panic(\"missing return\");\n")
+		}
+	}
+
+	up := "void *up"
+	if g.frame.parent != nil {
+		up = fmt.Sprintf("struct frame_%d *up", g.frame.parent.id)
+	}
+	fmt.Fprintf(&g.frames, "struct frame_%d {\n%s;\n%s};\n\n", g.frame.id, up, g.frame.fields.String())
+
+	alloc := fmt.Sprintf("&(struct frame_%d){0}", g.frame.id)
+	if g.frame.escapes {
+		alloc = fmt.Sprintf("wl_alloc(sizeof(struct frame_%d), NULL)", g.frame.id)
+	}
+
+	header := fmt.Sprintf("static %s %s(%s)", g.ctype(g.returnType), cname, strings.Join(params, ", "))
+	fmt.Fprintf(&g.protos, "%s;\n", header)
+	fmt.Fprintf(&g.funcs, "%s {\nstruct frame_%d *f = %s;\nf->up = env;\n%s}\n\n", header, g.frame.id, alloc, body.String())
+}
+
+func (g *Generator) block(node ast.BlockStmt, s *scope, out *strings.Builder) {
+	for _, item := range node.Contents {
+		g.statement(item, s, out)
+	}
+}
+
+func (g *Generator) ifStmt(node ast.IfStmt, s *scope, out *strings.Builder) {
+	fmt.Fprintf(out, "if (%s) {\n", g.expr(node.Condition, s))
+	g.block(node.Block, newScope(s), out)
+	switch t := node.AlternateBlock.(type) {
+	case ast.IfStmt:
+		out.WriteString("} else ")
+		g.ifStmt(t, s, out)
+		return
+	case ast.BlockStmt:
+		out.WriteString("} else {\n")
+		g.block(t, newScope(s), out)
+	}
+	out.WriteString("}\n")
+}
+
+func (g *Generator) forStmt(node ast.ForStmt, s *scope, out *strings.Builder) {
+	loopScope := newScope(s)
+
+	init := ""
+	switch t := node.Init.(type) {
+	case nil:
+	case ast.VarDeclStmt:
+		assignments := make([]string, len(t.Variables))
+		for i, variable := range t.Variables {
+			assignments[i] = g.varDecl(variable, loopScope)
+		}
+		init = strings.Join(assignments, ", ")
+	default:
+		init = g.simpleStatement(t, loopScope)
+	}
+
+	condition := ""
+	if node.Condition != nil {
+		condition = g.expr(node.Condition, loopScope)
+	}
+
+	increment := ""
+	if node.Increment != nil {
+		increment = g.simpleStatement(node.Increment, loopScope)
+	}
+
+	g.loop(node.Label, fmt.Sprintf("for (%s; %s; %s) {", init, condition, increment), "}", node.Block, loopScope, out)
+}
+
+func (g *Generator) whileStmt(node ast.WhileStmt, s *scope, out *strings.Builder) {
+	condition := g.expr(node.Condition, s)
+	if node.IsDo {
+		g.loop(node.Label, "do {", fmt.Sprintf("} while (%s);", condition), node.Block, s, out)
+		return
+	}
+	g.loop(node.Label, fmt.Sprintf("while (%s) {", condition), "}", node.Block, s, out)
+}
+
+// loop writes a loop, the header before its block and the footer after it.
+func (g *Generator) loop(label ast.IdentifierExpr, header, footer string, block ast.BlockStmt, s *scope, out *strings.Builder) {
+	out.WriteString(header + "\n")
+	if label.Name == "" {
+		g.block(block, newScope(s), out)
+		out.WriteString(footer + "\n")
+		return
+	}
+
+	// C has no labeled loops, jumps to an outer loop go to the end of its body or after it
+	id := g.next()
+	g.labels[label.Name] = id
+	g.block(block, newScope(s), out)
+	delete(g.labels, label.Name)
+	fmt.Fprintf(out, "wl_continue_%d: ;\n%s\nwl_break_%d: ;\n", id, footer, id)
+}
+
+// loopJump generates a 'break' or a 'continue'. One with a label jumps to the labels of its loop.
+func (g *Generator) loopJump(keyword string, label ast.IdentifierExpr) string {
+	if label.Name == "" {
+		return keyword + ";\n"
+	}
+	return fmt.Sprintf("goto wl_%s_%d;\n", keyword, g.labels[label.Name])
+}
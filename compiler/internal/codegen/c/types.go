@@ -0,0 +1,265 @@
+package c
+
+import (
+	//Standard packages
+	"fmt"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+)
+
+// ctype returns the C type of a walrus type. Structs, arrays, maps and functions are
+// pointers because walrus shares them by reference.
+func (g *Generator) ctype(dtype ast.DataType) string {
+	def, structName := g.declared.Types.Underlying(dtype)
+	switch t := def.(type) {
+	case nil, ast.VoidType:
+		return "void"
+	case ast.IntegerType:
+		if t.BitSize > 64 {
+			if t.IsSigned {
+				return "wl_i128"
+			}
+			return "wl_u128"
+		}
+		if t.IsSigned {
+			return fmt.Sprintf("int%d_t", t.BitSize)
+		}
+		return fmt.Sprintf("uint%d_t", t.BitSize)
+	case ast.FloatType:
+		if t.BitSize == 32 {
+			return "float"
+		}
+		return "double"
+	case ast.StringType:
+		return "wl_str"
+	case ast.BooleanType:
+		return "bool"
+	case ast.ArrayType:
+		return "wl_array *"
+	case ast.MapType:
+		return "wl_map *"
+	case ast.StructType:
+		if structName != "" {
+			return "w_" + g.declared.Types.StructName(structName) + " *"
+		}
+		return g.anonymousStruct(t) + " *"
+	case ast.InterfaceType:
+		return "wl_iface"
+	case ast.FunctionType:
+		return "wl_fn *"
+	case ast.RangeType:
+		return g.rangeType(t)
+	default:
+		return "void *"
+	}
+}
+
+// declaration returns the declaration of a variable or a field of the given type.
+func (g *Generator) declaration(dtype ast.DataType, name string) string {
+	ctype := g.ctype(dtype)
+	if strings.HasSuffix(ctype, "*") {
+		return ctype + name
+	}
+	return ctype + " " + name
+}
+
+// structDef generates the definition of a struct. The fields of the struct are generated
+// first, so the types they use are declared before the struct.
+func (g *Generator) structDef(cname string, t ast.StructType) {
+	fields := make([]string, len(t.Properties))
+	for i, prop := range t.Properties {
+		fields[i] = g.declaration(prop.PropType, "w_"+prop.Prop.Name) + ";\n"
+	}
+	fmt.Fprintf(&g.types, "struct %s {\n%s};\n\n", cname, strings.Join(fields, ""))
+}
+
+// anonymousStruct returns the name of the struct generated for a struct type without a name.
+func (g *Generator) anonymousStruct(t ast.StructType) string {
+	key := "struct " + g.declared.Types.Name(t)
+	if cname, ok := g.generated[key]; ok {
+		return cname
+	}
+	cname := fmt.Sprintf("wl_struct_%d", g.next())
+	g.generated[key] = cname
+	fmt.Fprintf(&g.typedefs, "typedef struct %s %s;\n", cname, cname)
+	g.structDef(cname, t)
+	return cname
+}
+
+// rangeType returns the name of the struct generated for a range type. Ranges are values.
+func (g *Generator) rangeType(t ast.RangeType) string {
+	key := "range " + g.declared.Types.Name(t)
+	if cname, ok := g.generated[key]; ok {
+		return cname
+	}
+	cname := fmt.Sprintf("wl_range_%d", g.next())
+	g.generated[key] = cname
+	fmt.Fprintf(&g.types, "typedef struct {\n%s;\n%s;\n} %s;\n\n", g.declaration(t.RangeStart, "start"), g.declaration(t.RangeEnd, "end"), cname)
+	return cname
+}
+
+// structName returns the C name of the struct of a struct type, without the pointer.
+func (g *Generator) structName(dtype ast.DataType) string {
+	return strings.TrimSuffix(g.ctype(dtype), " *")
+}
+
+// signature returns the C type of a pointer to the code of a function value.
+func (g *Generator) signature(t ast.FunctionType) string {
+	params := []string{"void *"}
+	for _, param := range t.Parameters {
+		params = append(params, g.ctype(param.Type))
+	}
+	return fmt.Sprintf("%s (*)(%s)", g.ctype(t.ReturnType), strings.Join(params, ", "))
+}
+
+// mapKind returns the kind of the keys of a map, strings are hashed by their contents.
+func (g *Generator) mapKind(t ast.MapType) string {
+	if _, ok := g.underlying(t.KeyType).(ast.StringType); ok {
+		return "WL_KEY_STR"
+	}
+	return "WL_KEY_RAW"
+}
+
+// zero returns the value a variable of the type holds before it is assigned.
+func (g *Generator) zero(dtype ast.DataType) string {
+	switch t := g.underlying(dtype).(type) {
+	case ast.IntegerType, ast.FloatType:
+		return "0"
+	case ast.StringType:
+		return `""`
+	case ast.BooleanType:
+		return "false"
+	case ast.ArrayType:
+		return fmt.Sprintf("wl_array_new(sizeof(%s), 0, NULL)", g.ctype(t.ArrayType))
+	case ast.MapType:
+		return fmt.Sprintf("wl_map_new(%s, sizeof(%s), sizeof(%s))", g.mapKind(t), g.ctype(t.KeyType), g.ctype(t.ValueType))
+	case ast.StructType:
+		return g.newStruct(dtype, t, nil)
+	case ast.RangeType:
+		return fmt.Sprintf("((%s){%s, %s})", g.rangeType(t), g.zero(t.RangeStart), g.zero(t.RangeEnd))
+	case ast.InterfaceType:
+		return "((wl_iface){0})"
+	default:
+		return "NULL"
+	}
+}
+
+// newStruct allocates a struct. Fields without a value get the value they would have as variables.
+func (g *Generator) newStruct(dtype ast.DataType, t ast.StructType, values map[string]string) string {
+	cname := g.structName(dtype)
+	fields := make([]string, len(t.Properties))
+	for i, prop := range t.Properties {
+		value, ok := values[prop.Prop.Name]
+		if !ok {
+			value = g.zero(prop.PropType)
+		}
+		fields[i] = fmt.Sprintf(".w_%s = %s", prop.Prop.Name, value)
+	}
+	return fmt.Sprintf("((%s *)wl_alloc(sizeof(%s), &(%s){%s}))", cname, cname, cname, strings.Join(fields, ", "))
+}
+
+// format returns an expression formatting a value the way walrus prints it. Strings
+// inside arrays, maps and structs are quoted.
+func (g *Generator) format(code string, dtype ast.DataType, quoted bool) string {
+	switch t := g.underlying(dtype).(type) {
+	case ast.IntegerType:
+		switch {
+		case t.BitSize > 64 && t.IsSigned:
+			return "wl_fmt_i128(" + code + ")"
+		case t.BitSize > 64:
+			return "wl_fmt_u128(" + code + ")"
+		case t.IsSigned:
+			return "wl_fmt_i64(" + code + ")"
+		}
+		return "wl_fmt_u64(" + code + ")"
+	case ast.FloatType:
+		return fmt.Sprintf("wl_fmt_f%d(%s)", t.BitSize, code)
+	case ast.BooleanType:
+		return "wl_fmt_bool(" + code + ")"
+	case ast.StringType:
+		if quoted {
+			return "wl_quote(" + code + ")"
+		}
+		return code
+	case ast.InterfaceType:
+		return "wl_fmt_iface(" + code + ")"
+	case ast.ArrayType, ast.MapType, ast.StructType, ast.RangeType:
+		return g.formatter(dtype) + "(" + code + ")"
+	case ast.FunctionType:
+		return `"<fn>"`
+	default:
+		return `"void"`
+	}
+}
+
+// formatter returns the name of the function formatting values of a composite type.
+// Struct formatters take a pointer to any struct, so interface values can use them.
+func (g *Generator) formatter(dtype ast.DataType) string {
+	def, structName := g.declared.Types.Underlying(dtype)
+	key := "format " + g.declared.Types.Name(dtype)
+	if cname, ok := g.generated[key]; ok {
+		return cname
+	}
+	cname := fmt.Sprintf("wl_fmt_%d", g.next())
+	g.generated[key] = cname
+
+	param := g.declaration(dtype, "v")
+	if _, ok := def.(ast.StructType); ok {
+		param = "void *p"
+	}
+	fmt.Fprintf(&g.protos, "static wl_str %s(%s);\n", cname, param)
+
+	var body strings.Builder
+	body.WriteString("wl_buf b = {0};\n")
+	switch t := def.(type) {
+	case ast.ArrayType:
+		body.WriteString("wl_buf_add(&b, \"[\");\nfor (int64_t i = 0; i < v->len; i++) {\nif (i > 0) {\nwl_buf_add(&b, \", \");\n}\n")
+		fmt.Fprintf(&body, "wl_buf_add(&b, %s);\n}\nwl_buf_add(&b, \"]\");\n", g.format(fmt.Sprintf("*(%s *)wl_array_at(v, i)", g.ctype(t.ArrayType)), t.ArrayType, true))
+	case ast.MapType:
+		body.WriteString("wl_buf_add(&b, \"{\");\nfor (int64_t i = 0; i < v->len; i++) {\nif (i > 0) {\nwl_buf_add(&b, \", \");\n}\n")
+		fmt.Fprintf(&body, "wl_buf_add(&b, %s);\n", g.format(fmt.Sprintf("*(%s *)wl_map_key(v, i)", g.ctype(t.KeyType)), t.KeyType, true))
+		body.WriteString("wl_buf_add(&b, \" => \");\n")
+		fmt.Fprintf(&body, "wl_buf_add(&b, %s);\n}\nwl_buf_add(&b, \"}\");\n", g.format(fmt.Sprintf("*(%s *)wl_map_value(v, i)", g.ctype(t.ValueType)), t.ValueType, true))
+	case ast.StructType:
+		name := "@struct"
+		if structName != "" {
+			name = "@" + g.declared.Types.StructName(structName)
+		}
+		fmt.Fprintf(&body, "%s = p;\nwl_buf_add(&b, %s);\n", g.declaration(dtype, "v"), cString(name+"{"))
+		for i, prop := range t.Properties {
+			separator := ", "
+			if i == 0 {
+				separator = ""
+			}
+			fmt.Fprintf(&body, "wl_buf_add(&b, %s);\n", cString(separator+prop.Prop.Name+": "))
+			fmt.Fprintf(&body, "wl_buf_add(&b, %s);\n", g.format("v->w_"+prop.Prop.Name, prop.PropType, true))
+		}
+		body.WriteString("wl_buf_add(&b, \"}\");\n")
+	case ast.RangeType:
+		fmt.Fprintf(&body, "wl_buf_add(&b, %s);\nwl_buf_add(&b, \"..\");\nwl_buf_add(&b, %s);\n", g.format("v.start", t.RangeStart, false), g.format("v.end", t.RangeEnd, false))
+	}
+	fmt.Fprintf(&g.funcs, "static wl_str %s(%s) {\n%sreturn wl_buf_str(&b);\n}\n\n", cname, param, body.String())
+	return cname
+}
+
+// vtable returns the name of the table of the methods a struct implements for an interface.
+func (g *Generator) vtable(structName string, iface ast.InterfaceType, ifaceName string) string {
+	key := "vtable " + structName + " " + ifaceName + " " + g.declared.Types.Name(iface)
+	if cname, ok := g.generated[key]; ok {
+		return cname
+	}
+	cname := fmt.Sprintf("wl_vtable_%d", g.next())
+	g.generated[key] = cname
+
+	methods := make([]string, len(iface.Methods))
+	for i, m := range iface.Methods {
+		methods[i] = "(void *)" + methodName(structName, m.Identifier.Name)
+	}
+	if len(methods) == 0 {
+		methods = []string{"NULL"}
+	}
+	fmt.Fprintf(&g.data, "static void *const %s[] = {%s};\n", cname, strings.Join(methods, ", "))
+	return cname
+}
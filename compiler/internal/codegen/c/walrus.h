@@ -0,0 +1,521 @@
+/*
+ * Runtime of the C code generated by walrus: strings, growable arrays, insertion
+ * ordered hash maps, closures and value formatting. Memory is never freed.
+ * Requires a compiler with 128 bit integers and statement expressions (gcc, clang).
+ */
+#ifndef WALRUS_H
+#define WALRUS_H
+
+#include <inttypes.h>
+#include <math.h>
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+
+typedef const char *wl_str;
+typedef __int128 wl_i128;
+typedef unsigned __int128 wl_u128;
+
+__attribute__((noreturn)) static void wl_panic(const char *msg) {
+    fflush(stdout);
+    fprintf(stderr, "runtime error: %s\n", msg);
+    exit(1);
+}
+
+static void *wl_alloc(size_t size, const void *init) {
+    void *p = calloc(1, size ? size : 1);
+    if (!p) {
+        wl_panic("out of memory");
+    }
+    if (init) {
+        memcpy(p, init, size);
+    }
+    return p;
+}
+
+/* strings */
+
+typedef struct {
+    char *data;
+    size_t len, cap;
+} wl_buf;
+
+static void wl_buf_add(wl_buf *b, wl_str s) {
+    size_t n = strlen(s);
+    if (b->len + n + 1 > b->cap) {
+        b->cap = (b->len + n + 1) * 2;
+        b->data = realloc(b->data, b->cap);
+        if (!b->data) {
+            wl_panic("out of memory");
+        }
+    }
+    memcpy(b->data + b->len, s, n + 1);
+    b->len += n;
+}
+
+static wl_str wl_buf_str(wl_buf *b) {
+    return b->data ? b->data : "";
+}
+
+static wl_str wl_concat(wl_str a, wl_str b) {
+    wl_buf buf = {0};
+    wl_buf_add(&buf, a);
+    wl_buf_add(&buf, b);
+    return wl_buf_str(&buf);
+}
+
+static bool wl_str_eq(wl_str a, wl_str b) {
+    return strcmp(a, b) == 0;
+}
+
+static int wl_str_cmp(wl_str a, wl_str b) {
+    return strcmp(a, b);
+}
+
+static uint8_t wl_str_at(wl_str s, int64_t i) {
+    int64_t len = (int64_t)strlen(s);
+    if (i < 0 || i >= len) {
+        char msg[96];
+        snprintf(msg, sizeof msg, "index %" PRId64 " out of range with length %" PRId64, i, len);
+        wl_panic(msg);
+    }
+    return (uint8_t)s[i];
+}
+
+static void wl_print(wl_str s) {
+    puts(s);
+}
+
+/* wl_print_fn is 'print' used as a function value. */
+static void wl_print_fn(void *env, wl_str s) {
+    (void)env;
+    puts(s);
+}
+
+/* wl_quote formats a string in double quotes with escape sequences. */
+static wl_str wl_quote(wl_str s) {
+    wl_buf buf = {0};
+    wl_buf_add(&buf, "\"");
+    for (const unsigned char *p = (const unsigned char *)s; *p; p++) {
+        char esc[8] = {0};
+        switch (*p) {
+        case '"': strcpy(esc, "\\\""); break;
+        case '\\': strcpy(esc, "\\\\"); break;
+        case '\a': strcpy(esc, "\\a"); break;
+        case '\b': strcpy(esc, "\\b"); break;
+        case '\f': strcpy(esc, "\\f"); break;
+        case '\n': strcpy(esc, "\\n"); break;
+        case '\r': strcpy(esc, "\\r"); break;
+        case '\t': strcpy(esc, "\\t"); break;
+        case '\v': strcpy(esc, "\\v"); break;
+        default:
+            if (*p < 0x20 || *p == 0x7f) {
+                snprintf(esc, sizeof esc, "\\x%02x", *p);
+            } else {
+                esc[0] = (char)*p;
+            }
+        }
+        wl_buf_add(&buf, esc);
+    }
+    wl_buf_add(&buf, "\"");
+    return wl_buf_str(&buf);
+}
+
+/* formatting */
+
+static wl_str wl_fmt_i64(int64_t v) {
+    char buf[24];
+    snprintf(buf, sizeof buf, "%" PRId64, v);
+    return wl_concat(buf, "");
+}
+
+static wl_str wl_fmt_u64(uint64_t v) {
+    char buf[24];
+    snprintf(buf, sizeof buf, "%" PRIu64, v);
+    return wl_concat(buf, "");
+}
+
+static wl_str wl_fmt_u128(wl_u128 v) {
+    char buf[48];
+    int i = sizeof buf - 1;
+    buf[i] = 0;
+    do {
+        buf[--i] = (char)('0' + (int)(v % 10));
+        v /= 10;
+    } while (v);
+    return wl_concat(buf + i, "");
+}
+
+static wl_str wl_fmt_i128(wl_i128 v) {
+    if (v < 0) {
+        return wl_concat("-", wl_fmt_u128(-(wl_u128)v));
+    }
+    return wl_fmt_u128((wl_u128)v);
+}
+
+static wl_str wl_fmt_bool(bool v) {
+    return v ? "true" : "false";
+}
+
+/* wl_fmt_float formats a float with the fewest digits that read back to the same value,
+ * in exponent form for large and small exponents like walrus does. */
+static wl_str wl_fmt_float(double v, int bits) {
+    if (isnan(v)) {
+        return "NaN";
+    }
+    if (isinf(v)) {
+        return v > 0 ? "+Inf" : "-Inf";
+    }
+
+    char sci[40];
+    for (int prec = 1; prec <= 17; prec++) {
+        snprintf(sci, sizeof sci, "%.*e", prec - 1, v);
+        if (bits == 32 ? strtof(sci, NULL) == (float)v : strtod(sci, NULL) == v) {
+            break;
+        }
+    }
+
+    /* split "-d.ddde+xx" into its sign, digits and exponent */
+    char digits[24];
+    int nd = 0;
+    const char *p = sci;
+    bool negative = *p == '-';
+    if (negative) {
+        p++;
+    }
+    for (; *p && *p != 'e'; p++) {
+        if (*p != '.') {
+            digits[nd++] = *p;
+        }
+    }
+    int exp = atoi(p + 1);
+    while (nd > 1 && digits[nd - 1] == '0') {
+        nd--;
+    }
+    digits[nd] = 0;
+
+    char out[64];
+    int n = 0;
+    if (negative) {
+        out[n++] = '-';
+    }
+    if (exp < -4 || exp >= 6) {
+        out[n++] = digits[0];
+        if (nd > 1) {
+            out[n++] = '.';
+            memcpy(out + n, digits + 1, nd - 1);
+            n += nd - 1;
+        }
+        snprintf(out + n, sizeof out - n, "e%c%02d", exp < 0 ? '-' : '+', exp < 0 ? -exp : exp);
+        return wl_concat(out, "");
+    }
+
+    int dp = exp + 1;
+    if (dp <= 0) {
+        out[n++] = '0';
+        out[n++] = '.';
+        for (int i = 0; i < -dp; i++) {
+            out[n++] = '0';
+        }
+        memcpy(out + n, digits, nd);
+        n += nd;
+    } else if (dp >= nd) {
+        memcpy(out + n, digits, nd);
+        n += nd;
+        for (int i = nd; i < dp; i++) {
+            out[n++] = '0';
+        }
+    } else {
+        memcpy(out + n, digits, dp);
+        n += dp;
+        out[n++] = '.';
+        memcpy(out + n, digits + dp, nd - dp);
+        n += nd - dp;
+    }
+    out[n] = 0;
+    return wl_concat(out, "");
+}
+
+static wl_str wl_fmt_f64(double v) {
+    return wl_fmt_float(v, 64);
+}
+
+static wl_str wl_fmt_f32(float v) {
+    return wl_fmt_float(v, 32);
+}
+
+/* integers: signed arithmetic is done on unsigned values so it wraps around */
+
+static wl_i128 wl_sdiv(wl_i128 a, wl_i128 b) {
+    if (b == 0) {
+        wl_panic("integer division by zero");
+    }
+    if (b == -1) {
+        return (wl_i128)(0 - (wl_u128)a);
+    }
+    return a / b;
+}
+
+static wl_i128 wl_smod(wl_i128 a, wl_i128 b) {
+    if (b == 0) {
+        wl_panic("integer division by zero");
+    }
+    if (b == -1) {
+        return 0;
+    }
+    return a % b;
+}
+
+static wl_u128 wl_udiv(wl_u128 a, wl_u128 b) {
+    if (b == 0) {
+        wl_panic("integer division by zero");
+    }
+    return a / b;
+}
+
+static wl_u128 wl_umod(wl_u128 a, wl_u128 b) {
+    if (b == 0) {
+        wl_panic("integer division by zero");
+    }
+    return a % b;
+}
+
+/* wl_scount and wl_ucount check a shift count. Counts past the width of any integer shift every bit out. */
+static unsigned wl_scount(wl_i128 n) {
+    if (n < 0) {
+        wl_panic("negative shift count");
+    }
+    return n > 128 ? 128 : (unsigned)n;
+}
+
+static unsigned wl_ucount(wl_u128 n) {
+    return n > 128 ? 128 : (unsigned)n;
+}
+
+static wl_u128 wl_shl(wl_u128 a, unsigned n) {
+    return n >= 128 ? 0 : a << n;
+}
+
+static wl_i128 wl_sshr(wl_i128 a, unsigned n) {
+    if (n >= 128) {
+        return a < 0 ? -1 : 0;
+    }
+    return a >> n;
+}
+
+static wl_u128 wl_ushr(wl_u128 a, unsigned n) {
+    return n >= 128 ? 0 : a >> n;
+}
+
+/* wl_pow returns the low 128 bits of base ** exp, which truncate to any smaller integer. */
+static wl_u128 wl_pow(wl_u128 base, wl_i128 exp) {
+    if (exp < 0) {
+        wl_panic("negative integer exponent");
+    }
+    wl_u128 result = 1;
+    while (exp > 0) {
+        if (exp & 1) {
+            result *= base;
+        }
+        base *= base;
+        exp >>= 1;
+    }
+    return result;
+}
+
+/* wl_ftoi truncates a float and returns the low 128 bits of the result. */
+static wl_u128 wl_ftoi(double v) {
+    if (isnan(v) || isinf(v)) {
+        char msg[64];
+        snprintf(msg, sizeof msg, "cannot convert %s to an integer", wl_fmt_f64(v));
+        wl_panic(msg);
+    }
+    v = trunc(v);
+    if (fabs(v) < 0x1p126) {
+        return (wl_u128)(wl_i128)v;
+    }
+    double m = fmod(v, 0x1p128);
+    if (m < 0) {
+        m += 0x1p128;
+    }
+    return (wl_u128)m;
+}
+
+/* arrays */
+
+typedef struct {
+    int64_t len, cap;
+    size_t elem;
+    char *data;
+} wl_array;
+
+static wl_array *wl_array_new(size_t elem, int64_t len, const void *values) {
+    wl_array *a = wl_alloc(sizeof(wl_array), NULL);
+    a->len = len;
+    a->cap = len;
+    a->elem = elem;
+    a->data = wl_alloc(elem * (size_t)len, values);
+    return a;
+}
+
+/* wl_array_push appends an element, growing the array when it is full. */
+static void wl_array_push(wl_array *a, const void *value) {
+    if (a->len == a->cap) {
+        a->cap = a->cap ? a->cap * 2 : 8;
+        a->data = realloc(a->data, a->elem * (size_t)a->cap);
+        if (!a->data) {
+            wl_panic("out of memory");
+        }
+    }
+    memcpy(a->data + (size_t)a->len * a->elem, value, a->elem);
+    a->len++;
+}
+
+static void *wl_array_at(wl_array *a, int64_t i) {
+    if (i < 0 || i >= a->len) {
+        char msg[96];
+        snprintf(msg, sizeof msg, "index %" PRId64 " out of range with length %" PRId64, i, a->len);
+        wl_panic(msg);
+    }
+    return a->data + (size_t)i * a->elem;
+}
+
+/* maps keep their entries in insertion order, the hash index points into the entries */
+
+enum { WL_KEY_RAW, WL_KEY_STR };
+
+typedef struct {
+    int kind;
+    size_t ksize, vsize;
+    int64_t len, cap;
+    char *keys, *values;
+    int64_t *slots; /* entry index + 1, 0 when the slot is empty */
+    int64_t nslots;
+} wl_map;
+
+static uint64_t wl_hash(const wl_map *m, const void *key) {
+    uint64_t h = 14695981039346656037u;
+    const unsigned char *p = m->kind == WL_KEY_STR ? *(const unsigned char *const *)key : key;
+    size_t n = m->kind == WL_KEY_STR ? strlen((const char *)p) : m->ksize;
+    for (size_t i = 0; i < n; i++) {
+        h = (h ^ p[i]) * 1099511628211u;
+    }
+    return h;
+}
+
+static bool wl_key_eq(const wl_map *m, const void *a, const void *b) {
+    if (m->kind == WL_KEY_STR) {
+        return strcmp(*(wl_str const *)a, *(wl_str const *)b) == 0;
+    }
+    return memcmp(a, b, m->ksize) == 0;
+}
+
+static void *wl_map_key(wl_map *m, int64_t i) {
+    return m->keys + (size_t)i * m->ksize;
+}
+
+static void *wl_map_value(wl_map *m, int64_t i) {
+    return m->values + (size_t)i * m->vsize;
+}
+
+/* wl_map_find returns the slot of a key, which is empty when the key is missing. */
+static int64_t wl_map_find(wl_map *m, const void *key) {
+    int64_t slot = (int64_t)(wl_hash(m, key) % (uint64_t)m->nslots);
+    while (m->slots[slot] && !wl_key_eq(m, wl_map_key(m, m->slots[slot] - 1), key)) {
+        slot = (slot + 1) % m->nslots;
+    }
+    return slot;
+}
+
+static void wl_map_rehash(wl_map *m) {
+    m->nslots *= 2;
+    m->slots = wl_alloc(sizeof(int64_t) * (size_t)m->nslots, NULL);
+    for (int64_t i = 0; i < m->len; i++) {
+        m->slots[wl_map_find(m, wl_map_key(m, i))] = i + 1;
+    }
+}
+
+static wl_map *wl_map_new(int kind, size_t ksize, size_t vsize) {
+    wl_map *m = wl_alloc(sizeof(wl_map), NULL);
+    m->kind = kind;
+    m->ksize = ksize;
+    m->vsize = vsize;
+    m->nslots = 8;
+    m->slots = wl_alloc(sizeof(int64_t) * (size_t)m->nslots, NULL);
+    return m;
+}
+
+/* wl_map_set stores a value and returns the stored copy. */
+static void *wl_map_set(wl_map *m, const void *key, const void *value) {
+    int64_t slot = wl_map_find(m, key);
+    if (!m->slots[slot]) {
+        if (m->len == m->cap) {
+            m->cap = m->cap ? m->cap * 2 : 8;
+            m->keys = realloc(m->keys, m->ksize * (size_t)m->cap);
+            m->values = realloc(m->values, m->vsize * (size_t)m->cap);
+            if (!m->keys || !m->values) {
+                wl_panic("out of memory");
+            }
+        }
+        memcpy(wl_map_key(m, m->len), key, m->ksize);
+        m->slots[slot] = ++m->len;
+        if (m->len * 2 > m->nslots) {
+            wl_map_rehash(m);
+        }
+        slot = wl_map_find(m, key);
+    }
+    void *stored = wl_map_value(m, m->slots[slot] - 1);
+    memcpy(stored, value, m->vsize);
+    return stored;
+}
+
+static void *wl_map_get(wl_map *m, const void *key) {
+    int64_t slot = wl_map_find(m, key);
+    if (!m->slots[slot]) {
+        if (m->kind == WL_KEY_STR) {
+            wl_panic(wl_concat(wl_concat("key ", wl_quote(*(wl_str const *)key)), " not found in map"));
+        }
+        wl_panic("key not found in map");
+    }
+    return wl_map_value(m, m->slots[slot] - 1);
+}
+
+static wl_map *wl_map_from(int kind, size_t ksize, size_t vsize, int64_t n, const void *keys, const void *values) {
+    wl_map *m = wl_map_new(kind, ksize, vsize);
+    for (int64_t i = 0; i < n; i++) {
+        wl_map_set(m, (const char *)keys + (size_t)i * ksize, (const char *)values + (size_t)i * vsize);
+    }
+    return m;
+}
+
+/* closures and interfaces */
+
+/* wl_fn is a function value: the code of a function and the environment it captured.
+ * The code takes the environment as its first argument. */
+typedef struct {
+    void *code;
+    void *env;
+} wl_fn;
+
+static wl_fn *wl_fn_new(void *code, void *env) {
+    wl_fn *fn = wl_alloc(sizeof(wl_fn), NULL);
+    fn->code = code;
+    fn->env = env;
+    return fn;
+}
+
+/* wl_iface is an interface value: a struct, the methods the interface needs in the order
+ * of the interface and the function formatting the struct. */
+typedef struct {
+    void *obj;
+    void *const *methods;
+    wl_str (*fmt)(void *);
+} wl_iface;
+
+static wl_str wl_fmt_iface(wl_iface v) {
+    return v.obj ? v.fmt(v.obj) : "void";
+}
+
+#endif
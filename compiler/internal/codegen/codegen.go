@@ -0,0 +1,175 @@
+package codegen
+
+import (
+	//Standard packages
+	"strconv"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/builtins"
+	"walrus/compiler/internal/lexer"
+	"walrus/compiler/internal/values"
+)
+
+// Scope tracks the declared type of every variable while a backend walks the tree.
+type Scope struct {
+	parent *Scope
+	vars   map[string]ast.DataType
+}
+
+func NewScope(parent *Scope) *Scope {
+	return &Scope{parent: parent, vars: make(map[string]ast.DataType)}
+}
+
+func (s *Scope) Declare(name string, dtype ast.DataType) {
+	s.vars[name] = dtype
+}
+
+func (s *Scope) Lookup(name string) (ast.DataType, bool) {
+	for current := s; current != nil; current = current.parent {
+		if dtype, ok := current.vars[name]; ok {
+			return dtype, true
+		}
+	}
+	return nil, false
+}
+
+// Owner returns the scope the name is declared in, or nil when it is not declared.
+func (s *Scope) Owner(name string) *Scope {
+	for current := s; current != nil; current = current.parent {
+		if _, ok := current.vars[name]; ok {
+			return current
+		}
+	}
+	return nil
+}
+
+// IsLocal reports whether the name is declared in this scope itself.
+func (s *Scope) IsLocal(name string) bool {
+	_, ok := s.vars[name]
+	return ok
+}
+
+// Method is a method implemented for a struct.
+type Method struct {
+	Name      string
+	Type      ast.FunctionType
+	IsPrivate bool
+}
+
+// Declarations holds the types and the methods a program declares, which backends look up
+// to spell types and to find fields and methods. The types of expressions are the ones the
+// typechecker resolved, see typechecker.TypeInfo.
+type Declarations struct {
+	Types   values.TypeTable
+	Methods map[string][]Method // struct name -> methods in declaration order
+}
+
+func NewDeclarations() *Declarations {
+	return &Declarations{
+		Types:   make(values.TypeTable),
+		Methods: make(map[string][]Method),
+	}
+}
+
+// helper type initialization functions
+func IntType(bitSize uint8, isSigned bool) ast.IntegerType {
+	prefix := "u"
+	if isSigned {
+		prefix = "i"
+	}
+	return ast.IntegerType{TypeName: builtins.PARSER_TYPE(prefix + strconv.Itoa(int(bitSize))), BitSize: bitSize, IsSigned: isSigned}
+}
+
+func FloatType(bitSize uint8) ast.FloatType {
+	return ast.FloatType{TypeName: builtins.PARSER_TYPE("f" + strconv.Itoa(int(bitSize))), BitSize: bitSize}
+}
+
+func StrType() ast.StringType {
+	return ast.StringType{TypeName: builtins.STRING}
+}
+
+func BoolType() ast.BooleanType {
+	return ast.BooleanType{TypeName: builtins.BOOL}
+}
+
+func VoidType() ast.VoidType {
+	return ast.VoidType{TypeName: builtins.VOID}
+}
+
+// FunctionTypeOf returns the type of a function literal.
+func FunctionTypeOf(fn ast.FunctionLiteral) ast.FunctionType {
+	params := make([]ast.FunctionTypeParam, len(fn.Params))
+	for i, param := range fn.Params {
+		params[i] = ast.FunctionTypeParam{Identifier: param.Identifier, Type: param.Type, Location: param.Location}
+	}
+	return ast.FunctionType{TypeName: builtins.FUNCTION, Parameters: params, ReturnType: fn.ReturnType, Location: fn.Location}
+}
+
+// DeclareType records a type declaration.
+func (d *Declarations) DeclareType(node ast.TypeDeclStmt) {
+	d.Types[node.UDTypeName.Name] = node.UDTypeValue
+}
+
+// DeclareMethods records the methods of an implement statement.
+func (d *Declarations) DeclareMethods(node ast.ImplStmt) {
+	name := d.Types.StructName(node.ImplFor.Name)
+	for _, method := range node.Methods {
+		d.Methods[name] = append(d.Methods[name], Method{Name: method.Identifier.Name, Type: FunctionTypeOf(method.FunctionLiteral), IsPrivate: method.IsPrivate})
+	}
+}
+
+// FindMethod returns the method of a struct with the given name.
+func (d *Declarations) FindMethod(structName string, name string) (Method, bool) {
+	for _, method := range d.Methods[d.Types.StructName(structName)] {
+		if method.Name == name {
+			return method, true
+		}
+	}
+	return Method{}, false
+}
+
+// FindProperty returns the declaration of a struct field.
+func (d *Declarations) FindProperty(dtype ast.DataType, name string) (ast.StructPropType, bool) {
+	def, _ := d.Types.Underlying(dtype)
+	if structType, ok := def.(ast.StructType); ok {
+		for _, prop := range structType.Properties {
+			if prop.Prop.Name == name {
+				return prop, true
+			}
+		}
+	}
+	return ast.StructPropType{}, false
+}
+
+// ReturnType returns the return type of a function type, which is void when it is omitted.
+func ReturnType(fn ast.FunctionType) ast.DataType {
+	if fn.ReturnType == nil {
+		return VoidType()
+	}
+	return fn.ReturnType
+}
+
+// IsVoid reports whether a type is void or unknown.
+func IsVoid(dtype ast.DataType) bool {
+	if dtype == nil {
+		return true
+	}
+	_, ok := dtype.(ast.VoidType)
+	return ok
+}
+
+// Concat writes a string like "a {x} b" as the additions "" + "a" + x + " b", which backends
+// already generate, a string plus any value writes the value.
+func Concat(node ast.InterpolatedStringExpr) ast.Node {
+	var text ast.Node = ast.StringLiteralExpr{Value: "", Location: node.Location}
+	for _, part := range node.Parts {
+		text = ast.BinaryExpr{
+			Binop:    lexer.Token{Kind: lexer.PLUS_TOKEN, Value: "+", Start: node.Start, End: node.End},
+			Left:     text,
+			Right:    part,
+			Location: node.Location,
+		}
+	}
+	return text
+}
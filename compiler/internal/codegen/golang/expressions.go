@@ -0,0 +1,559 @@
+package golang
+
+import (
+	//Standard packages
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/codegen"
+	"walrus/compiler/internal/lexer"
+	"walrus/compiler/internal/values"
+)
+
+func (g *Generator) expr(node ast.Node, scope *codegen.Scope) string {
+	if value, ok := g.info.ConstantOf(node); ok {
+		return g.literal(value, node)
+	}
+
+	switch t := node.(type) {
+	case ast.IdentifierExpr:
+		return g.identifier(t, scope)
+	case ast.IntegerLiteralExpr, ast.FloatLiteralExpr:
+		g.unsupported(t, "invalid numeric literal")
+		return ""
+	case ast.StringLiteralExpr:
+		return strconv.Quote(t.Value)
+	case ast.InterpolatedStringExpr:
+		return g.interpolation(t, scope)
+	case ast.ByteLiteralExpr:
+		return strconv.Itoa(int(t.Value[0]))
+	case ast.BinaryExpr:
+		return g.binary(t.Binop, t.Left, t.Right, scope)
+	case ast.UnaryExpr:
+		return g.unary(t, scope)
+	case ast.IncrementalInterface:
+		return g.closure(g.typeOf(node), g.incrementValue(t, scope))
+	case ast.VarAssignmentExpr:
+		return g.closure(g.typeOf(t.Assignee), g.assignment(t, scope)+"\nreturn "+g.expr(t.Assignee, scope))
+	case ast.TypeCastExpr:
+		return g.convert(g.expr(t.Expression, scope), g.typeOf(t.Expression), t.ToCast)
+	case ast.ResultExpr, ast.PropagateExpr:
+		g.unsupported(t, "results cannot be generated yet")
+		return ""
+	case ast.TupleLiteral:
+		g.unsupported(t, "tuples cannot be generated yet")
+		return ""
+	case ast.TypeofExpr:
+		return strconv.Quote(g.declared.Types.Name(g.typeOf(t.Expression)))
+	case ast.RangeExpr:
+		dtype := g.typeOf(t)
+		return fmt.Sprintf("%s{Start: %s, End: %s}", g.goType(dtype), g.expr(t.Start, scope), g.expr(t.End, scope))
+	case ast.ArrayLiteral:
+		return g.arrayLiteral(t, g.typeOf(t), scope)
+	case ast.Indexable:
+		return g.index(t, scope)
+	case ast.StructLiteral:
+		return g.structLiteral(t, scope)
+	case ast.StructPropertyAccessExpr:
+		return g.property(t, scope)
+	case ast.MapLiteral:
+		return g.mapLiteral(t, scope)
+	case ast.FunctionLiteral:
+		return g.function(t, scope)
+	case ast.FunctionCallExpr:
+		return g.call(t, scope)
+	default:
+		g.unsupported(node, fmt.Sprintf("<%T> node cannot be generated yet", node))
+		return ""
+	}
+}
+
+// exprAs generates an expression whose value is stored in a place of the given type.
+// Numbers are converted and empty arrays take the element type of the place.
+func (g *Generator) exprAs(node ast.Node, dtype ast.DataType, scope *codegen.Scope) string {
+	if array, ok := node.(ast.ArrayLiteral); ok {
+		return g.arrayLiteral(array, dtype, scope)
+	}
+	from := g.typeOf(node)
+	code := g.expr(node, scope)
+	if g.isNumber(from) && g.isNumber(dtype) && g.goType(from) != g.goType(dtype) {
+		return g.convert(code, from, dtype)
+	}
+	return code
+}
+
+// isUntyped reports whether an expression is generated as an untyped Go constant, which
+// takes the type of the other operand or of the place it is stored in.
+func (g *Generator) isUntyped(node ast.Node) bool {
+	switch node.(type) {
+	case ast.IntegerLiteralExpr, ast.FloatLiteralExpr:
+		return !g.isBig(g.typeOf(node))
+	}
+	return false
+}
+
+// literal generates a folded constant. Plain literals stay untyped Go constants, folded
+// results are converted to their walrus type.
+func (g *Generator) literal(value values.Value, node ast.Node) string {
+	switch v := value.(type) {
+	case values.Int:
+		if v.BitSize > 64 {
+			g.use("walrusBig")
+			return fmt.Sprintf("walrusBigLiteral(%q)", v.Value.String())
+		}
+		if _, ok := node.(ast.IntegerLiteralExpr); ok {
+			return v.Value.String()
+		}
+		return fmt.Sprintf("%s(%s)", g.goType(codegen.IntType(v.BitSize, v.IsSigned)), v.Value.String())
+	case values.Float:
+		if math.IsNaN(v.Value) {
+			g.imports["math"] = true
+			return fmt.Sprintf("float%d(math.NaN())", v.BitSize)
+		}
+		if math.IsInf(v.Value, 0) {
+			g.imports["math"] = true
+			return fmt.Sprintf("float%d(math.Inf(%d))", v.BitSize, sign(v.Value))
+		}
+		text := strconv.FormatFloat(v.Value, 'g', -1, 64)
+		if !strings.ContainsAny(text, ".e") {
+			text += ".0"
+		}
+		if _, ok := node.(ast.FloatLiteralExpr); ok {
+			return text
+		}
+		return fmt.Sprintf("float%d(%s)", v.BitSize, text)
+	case values.Str:
+		return strconv.Quote(v.Value)
+	default:
+		return value.String()
+	}
+}
+
+func sign(value float64) int {
+	if value < 0 {
+		return -1
+	}
+	return 1
+}
+
+// identifier resolves a name. Builtins become their Go counterparts and the methods of
+// the struct whose method is being generated are accessed through 'this'.
+func (g *Generator) identifier(node ast.IdentifierExpr, scope *codegen.Scope) string {
+	owner := scope.Owner(node.Name)
+	switch {
+	case owner == nil:
+		g.unsupported(node, fmt.Sprintf("'%s' is not declared", node.Name))
+	case owner == g.builtins:
+		switch node.Name {
+		case "PI":
+			g.imports["math"] = true
+			return "float32(math.Pi)"
+		case "print":
+			g.imports["fmt"] = true
+			return "func(value string) { fmt.Println(value) }"
+		}
+		return node.Name
+	case owner == g.receiver && node.Name != "this":
+		method, _ := g.declared.FindMethod(g.receiverName(), node.Name)
+		return "this." + memberName(node.Name, method.IsPrivate)
+	}
+	return name(node.Name)
+}
+
+// receiverName returns the name of the struct whose method is being generated.
+func (g *Generator) receiverName() string {
+	dtype, _ := g.receiver.Lookup("this")
+	return dtype.(ast.UserDefinedType).AliasName
+}
+
+// interpolation generates a string like "a {x} b" as the additions "" + "a" + x + " b",
+// a string plus any value writes the value.
+func (g *Generator) interpolation(node ast.InterpolatedStringExpr, scope *codegen.Scope) string {
+	text := `""`
+	for _, part := range node.Parts {
+		code := g.operand(g.expr(part, scope))
+		if _, ok := g.underlying(g.typeOf(part)).(ast.StringType); !ok {
+			g.use("walrusString")
+			code = fmt.Sprintf("walrusString(%s)", code)
+		}
+		text = fmt.Sprintf("(%s + %s)", text, code)
+	}
+	return text
+}
+
+// binary generates a binary operation. Like in walrus, the right operand is converted to
+// the type of the left operand and the result has the type of the left operand, but an
+// integer and a float give the float type.
+func (g *Generator) binary(op lexer.Token, leftNode, rightNode ast.Node, scope *codegen.Scope) string {
+	leftType := g.typeOf(leftNode)
+	rightType := g.typeOf(rightNode)
+	left := g.operand(g.expr(leftNode, scope))
+	right := g.operand(g.expr(rightNode, scope))
+
+	if _, ok := g.underlying(leftType).(ast.StringType); ok && op.Kind == lexer.PLUS_TOKEN {
+		if _, ok := g.underlying(rightType).(ast.StringType); !ok {
+			g.use("walrusString")
+			right = fmt.Sprintf("walrusString(%s)", right)
+		}
+		return fmt.Sprintf("(%s + %s)", left, right)
+	}
+
+	if g.isNumber(leftType) && !g.isFloat(leftType) && g.isFloat(rightType) && !g.isComparison(op) {
+		// an integer and a float give a float
+		left = g.convert(left, leftType, rightType)
+		leftType = rightType
+	}
+
+	if g.isNumber(leftType) && g.isNumber(rightType) && g.goType(leftType) != g.goType(rightType) {
+		switch {
+		case g.isComparison(op) && (g.isFloat(leftType) || g.isFloat(rightType)) && !g.isBig(leftType) && !g.isBig(rightType):
+			left = "float64(" + left + ")"
+			right = "float64(" + right + ")"
+		case !g.isUntyped(rightNode) || g.isBig(leftType):
+			right = g.convert(right, rightType, leftType)
+		}
+	}
+
+	_, leftConstant := g.info.ConstantOf(leftNode)
+	_, rightConstant := g.info.ConstantOf(rightNode)
+	if leftConstant && rightConstant && g.isNumber(leftType) && !g.isBig(leftType) {
+		// the typechecker could not fold the operation, it overflows or divides by zero
+		// when the program gets there
+		g.use("walrusValue")
+		left = fmt.Sprintf("walrusValue(%s(%s))", g.goType(leftType), left)
+	}
+
+	if g.isBig(leftType) {
+		if g.isComparison(op) {
+			return fmt.Sprintf("(%s.Cmp(%s) %s 0)", left, right, op.Kind)
+		}
+		g.use("walrusBig")
+		return fmt.Sprintf("walrusBig(%q, %s, %s, %t)", op.Kind, left, right, g.underlying(leftType).(ast.IntegerType).IsSigned)
+	}
+
+	switch op.Kind {
+	case lexer.EXP_TOKEN:
+		if g.isFloat(leftType) {
+			g.imports["math"] = true
+			return fmt.Sprintf("%s(math.Pow(float64(%s), float64(%s)))", g.goType(leftType), left, right)
+		}
+		g.use("walrusPow")
+		if g.isUntyped(leftNode) {
+			left = g.goType(leftType) + "(" + left + ")"
+		}
+		return fmt.Sprintf("walrusPow(%s, %s)", left, right)
+	case lexer.DIV_TOKEN, lexer.MOD_TOKEN:
+		if g.isFloat(leftType) {
+			if op.Kind == lexer.DIV_TOKEN {
+				break
+			}
+			g.imports["math"] = true
+			return fmt.Sprintf("%s(math.Mod(float64(%s), float64(%s)))", g.goType(leftType), left, right)
+		}
+		if value, ok := g.info.ConstantOf(rightNode); ok && value.(values.Int).Value.Sign() == 0 {
+			// dividing by zero is only an error when the program gets there
+			g.use("walrusValue")
+			right = fmt.Sprintf("walrusValue(%s(%s))", g.goType(leftType), right)
+		}
+	case lexer.SHIFT_LEFT_TOKEN, lexer.SHIFT_RIGHT_TOKEN:
+		// Go gives an untyped constant shifted by a variable the type int
+		if g.isUntyped(leftNode) {
+			left = g.goType(leftType) + "(" + left + ")"
+		}
+	}
+
+	return fmt.Sprintf("(%s %s %s)", left, op.Kind, right)
+}
+
+func (g *Generator) unary(node ast.UnaryExpr, scope *codegen.Scope) string {
+	argument := g.operand(g.expr(node.Argument, scope))
+	dtype := g.typeOf(node.Argument)
+	if (node.Operator.Kind == lexer.MINUS_TOKEN || node.Operator.Kind == lexer.BIT_NOT_TOKEN) && g.isBig(dtype) {
+		g.use("walrusBig")
+		return fmt.Sprintf("walrusBig(%q, new(big.Int), %s, %t)", node.Operator.Kind, argument, g.underlying(dtype).(ast.IntegerType).IsSigned)
+	}
+	if node.Operator.Kind == lexer.BIT_NOT_TOKEN {
+		// Go writes the bitwise complement as ^
+		return fmt.Sprintf("(^%s)", argument)
+	}
+	return fmt.Sprintf("(%s%s)", node.Operator.Kind, argument)
+}
+
+// increment generates ++ and -- as a statement.
+func (g *Generator) increment(node ast.IncrementalInterface, scope *codegen.Scope) string {
+	target := g.identifier(node.Arg(), scope)
+	dtype := g.typeOf(node.Arg())
+	if g.isBig(dtype) {
+		g.use("walrusBig")
+		return fmt.Sprintf("%s = walrusBig(%q, %s, big.NewInt(1), %t)", target, string(node.Op().Kind)[:1], target, g.underlying(dtype).(ast.IntegerType).IsSigned)
+	}
+	return target + string(node.Op().Kind)
+}
+
+// incrementValue returns the body of a function that increments a variable and returns
+// the updated value for the prefix form and the old value for the postfix form.
+func (g *Generator) incrementValue(node ast.IncrementalInterface, scope *codegen.Scope) string {
+	target := g.identifier(node.Arg(), scope)
+	if _, ok := node.(ast.PrefixExpr); ok {
+		return fmt.Sprintf("%s\nreturn %s", g.increment(node, scope), target)
+	}
+	return fmt.Sprintf("walrusOld := %s\n%s\nreturn walrusOld", target, g.increment(node, scope))
+}
+
+// closure wraps statements in a function that is called immediately, for the expressions
+// that are statements in Go.
+func (g *Generator) closure(dtype ast.DataType, body string) string {
+	return fmt.Sprintf("func() %s {\n%s\n}()", g.goType(dtype), body)
+}
+
+// assignment generates an assignment as a statement. Compound operators are generated
+// as the binary operation they apply.
+func (g *Generator) assignment(node ast.VarAssignmentExpr, scope *codegen.Scope) string {
+	var target string
+	switch t := node.Assignee.(type) {
+	case ast.IdentifierExpr:
+		target = g.identifier(t, scope)
+	case ast.Indexable:
+		target = fmt.Sprintf("%s[%s]", g.operand(g.expr(t.Container, scope)), g.indexValue(t, scope))
+	case ast.StructPropertyAccessExpr:
+		target = g.property(t, scope)
+	default:
+		g.unsupported(node.Assignee, "invalid assignment target")
+	}
+
+	dtype := g.typeOf(node.Assignee)
+	op := node.Operator
+	switch op.Kind {
+	case lexer.EQUALS_TOKEN:
+		return fmt.Sprintf("%s = %s", target, g.exprAs(node.Value, dtype, scope))
+	case lexer.PLUS_EQUALS_TOKEN:
+		op.Kind = lexer.PLUS_TOKEN
+	case lexer.MINUS_EQUALS_TOKEN:
+		op.Kind = lexer.MINUS_TOKEN
+	case lexer.MUL_EQUALS_TOKEN:
+		op.Kind = lexer.MUL_TOKEN
+	case lexer.DIV_EQUALS_TOKEN:
+		op.Kind = lexer.DIV_TOKEN
+	case lexer.MOD_EQUALS_TOKEN:
+		op.Kind = lexer.MOD_TOKEN
+	case lexer.EXP_EQUALS_TOKEN:
+		op.Kind = lexer.EXP_TOKEN
+	case lexer.BIT_AND_EQUALS_TOKEN:
+		op.Kind = lexer.BIT_AND_TOKEN
+	case lexer.BIT_OR_EQUALS_TOKEN:
+		op.Kind = lexer.BIT_OR_TOKEN
+	case lexer.BIT_XOR_EQUALS_TOKEN:
+		op.Kind = lexer.BIT_XOR_TOKEN
+	case lexer.SHIFT_LEFT_EQUALS_TOKEN:
+		op.Kind = lexer.SHIFT_LEFT_TOKEN
+	case lexer.SHIFT_RIGHT_EQUALS_TOKEN:
+		op.Kind = lexer.SHIFT_RIGHT_TOKEN
+	}
+	return fmt.Sprintf("%s = %s", target, g.binary(op, node.Assignee, node.Value, scope))
+}
+
+// convert converts a value between numeric types with the wrapping semantics of walrus.
+// Casts between other types are checked by the typechecker and need no conversion, except
+// for structs which are copied into a value of the target type.
+func (g *Generator) convert(code string, from, to ast.DataType) string {
+	fromType, toType := g.goType(from), g.goType(to)
+
+	switch target := g.underlying(to).(type) {
+	case ast.IntegerType:
+		switch {
+		case g.isBig(to) && g.isBig(from):
+			g.use("walrusBig")
+			return fmt.Sprintf("walrusWrap(%s, %t)", code, target.IsSigned)
+		case g.isBig(to) && g.isFloat(from):
+			g.use("walrusBig")
+			return fmt.Sprintf("walrusWrap(walrusFloatBig(float64(%s)), %t)", code, target.IsSigned)
+		case g.isBig(to):
+			g.use("walrusBig")
+			if g.underlying(from).(ast.IntegerType).IsSigned {
+				return fmt.Sprintf("walrusWrap(big.NewInt(int64(%s)), %t)", code, target.IsSigned)
+			}
+			return fmt.Sprintf("walrusWrap(new(big.Int).SetUint64(uint64(%s)), %t)", code, target.IsSigned)
+		case g.isBig(from):
+			g.use("walrusBig")
+			return fmt.Sprintf("%s(walrusLow(%s))", toType, code)
+		case fromType == toType:
+			return code
+		}
+		return fmt.Sprintf("%s(%s)", toType, code)
+	case ast.FloatType:
+		if g.isBig(from) {
+			g.use("walrusBig")
+			return fmt.Sprintf("%s(walrusBigFloat(%s))", toType, code)
+		}
+		if fromType == toType {
+			return code
+		}
+		return fmt.Sprintf("%s(%s)", toType, code)
+	case ast.StructType:
+		source, ok := g.underlying(from).(ast.StructType)
+		if !ok {
+			return code
+		}
+		values := make(map[string]string, len(target.Properties))
+		for _, prop := range target.Properties {
+			for _, field := range source.Properties {
+				if field.Prop.Name == prop.Prop.Name {
+					values[prop.Prop.Name] = "value." + memberName(field.Prop.Name, field.IsPrivate)
+				}
+			}
+		}
+		return fmt.Sprintf("func(value %s) %s {\nreturn &%s{%s}\n}(%s)", fromType, toType, strings.TrimPrefix(toType, "*"), g.fields(target, values), code)
+	}
+
+	if fromType == toType {
+		return code
+	}
+	return fmt.Sprintf("(%s)(%s)", toType, code)
+}
+
+func (g *Generator) arrayLiteral(node ast.ArrayLiteral, dtype ast.DataType, scope *codegen.Scope) string {
+	array, ok := g.underlying(dtype).(ast.ArrayType)
+	if !ok {
+		array, _ = g.typeOf(node).(ast.ArrayType)
+	}
+	values := make([]string, len(node.Values))
+	for i, value := range node.Values {
+		values[i] = g.exprAs(value, array.ArrayType, scope)
+	}
+	return fmt.Sprintf("%s{%s}", g.goType(array), strings.Join(values, ", "))
+}
+
+// index reads an array element, a byte of a string or a map value.
+func (g *Generator) index(node ast.Indexable, scope *codegen.Scope) string {
+	container := g.operand(g.expr(node.Container, scope))
+	if _, ok := g.underlying(g.typeOf(node.Container)).(ast.MapType); ok {
+		g.use("walrusLookup")
+		return fmt.Sprintf("walrusLookup(%s, %s)", container, g.expr(node.Index, scope))
+	}
+	return fmt.Sprintf("%s[%s]", container, g.indexValue(node, scope))
+}
+
+// indexValue returns the index of an element access. Map keys keep their type, array
+// and string indexes are converted when they are 128 bit integers.
+func (g *Generator) indexValue(node ast.Indexable, scope *codegen.Scope) string {
+	containerType := g.underlying(g.typeOf(node.Container))
+	if mapType, ok := containerType.(ast.MapType); ok {
+		return g.exprAs(node.Index, mapType.KeyType, scope)
+	}
+	index := g.expr(node.Index, scope)
+	if g.isBig(g.typeOf(node.Index)) {
+		return g.operand(index) + ".Int64()"
+	}
+	return index
+}
+
+func (g *Generator) structLiteral(node ast.StructLiteral, scope *codegen.Scope) string {
+	dtype := g.typeOf(node)
+	structType, ok := g.underlying(dtype).(ast.StructType)
+	if !ok {
+		g.unsupported(node.Identifier, fmt.Sprintf("'%s' is not a struct", node.Identifier.Name))
+	}
+
+	values := make(map[string]string, len(node.Properties))
+	for _, prop := range node.Properties {
+		propType := g.typeOf(prop.Value)
+		if field, ok := g.declared.FindProperty(structType, prop.Prop.Name); ok {
+			propType = field.PropType
+		}
+		values[prop.Prop.Name] = g.exprAs(prop.Value, propType, scope)
+	}
+	return "&" + strings.TrimPrefix(g.goType(dtype), "*") + "{" + g.fields(structType, values) + "}"
+}
+
+func (g *Generator) property(node ast.StructPropertyAccessExpr, scope *codegen.Scope) string {
+	if node.Optional {
+		g.unsupported(node, "optional chaining cannot be generated yet")
+	}
+	object := g.operand(g.expr(node.Object, scope))
+	objectType := g.typeOf(node.Object)
+
+	if prop, ok := g.declared.FindProperty(objectType, node.Property.Name); ok {
+		return object + "." + memberName(node.Property.Name, prop.IsPrivate)
+	}
+	if _, structName := g.declared.Types.Underlying(objectType); structName != "" {
+		if method, ok := g.declared.FindMethod(structName, node.Property.Name); ok {
+			return object + "." + memberName(node.Property.Name, method.IsPrivate)
+		}
+	}
+	return object + "." + memberName(node.Property.Name, false)
+}
+
+func (g *Generator) mapLiteral(node ast.MapLiteral, scope *codegen.Scope) string {
+	mapType, _ := g.underlying(node.MapType).(ast.MapType)
+	entries := make([]string, len(node.Values))
+	for i, entry := range node.Values {
+		entries[i] = g.exprAs(entry.Key, mapType.KeyType, scope) + ": " + g.exprAs(entry.Value, mapType.ValueType, scope)
+	}
+	return fmt.Sprintf("%s{%s}", g.goType(node.MapType), strings.Join(entries, ", "))
+}
+
+// call generates a function call. Arguments are converted to the types of the parameters.
+func (g *Generator) call(node ast.FunctionCallExpr, scope *codegen.Scope) string {
+	fnType, _ := g.underlying(g.typeOf(node.Caller)).(ast.FunctionType)
+
+	args := make([]string, len(node.Arguments))
+	for i, arg := range node.Arguments {
+		if i < len(fnType.Parameters) {
+			args[i] = g.exprAs(arg, fnType.Parameters[i].Type, scope)
+		} else {
+			args[i] = g.expr(arg, scope)
+		}
+	}
+
+	if caller, ok := node.Caller.(ast.IdentifierExpr); ok && caller.Name == "print" && scope.Owner(caller.Name) == g.builtins {
+		g.imports["fmt"] = true
+		return fmt.Sprintf("fmt.Println(%s)", strings.Join(args, ", "))
+	}
+
+	return fmt.Sprintf("%s(%s)", g.operand(g.expr(node.Caller, scope)), strings.Join(args, ", "))
+}
+
+// operand parenthesizes an expression that would otherwise bind wrongly when it is
+// followed by a selector, an index or a call.
+func (g *Generator) operand(code string) string {
+	if strings.HasPrefix(code, "&") || strings.HasPrefix(code, "func(") || strings.HasPrefix(code, "-") {
+		return "(" + code + ")"
+	}
+	return code
+}
+
+func (g *Generator) underlying(dtype ast.DataType) ast.DataType {
+	def, _ := g.declared.Types.Underlying(dtype)
+	return def
+}
+
+func (g *Generator) isNumber(dtype ast.DataType) bool {
+	switch g.underlying(dtype).(type) {
+	case ast.IntegerType, ast.FloatType:
+		return true
+	}
+	return false
+}
+
+func (g *Generator) isFloat(dtype ast.DataType) bool {
+	_, ok := g.underlying(dtype).(ast.FloatType)
+	return ok
+}
+
+// isBig reports whether a type is a 128 bit integer, which is generated as a *big.Int.
+func (g *Generator) isBig(dtype ast.DataType) bool {
+	t, ok := g.underlying(dtype).(ast.IntegerType)
+	return ok && t.BitSize > 64
+}
+
+func (g *Generator) isComparison(op lexer.Token) bool {
+	switch op.Kind {
+	case lexer.DOUBLE_EQUAL_TOKEN, lexer.NOT_EQUAL_TOKEN, lexer.LESS_TOKEN, lexer.LESS_EQUAL_TOKEN, lexer.GREATER_TOKEN, lexer.GREATER_EQUAL_TOKEN:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package golang
+
+import (
+	//Standard packages
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/builtins"
+	"walrus/compiler/internal/codegen"
+	"walrus/compiler/internal/modules"
+	"walrus/compiler/internal/typechecker"
+	"walrus/compiler/report"
+)
+
+// Generator lowers a type checked program into the source of a Go 'main' package.
+// Top level functions, types and methods become package level declarations, top level
+// variables become package level variables and every other statement runs in 'main'.
+// Expressions have the types the typechecker resolved for the module being generated.
+type Generator struct {
+	filePath   string
+	info       *typechecker.TypeInfo
+	declared   *codegen.Declarations
+	builtins   *codegen.Scope  // the names walrus declares for every program
+	receiver   *codegen.Scope  // 'this' and the methods of the struct whose method is being generated
+	returnType ast.DataType    // return type of the function being generated
+	labels     map[string]bool // loop labels of the function being generated, true once a jump uses them
+	imports    map[string]bool
+	helpers    map[string]bool
+	decls      strings.Builder
+}
+
+// Generate returns the formatted Go source of a type checked program, from its modules in
+// the order they are linked and the type info of each of them.
+func Generate(program []modules.Module, infos []*typechecker.TypeInfo) ([]byte, error) {
+	g := &Generator{
+		declared: codegen.NewDeclarations(),
+		builtins: codegen.NewScope(nil),
+		imports:  make(map[string]bool),
+		helpers:  make(map[string]bool),
+		labels:   make(map[string]bool),
+	}
+
+	g.builtins.Declare("true", codegen.BoolType())
+	g.builtins.Declare("false", codegen.BoolType())
+	g.builtins.Declare("PI", codegen.FloatType(32))
+	g.builtins.Declare("print", ast.FunctionType{
+		TypeName:   builtins.FUNCTION,
+		Parameters: []ast.FunctionTypeParam{{Identifier: ast.IdentifierExpr{Name: "value"}, Type: codegen.StrType()}},
+		ReturnType: codegen.VoidType(),
+	})
+
+	global := codegen.NewScope(g.builtins)
+
+	// types, methods and functions are visible to the whole program
+	g.each(program, infos, func(node ast.Node) {
+		switch t := node.(type) {
+		case ast.TypeDeclStmt:
+			switch t.UDTypeValue.(type) {
+			case ast.GenericType:
+				g.unsupported(t, "generic types cannot be generated yet")
+			case ast.EnumType:
+				g.unsupported(t, "enum types cannot be generated yet")
+			}
+			g.declared.DeclareType(t)
+		case ast.ImplStmt:
+			g.declared.DeclareMethods(t)
+		case ast.FunctionDeclStmt:
+			global.Declare(t.Identifier.Name, codegen.FunctionTypeOf(t.FunctionLiteral))
+		}
+	})
+
+	var main strings.Builder
+	g.each(program, infos, func(node ast.Node) {
+		switch t := node.(type) {
+		case ast.TypeDeclStmt:
+			g.typeDecl(t)
+		case ast.VarDeclStmt:
+			g.globalVarDecl(t, global, &main)
+		case ast.ImplStmt, ast.FunctionDeclStmt:
+			// generated once every global variable is declared
+		default:
+			g.statement(node, global, &main)
+		}
+	})
+
+	g.each(program, infos, func(node ast.Node) {
+		switch t := node.(type) {
+		case ast.ImplStmt:
+			g.implStmt(t, global)
+		case ast.FunctionDeclStmt:
+			fmt.Fprintf(&g.decls, "func %s%s\n\n", name(t.Identifier.Name), g.function(t.FunctionLiteral, global)[len("func"):])
+		}
+	})
+
+	return g.source(program[len(program)-1].FilePath, &main)
+}
+
+// each calls fn for the top level statements of every module but its imports, with the type
+// info of the module.
+func (g *Generator) each(program []modules.Module, infos []*typechecker.TypeInfo, fn func(node ast.Node)) {
+	for i, module := range program {
+		g.filePath, g.info = module.FilePath, infos[i]
+		for _, node := range module.Tree.(ast.ProgramStmt).Contents {
+			if _, ok := node.(ast.ImportStmt); !ok {
+				fn(node)
+			}
+		}
+	}
+}
+
+// typeOf returns the type the typechecker resolved for an expression, or nil.
+func (g *Generator) typeOf(node ast.Node) ast.DataType {
+	dtype, _ := g.info.DataTypeOf(node)
+	return dtype
+}
+
+// source assembles the file generated from the entry file of the program and formats it.
+func (g *Generator) source(entry string, main *strings.Builder) ([]byte, error) {
+	for _, helper := range sortedKeys(g.helpers) {
+		for _, imp := range helpers[helper].imports {
+			g.imports[imp] = true
+		}
+	}
+
+	var file strings.Builder
+	fmt.Fprintf(&file, "// Code generated by walrus from %s. DO NOT EDIT.\n\npackage main\n\n", filepath.Base(entry))
+	if len(g.imports) > 0 {
+		file.WriteString("import (\n")
+		for _, imp := range sortedKeys(g.imports) {
+			fmt.Fprintf(&file, "%q\n", imp)
+		}
+		file.WriteString(")\n\n")
+	}
+	file.WriteString(g.decls.String())
+	fmt.Fprintf(&file, "func main() {\n%s}\n", main.String())
+	for _, helper := range sortedKeys(g.helpers) {
+		file.WriteString(helpers[helper].source)
+	}
+
+	source, err := format.Source([]byte(file.String()))
+	if err != nil {
+		return []byte(file.String()), fmt.Errorf("generated invalid Go source: %v", err)
+	}
+	return source, nil
+}
+
+// use marks a helper function and the helpers it calls as used.
+func (g *Generator) use(helper string) {
+	if g.helpers[helper] {
+		return
+	}
+	g.helpers[helper] = true
+	for _, required := range helpers[helper].requires {
+		g.use(required)
+	}
+}
+
+// unsupported reports a node the Go backend cannot generate.
+func (g *Generator) unsupported(node ast.Node, msg string) {
+	report.Add(g.filePath, node.StartPos().Line, node.EndPos().Line, node.StartPos().Column, node.EndPos().Column, msg).SetLevel(report.CRITICAL_ERROR)
+}
+
+// typeDecl declares a named type. Structs and interfaces become Go types of their own,
+// every other type becomes an alias so values convert freely like they do in walrus.
+func (g *Generator) typeDecl(node ast.TypeDeclStmt) {
+	g.declared.DeclareType(node)
+	switch t := node.UDTypeValue.(type) {
+	case ast.StructType:
+		fmt.Fprintf(&g.decls, "type %s %s\n\n", name(node.UDTypeName.Name), g.structType(t))
+	case ast.InterfaceType:
+		fmt.Fprintf(&g.decls, "type %s %s\n\n", name(node.UDTypeName.Name), g.interfaceType(t))
+	case ast.UserDefinedType:
+		fmt.Fprintf(&g.decls, "type %s = %s\n\n", name(node.UDTypeName.Name), name(t.AliasName))
+	default:
+		fmt.Fprintf(&g.decls, "type %s = %s\n\n", name(node.UDTypeName.Name), g.goType(t))
+	}
+}
+
+// implStmt generates the methods of a struct. Inside a method, 'this' and the other
+// methods of the struct are in scope.
+func (g *Generator) implStmt(node ast.ImplStmt, scope *codegen.Scope) {
+	structName := g.declared.Types.StructName(node.ImplFor.Name)
+
+	receiver := codegen.NewScope(scope)
+	receiver.Declare("this", ast.UserDefinedType{TypeName: builtins.USER_DEFINED, AliasName: structName, Location: node.ImplFor.Location})
+	for _, method := range g.declared.Methods[structName] {
+		receiver.Declare(method.Name, method.Type)
+	}
+
+	previous := g.receiver
+	g.receiver = receiver
+	defer func() { g.receiver = previous }()
+
+	for _, method := range node.Methods {
+		methodName := memberName(method.Identifier.Name, method.IsPrivate)
+		fmt.Fprintf(&g.decls, "func (this *%s) %s%s\n\n", name(structName), methodName, g.function(method.FunctionLiteral, receiver)[len("func"):])
+	}
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,230 @@
+package golang
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"walrus/compiler/internal/modules"
+	"walrus/compiler/internal/parser"
+	"walrus/compiler/internal/typechecker"
+	"walrus/compiler/report"
+)
+
+// generate type checks a program and returns its Go source.
+func generate(t *testing.T, code string) string {
+	t.Helper()
+
+	tmpfile, e := os.CreateTemp("", "test*.wal")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.Remove(tmpfile.Name())
+
+	if _, e := tmpfile.Write([]byte(code)); e != nil {
+		t.Fatal(e)
+	}
+	if e := tmpfile.Close(); e != nil {
+		t.Fatal(e)
+	}
+
+	defer report.ClearReports()
+
+	tree, e := parser.NewParser(tmpfile.Name(), false).Parse()
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	program := []modules.Module{{FilePath: tmpfile.Name(), Tree: tree}}
+	infos := typechecker.AnalyzeProgram(program)
+	if report.GetReports().HasErrors() {
+		t.Fatalf("Expected program to type check, got %v", report.GetReports()[0].Message)
+	}
+
+	source, e := Generate(program, infos)
+	if e != nil {
+		t.Fatalf("Expected valid Go source, got %v\n%s", e, source)
+	}
+	return string(source)
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected []string
+	}{
+		{
+			name:     "Structs and methods",
+			code:     `type Account struct { owner: str, priv balance: i64 }; impl Account { fn deposit(amount: i64) { } priv fn audit() { } }`,
+			expected: []string{"type Account struct {", "Owner   string `walrus:\"owner\"`", "balance int64  `walrus:\"balance\"`", "func (this *Account) Deposit(amount int64) {", "func (this *Account) audit() {"},
+		},
+		{
+			name:     "Interfaces",
+			code:     `type Shape interface { fn area() -> f32 };`,
+			expected: []string{"type Shape interface {", "Area() float32"},
+		},
+		{
+			name:     "Maps",
+			code:     `type Ages map[str]i32; let ages := $Ages{"a" => 1}; let m : map[str]bool;`,
+			expected: []string{"type Ages = map[string]int32", "ages = Ages{\"a\": 1}", "m = map[string]bool{}"},
+		},
+		{
+			name:     "Reserved names",
+			code:     `let string := "a"; fn main() {}`,
+			expected: []string{"var string_ string", "func main_() {"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := generate(t, tt.code)
+			for _, expected := range tt.expected {
+				if !strings.Contains(source, expected) {
+					t.Errorf("Expected source to contain %q, got\n%s", expected, source)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratedPrograms(t *testing.T) {
+	goTool, e := exec.LookPath("go")
+	if e != nil {
+		t.Skip("go is not installed")
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		expected string
+	}{
+		{
+			name:     "Arithmetic",
+			code:     `print("" + (1 + 2 * 3)); print("" + (7 / 2)); print("" + (7 % 3)); print("" + (2 ** 10)); print("" + (1.5 * 2.0)); let f := 5.5; print("" + (f % 2.0));`,
+			expected: "7\n3\n1\n1024\n3\n1.5\n",
+		},
+		{
+			name:     "Bitwise and logical operators",
+			code:     `fn side() -> bool { print("side"); ret true; } let a := 6; print("" + (a & 3) + " " + (a | 3) + " " + (a ^ 3) + " " + (1 << 4) + " " + (a >> 1) + " " + (~a)); print("" + (2 ** 3 ** 2) + " " + (1 + 2 << 1) + " " + (a & 1 == 0)); print("" + (false && side()) + " " + (true || side())); let c := 200 as u8; let n := -128 as i8; print("" + (c << 1 as u8) + " " + (~c) + " " + (n >> 100)); let e := 5; e <<= 2; e |= 1; e ^= 3; e &= 14; e >>= 1; print("" + e);`,
+			expected: "2 7 5 16 3 -7\n512 6 true\nfalse true\n144 55 -1\n3\n",
+		},
+		{
+			name:     "Integer overflow wraps",
+			code:     `let x : u8 = 255 as u8; x += 1 as u8; print("" + x); let y := 300 as i8; print("" + y);`,
+			expected: "0\n44\n",
+		},
+		{
+			name:     "Functions and closures",
+			code:     `fn fib(n: i32) -> i32 { if n < 2 { ret n; } ret fib(n - 1) + fib(n - 2); } fn counter() -> fn() -> i32 { let n := 0; ret fn() -> i32 { n++; ret n; }; } let c := counter(); c(); print("" + fib(15)); print("" + c());`,
+			expected: "610\n2\n",
+		},
+		{
+			name:     "Shadowed builtin",
+			code:     `fn shout(message: str) -> str { fn print(m: str) -> str { ret m + "!"; } ret print(message); } print(shout("hi"));`,
+			expected: "hi!\n",
+		},
+		{
+			name:     "Loops and conditionals",
+			code:     `let total := 0; for let i := 0; i < 5; i++ { if i == 2 { total += 10; } else if i > 3 { total -= 1; } else { total += i; } } let k := 0; for k < 3 { k++; } print("" + total); print("" + k);`,
+			expected: "13\n3\n",
+		},
+		{
+			name:     "Break and continue",
+			code:     `let total := 0; for let i := 0; i < 10; i++ { if i == 5 { break; } if i % 2 == 0 { continue; } total += i; } print("" + total); let pairs := 0; outer: for let i := 0; i < 3; i++ { for let j := 0; j < 3; j++ { if j == 2 { continue outer; } if i == 2 { break outer; } pairs += 1; } } print("" + pairs); unused: for { break; }`,
+			expected: "4\n4\n",
+		},
+		{
+			name:     "While loops",
+			code:     `let n := 0; while n < 5 { n++; if n == 2 { continue; } } let d := 10; do { d++; } while d < 5; print("" + n + " " + d); fn digits(x: i32) -> i32 { let count := 0; let v := x; do { count++; v /= 10; } while v > 0; ret count; } print("" + digits(0) + " " + digits(123)); fn first() -> i32 { do { ret 7; } while true; } print("" + first()); let hits := 0; rows: while hits < 10 { do { hits++; if hits == 3 { break rows; } } while false; } print("" + hits);`,
+			expected: "5 11\n1 3\n7\n3\n",
+		},
+		{
+			name:     "Strings",
+			code:     `let name := "walrus"; let n := 3; print("hi {name}, {n + 1} \{x\}"); print("tab\there \"q\" \\ \u{e9}"); print(` + "`raw \\n {name}`" + `); print(` + "`line1\nline2`" + `); print("{n}{"-"}{n > 2}"); let f := 1.5; print("f={f}");`,
+			expected: "hi walrus, 4 {x}\ntab\there \"q\" \\ é\nraw \\n {name}\nline1\nline2\n3-true\nf=1.5\n",
+		},
+		{
+			name:     "Number literals",
+			code:     `print("" + 0xff + " " + 0o17 + " " + 0b101 + " " + 1_000_000 + " " + 1.5e3 + " " + 2.5e-1f64); let a := 255u8; let b := -128i8; let c := 0xffff_ffff_ffff_ffffu64; let d := 10f32 / 4f32; print("" + a + " " + b + " " + c + " " + d);`,
+			expected: "255 15 5 1000000 1500 0.25\n255 -128 18446744073709551615 2.5\n",
+		},
+		{
+			name:     "Untyped numbers",
+			code:     `let big: i64 = 5000000000; let neg: i64 = -5000000000; let small: u8 = 200; let half: f64 = 0.1; let whole: f32 = 3; print("" + big + " " + neg + " " + small + " " + half + " " + whole); fn wide(n: i64) -> i64 { ret n; } fn limit() -> u64 { ret 18446744073709551615; } fn precise(x: f64, y: f64) -> f64 { ret x; } print("" + wide(5000000000) + " " + limit() + " " + precise(3.141592653589793, 1) + " " + precise(0.1, 2));`,
+			expected: "5000000000 -5000000000 200 0.1 3\n5000000000 18446744073709551615 3.141592653589793 0.1\n",
+		},
+		{
+			name: "Untyped constants",
+			code: `
+				type S struct { a: i64 };
+				let x: i64 = 5000000000 + 1;
+				x += 1;
+				let y: f64 = 1.5 * 2.0;
+				let z: u8 = 2 * 100;
+				let half: f64 = 1 / 2;
+				let arr: []i64 = [5000000000, 2];
+				let m := $map[str]f64{"a" => 1};
+				let s := @S{a: 5000000000};
+				fn quarter(v: f64) -> f64 { ret v / 4; }
+				print("" + x + " " + y + " " + z + " " + half);
+				print("" + (arr[0] + arr[1]) + " " + m["a"] / 4 + " " + s.a + " " + quarter(1 + 2));
+			`,
+			expected: "5000000002 3 200 0.5\n5000000002 0.25 5000000000 0.75\n",
+		},
+		{
+			name:     "Constant errors outside of constants",
+			code:     `fn never(n: i32) -> i32 { ret n / 0; } const max := 2147483647; let b := max + 1; let z: u8 = 200u8 + 100u8; print("" + b + " " + z);`,
+			expected: "-2147483648 44\n",
+		},
+		{
+			name:     "Default parameters",
+			code:     `const size := 10; fn add(a: i32, b?: i32 = size - 1, s?: str = "x", n?: i64 = -3, on?: bool = true) -> str { ret "" + a + " " + b + " " + s + " " + n + " " + on; } print(add(1)); print(add(1, 2, "y"));`,
+			expected: "1 9 x -3 true\n1 2 y -3 true\n",
+		},
+		{
+			name:     "Integers with floats",
+			code:     `let a: i32 = 23; let d: i64 = 3; const k := 7 / 2.0; print("" + (a + 2.4) + " " + (1.5f64 * d) + " " + (a / 2.0) + " " + (10 - a * 0.5) + " " + k);`,
+			expected: "25.4 4.5 11.5 -1.5 3.5\n",
+		},
+		{
+			name:     "128 bit integers",
+			code:     `let a: i128 = -170141183460469231731687303715884105728; let b := 340282366920938463463374607431768211455u128; fn same(x: u128) -> u128 { ret x; } print("" + a + " " + b + " " + (b as u8) + " " + (a as i64) + " " + same(18446744073709551616) + " " + (5 as i128));`,
+			expected: "-170141183460469231731687303715884105728 340282366920938463463374607431768211455 255 0 18446744073709551616 5\n",
+		},
+		{
+			name:     "Arrays and maps",
+			code:     `let arr := [1, 2, 3]; arr[1] = 20; let m := $map[str]i32{"a" => 1}; m["a"] += 2; print("" + arr); print("" + m); print("" + m["a"]);`,
+			expected: "[1, 20, 3]\n{\"a\" => 3}\n3\n",
+		},
+		{
+			name:     "Structs, methods and interfaces",
+			code:     `type Point struct { x: i32, priv y: i32 }; type Shape interface { fn area() -> i32 }; impl Point { fn area() -> i32 { ret this.x * this.y; } fn twice() -> i32 { ret area() * 2; } } let p := @Point{x: 2, y: 3}; p.x = 5; let s : Shape = p; print("" + p.twice()); print("" + s.area()); print("" + p); print(typeof p);`,
+			expected: "30\n15\n@Point{x: 5, y: 3}\nPoint\n",
+		},
+		{
+			name:     "Casts and ranges",
+			code:     `let a := 7.9 as i32; let b := a as f32; let r := 1..a; print("" + a); print("" + (b / 2.0)); print("" + r);`,
+			expected: "7\n3.5\n1..7\n",
+		},
+	}
+
+	dir := t.TempDir()
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "_")+".go")
+			if e := os.WriteFile(file, []byte(generate(t, tt.code)), 0644); e != nil {
+				t.Fatal(e)
+			}
+			output, e := exec.Command(goTool, "run", file).CombinedOutput()
+			if e != nil {
+				t.Fatalf("Expected program %d to run, got %v\n%s", i, e, output)
+			}
+			if string(output) != tt.expected {
+				t.Errorf("Expected output %q, got %q", tt.expected, string(output))
+			}
+		})
+	}
+}
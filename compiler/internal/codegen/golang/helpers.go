@@ -0,0 +1,204 @@
+package golang
+
+// helper is a function the generated code calls for operations Go has no operator for.
+// Helpers are only emitted when the program uses them.
+type helper struct {
+	imports  []string
+	requires []string
+	source   string
+}
+
+var helpers = map[string]helper{
+	"walrusString": {
+		imports: []string{"fmt", "reflect", "sort", "strconv", "strings", "unsafe"},
+		source: `
+// walrusString formats a value the way walrus prints it.
+func walrusString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case fmt.Stringer:
+		return v.String()
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice:
+		values := make([]string, rv.Len())
+		for i := range values {
+			values[i] = walrusQuoted(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	case reflect.Map:
+		values := make([]string, 0, rv.Len())
+		for _, key := range rv.MapKeys() {
+			values = append(values, walrusQuoted(key.Interface())+" => "+walrusQuoted(rv.MapIndex(key).Interface()))
+		}
+		sort.Strings(values)
+		return "{" + strings.Join(values, ", ") + "}"
+	case reflect.Pointer:
+		if rv.Elem().Kind() != reflect.Struct {
+			break
+		}
+		structValue := rv.Elem()
+		name := structValue.Type().Name()
+		if name == "" {
+			name = "struct"
+		}
+		values := make([]string, structValue.NumField())
+		for i := range values {
+			// private fields are unexported, so they are read through their address
+			field := structValue.Field(i)
+			value := reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem().Interface()
+			values[i] = structValue.Type().Field(i).Tag.Get("walrus") + ": " + walrusQuoted(value)
+		}
+		return "@" + name + "{" + strings.Join(values, ", ") + "}"
+	case reflect.Func:
+		return "<fn>"
+	}
+	return fmt.Sprint(value)
+}
+
+// walrusQuoted formats a value inside an array, map or struct, with strings in quotes.
+func walrusQuoted(value any) string {
+	if s, ok := value.(string); ok {
+		return strconv.Quote(s)
+	}
+	return walrusString(value)
+}
+`,
+	},
+	"walrusPow": {
+		source: `
+// walrusPow raises an integer to a non negative integer power, wrapping around on overflow.
+func walrusPow[T ~int8 | ~int16 | ~int32 | ~int64 | ~uint8 | ~uint16 | ~uint32 | ~uint64](base, exp T) T {
+	if exp < 0 {
+		panic("negative integer exponent")
+	}
+	result := T(1)
+	for ; exp > 0; exp-- {
+		result *= base
+	}
+	return result
+}
+`,
+	},
+	"walrusValue": {
+		source: `
+// walrusValue hides a constant from Go, which rejects constant operations that overflow or
+// divide by zero where walrus wraps or fails at runtime.
+func walrusValue[T ~int8 | ~int16 | ~int32 | ~int64 | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~float32 | ~float64](value T) T {
+	return value
+}
+`,
+	},
+	"walrusRange": {
+		requires: []string{"walrusString"},
+		source: `
+// walrusRange is the value of a range expression.
+type walrusRange[S, E any] struct {
+	Start S
+	End   E
+}
+
+func (r walrusRange[S, E]) String() string {
+	return walrusString(r.Start) + ".." + walrusString(r.End)
+}
+`,
+	},
+	"walrusLookup": {
+		imports:  []string{"fmt"},
+		requires: []string{"walrusString"},
+		source: `
+// walrusLookup reads a map entry, failing like walrus does when the key is missing.
+func walrusLookup[K comparable, V any](m map[K]V, key K) V {
+	value, ok := m[key]
+	if !ok {
+		panic(fmt.Sprintf("key %s not found in map", walrusQuoted(key)))
+	}
+	return value
+}
+`,
+	},
+	"walrusBig": {
+		imports: []string{"math/big"},
+		source: `
+// walrusBig applies an operator to 128 bit integers, wrapping the result like fixed size integers.
+func walrusBig(op string, left, right *big.Int, signed bool) *big.Int {
+	result := new(big.Int)
+	switch op {
+	case "+":
+		result.Add(left, right)
+	case "-":
+		result.Sub(left, right)
+	case "*":
+		result.Mul(left, right)
+	case "/":
+		result.Quo(left, right)
+	case "%":
+		result.Rem(left, right)
+	case "**":
+		result.Exp(left, right, new(big.Int).Lsh(big.NewInt(1), 128))
+	case "&":
+		result.And(left, right)
+	case "|":
+		result.Or(left, right)
+	case "^":
+		result.Xor(left, right)
+	case "~":
+		result.Not(right)
+	case "<<", ">>":
+		if right.Sign() < 0 {
+			panic("negative shift count")
+		}
+		count := uint(128)
+		if right.Cmp(big.NewInt(128)) < 0 {
+			count = uint(right.Uint64())
+		}
+		if op == "<<" {
+			result.Lsh(left, count)
+		} else {
+			result.Rsh(left, count)
+		}
+	}
+	return walrusWrap(result, signed)
+}
+
+// walrusWrap truncates an integer to 128 bits.
+func walrusWrap(value *big.Int, signed bool) *big.Int {
+	modulus := new(big.Int).Lsh(big.NewInt(1), 128)
+	wrapped := new(big.Int).Mod(value, modulus)
+	if signed && wrapped.Cmp(new(big.Int).Rsh(modulus, 1)) >= 0 {
+		wrapped.Sub(wrapped, modulus)
+	}
+	return wrapped
+}
+
+// walrusLow returns the low 64 bits of an integer in two's complement.
+func walrusLow(value *big.Int) uint64 {
+	return new(big.Int).And(value, new(big.Int).SetUint64(^uint64(0))).Uint64()
+}
+
+// walrusBigFloat converts a 128 bit integer to a float.
+func walrusBigFloat(value *big.Int) float64 {
+	result, _ := new(big.Float).SetInt(value).Float64()
+	return result
+}
+
+// walrusFloatBig truncates a float to an integer.
+func walrusFloatBig(value float64) *big.Int {
+	result, _ := big.NewFloat(value).Int(nil)
+	return result
+}
+
+// walrusBigLiteral parses a 128 bit integer literal.
+func walrusBigLiteral(value string) *big.Int {
+	result, _ := new(big.Int).SetString(value, 10)
+	return result
+}
+`,
+	},
+}
@@ -0,0 +1,266 @@
+package golang
+
+import (
+	//Standard packages
+	"fmt"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/codegen"
+)
+
+func (g *Generator) statement(node ast.Node, scope *codegen.Scope, out *strings.Builder) {
+	switch t := node.(type) {
+	case ast.VarDeclStmt:
+		g.varDecl(t, scope, out)
+	case ast.TypeDeclStmt:
+		g.typeDecl(t)
+	case ast.FunctionDeclStmt:
+		g.functionDecl(t, scope, out)
+	case ast.IfStmt:
+		g.ifStmt(t, scope, out)
+	case ast.ForStmt:
+		g.forStmt(t, scope, out)
+	case ast.WhileStmt:
+		g.whileStmt(t, scope, out)
+	case ast.ReturnStmt:
+		if t.Value == nil {
+			out.WriteString("return\n")
+		} else {
+			fmt.Fprintf(out, "return %s\n", g.exprAs(t.Value, g.returnType, scope))
+		}
+	case ast.BreakStmt:
+		out.WriteString(g.loopJump("break", t.Label))
+	case ast.ContinueStmt:
+		out.WriteString(g.loopJump("continue", t.Label))
+	case ast.ImplStmt:
+		g.unsupported(t, "methods can only be implemented at the top level of a program")
+	case ast.ForEachStmt:
+		g.unsupported(t, "foreach loops cannot be generated yet")
+	case ast.MatchStmt:
+		g.unsupported(t, "match statements cannot be generated yet")
+	case ast.SafeStmt:
+		g.unsupported(t, "safe statements cannot be generated yet")
+	default:
+		fmt.Fprintf(out, "%s\n", g.simpleStatement(t, scope))
+	}
+}
+
+// simpleStatement generates an expression used as a statement. Go only allows calls,
+// assignments and increments as statements, any other value is discarded.
+func (g *Generator) simpleStatement(node ast.Node, scope *codegen.Scope) string {
+	switch t := node.(type) {
+	case ast.FunctionCallExpr:
+		return g.expr(t, scope)
+	case ast.VarAssignmentExpr:
+		return g.assignment(t, scope)
+	case ast.IncrementalInterface:
+		return g.increment(t, scope)
+	default:
+		return "_ = " + g.expr(node, scope)
+	}
+}
+
+// varDecl declares local variables. Walrus allows unused variables, Go does not.
+func (g *Generator) varDecl(node ast.VarDeclStmt, scope *codegen.Scope, out *strings.Builder) {
+	for _, variable := range node.Variables {
+		dtype, value := g.declare(variable, scope)
+		fmt.Fprintf(out, "var %s %s", name(variable.Identifier.Name), g.goType(dtype))
+		if value != "" {
+			fmt.Fprintf(out, " = %s", value)
+		}
+		fmt.Fprintf(out, "\n_ = %s\n", name(variable.Identifier.Name))
+	}
+}
+
+// globalVarDecl declares top level variables at the package level, so functions can use
+// them, and assigns them in 'main' in the order of the program.
+func (g *Generator) globalVarDecl(node ast.VarDeclStmt, scope *codegen.Scope, out *strings.Builder) {
+	for _, variable := range node.Variables {
+		dtype, value := g.declare(variable, scope)
+		fmt.Fprintf(&g.decls, "var %s %s\n\n", name(variable.Identifier.Name), g.goType(dtype))
+		if value != "" {
+			fmt.Fprintf(out, "%s = %s\n", name(variable.Identifier.Name), value)
+		}
+	}
+}
+
+// declare records the type of a variable, which is its explicit type or the type of its value.
+// It returns the type and the initial value, which is empty when the Go zero value matches.
+func (g *Generator) declare(variable ast.VarDeclStmtVar, scope *codegen.Scope) (ast.DataType, string) {
+	if variable.Destructure != nil {
+		g.unsupported(variable.Value, "destructuring cannot be generated yet")
+		return nil, ""
+	}
+	dtype := variable.ExplicitType
+	if dtype == nil {
+		dtype = g.typeOf(variable.Value)
+	}
+	if dtype == nil {
+		g.unsupported(variable.Identifier, fmt.Sprintf("cannot infer the type of '%s'", variable.Identifier.Name))
+	}
+
+	value := ""
+	if variable.Value == nil {
+		value = g.zero(dtype)
+	} else {
+		value = g.exprAs(variable.Value, dtype, scope)
+	}
+
+	scope.Declare(variable.Identifier.Name, dtype)
+	return dtype, value
+}
+
+// functionDecl declares a nested function. The variable is declared before the function
+// is assigned so the function can call itself.
+func (g *Generator) functionDecl(node ast.FunctionDeclStmt, scope *codegen.Scope, out *strings.Builder) {
+	dtype := codegen.FunctionTypeOf(node.FunctionLiteral)
+	scope.Declare(node.Identifier.Name, dtype)
+	fnName := name(node.Identifier.Name)
+	fmt.Fprintf(out, "var %s %s\n%s = %s\n_ = %s\n", fnName, g.goType(dtype), fnName, g.function(node.FunctionLiteral, scope), fnName)
+}
+
+// function generates a function literal. A function that returns a value ends with a
+// panic when its last statement is not a return, which Go requires and the typechecker
+// guarantees is never reached.
+func (g *Generator) function(node ast.FunctionLiteral, scope *codegen.Scope) string {
+	if len(node.TypeParams) > 0 {
+		g.unsupported(node, "generic functions cannot be generated yet")
+	}
+
+	fnType := codegen.FunctionTypeOf(node)
+
+	previous, previousLabels := g.returnType, g.labels
+	g.returnType, g.labels = codegen.ReturnType(fnType), make(map[string]bool)
+	defer func() { g.returnType, g.labels = previous, previousLabels }()
+
+	fnScope := codegen.NewScope(scope)
+	params := make([]string, len(node.Params))
+	for i, param := range node.Params {
+		fnScope.Declare(param.Identifier.Name, param.Type)
+		params[i] = name(param.Identifier.Name) + " " + g.goType(param.Type)
+	}
+
+	result := g.goType(fnType.ReturnType)
+	if result != "" {
+		result = " " + result
+	}
+
+	var body strings.Builder
+	g.block(node.Body, fnScope, &body)
+	if !codegen.IsVoid(g.returnType) {
+		contents := node.Body.Contents
+		if len(contents) == 0 {
+			body.WriteString("panic(\"unreachable\")\n")
+		} else if _, ok := contents[len(contents)-1].(ast.ReturnStmt); !ok {
+			body.WriteString("panic(\"unreachable\")\n")
+		}
+	}
+
+	return fmt.Sprintf("func(%s)%s {\n%s}", strings.Join(params, ", "), result, body.String())
+}
+
+func (g *Generator) block(node ast.BlockStmt, scope *codegen.Scope, out *strings.Builder) {
+	for _, item := range node.Contents {
+		g.statement(item, scope, out)
+	}
+}
+
+func (g *Generator) ifStmt(node ast.IfStmt, scope *codegen.Scope, out *strings.Builder) {
+	fmt.Fprintf(out, "if %s {\n", g.expr(node.Condition, scope))
+	g.block(node.Block, codegen.NewScope(scope), out)
+	switch t := node.AlternateBlock.(type) {
+	case ast.IfStmt:
+		out.WriteString("} else ")
+		g.ifStmt(t, scope, out)
+		return
+	case ast.BlockStmt:
+		out.WriteString("} else {\n")
+		g.block(t, codegen.NewScope(scope), out)
+	}
+	out.WriteString("}\n")
+}
+
+// forStmt generates a loop. A variable declared by the loop is initialized with a short
+// variable declaration, the only declaration Go allows there.
+func (g *Generator) forStmt(node ast.ForStmt, scope *codegen.Scope, out *strings.Builder) {
+	loopScope := codegen.NewScope(scope)
+
+	init := ""
+	switch t := node.Init.(type) {
+	case nil:
+	case ast.VarDeclStmt:
+		names := make([]string, len(t.Variables))
+		values := make([]string, len(t.Variables))
+		for i, variable := range t.Variables {
+			dtype, value := g.declare(variable, loopScope)
+			names[i] = name(variable.Identifier.Name)
+			switch {
+			case value == "":
+				values[i] = fmt.Sprintf("*new(%s)", g.goType(dtype))
+			case g.isUntyped(variable.Value):
+				values[i] = fmt.Sprintf("%s(%s)", g.goType(dtype), value)
+			default:
+				values[i] = value
+			}
+		}
+		init = strings.Join(names, ", ") + " := " + strings.Join(values, ", ")
+	default:
+		init = g.simpleStatement(t, loopScope)
+	}
+
+	condition := ""
+	if node.Condition != nil {
+		condition = g.expr(node.Condition, loopScope)
+	}
+
+	increment := ""
+	if node.Increment != nil {
+		increment = g.simpleStatement(node.Increment, loopScope)
+	}
+
+	g.loop(node.Label, fmt.Sprintf("for %s; %s; %s", init, condition, increment), node.Block, loopScope, out)
+}
+
+// whileStmt generates a 'while' loop as a loop with only a condition. A 'do-while' loop
+// checks its condition after the first iteration, which 'continue' starts like the others.
+func (g *Generator) whileStmt(node ast.WhileStmt, scope *codegen.Scope, out *strings.Builder) {
+	condition := g.expr(node.Condition, scope)
+	if node.IsDo {
+		g.loop(node.Label, fmt.Sprintf("for walrusFirst := true; walrusFirst || %s; walrusFirst = false", condition), node.Block, scope, out)
+		return
+	}
+	g.loop(node.Label, "for "+condition, node.Block, scope, out)
+}
+
+// loop writes a loop with its header, and with its label when a 'break' or a 'continue'
+// uses it.
+func (g *Generator) loop(label ast.IdentifierExpr, header string, block ast.BlockStmt, scope *codegen.Scope, out *strings.Builder) {
+	if label.Name != "" {
+		if _, declared := g.labels[label.Name]; declared {
+			g.unsupported(label, "a label used by two loops of a function cannot be generated yet")
+		}
+		g.labels[label.Name] = false
+	}
+
+	var loop strings.Builder
+	loop.WriteString(header + " {\n")
+	g.block(block, codegen.NewScope(scope), &loop)
+	loop.WriteString("}\n")
+
+	// Go does not allow a label no jump uses
+	if g.labels[label.Name] {
+		fmt.Fprintf(out, "%s:\n", name(label.Name))
+	}
+	out.WriteString(loop.String())
+}
+
+// loopJump generates a 'break' or a 'continue', which Go has with the same meaning.
+func (g *Generator) loopJump(keyword string, label ast.IdentifierExpr) string {
+	if label.Name == "" {
+		return keyword + "\n"
+	}
+	g.labels[label.Name] = true
+	return keyword + " " + name(label.Name) + "\n"
+}
@@ -0,0 +1,171 @@
+package golang
+
+import (
+	//Standard packages
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/builtins"
+)
+
+// reserved are the names a walrus identifier cannot keep in Go: keywords, predeclared
+// identifiers, the names of the packages the generated code imports and the entry points.
+var reserved = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true, "default": true,
+	"defer": true, "else": true, "fallthrough": true, "for": true, "func": true, "go": true,
+	"goto": true, "if": true, "import": true, "interface": true, "map": true, "package": true,
+	"range": true, "return": true, "select": true, "struct": true, "switch": true, "type": true, "var": true,
+
+	"any": true, "append": true, "bool": true, "byte": true, "cap": true, "clear": true, "close": true,
+	"comparable": true, "complex": true, "complex64": true, "complex128": true, "copy": true, "delete": true,
+	"error": true, "false": true, "float32": true, "float64": true, "imag": true, "int": true, "int8": true,
+	"int16": true, "int32": true, "int64": true, "iota": true, "len": true, "make": true, "max": true,
+	"min": true, "new": true, "nil": true, "panic": true, "print": true, "println": true, "real": true,
+	"recover": true, "rune": true, "string": true, "true": true, "uint": true, "uint8": true, "uint16": true,
+	"uint32": true, "uint64": true, "uintptr": true,
+
+	"big": true, "fmt": true, "math": true, "reflect": true, "sort": true, "strconv": true, "strings": true,
+	"main": true, "init": true,
+}
+
+// name returns the Go identifier of a walrus variable, function or type.
+func name(identifier string) string {
+	if reserved[identifier] || strings.HasPrefix(identifier, "walrus") {
+		return identifier + "_"
+	}
+	return identifier
+}
+
+// memberName returns the Go name of a struct field or method. Public members are
+// exported and private members keep their name, so 'priv' members are unexported.
+func memberName(identifier string, isPrivate bool) string {
+	if isPrivate {
+		return name(identifier)
+	}
+	r, size := utf8.DecodeRuneInString(identifier)
+	return string(unicode.ToUpper(r)) + identifier[size:]
+}
+
+// goType returns the Go type of a walrus type. Structs are used through pointers
+// because walrus structs are references.
+func (g *Generator) goType(dtype ast.DataType) string {
+	switch t := dtype.(type) {
+	case nil, ast.VoidType:
+		return ""
+	case ast.IntegerType:
+		if t.BitSize > 64 {
+			g.imports["math/big"] = true
+			return "*big.Int"
+		}
+		if t.IsSigned {
+			return fmt.Sprintf("int%d", t.BitSize)
+		}
+		return fmt.Sprintf("uint%d", t.BitSize)
+	case ast.FloatType:
+		return fmt.Sprintf("float%d", t.BitSize)
+	case ast.StringType:
+		return "string"
+	case ast.BooleanType:
+		return "bool"
+	case ast.ArrayType:
+		return "[]" + g.goType(t.ArrayType)
+	case ast.StructType:
+		return "*" + g.structType(t)
+	case ast.InterfaceType:
+		return g.interfaceType(t)
+	case ast.FunctionType:
+		return "func" + g.signature(t)
+	case ast.MapType:
+		if t.Map.Name != "map" && t.Map.Name != "" {
+			return name(t.Map.Name)
+		}
+		return fmt.Sprintf("map[%s]%s", g.goType(t.KeyType), g.goType(t.ValueType))
+	case ast.RangeType:
+		g.use("walrusRange")
+		return fmt.Sprintf("walrusRange[%s, %s]", g.goType(t.RangeStart), g.goType(t.RangeEnd))
+	case ast.UserDefinedType:
+		if t.AliasName == builtins.BYTE {
+			return "uint8"
+		}
+		if def, _ := g.declared.Types.Underlying(t); def != nil {
+			if _, ok := def.(ast.StructType); ok {
+				return "*" + name(t.AliasName)
+			}
+		}
+		return name(t.AliasName)
+	default:
+		return "any"
+	}
+}
+
+// structType returns a Go struct type. Fields are tagged with their walrus name,
+// which is the name used when the struct is printed.
+func (g *Generator) structType(t ast.StructType) string {
+	var fields strings.Builder
+	fields.WriteString("struct {\n")
+	for _, prop := range t.Properties {
+		fmt.Fprintf(&fields, "%s %s `walrus:%q`\n", memberName(prop.Prop.Name, prop.IsPrivate), g.goType(prop.PropType), prop.Prop.Name)
+	}
+	fields.WriteString("}")
+	return fields.String()
+}
+
+func (g *Generator) interfaceType(t ast.InterfaceType) string {
+	var methods strings.Builder
+	methods.WriteString("interface {\n")
+	for _, method := range t.Methods {
+		fmt.Fprintf(&methods, "%s%s\n", memberName(method.Identifier.Name, false), g.signature(method.FunctionType))
+	}
+	methods.WriteString("}")
+	return methods.String()
+}
+
+// signature returns the parameters and the result of a function type.
+func (g *Generator) signature(t ast.FunctionType) string {
+	params := make([]string, len(t.Parameters))
+	for i, param := range t.Parameters {
+		params[i] = g.goType(param.Type)
+	}
+	result := g.goType(t.ReturnType)
+	if result != "" {
+		result = " " + result
+	}
+	return "(" + strings.Join(params, ", ") + ")" + result
+}
+
+// zero returns the Go expression of the value a variable of the type holds before it is
+// assigned, or an empty string when the Go zero value already matches it.
+func (g *Generator) zero(dtype ast.DataType) string {
+	def, _ := g.declared.Types.Underlying(dtype)
+	switch t := def.(type) {
+	case ast.IntegerType:
+		if t.BitSize > 64 {
+			return "new(big.Int)"
+		}
+	case ast.StructType:
+		return "&" + strings.TrimPrefix(g.goType(dtype), "*") + "{" + g.fields(t, nil) + "}"
+	case ast.MapType:
+		return g.goType(dtype) + "{}"
+	}
+	return ""
+}
+
+// fields returns the keyed elements of a struct literal. Fields without a value get
+// the value they would have as variables.
+func (g *Generator) fields(t ast.StructType, values map[string]string) string {
+	elements := make([]string, 0, len(t.Properties))
+	for _, prop := range t.Properties {
+		value, ok := values[prop.Prop.Name]
+		if !ok {
+			value = g.zero(prop.PropType)
+		}
+		if value != "" {
+			elements = append(elements, memberName(prop.Prop.Name, prop.IsPrivate)+": "+value)
+		}
+	}
+	return strings.Join(elements, ", ")
+}
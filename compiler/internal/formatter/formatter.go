@@ -0,0 +1,172 @@
+package formatter
+
+import (
+	//Standard packages
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// indent is the indentation of one nesting level.
+const indent = "    "
+
+// state is where a line ends: in code, in a multi line comment or in a raw string.
+type state int
+
+const (
+	inCode state = iota
+	inComment
+	inRawString
+)
+
+// Format lays out walrus source code: every line is indented by the nesting of the brackets
+// around it, trailing whitespace is removed and runs of blank lines are collapsed into one.
+// The tokens of a line are left as they are. Source with unbalanced brackets is an error.
+func Format(src []byte) ([]byte, error) {
+
+	var out bytes.Buffer
+
+	lines := strings.Split(strings.ReplaceAll(string(src), "\r\n", "\n"), "\n")
+
+	depth := 0
+	at := inCode   // where the previous line ended
+	blank := false // a blank line is waiting to be written
+
+	for i, line := range lines {
+
+		//the lines of a multi line comment are kept as they are, and raw strings with their spaces
+		if at != inCode {
+			if at == inComment {
+				line = strings.TrimRight(line, " \t")
+			}
+			out.WriteString(line + "\n")
+			change, _, end := scan(line, at)
+			depth += change
+			at = end
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			blank = out.Len() > 0
+			continue
+		}
+		if blank {
+			out.WriteString("\n")
+			blank = false
+		}
+
+		change, closers, end := scan(trimmed, inCode)
+
+		level := depth - closers
+		if level < 0 {
+			return nil, fmt.Errorf("%d:1: unexpected closing bracket", i+1)
+		}
+
+		out.WriteString(strings.Repeat(indent, level) + trimmed + "\n")
+
+		depth += change
+		if depth < 0 {
+			return nil, fmt.Errorf("%d:1: unexpected closing bracket", i+1)
+		}
+		at = end
+	}
+
+	if depth != 0 {
+		return nil, fmt.Errorf("%d unclosed bracket(s) at the end of the file", depth)
+	}
+
+	return out.Bytes(), nil
+}
+
+// scan returns how much a line starting at the given state changes the bracket nesting, the
+// number of closing brackets it starts with and the state it ends at. Brackets in strings,
+// byte literals and comments are not counted.
+func scan(line string, at state) (change int, closers int, end state) {
+
+	leading := true
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+
+		switch at {
+		case inComment:
+			if c == '*' && i+1 < len(line) && line[i+1] == '/' {
+				at = inCode
+				i++
+			}
+			continue
+		case inRawString:
+			if c == '`' {
+				at = inCode
+			}
+			continue
+		}
+
+		switch c {
+		case '/':
+			if i+1 < len(line) && line[i+1] == '/' {
+				return change, closers, inCode
+			}
+			if i+1 < len(line) && line[i+1] == '*' {
+				at = inComment
+				i++
+			}
+		case '`':
+			at = inRawString
+		case '"', '\'':
+			i = skipLiteral(line, i)
+		case '(', '[', '{':
+			change++
+		case ')', ']', '}':
+			change--
+			if leading {
+				closers++
+			}
+			continue
+		case ' ', '\t':
+			continue
+		}
+		leading = false
+	}
+
+	return change, closers, at
+}
+
+// skipLiteral returns the index of the quote closing the literal starting at i.
+func skipLiteral(line string, i int) int {
+	quote := line[i]
+	for i++; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case quote:
+			return i
+		case '{':
+			if quote == '"' {
+				i = skipInterpolation(line, i)
+			}
+		}
+	}
+	return i
+}
+
+// skipInterpolation returns the index of the brace closing the value put in a string at i,
+// like '{name}' in "hello {name}". The value can have strings and braces of its own.
+func skipInterpolation(line string, i int) int {
+	depth := 0
+	for ; i < len(line); i++ {
+		switch line[i] {
+		case '"', '\'':
+			i = skipLiteral(line, i)
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return i
+}
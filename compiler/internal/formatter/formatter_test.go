@@ -0,0 +1,73 @@
+package formatter
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			name:     "Indents blocks",
+			src:      "fn add(a: i32, b: i32) -> i32 {\nret a + b;\n}\n",
+			expected: "fn add(a: i32, b: i32) -> i32 {\n    ret a + b;\n}\n",
+		},
+		{
+			name:     "Closing brackets start a line",
+			src:      "if a {\n  print(\"a\");\n    } else {\nprint(\"b\");\n}",
+			expected: "if a {\n    print(\"a\");\n} else {\n    print(\"b\");\n}\n",
+		},
+		{
+			name:     "Nested literals",
+			src:      "let p := @Point{\nx: foo(\n1,\n2),\ny: 1\n};",
+			expected: "let p := @Point{\n    x: foo(\n        1,\n        2),\n    y: 1\n};\n",
+		},
+		{
+			name:     "Blank lines and trailing whitespace",
+			src:      "\n\nlet a := 1;   \n\n\n\nlet b := 2;\n\n",
+			expected: "let a := 1;\n\nlet b := 2;\n",
+		},
+		{
+			name:     "Brackets in strings and comments",
+			src:      "fn f() {\nlet s := \"{ (\"; // }\nlet c := '{';\n/* {\n   kept as is\n*/\n}",
+			expected: "fn f() {\n    let s := \"{ (\"; // }\n    let c := '{';\n    /* {\n   kept as is\n*/\n}\n",
+		},
+		{
+			name:     "Raw strings and interpolations",
+			src:      "fn f() {\nlet s := `a {\n  kept as is }\n`;\nlet t := \"{g(\"}\")} {x}\";\n}",
+			expected: "fn f() {\n    let s := `a {\n  kept as is }\n`;\n    let t := \"{g(\"}\")} {x}\";\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Format([]byte(tt.src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("Expected\n%q\ngot\n%q", tt.expected, string(got))
+			}
+		})
+	}
+}
+
+func TestFormatUnbalanced(t *testing.T) {
+	for _, src := range []string{"fn f() {\n", "}\n", "let a := (1));"} {
+		if _, err := Format([]byte(src)); err == nil {
+			t.Errorf("Expected an error for %q", src)
+		}
+	}
+}
+
+func TestFormatIsStable(t *testing.T) {
+	src := "type Point struct {\n    x: i32,\n    y: i32\n};\n\nimpl Point {\n    fn sum() -> i32 {\n        ret this.x + this.y;\n    }\n}\n"
+	got, err := Format([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != src {
+		t.Errorf("Expected formatted source to be unchanged, got\n%s", got)
+	}
+}
@@ -0,0 +1,41 @@
+package interpreter
+
+import (
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/values"
+)
+
+func (interp *Interpreter) evaluateArrayLiteral(node ast.ArrayLiteral, env *Environment) values.Value {
+	elements := make([]values.Value, len(node.Values))
+	for i, value := range node.Values {
+		elements[i] = interp.evaluate(value, env)
+	}
+	elementType := "void"
+	if len(elements) > 0 {
+		elementType = values.TypeName(elements[0])
+	}
+	return values.NewArray(elementType, elements)
+}
+
+// evaluateIndexableAccess reads an array element, a byte of a string or a map value.
+func (interp *Interpreter) evaluateIndexableAccess(node ast.Indexable, env *Environment) values.Value {
+	container := interp.evaluate(node.Container, env)
+	index := interp.evaluate(node.Index, env)
+
+	value, err := values.Index(container, index)
+	if err != nil {
+		interp.runtimeError(node.Index, err.Error())
+	}
+	return value
+}
+
+// assignIndex stores a value in an array element or a map entry.
+func (interp *Interpreter) assignIndex(node ast.Indexable, value values.Value, env *Environment) {
+	container := interp.evaluate(node.Container, env)
+	index := interp.evaluate(node.Index, env)
+
+	if err := values.SetIndex(container, index, value); err != nil {
+		interp.runtimeError(node, err.Error())
+	}
+}
@@ -0,0 +1,41 @@
+package interpreter
+
+import (
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/values"
+)
+
+// isTruthy evaluates a condition. The typechecker guarantees conditions are booleans.
+func (interp *Interpreter) isTruthy(node ast.Node, env *Environment) bool {
+	value := interp.evaluate(node, env)
+	condition, ok := value.(values.Bool)
+	if !ok {
+		interp.runtimeError(node, "condition must be a boolean expression, got '"+values.TypeName(value)+"'")
+	}
+	return condition.Value
+}
+
+// executeIfStmt runs the first branch whose condition holds. Like the typechecker,
+// the branches share the scope of the if statement.
+func (interp *Interpreter) executeIfStmt(node ast.IfStmt, env *Environment) values.Value {
+	if interp.isTruthy(node.Condition, env) {
+		return interp.executeBlock(node.Block, env)
+	}
+	switch t := node.AlternateBlock.(type) {
+	case ast.IfStmt:
+		return interp.executeIfStmt(t, env)
+	case ast.BlockStmt:
+		return interp.executeBlock(t, env)
+	}
+	return values.NewVoid()
+}
+
+// executeSafeStmt runs the safe block when the variable is not null and the otherwise block
+// when it is. Each block has its own scope.
+func (interp *Interpreter) executeSafeStmt(node ast.SafeStmt, env *Environment) values.Value {
+	if _, ok := interp.evaluate(node.Value, env).(values.Null); !ok {
+		return interp.executeBlock(node.SafeBlock, NewEnvironment(env))
+	}
+	return interp.executeBlock(node.UnsafeBlock, NewEnvironment(env))
+}
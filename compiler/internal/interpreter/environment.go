@@ -0,0 +1,56 @@
+package interpreter
+
+import (
+	//Standard packages
+	"fmt"
+
+	//Walrus packages
+	"walrus/compiler/internal/values"
+)
+
+// Environment holds the runtime values of a scope. Scopes are created the same way the
+// typechecker creates them: one for the program, one per function call and one per loop.
+type Environment struct {
+	parent *Environment
+	values map[string]values.Value
+}
+
+func NewEnvironment(parent *Environment) *Environment {
+	return &Environment{
+		parent: parent,
+		values: make(map[string]values.Value),
+	}
+}
+
+// declare binds a value to a name in this scope. Redeclaring overwrites the old value, which
+// happens when a loop body runs more than once.
+func (e *Environment) declare(name string, value values.Value) {
+	e.values[name] = value
+}
+
+func (e *Environment) resolve(name string) (*Environment, error) {
+	if _, ok := e.values[name]; ok {
+		return e, nil
+	}
+	if e.parent == nil {
+		return nil, fmt.Errorf("'%s' was not declared in this scope", name)
+	}
+	return e.parent.resolve(name)
+}
+
+func (e *Environment) get(name string) (values.Value, error) {
+	scope, err := e.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	return scope.values[name], nil
+}
+
+func (e *Environment) assign(name string, value values.Value) error {
+	scope, err := e.resolve(name)
+	if err != nil {
+		return err
+	}
+	scope.values[name] = value
+	return nil
+}
@@ -0,0 +1,128 @@
+package interpreter
+
+import (
+	//Standard packages
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/lexer"
+	"walrus/compiler/internal/values"
+)
+
+func (interp *Interpreter) evaluateIntegerLiteral(node ast.IntegerLiteralExpr) values.Value {
+	value, ok := new(big.Int).SetString(node.Value, 10)
+	if !ok {
+		interp.runtimeError(node, fmt.Sprintf("invalid integer literal '%s'", node.Value))
+	}
+	return values.Int{Value: values.WrapInt(value, node.BitSize, node.IsSigned), BitSize: node.BitSize, IsSigned: node.IsSigned}
+}
+
+func (interp *Interpreter) evaluateFloatLiteral(node ast.FloatLiteralExpr) values.Value {
+	value, err := strconv.ParseFloat(node.Value, 64)
+	if err != nil {
+		interp.runtimeError(node, fmt.Sprintf("invalid float literal '%s'", node.Value))
+	}
+	return values.NewFloat(value, node.BitSize)
+}
+
+// evaluateInterpolatedString writes the values of a string like "hello {name}" the way they
+// are printed.
+func (interp *Interpreter) evaluateInterpolatedString(node ast.InterpolatedStringExpr, env *Environment) values.Value {
+	var text strings.Builder
+	for _, part := range node.Parts {
+		text.WriteString(interp.evaluate(part, env).String())
+	}
+	return values.NewStr(text.String())
+}
+
+func (interp *Interpreter) evaluateBinaryExpr(node ast.BinaryExpr, env *Environment) values.Value {
+	left := interp.evaluate(node.Left, env)
+	// '&&' and '||' only evaluate the right operand when the left one does not decide the result
+	if condition, ok := left.(values.Bool); ok {
+		if (node.Binop.Kind == lexer.AND_TOKEN && !condition.Value) || (node.Binop.Kind == lexer.OR_TOKEN && condition.Value) {
+			return condition
+		}
+	}
+	right := interp.evaluate(node.Right, env)
+	return interp.binaryOperation(node.Binop, left, right, node)
+}
+
+// binaryOperation applies a binary operator and reports a failed operation as a runtime error.
+func (interp *Interpreter) binaryOperation(op lexer.Token, left, right values.Value, node ast.Node) values.Value {
+	result, err := values.BinaryOperation(op.Kind, left, right)
+	if err != nil {
+		interp.runtimeError(node, err.Error())
+	}
+	return result
+}
+
+func (interp *Interpreter) evaluateUnaryExpr(node ast.UnaryExpr, env *Environment) values.Value {
+	value := interp.evaluate(node.Argument, env)
+	result, err := values.UnaryOperation(node.Operator.Kind, value)
+	if err != nil {
+		interp.runtimeError(node, err.Error())
+	}
+	return result
+}
+
+// evaluateIncrementalExpr evaluates ++ and -- in prefix and postfix form.
+// The prefix form evaluates to the updated value and the postfix form to the old one.
+func (interp *Interpreter) evaluateIncrementalExpr(node ast.IncrementalInterface, env *Environment) values.Value {
+	arg := node.Arg()
+	old := interp.evaluate(arg, env)
+
+	op := node.Op()
+	if op.Kind == lexer.PLUS_PLUS_TOKEN {
+		op.Kind = lexer.PLUS_TOKEN
+	} else {
+		op.Kind = lexer.MINUS_TOKEN
+	}
+
+	updated := interp.binaryOperation(op, old, values.NewInt(1, 32, true), arg)
+	if err := env.assign(arg.Name, updated); err != nil {
+		interp.runtimeError(arg, err.Error())
+	}
+
+	if _, ok := node.(ast.PrefixExpr); ok {
+		return updated
+	}
+	return old
+}
+
+// evaluateTypeCast converts a value with the 'as' operator. Numbers are converted between
+// sizes, structs are copied into the target struct type and everything else is unchanged.
+func (interp *Interpreter) evaluateTypeCast(node ast.TypeCastExpr, env *Environment) values.Value {
+	value := interp.evaluate(node.Expression, env)
+
+	def, name := interp.types.Underlying(node.ToCast)
+
+	var result values.Value
+	var err error
+
+	switch t := def.(type) {
+	case ast.IntegerType:
+		result, err = values.ToInt(value, t.BitSize, t.IsSigned)
+	case ast.FloatType:
+		result, err = values.ToFloat(value, t.BitSize)
+	case ast.StructType:
+		fields := make([]string, len(t.Properties))
+		for i, prop := range t.Properties {
+			fields[i] = prop.Prop.Name
+		}
+		if name != "" {
+			name = interp.types.StructName(name)
+		}
+		result, err = values.CastStruct(value, name, fields)
+	default:
+		result = value
+	}
+
+	if err != nil {
+		interp.runtimeError(node, err.Error())
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+package interpreter
+
+import (
+	//Standard packages
+	"fmt"
+	"math/big"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/values"
+)
+
+// executeForStmt runs a loop in its own scope. A loop without a condition runs until it returns.
+func (interp *Interpreter) executeForStmt(node ast.ForStmt, env *Environment) values.Value {
+	loopEnv := NewEnvironment(env)
+
+	if node.Init != nil {
+		interp.execute(node.Init, loopEnv)
+	}
+
+	for node.Condition == nil || interp.isTruthy(node.Condition, loopEnv) {
+		if stop, result := loopControl(interp.executeBlock(node.Block, loopEnv), node.Label.Name); stop {
+			return result
+		}
+		if node.Increment != nil {
+			interp.evaluate(node.Increment, loopEnv)
+		}
+	}
+	return values.NewVoid()
+}
+
+// executeWhileStmt runs a 'while' loop, or a 'do-while' loop, which runs its block once
+// before checking the condition.
+func (interp *Interpreter) executeWhileStmt(node ast.WhileStmt, env *Environment) values.Value {
+	loopEnv := NewEnvironment(env)
+
+	for first := node.IsDo; first || interp.isTruthy(node.Condition, env); first = false {
+		if stop, result := loopControl(interp.executeBlock(node.Block, loopEnv), node.Label.Name); stop {
+			return result
+		}
+	}
+	return values.NewVoid()
+}
+
+// executeForEachStmt runs a loop over the elements of an array, the entries of a map or the
+// numbers of a range, from its start up to its end, which is left out. Every iteration has
+// its own scope holding the loop variables.
+func (interp *Interpreter) executeForEachStmt(node ast.ForEachStmt, env *Environment) values.Value {
+
+	iteration := func(key, value values.Value) (bool, values.Value) {
+		loopEnv := NewEnvironment(env)
+		if node.Key != nil {
+			loopEnv.declare(node.Key.(ast.IdentifierExpr).Name, key)
+		}
+		loopEnv.declare(node.Value.(ast.IdentifierExpr).Name, value)
+		return loopControl(interp.executeBlock(node.Block, loopEnv), node.Label.Name)
+	}
+
+	switch iterable := interp.evaluate(node.Iterable, env).(type) {
+	case *values.Array:
+		for i := 0; i < len(iterable.Values); i++ {
+			if stop, result := iteration(values.NewInt(int64(i), 32, true), iterable.Values[i]); stop {
+				return result
+			}
+		}
+	case *values.Map:
+		// entries added by the loop are not visited
+		for _, hash := range append([]string{}, iterable.Order...) {
+			entry := iterable.Entries[hash]
+			if stop, result := iteration(entry.Key, entry.Value); stop {
+				return result
+			}
+		}
+	case values.Range:
+		start, end := iterable.Start.(values.Int), iterable.End.(values.Int)
+		index := int64(0)
+		for n := new(big.Int).Set(start.Value); n.Cmp(end.Value) < 0; n.Add(n, big.NewInt(1)) {
+			value := values.Int{Value: new(big.Int).Set(n), BitSize: start.BitSize, IsSigned: start.IsSigned}
+			if stop, result := iteration(values.NewInt(index, 32, true), value); stop {
+				return result
+			}
+			index++
+		}
+	default:
+		interp.runtimeError(node.Iterable, fmt.Sprintf("cannot iterate over '%s'", values.TypeName(iterable)))
+	}
+
+	return values.NewVoid()
+}
+
+// loopControl tells a loop what to do after an iteration whose body gave the result. The
+// loop stops at a 'ret', and at a 'break' of its own; a 'break' or a 'continue' of an outer
+// loop stops it too and travels up. It gives the value the loop returns when it stops.
+func loopControl(result values.Value, label string) (bool, values.Value) {
+	switch t := result.(type) {
+	case Return:
+		return true, t
+	case LoopJump:
+		if t.Label != "" && t.Label != label {
+			return true, t
+		}
+		if t.IsContinue {
+			return false, nil
+		}
+		return true, values.NewVoid()
+	}
+	return false, nil
+}
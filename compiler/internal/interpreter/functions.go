@@ -0,0 +1,73 @@
+package interpreter
+
+import (
+	//Standard packages
+	"fmt"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/values"
+)
+
+func (interp *Interpreter) evaluateFunctionCall(node ast.FunctionCallExpr, env *Environment) values.Value {
+	caller := interp.evaluate(node.Caller, env)
+
+	args := make([]values.Value, len(node.Arguments))
+	for i, arg := range node.Arguments {
+		args[i] = interp.evaluate(arg, env)
+	}
+
+	return interp.callFunction(caller, args, node)
+}
+
+// callFunction calls a function value with already evaluated arguments. Methods run in a scope
+// where 'this' and the other methods of the struct are declared. An error passed up with '?'
+// in the body is returned as the result of the call.
+func (interp *Interpreter) callFunction(caller values.Value, args []values.Value, node ast.Node) (result values.Value) {
+	switch fn := caller.(type) {
+	case *Builtin:
+		return fn.Call(interp, args)
+	case *Fn:
+		defer func() {
+			if r := recover(); r != nil {
+				p, ok := r.(propagation)
+				if !ok {
+					panic(r)
+				}
+				result = p.err
+			}
+		}()
+
+		if len(args) != len(fn.Literal.Params) {
+			interp.runtimeError(node, fmt.Sprintf("function '%s' expects %d arguments, got %d", fn.Name, len(fn.Literal.Params), len(args)))
+		}
+
+		interp.callDepth++
+		defer func() { interp.callDepth-- }()
+		if interp.callDepth > maxCallDepth {
+			interp.runtimeError(node, fmt.Sprintf("maximum call depth of %d exceeded", maxCallDepth))
+		}
+
+		scope := fn.Closure
+		if fn.This != nil {
+			scope = NewEnvironment(scope)
+			for name, method := range interp.methods[fn.This.StructName] {
+				scope.declare(name, &Fn{Name: method.Name, Literal: method.Literal, Closure: method.Closure, This: fn.This})
+			}
+			scope.declare("this", fn.This)
+		}
+
+		fnEnv := NewEnvironment(scope)
+		for i, param := range fn.Literal.Params {
+			fnEnv.declare(param.Identifier.Name, args[i])
+		}
+
+		if ret, ok := interp.executeBlock(fn.Literal.Body, fnEnv).(Return); ok {
+			return ret.Value
+		}
+		return values.NewVoid()
+	default:
+		interp.runtimeError(node, fmt.Sprintf("cannot call a value of type '%s'", values.TypeName(caller)))
+		return values.NewVoid()
+	}
+}
@@ -0,0 +1,185 @@
+package interpreter
+
+import (
+	//Standard packages
+	"fmt"
+	"io"
+	"math"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/values"
+	"walrus/compiler/report"
+)
+
+// maxCallDepth limits recursion so a runaway program reports an error instead of crashing the compiler.
+const maxCallDepth = 10000
+
+type Interpreter struct {
+	filePath  string
+	out       io.Writer
+	globals   *Environment
+	types     values.TypeTable
+	methods   map[string]map[string]*Fn
+	callDepth int
+}
+
+func NewInterpreter(filePath string, out io.Writer) *Interpreter {
+	interp := &Interpreter{
+		filePath: filePath,
+		out:      out,
+		types:    make(values.TypeTable),
+		methods:  make(map[string]map[string]*Fn),
+	}
+	interp.globals = interp.programEnv()
+	return interp
+}
+
+// programEnv creates the global scope with the same builtin values the typechecker declares.
+func (interp *Interpreter) programEnv() *Environment {
+	env := NewEnvironment(nil)
+	env.declare("true", values.NewBool(true))
+	env.declare("false", values.NewBool(false))
+	env.declare("PI", values.NewFloat(math.Pi, 32))
+	env.declare("print", &Builtin{
+		Name: "print",
+		Call: func(interp *Interpreter, args []values.Value) values.Value {
+			fmt.Fprintln(interp.out, args[0].String())
+			return values.NewVoid()
+		},
+	})
+	return env
+}
+
+// Run executes a type checked program. The program output is written to out.
+// Runtime errors are reported with the RUNTIME_ERROR level, which halts the execution.
+func Run(program ast.Node, filePath string, out io.Writer) {
+	interp := NewInterpreter(filePath, out)
+	interp.execute(program, interp.globals)
+}
+
+func (interp *Interpreter) runtimeError(node ast.Node, msg string) {
+	report.Add(interp.filePath, node.StartPos().Line, node.EndPos().Line, node.StartPos().Column, node.EndPos().Column, msg).SetLevel(report.RUNTIME_ERROR)
+}
+
+// execute runs a statement. It returns a Return value when a 'ret' statement was executed,
+// so the enclosing function call can stop running its body, and a LoopJump value for
+// 'break' and 'continue', so the enclosing loop can stop or skip its iteration.
+func (interp *Interpreter) execute(node ast.Node, env *Environment) values.Value {
+	switch t := node.(type) {
+	case ast.ProgramStmt:
+		for _, item := range t.Contents {
+			interp.execute(item, env)
+		}
+		return values.NewVoid()
+	case ast.VarDeclStmt:
+		return interp.executeVariableDeclaration(t, env)
+	case ast.TypeDeclStmt:
+		interp.types[t.UDTypeName.Name] = t.UDTypeValue
+		return values.NewVoid()
+	case ast.ImplStmt:
+		return interp.executeImplStmt(t, env)
+	case ast.FunctionDeclStmt:
+		env.declare(t.Identifier.Name, &Fn{Name: t.Identifier.Name, Literal: t.FunctionLiteral, Closure: env})
+		return values.NewVoid()
+	case ast.IfStmt:
+		return interp.executeIfStmt(t, env)
+	case ast.ForStmt:
+		return interp.executeForStmt(t, env)
+	case ast.ForEachStmt:
+		return interp.executeForEachStmt(t, env)
+	case ast.WhileStmt:
+		return interp.executeWhileStmt(t, env)
+	case ast.MatchStmt:
+		return interp.executeMatchStmt(t, env)
+	case ast.SafeStmt:
+		return interp.executeSafeStmt(t, env)
+	case ast.ReturnStmt:
+		if t.Value == nil {
+			return Return{Value: values.NewVoid()}
+		}
+		return Return{Value: interp.evaluate(t.Value, env)}
+	case ast.BreakStmt:
+		return LoopJump{Label: t.Label.Name}
+	case ast.ContinueStmt:
+		return LoopJump{Label: t.Label.Name, IsContinue: true}
+	default:
+		return interp.evaluate(t, env)
+	}
+}
+
+// executeBlock runs the statements of a block in the given scope and stops at the first 'ret',
+// 'break' or 'continue'.
+func (interp *Interpreter) executeBlock(block ast.BlockStmt, env *Environment) values.Value {
+	for _, stmt := range block.Contents {
+		switch result := interp.execute(stmt, env).(type) {
+		case Return, LoopJump:
+			return result
+		}
+	}
+	return values.NewVoid()
+}
+
+func (interp *Interpreter) evaluate(node ast.Node, env *Environment) values.Value {
+	switch t := node.(type) {
+	case ast.VarAssignmentExpr:
+		return interp.evaluateAssignment(t, env)
+	case ast.TypeofExpr:
+		return values.NewStr(values.TypeName(interp.evaluate(t.Expression, env)))
+	case ast.TypeCastExpr:
+		return interp.evaluateTypeCast(t, env)
+	case ast.IdentifierExpr:
+		value, err := env.get(t.Name)
+		if err != nil {
+			interp.runtimeError(t, err.Error())
+		}
+		return value
+	case ast.IntegerLiteralExpr:
+		return interp.evaluateIntegerLiteral(t)
+	case ast.FloatLiteralExpr:
+		return interp.evaluateFloatLiteral(t)
+	case ast.StringLiteralExpr:
+		return values.NewStr(t.Value)
+	case ast.InterpolatedStringExpr:
+		return interp.evaluateInterpolatedString(t, env)
+	case ast.TupleLiteral:
+		elements := make([]values.Value, len(t.Values))
+		for i, value := range t.Values {
+			elements[i] = interp.evaluate(value, env)
+		}
+		return &values.Tuple{Values: elements}
+	case ast.ByteLiteralExpr:
+		return values.NewInt(int64(t.Value[0]), 8, false)
+	case ast.NullLiteralExpr:
+		return values.Null{}
+	case ast.ResultExpr:
+		return interp.evaluateResultExpr(t, env)
+	case ast.PropagateExpr:
+		return interp.evaluatePropagateExpr(t, env)
+	case ast.BinaryExpr:
+		return interp.evaluateBinaryExpr(t, env)
+	case ast.UnaryExpr:
+		return interp.evaluateUnaryExpr(t, env)
+	case ast.IncrementalInterface:
+		return interp.evaluateIncrementalExpr(t, env)
+	case ast.RangeExpr:
+		return values.Range{Start: interp.evaluate(t.Start, env), End: interp.evaluate(t.End, env)}
+	case ast.ArrayLiteral:
+		return interp.evaluateArrayLiteral(t, env)
+	case ast.Indexable:
+		return interp.evaluateIndexableAccess(t, env)
+	case ast.StructLiteral:
+		return interp.evaluateStructLiteral(t, env)
+	case ast.StructPropertyAccessExpr:
+		return interp.evaluatePropertyAccess(t, env)
+	case ast.MapLiteral:
+		return interp.evaluateMapLiteral(t, env)
+	case ast.FunctionLiteral:
+		return &Fn{Name: "anonymous", Literal: t, Closure: env}
+	case ast.FunctionCallExpr:
+		return interp.evaluateFunctionCall(t, env)
+	default:
+		interp.runtimeError(node, fmt.Sprintf("<%T> node cannot be executed yet", node))
+		return values.NewVoid()
+	}
+}
@@ -0,0 +1,24 @@
+package interpreter
+
+import (
+	//Standard packages
+	"fmt"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/values"
+)
+
+func (interp *Interpreter) evaluateMapLiteral(node ast.MapLiteral, env *Environment) values.Value {
+	def, _ := interp.types.Underlying(node.MapType)
+	mapType, ok := def.(ast.MapType)
+	if !ok {
+		interp.runtimeError(node, fmt.Sprintf("'%s' is not a map", node.MapType.Map.Name))
+	}
+
+	value := values.NewMap(interp.types.Name(mapType.KeyType), interp.types.Name(mapType.ValueType))
+	for _, prop := range node.Values {
+		value.Set(interp.evaluate(prop.Key, env), interp.evaluate(prop.Value, env))
+	}
+	return value
+}
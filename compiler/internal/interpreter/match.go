@@ -0,0 +1,86 @@
+package interpreter
+
+import (
+	//Standard packages
+	"fmt"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/values"
+)
+
+// executeMatchStmt runs the block of the first arm with a pattern matching the value.
+// Every arm has its own scope, holding the fields bound by its pattern.
+func (interp *Interpreter) executeMatchStmt(node ast.MatchStmt, env *Environment) values.Value {
+	value := interp.evaluate(node.Value, env)
+	for _, arm := range node.Arms {
+		for _, pattern := range arm.Patterns {
+			armEnv := NewEnvironment(env)
+			if interp.matches(pattern, value, armEnv) {
+				return interp.executeBlock(arm.Block, armEnv)
+			}
+		}
+	}
+	return values.NewVoid()
+}
+
+// matches reports whether a value matches a pattern. '_' matches every value, a struct
+// type name matches the structs of that type and a range matches the numbers from its
+// start up to its end, which is left out. A variant with fields matches the values of
+// that variant and declares its fields under the names of the pattern, 'ok(name)' and
+// 'err(name)' match the values and the errors of a result and declare them. Other
+// patterns match equal values.
+func (interp *Interpreter) matches(pattern ast.Node, value values.Value, env *Environment) bool {
+	switch t := pattern.(type) {
+	case ast.ResultExpr:
+		errValue, isErr := value.(values.Err)
+		if isErr != t.IsErr {
+			return false
+		}
+		if isErr {
+			value = errValue.Value
+		}
+		if ident, ok := t.Value.(ast.IdentifierExpr); ok && ident.Name != "_" {
+			env.declare(ident.Name, value)
+		}
+		return true
+	case ast.FunctionCallExpr:
+		access, ok := t.Caller.(ast.StructPropertyAccessExpr)
+		if !ok {
+			break
+		}
+		_, enumName, ok := interp.enumOf(access.Object)
+		if !ok {
+			break
+		}
+		variant, ok := value.(*values.Variant)
+		if !ok || variant.EnumName != enumName || variant.Name != access.Property.Name {
+			return false
+		}
+		for i, arg := range t.Arguments {
+			if ident, ok := arg.(ast.IdentifierExpr); ok && ident.Name != "_" && i < len(variant.Fields) {
+				env.declare(ident.Name, variant.Fields[i])
+			}
+		}
+		return true
+	case ast.IdentifierExpr:
+		if t.Name == "_" {
+			return true
+		}
+		if _, ok := interp.types[t.Name]; ok {
+			structValue, ok := value.(*values.Struct)
+			return ok && structValue.StructName == interp.types.StructName(t.Name)
+		}
+	case ast.RangeExpr:
+		start, err := values.Compare(value, interp.evaluate(t.Start, env))
+		if err != nil {
+			interp.runtimeError(pattern, fmt.Sprintf("cannot match '%s' against a range", values.TypeName(value)))
+		}
+		end, err := values.Compare(value, interp.evaluate(t.End, env))
+		if err != nil {
+			interp.runtimeError(pattern, fmt.Sprintf("cannot match '%s' against a range", values.TypeName(value)))
+		}
+		return start >= 0 && end < 0
+	}
+	return values.Equals(value, interp.evaluate(pattern, env))
+}
@@ -0,0 +1,32 @@
+package interpreter
+
+import (
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/values"
+)
+
+// propagation carries the error of a '?' expression up to the call of the function it
+// returns from.
+type propagation struct {
+	err values.Err
+}
+
+// evaluateResultExpr evaluates 'ok(value)', which is the value itself, and 'err(value)'.
+func (interp *Interpreter) evaluateResultExpr(node ast.ResultExpr, env *Environment) values.Value {
+	value := interp.evaluate(node.Value, env)
+	if node.IsErr {
+		return values.Err{Value: value}
+	}
+	return value
+}
+
+// evaluatePropagateExpr evaluates 'value?'. It gives the value of a result, or returns its
+// error from the running function.
+func (interp *Interpreter) evaluatePropagateExpr(node ast.PropagateExpr, env *Environment) values.Value {
+	value := interp.evaluate(node.Value, env)
+	if err, ok := value.(values.Err); ok {
+		panic(propagation{err: err})
+	}
+	return value
+}
@@ -0,0 +1,122 @@
+package interpreter
+
+import (
+	//Standard packages
+	"fmt"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/values"
+)
+
+// executeImplStmt records the methods of a struct. The methods are bound to a receiver
+// every time they are accessed through a struct value.
+func (interp *Interpreter) executeImplStmt(node ast.ImplStmt, env *Environment) values.Value {
+	name := interp.types.StructName(node.ImplFor.Name)
+	methods, ok := interp.methods[name]
+	if !ok {
+		methods = make(map[string]*Fn)
+		interp.methods[name] = methods
+	}
+	for _, method := range node.Methods {
+		methods[method.Identifier.Name] = &Fn{Name: method.Identifier.Name, Literal: method.FunctionLiteral, Closure: env}
+	}
+	return values.NewVoid()
+}
+
+// evaluateStructLiteral creates a struct value. Named structs keep the field order of
+// their declaration, anonymous structs keep the order of the literal.
+func (interp *Interpreter) evaluateStructLiteral(node ast.StructLiteral, env *Environment) values.Value {
+	fields := make(map[string]values.Value, len(node.Properties))
+	for _, prop := range node.Properties {
+		fields[prop.Prop.Name] = interp.evaluate(prop.Value, env)
+	}
+
+	if node.Identifier.Name == "" {
+		value := values.NewStruct("")
+		for _, prop := range node.Properties {
+			value.SetField(prop.Prop.Name, fields[prop.Prop.Name])
+		}
+		return value
+	}
+
+	def, _ := interp.types.Underlying(ast.UserDefinedType{AliasName: node.Identifier.Name, Location: node.Identifier.Location})
+	structType, ok := def.(ast.StructType)
+	if !ok {
+		interp.runtimeError(node.Identifier, fmt.Sprintf("'%s' is not a struct", node.Identifier.Name))
+	}
+
+	value := values.NewStruct(interp.types.StructName(node.Identifier.Name))
+	for _, prop := range structType.Properties {
+		if v, ok := fields[prop.Prop.Name]; ok {
+			value.SetField(prop.Prop.Name, v)
+		} else {
+			value.SetField(prop.Prop.Name, interp.zeroValue(prop.PropType))
+		}
+	}
+	return value
+}
+
+// evaluatePropertyAccess returns a field of a struct, or one of its methods bound to the struct.
+// A property accessed with '?.' of a null object is null.
+func (interp *Interpreter) evaluatePropertyAccess(node ast.StructPropertyAccessExpr, env *Environment) values.Value {
+	if enumType, name, ok := interp.enumOf(node.Object); ok {
+		return interp.evaluateVariant(node, enumType, name)
+	}
+
+	object := interp.evaluate(node.Object, env)
+
+	if _, ok := object.(values.Null); ok && node.Optional {
+		return object
+	}
+
+	structValue, ok := object.(*values.Struct)
+	if !ok {
+		interp.runtimeError(node.Object, fmt.Sprintf("cannot access property '%s' of type '%s'", node.Property.Name, values.TypeName(object)))
+	}
+
+	if value, ok := structValue.Fields[node.Property.Name]; ok {
+		return value
+	}
+
+	if method, ok := interp.methods[structValue.StructName][node.Property.Name]; ok {
+		return &Fn{Name: method.Name, Literal: method.Literal, Closure: method.Closure, This: structValue}
+	}
+
+	interp.runtimeError(node.Property, fmt.Sprintf("'%s' does not exist on type '%s'", node.Property.Name, values.TypeName(object)))
+	return values.NewVoid()
+}
+
+// enumOf returns the enum named by the object of a property access, like 'Shape' in 'Shape.Circle',
+// with the name the enum was declared with.
+func (interp *Interpreter) enumOf(object ast.Node) (ast.EnumType, string, bool) {
+	ident, ok := object.(ast.IdentifierExpr)
+	if !ok {
+		return ast.EnumType{}, "", false
+	}
+	if _, ok := interp.types[ident.Name]; !ok {
+		return ast.EnumType{}, "", false
+	}
+	def, name := interp.types.Underlying(ast.UserDefinedType{AliasName: ident.Name, Location: ident.Location})
+	enumType, ok := def.(ast.EnumType)
+	return enumType, name, ok
+}
+
+// evaluateVariant returns a variant of an enum. A variant without fields is a value,
+// a variant with fields is a function creating one.
+func (interp *Interpreter) evaluateVariant(node ast.StructPropertyAccessExpr, enumType ast.EnumType, enumName string) values.Value {
+	for _, variant := range enumType.Variants {
+		if variant.Name.Name != node.Property.Name {
+			continue
+		}
+		if len(variant.Fields) == 0 {
+			return &values.Variant{EnumName: enumName, Name: variant.Name.Name}
+		}
+		name := variant.Name.Name
+		return &Builtin{Name: enumName + "." + name, Call: func(interp *Interpreter, args []values.Value) values.Value {
+			return &values.Variant{EnumName: enumName, Name: name, Fields: args}
+		}}
+	}
+	interp.runtimeError(node.Property, fmt.Sprintf("'%s' is not a variant of enum '%s'", node.Property.Name, enumName))
+	return values.NewVoid()
+}
@@ -0,0 +1,50 @@
+package interpreter
+
+import (
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/values"
+)
+
+// zeroValue returns the value a variable holds when it is declared with a type but without a value.
+func (interp *Interpreter) zeroValue(dtype ast.DataType) values.Value {
+	def, name := interp.types.Underlying(dtype)
+	switch t := def.(type) {
+	case ast.IntegerType:
+		return values.NewInt(0, t.BitSize, t.IsSigned)
+	case ast.FloatType:
+		return values.NewFloat(0, t.BitSize)
+	case ast.StringType:
+		return values.NewStr("")
+	case ast.BooleanType:
+		return values.NewBool(false)
+	case ast.ArrayType:
+		return values.NewArray(interp.types.Name(t.ArrayType), nil)
+	case ast.MapType:
+		return values.NewMap(interp.types.Name(t.KeyType), interp.types.Name(t.ValueType))
+	case ast.RangeType:
+		return values.Range{Start: interp.zeroValue(t.RangeStart), End: interp.zeroValue(t.RangeEnd)}
+	case ast.MaybeType:
+		return values.Null{}
+	case ast.ResultType:
+		return interp.zeroValue(t.OkType)
+	case ast.TupleType:
+		elements := make([]values.Value, len(t.Elements))
+		for i, element := range t.Elements {
+			elements[i] = interp.zeroValue(element)
+		}
+		return &values.Tuple{Values: elements}
+	case ast.StructType:
+		value := values.NewStruct("")
+		if name != "" {
+			value.StructName = interp.types.StructName(name)
+		}
+		for _, prop := range t.Properties {
+			value.SetField(prop.Prop.Name, interp.zeroValue(prop.PropType))
+		}
+		return value
+	default:
+		// functions and interfaces have no value until one is assigned
+		return values.NewVoid()
+	}
+}
@@ -0,0 +1,77 @@
+package interpreter
+
+import (
+	//Standard packages
+	"fmt"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/builtins"
+	"walrus/compiler/internal/values"
+)
+
+const (
+	RETURN_VALUE builtins.TC_TYPE = "return"
+	JUMP_VALUE   builtins.TC_TYPE = "loop jump"
+)
+
+// Fn is a closure. Methods carry their receiver in This.
+type Fn struct {
+	Name    string
+	Literal ast.FunctionLiteral
+	Closure *Environment
+	This    *values.Struct
+}
+
+func (v *Fn) DType() builtins.TC_TYPE {
+	return values.FUNCTION_VALUE
+}
+
+func (v *Fn) String() string {
+	return fmt.Sprintf("<fn %s>", v.Name)
+}
+
+// Builtin is a function implemented by the interpreter itself.
+type Builtin struct {
+	Name string
+	Call func(interp *Interpreter, args []values.Value) values.Value
+}
+
+func (v *Builtin) DType() builtins.TC_TYPE {
+	return values.FUNCTION_VALUE
+}
+
+func (v *Builtin) String() string {
+	return fmt.Sprintf("<builtin fn %s>", v.Name)
+}
+
+// Return wraps the value of a 'ret' statement while it travels up to the function call.
+type Return struct {
+	Value values.Value
+}
+
+func (v Return) DType() builtins.TC_TYPE {
+	return RETURN_VALUE
+}
+
+func (v Return) String() string {
+	return v.Value.String()
+}
+
+// LoopJump is the signal of a 'break' or a 'continue' statement while it travels up to the
+// loop with its label, or to the innermost loop when it has none.
+type LoopJump struct {
+	Label      string
+	IsContinue bool
+}
+
+func (v LoopJump) DType() builtins.TC_TYPE {
+	return JUMP_VALUE
+}
+
+func (v LoopJump) String() string {
+	if v.IsContinue {
+		return "continue"
+	}
+	return "break"
+}
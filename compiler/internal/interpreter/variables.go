@@ -0,0 +1,117 @@
+package interpreter
+
+import (
+	//Standard packages
+	"fmt"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/lexer"
+	"walrus/compiler/internal/values"
+)
+
+// executeVariableDeclaration declares every variable of a let/const statement. A variable
+// declared with only a type starts with the zero value of that type, a value unpacked into
+// names declares each of them.
+func (interp *Interpreter) executeVariableDeclaration(node ast.VarDeclStmt, env *Environment) values.Value {
+	for _, variable := range node.Variables {
+		var value values.Value
+		if variable.Value != nil {
+			value = interp.evaluate(variable.Value, env)
+		} else {
+			value = interp.zeroValue(variable.ExplicitType)
+		}
+		if variable.Destructure != nil {
+			interp.destructure(*variable.Destructure, value, env)
+			continue
+		}
+		env.declare(variable.Identifier.Name, value)
+	}
+	return values.NewVoid()
+}
+
+// destructure declares the names a tuple is unpacked into by position, skipping '_', or the
+// names a struct is unpacked into by field names.
+func (interp *Interpreter) destructure(pattern ast.Destructuring, value values.Value, env *Environment) {
+	for i, name := range pattern.Names {
+		if name.Name == "_" {
+			continue
+		}
+		if pattern.IsStruct {
+			structValue, ok := value.(*values.Struct)
+			if !ok {
+				interp.runtimeError(name, fmt.Sprintf("cannot access property '%s' of type '%s'", name.Name, values.TypeName(value)))
+			}
+			env.declare(name.Name, structValue.Fields[name.Name])
+			continue
+		}
+		element, err := values.Index(value, values.NewInt(int64(i), 32, true))
+		if err != nil {
+			interp.runtimeError(name, err.Error())
+		}
+		env.declare(name.Name, element)
+	}
+}
+
+// compoundOperator turns an assignment operator like += into the binary operator it applies.
+func compoundOperator(op lexer.Token) (lexer.Token, bool) {
+	switch op.Kind {
+	case lexer.PLUS_EQUALS_TOKEN:
+		op.Kind = lexer.PLUS_TOKEN
+	case lexer.MINUS_EQUALS_TOKEN:
+		op.Kind = lexer.MINUS_TOKEN
+	case lexer.MUL_EQUALS_TOKEN:
+		op.Kind = lexer.MUL_TOKEN
+	case lexer.DIV_EQUALS_TOKEN:
+		op.Kind = lexer.DIV_TOKEN
+	case lexer.MOD_EQUALS_TOKEN:
+		op.Kind = lexer.MOD_TOKEN
+	case lexer.EXP_EQUALS_TOKEN:
+		op.Kind = lexer.EXP_TOKEN
+	case lexer.BIT_AND_EQUALS_TOKEN:
+		op.Kind = lexer.BIT_AND_TOKEN
+	case lexer.BIT_OR_EQUALS_TOKEN:
+		op.Kind = lexer.BIT_OR_TOKEN
+	case lexer.BIT_XOR_EQUALS_TOKEN:
+		op.Kind = lexer.BIT_XOR_TOKEN
+	case lexer.SHIFT_LEFT_EQUALS_TOKEN:
+		op.Kind = lexer.SHIFT_LEFT_TOKEN
+	case lexer.SHIFT_RIGHT_EQUALS_TOKEN:
+		op.Kind = lexer.SHIFT_RIGHT_TOKEN
+	default:
+		return op, false
+	}
+	return op, true
+}
+
+// evaluateAssignment assigns to an identifier, an array or map element, or a struct property.
+// Compound operators like += first apply the operator to the current value.
+func (interp *Interpreter) evaluateAssignment(node ast.VarAssignmentExpr, env *Environment) values.Value {
+
+	value := interp.evaluate(node.Value, env)
+
+	if op, ok := compoundOperator(node.Operator); ok {
+		current := interp.evaluate(node.Assignee, env)
+		value = interp.binaryOperation(op, current, value, node)
+	}
+
+	switch t := node.Assignee.(type) {
+	case ast.IdentifierExpr:
+		if err := env.assign(t.Name, value); err != nil {
+			interp.runtimeError(t, err.Error())
+		}
+	case ast.Indexable:
+		interp.assignIndex(t, value, env)
+	case ast.StructPropertyAccessExpr:
+		object := interp.evaluate(t.Object, env)
+		structValue, ok := object.(*values.Struct)
+		if !ok {
+			interp.runtimeError(t.Object, "cannot assign a property of a non struct value")
+		}
+		structValue.SetField(t.Property.Name, value)
+	default:
+		interp.runtimeError(node.Assignee, "invalid assignment target")
+	}
+
+	return value
+}
@@ -0,0 +1,588 @@
+package ir
+
+import (
+	//Standard packages
+	"fmt"
+	"math"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/builtins"
+	"walrus/compiler/internal/codegen"
+	"walrus/compiler/internal/lexer"
+	"walrus/compiler/internal/values"
+)
+
+// operators names the instruction of every binary operator.
+var operators = map[builtins.TOKEN_KIND]string{
+	lexer.PLUS_TOKEN:          "add",
+	lexer.MINUS_TOKEN:         "sub",
+	lexer.MUL_TOKEN:           "mul",
+	lexer.DIV_TOKEN:           "div",
+	lexer.MOD_TOKEN:           "mod",
+	lexer.EXP_TOKEN:           "pow",
+	lexer.BIT_AND_TOKEN:       "and",
+	lexer.BIT_OR_TOKEN:        "or",
+	lexer.BIT_XOR_TOKEN:       "xor",
+	lexer.SHIFT_LEFT_TOKEN:    "shl",
+	lexer.SHIFT_RIGHT_TOKEN:   "shr",
+	lexer.DOUBLE_EQUAL_TOKEN:  "eq",
+	lexer.NOT_EQUAL_TOKEN:     "ne",
+	lexer.LESS_TOKEN:          "lt",
+	lexer.LESS_EQUAL_TOKEN:    "le",
+	lexer.GREATER_TOKEN:       "gt",
+	lexer.GREATER_EQUAL_TOKEN: "ge",
+}
+
+// compound maps compound assignment operators to the operator they apply.
+var compound = map[builtins.TOKEN_KIND]builtins.TOKEN_KIND{
+	lexer.PLUS_EQUALS_TOKEN:        lexer.PLUS_TOKEN,
+	lexer.MINUS_EQUALS_TOKEN:       lexer.MINUS_TOKEN,
+	lexer.MUL_EQUALS_TOKEN:         lexer.MUL_TOKEN,
+	lexer.DIV_EQUALS_TOKEN:         lexer.DIV_TOKEN,
+	lexer.MOD_EQUALS_TOKEN:         lexer.MOD_TOKEN,
+	lexer.EXP_EQUALS_TOKEN:         lexer.EXP_TOKEN,
+	lexer.BIT_AND_EQUALS_TOKEN:     lexer.BIT_AND_TOKEN,
+	lexer.BIT_OR_EQUALS_TOKEN:      lexer.BIT_OR_TOKEN,
+	lexer.BIT_XOR_EQUALS_TOKEN:     lexer.BIT_XOR_TOKEN,
+	lexer.SHIFT_LEFT_EQUALS_TOKEN:  lexer.SHIFT_LEFT_TOKEN,
+	lexer.SHIFT_RIGHT_EQUALS_TOKEN: lexer.SHIFT_RIGHT_TOKEN,
+}
+
+// expr lowers an expression and returns its value, which is nil for calls of functions
+// without a result.
+func (l *lowerer) expr(node ast.Node, s *scope) Value {
+	if value, ok := l.info.ConstantOf(node); ok {
+		return constant(value)
+	}
+
+	switch t := node.(type) {
+	case ast.IdentifierExpr:
+		return l.identifier(t, s)
+	case ast.IntegerLiteralExpr, ast.FloatLiteralExpr:
+		l.unsupported(t, "invalid numeric literal")
+		return nil
+	case ast.StringLiteralExpr:
+		return &Const{Value: values.NewStr(t.Value), DType: codegen.StrType()}
+	case ast.InterpolatedStringExpr:
+		return l.expr(codegen.Concat(t), s)
+	case ast.ByteLiteralExpr:
+		return &Const{Value: values.NewInt(int64(t.Value[0]), 8, false), DType: codegen.IntType(8, false)}
+	case ast.BinaryExpr:
+		if t.Binop.Kind == lexer.AND_TOKEN || t.Binop.Kind == lexer.OR_TOKEN {
+			return l.logical(t, s)
+		}
+		return l.binary(t.Binop.Kind, l.expr(t.Left, s), l.expr(t.Right, s))
+	case ast.UnaryExpr:
+		return l.unary(t, s)
+	case ast.PrefixExpr:
+		return l.increment(t, true, s)
+	case ast.PostfixExpr:
+		return l.increment(t, false, s)
+	case ast.VarAssignmentExpr:
+		return l.assignment(t, s)
+	case ast.TypeCastExpr:
+		return l.convert(l.expr(t.Expression, s), t.ToCast)
+	case ast.ResultExpr, ast.PropagateExpr:
+		l.unsupported(t, "results cannot be lowered yet")
+		return nil
+	case ast.TupleLiteral:
+		l.unsupported(t, "tuples cannot be lowered yet")
+		return nil
+	case ast.TypeofExpr:
+		return &Const{Value: values.NewStr(l.types.Name(l.typeOf(t.Expression))), DType: codegen.StrType()}
+	case ast.RangeExpr:
+		dest := l.temp(l.typeOf(t))
+		l.emit(&MakeRange{Dest: dest, Start: l.expr(t.Start, s), End: l.expr(t.End, s)})
+		return dest
+	case ast.ArrayLiteral:
+		return l.arrayLiteral(t, l.typeOf(t), s)
+	case ast.Indexable:
+		return l.index(t, s)
+	case ast.StructLiteral:
+		return l.structLiteral(t, s)
+	case ast.StructPropertyAccessExpr:
+		return l.property(t, s)
+	case ast.MapLiteral:
+		return l.mapLiteral(t, s)
+	case ast.FunctionLiteral:
+		return l.closure(t, "", s)
+	case ast.FunctionCallExpr:
+		return l.call(t, s)
+	default:
+		l.unsupported(node, fmt.Sprintf("<%T> node cannot be lowered yet", node))
+		return nil
+	}
+}
+
+// valueAs lowers an expression whose value is stored in a place of the given type.
+// Empty arrays take the type of the place.
+func (l *lowerer) valueAs(node ast.Node, dtype ast.DataType, s *scope) Value {
+	if array, ok := node.(ast.ArrayLiteral); ok {
+		return l.arrayLiteral(array, dtype, s)
+	}
+	return l.coerce(l.expr(node, s), dtype)
+}
+
+// coerce converts a value stored in a place of another type. Numbers are converted and
+// structs stored in interfaces become interface values.
+func (l *lowerer) coerce(value Value, dtype ast.DataType) Value {
+	if value == nil || dtype == nil {
+		return value
+	}
+	from := value.Type()
+	if l.isNumber(from) && l.isNumber(dtype) && l.types.Name(l.underlying(from)) != l.types.Name(l.underlying(dtype)) {
+		return l.convert(value, dtype)
+	}
+	if _, ok := l.underlying(dtype).(ast.InterfaceType); ok {
+		return l.convert(value, dtype)
+	}
+	return value
+}
+
+// constant returns the operand of a folded constant.
+func constant(value values.Value) Value {
+	switch v := value.(type) {
+	case values.Int:
+		return &Const{Value: v, DType: codegen.IntType(v.BitSize, v.IsSigned)}
+	case values.Float:
+		return &Const{Value: v, DType: codegen.FloatType(v.BitSize)}
+	case values.Str:
+		return &Const{Value: v, DType: codegen.StrType()}
+	default:
+		return &Const{Value: v, DType: codegen.BoolType()}
+	}
+}
+
+func (l *lowerer) zero(dtype ast.DataType) Value {
+	dest := l.temp(dtype)
+	l.emit(&Zero{Dest: dest})
+	return dest
+}
+
+// identifier reads a variable. Functions become function values, and the methods of the
+// struct whose method is being lowered are bound to it.
+func (l *lowerer) identifier(node ast.IdentifierExpr, s *scope) Value {
+	dtype := l.typeOf(node)
+	v, fn := l.lookup(node.Name, s)
+	switch {
+	case v != nil:
+		dest := l.temp(v.Type)
+		l.emit(&Load{Dest: dest, Var: v})
+		return dest
+	case fn != nil && fn.Receiver != "":
+		dest := l.temp(dtype)
+		l.emit(&BindMethod{Dest: dest, Method: node.Name, Receiver: l.this(s)})
+		return dest
+	case fn != nil:
+		return &FuncRef{Fn: fn, DType: dtype}
+	}
+
+	switch node.Name {
+	case "true", "false":
+		return &Const{Value: values.NewBool(node.Name == "true"), DType: codegen.BoolType()}
+	case "PI":
+		return &Const{Value: values.NewFloat(math.Pi, 32), DType: codegen.FloatType(32)}
+	case "print":
+		return &BuiltinRef{Name: node.Name, DType: dtype}
+	}
+	l.unsupported(node, fmt.Sprintf("'%s' is not declared", node.Name))
+	return nil
+}
+
+// this reads the struct whose method is being lowered.
+func (l *lowerer) this(s *scope) Value {
+	v, _ := l.lookup("this", s)
+	dest := l.temp(v.Type)
+	l.emit(&Load{Dest: dest, Var: v})
+	return dest
+}
+
+// binary applies a binary operator. Like in walrus, the right operand is converted to the
+// type of the left operand and the result has the type of the left operand, but an integer
+// and a float give the float type. Numbers of different types are compared as f64 when one of them is a float, and values added to a
+// string are formatted first.
+func (l *lowerer) binary(kind builtins.TOKEN_KIND, left, right Value) Value {
+	leftType, rightType := left.Type(), right.Type()
+	comparison := isComparison(kind)
+
+	if _, ok := l.underlying(leftType).(ast.StringType); ok && kind == lexer.PLUS_TOKEN {
+		if _, ok := l.underlying(rightType).(ast.StringType); !ok {
+			formatted := l.temp(codegen.StrType())
+			l.emit(&ToString{Dest: formatted, Value: right})
+			right = formatted
+		}
+		dest := l.temp(leftType)
+		l.emit(&BinOp{Dest: dest, Op: "concat", Left: left, Right: right})
+		return dest
+	}
+
+	if l.isNumber(leftType) && !l.isFloat(leftType) && l.isFloat(rightType) && !comparison {
+		// an integer and a float give a float
+		left = l.convert(left, rightType)
+		leftType = rightType
+	}
+
+	if l.isNumber(leftType) && l.isNumber(rightType) && l.types.Name(l.underlying(leftType)) != l.types.Name(l.underlying(rightType)) {
+		if comparison && (l.isFloat(leftType) || l.isFloat(rightType)) {
+			left = l.convert(left, codegen.FloatType(64))
+			right = l.convert(right, codegen.FloatType(64))
+		} else {
+			right = l.convert(right, leftType)
+		}
+	}
+
+	var dtype ast.DataType = leftType
+	if comparison {
+		dtype = codegen.BoolType()
+	}
+	dest := l.temp(dtype)
+	l.emit(&BinOp{Dest: dest, Op: operators[kind], Left: left, Right: right})
+	return dest
+}
+
+// logical lowers '&&' and '||' into a branch, so the right operand is only evaluated
+// when the left one does not decide the result.
+func (l *lowerer) logical(node ast.BinaryExpr, s *scope) Value {
+	name := "and"
+	if node.Binop.Kind == lexer.OR_TOKEN {
+		name = "or"
+	}
+	// the variable is declared in a scope of its own, so it cannot hide a variable of the program
+	result := l.declare(name, codegen.BoolType(), newScope(s), false)
+	left := l.expr(node.Left, s)
+	l.emit(&Store{Var: result, Value: left})
+
+	right := l.newBlock()
+	merge := l.newBlock()
+	if node.Binop.Kind == lexer.AND_TOKEN {
+		l.terminate(&Branch{Cond: left, Then: right, Else: merge})
+	} else {
+		l.terminate(&Branch{Cond: left, Then: merge, Else: right})
+	}
+
+	l.block = right
+	l.emit(&Store{Var: result, Value: l.expr(node.Right, s)})
+	l.jump(merge)
+
+	l.block = merge
+	dest := l.temp(codegen.BoolType())
+	l.emit(&Load{Dest: dest, Var: result})
+	return dest
+}
+
+func (l *lowerer) unary(node ast.UnaryExpr, s *scope) Value {
+	argument := l.expr(node.Argument, s)
+	op := "neg"
+	dtype := argument.Type()
+	switch node.Operator.Kind {
+	case lexer.NOT_TOKEN:
+		op = "not"
+		dtype = codegen.BoolType()
+	case lexer.BIT_NOT_TOKEN:
+		op = "bitnot"
+	}
+	dest := l.temp(dtype)
+	l.emit(&UnOp{Dest: dest, Op: op, Operand: argument})
+	return dest
+}
+
+// increment lowers ++ and --. The postfix form returns the value the variable had.
+func (l *lowerer) increment(node ast.IncrementalInterface, prefix bool, s *scope) Value {
+	v, _ := l.lookup(node.Arg().Name, s)
+	if v == nil {
+		l.unsupported(node.Arg(), fmt.Sprintf("'%s' is not a variable", node.Arg().Name))
+	}
+
+	old := l.temp(v.Type)
+	l.emit(&Load{Dest: old, Var: v})
+	var one values.Value = values.NewInt(1, 32, true)
+	switch t := l.underlying(v.Type).(type) {
+	case ast.IntegerType:
+		one = values.NewInt(1, t.BitSize, t.IsSigned)
+	case ast.FloatType:
+		one = values.NewFloat(1, t.BitSize)
+	}
+	updated := l.temp(v.Type)
+	l.emit(&BinOp{Dest: updated, Op: operators[builtins.TOKEN_KIND(string(node.Op().Kind)[:1])], Left: old, Right: &Const{Value: one, DType: v.Type}})
+	l.emit(&Store{Var: v, Value: updated})
+	if prefix {
+		return updated
+	}
+	return old
+}
+
+// assignment lowers an assignment, which evaluates to the assigned value. The container
+// of an element or a field is evaluated once, also by compound operators.
+func (l *lowerer) assignment(node ast.VarAssignmentExpr, s *scope) Value {
+	kind, isCompound := compound[node.Operator.Kind]
+	value := func(current func() Value, dtype ast.DataType) Value {
+		if isCompound {
+			return l.binary(kind, current(), l.expr(node.Value, s))
+		}
+		return l.valueAs(node.Value, dtype, s)
+	}
+
+	switch t := node.Assignee.(type) {
+	case ast.IdentifierExpr:
+		v, _ := l.lookup(t.Name, s)
+		if v == nil {
+			l.unsupported(t, fmt.Sprintf("'%s' is not a variable", t.Name))
+		}
+		result := value(func() Value { return l.identifier(t, s) }, v.Type)
+		l.emit(&Store{Var: v, Value: result})
+		return result
+	case ast.Indexable:
+		container := l.expr(t.Container, s)
+		var index Value
+		if mapType, ok := l.underlying(container.Type()).(ast.MapType); ok {
+			index = l.valueAs(t.Index, mapType.KeyType, s)
+		} else {
+			index = l.expr(t.Index, s)
+		}
+		dtype := l.typeOf(t)
+		result := value(func() Value {
+			dest := l.temp(dtype)
+			l.emit(&Index{Dest: dest, Container: container, Index: index})
+			return dest
+		}, dtype)
+		l.emit(&SetIndex{Container: container, Index: index, Value: result})
+		return result
+	case ast.StructPropertyAccessExpr:
+		object := l.expr(t.Object, s)
+		dtype := l.typeOf(t)
+		result := value(func() Value {
+			dest := l.temp(dtype)
+			l.emit(&Field{Dest: dest, Object: object, Name: t.Property.Name})
+			return dest
+		}, dtype)
+		l.emit(&SetField{Object: object, Name: t.Property.Name, Value: result})
+		return result
+	default:
+		l.unsupported(node.Assignee, "invalid assignment target")
+		return nil
+	}
+}
+
+// convert converts a value to another type. Constant numbers are converted right away and
+// structs converted to interfaces become interface values.
+func (l *lowerer) convert(value Value, to ast.DataType) Value {
+	if l.types.Name(value.Type()) == l.types.Name(to) {
+		return value
+	}
+	if c, ok := value.(*Const); ok {
+		switch t := l.underlying(to).(type) {
+		case ast.IntegerType:
+			if result, err := values.ToInt(c.Value, t.BitSize, t.IsSigned); err == nil {
+				return &Const{Value: result, DType: to}
+			}
+		case ast.FloatType:
+			if result, err := values.ToFloat(c.Value, t.BitSize); err == nil {
+				return &Const{Value: result, DType: to}
+			}
+		}
+	}
+	dest := l.temp(to)
+	if _, ok := l.underlying(to).(ast.InterfaceType); ok {
+		if _, ok := l.underlying(value.Type()).(ast.StructType); ok {
+			l.emit(&ToInterface{Dest: dest, Value: value})
+			return dest
+		}
+		return value
+	}
+	l.emit(&Convert{Dest: dest, Value: value})
+	return dest
+}
+
+func (l *lowerer) arrayLiteral(node ast.ArrayLiteral, dtype ast.DataType, s *scope) Value {
+	array, ok := l.underlying(dtype).(ast.ArrayType)
+	if !ok {
+		dtype = l.typeOf(node)
+		array, _ = dtype.(ast.ArrayType)
+	}
+	values := make([]Value, len(node.Values))
+	for i, value := range node.Values {
+		values[i] = l.valueAs(value, array.ArrayType, s)
+	}
+	dest := l.temp(dtype)
+	l.emit(&MakeArray{Dest: dest, Values: values})
+	return dest
+}
+
+// index reads an array element, a byte of a string or a map value.
+func (l *lowerer) index(node ast.Indexable, s *scope) Value {
+	container := l.expr(node.Container, s)
+	var index Value
+	if mapType, ok := l.underlying(container.Type()).(ast.MapType); ok {
+		index = l.valueAs(node.Index, mapType.KeyType, s)
+	} else {
+		index = l.expr(node.Index, s)
+	}
+	dest := l.temp(l.typeOf(node))
+	l.emit(&Index{Dest: dest, Container: container, Index: index})
+	return dest
+}
+
+// structLiteral creates a struct. Fields are evaluated in the order they are written and
+// the fields left out hold their zero value.
+func (l *lowerer) structLiteral(node ast.StructLiteral, s *scope) Value {
+	dtype := l.typeOf(node)
+	structType, ok := l.underlying(dtype).(ast.StructType)
+	if !ok {
+		l.unsupported(node.Identifier, fmt.Sprintf("'%s' is not a struct", node.Identifier.Name))
+	}
+
+	values := make(map[string]Value, len(node.Properties))
+	for _, prop := range node.Properties {
+		var propType ast.DataType
+		if field, ok := l.field(structType, prop.Prop.Name); ok {
+			propType = field.PropType
+		}
+		values[prop.Prop.Name] = l.valueAs(prop.Value, propType, s)
+	}
+
+	instr := &MakeStruct{Dest: l.temp(dtype)}
+	for _, prop := range structType.Properties {
+		value, ok := values[prop.Prop.Name]
+		if !ok {
+			value = l.zero(prop.PropType)
+		}
+		instr.Fields = append(instr.Fields, prop.Prop.Name)
+		instr.Values = append(instr.Values, value)
+	}
+	l.emit(instr)
+	return instr.Dest
+}
+
+// property reads a field, or creates a function value for a method bound to its receiver.
+func (l *lowerer) property(node ast.StructPropertyAccessExpr, s *scope) Value {
+	if node.Optional {
+		l.unsupported(node, "optional chaining cannot be lowered yet")
+	}
+	object := l.expr(node.Object, s)
+	dtype := l.typeOf(node)
+	if dtype == nil {
+		l.unsupported(node.Property, fmt.Sprintf("'%s' is not a property", node.Property.Name))
+	}
+
+	dest := l.temp(dtype)
+	if _, ok := l.field(object.Type(), node.Property.Name); ok {
+		l.emit(&Field{Dest: dest, Object: object, Name: node.Property.Name})
+	} else {
+		l.emit(&BindMethod{Dest: dest, Method: node.Property.Name, Receiver: object})
+	}
+	return dest
+}
+
+func (l *lowerer) mapLiteral(node ast.MapLiteral, s *scope) Value {
+	mapType, _ := l.underlying(node.MapType).(ast.MapType)
+	instr := &MakeMap{Dest: l.temp(node.MapType)}
+	for _, entry := range node.Values {
+		instr.Keys = append(instr.Keys, l.valueAs(entry.Key, mapType.KeyType, s))
+		instr.Values = append(instr.Values, l.valueAs(entry.Value, mapType.ValueType, s))
+	}
+	l.emit(instr)
+	return instr.Dest
+}
+
+// call lowers a function call. Top level functions and methods are called directly,
+// interface methods are dispatched on the interface value and every other function is
+// called through its function value. Arguments are converted to the types of the parameters.
+func (l *lowerer) call(node ast.FunctionCallExpr, s *scope) Value {
+	fnType, _ := l.underlying(l.typeOf(node.Caller)).(ast.FunctionType)
+
+	var dest *Temp
+	if result := codegen.ReturnType(fnType); !codegen.IsVoid(result) {
+		dest = l.temp(result)
+	}
+	var instr Instr
+	switch caller := node.Caller.(type) {
+	case ast.IdentifierExpr:
+		v, fn := l.lookup(caller.Name, s)
+		switch {
+		case v != nil:
+		case fn != nil && fn.Receiver != "":
+			instr = &CallMethod{Dest: dest, Fn: fn, Receiver: l.this(s), Args: l.args(node, fnType, s)}
+		case fn != nil:
+			instr = &Call{Dest: dest, Fn: fn, Args: l.args(node, fnType, s)}
+		default:
+			instr = &CallBuiltin{Dest: dest, Name: caller.Name, Args: l.args(node, fnType, s)}
+		}
+	case ast.StructPropertyAccessExpr:
+		objectType := l.typeOf(caller.Object)
+		if _, ok := l.field(objectType, caller.Property.Name); ok {
+			break
+		}
+		def, structName := l.types.Underlying(objectType)
+		switch def.(type) {
+		case ast.StructType:
+			if fn, ok := l.methods[l.types.StructName(structName)+"."+caller.Property.Name]; ok {
+				object := l.expr(caller.Object, s)
+				instr = &CallMethod{Dest: dest, Fn: fn, Receiver: object, Args: l.args(node, fnType, s)}
+			}
+		case ast.InterfaceType:
+			object := l.expr(caller.Object, s)
+			instr = &CallInterface{Dest: dest, Method: caller.Property.Name, Receiver: object, Args: l.args(node, fnType, s)}
+		}
+	}
+
+	if instr == nil {
+		callee := l.expr(node.Caller, s)
+		instr = &CallClosure{Dest: dest, Callee: callee, Args: l.args(node, fnType, s)}
+	}
+	l.emit(instr)
+	if dest == nil {
+		return nil
+	}
+	return dest
+}
+
+func (l *lowerer) args(node ast.FunctionCallExpr, fnType ast.FunctionType, s *scope) []Value {
+	args := make([]Value, len(node.Arguments))
+	for i, arg := range node.Arguments {
+		if i < len(fnType.Parameters) {
+			args[i] = l.valueAs(arg, fnType.Parameters[i].Type, s)
+		} else {
+			args[i] = l.expr(arg, s)
+		}
+	}
+	return args
+}
+
+// field returns the declaration of a struct field.
+func (l *lowerer) field(dtype ast.DataType, name string) (ast.StructPropType, bool) {
+	if structType, ok := l.underlying(dtype).(ast.StructType); ok {
+		for _, prop := range structType.Properties {
+			if prop.Prop.Name == name {
+				return prop, true
+			}
+		}
+	}
+	return ast.StructPropType{}, false
+}
+
+func (l *lowerer) underlying(dtype ast.DataType) ast.DataType {
+	def, _ := l.types.Underlying(dtype)
+	return def
+}
+
+func (l *lowerer) isNumber(dtype ast.DataType) bool {
+	switch l.underlying(dtype).(type) {
+	case ast.IntegerType, ast.FloatType:
+		return true
+	}
+	return false
+}
+
+func (l *lowerer) isFloat(dtype ast.DataType) bool {
+	_, ok := l.underlying(dtype).(ast.FloatType)
+	return ok
+}
+
+func isComparison(kind builtins.TOKEN_KIND) bool {
+	switch kind {
+	case lexer.DOUBLE_EQUAL_TOKEN, lexer.NOT_EQUAL_TOKEN, lexer.LESS_TOKEN, lexer.LESS_EQUAL_TOKEN, lexer.GREATER_TOKEN, lexer.GREATER_EQUAL_TOKEN:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,554 @@
+// Package ir defines the intermediate representation backends lower from, and the lowering
+// of a type checked program into it.
+//
+// Every function is a list of basic blocks ending in a terminator. Instructions compute
+// temporaries, which are assigned exactly once. Walrus variables live in named slots that
+// are read and written with explicit loads and stores, so the IR stays in SSA form without
+// phi nodes. Conversions, method dispatch and the formatting of values concatenated to
+// strings are explicit instructions.
+package ir
+
+import (
+	//Standard packages
+	"fmt"
+	"strconv"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/values"
+)
+
+// Program is a lowered walrus program. Main holds the top level statements.
+type Program struct {
+	Types     values.TypeTable
+	TypeNames []string // user defined types in declaration order
+	Globals   []*Var
+	Functions []*Function
+	Main      *Function
+	current   *Function // function being dumped
+}
+
+// Function is a function, a method or a function literal lifted out of its parent.
+type Function struct {
+	Name     string
+	Receiver string    // struct the method is implemented for, empty for functions
+	Parent   *Function // enclosing function of a nested function, nil at the top level
+	Params   []*Var
+	Locals   []*Var // every variable of the function, the parameters first
+	Result   ast.DataType
+	Blocks   []*Block
+	temps    int
+}
+
+// Var is a variable slot. Globals have no owner. A variable used by a nested function is
+// captured and must outlive the call of the function that owns it.
+type Var struct {
+	Name     string
+	Type     ast.DataType
+	Owner    *Function
+	Captured bool
+}
+
+// Block is a basic block. Only the last instruction, the terminator, transfers control.
+type Block struct {
+	ID     int
+	Instrs []Instr
+	Term   Terminator
+}
+
+func (b *Block) String() string {
+	return "b" + strconv.Itoa(b.ID)
+}
+
+// Value is an operand of an instruction.
+type Value interface {
+	Type() ast.DataType
+	format(p *Program) string
+}
+
+// Temp is the result of an instruction.
+type Temp struct {
+	ID    int
+	DType ast.DataType
+}
+
+func (t *Temp) Type() ast.DataType { return t.DType }
+
+func (t *Temp) format(p *Program) string {
+	return "%" + strconv.Itoa(t.ID)
+}
+
+// Const is a constant operand.
+type Const struct {
+	Value values.Value
+	DType ast.DataType
+}
+
+func (c *Const) Type() ast.DataType { return c.DType }
+
+func (c *Const) format(p *Program) string {
+	switch v := c.Value.(type) {
+	case values.Str:
+		return strconv.Quote(v.Value)
+	case values.Float:
+		text := v.String()
+		if !strings.ContainsAny(text, ".eIN") {
+			text += ".0"
+		}
+		return text
+	}
+	return c.Value.String()
+}
+
+// FuncRef is a function used as a value, which needs no captured variables.
+type FuncRef struct {
+	Fn    *Function
+	DType ast.DataType
+}
+
+func (f *FuncRef) Type() ast.DataType { return f.DType }
+
+func (f *FuncRef) format(p *Program) string {
+	return "@" + f.Fn.Name
+}
+
+// BuiltinRef is a function of the runtime used as a value.
+type BuiltinRef struct {
+	Name  string
+	DType ast.DataType
+}
+
+func (b *BuiltinRef) Type() ast.DataType { return b.DType }
+
+func (b *BuiltinRef) format(p *Program) string {
+	return "builtin " + b.Name
+}
+
+// Instr is an instruction. Dest is nil for instructions without a result.
+type Instr interface {
+	Result() *Temp
+	format(p *Program) string
+}
+
+// Load reads a variable.
+type Load struct {
+	Dest *Temp
+	Var  *Var
+}
+
+// Store writes a variable.
+type Store struct {
+	Var   *Var
+	Value Value
+}
+
+// BinOp applies an arithmetic, comparison or string operator. Both operands have the same type.
+type BinOp struct {
+	Dest        *Temp
+	Op          string
+	Left, Right Value
+}
+
+// UnOp applies a unary operator.
+type UnOp struct {
+	Dest    *Temp
+	Op      string
+	Operand Value
+}
+
+// Zero creates the value a variable of the type of Dest holds before it is assigned.
+type Zero struct {
+	Dest *Temp
+}
+
+// Convert converts a value to the type of Dest: numeric conversions and struct copies.
+type Convert struct {
+	Dest  *Temp
+	Value Value
+}
+
+// ToString formats a value the way print shows it, for string concatenation.
+type ToString struct {
+	Dest  *Temp
+	Value Value
+}
+
+// ToInterface stores a struct in an interface value.
+type ToInterface struct {
+	Dest  *Temp
+	Value Value
+}
+
+// Call calls a top level function directly.
+type Call struct {
+	Dest *Temp
+	Fn   *Function
+	Args []Value
+}
+
+// CallMethod calls the method of a struct directly.
+type CallMethod struct {
+	Dest     *Temp
+	Fn       *Function
+	Receiver Value
+	Args     []Value
+}
+
+// CallInterface calls a method through an interface value.
+type CallInterface struct {
+	Dest     *Temp
+	Method   string
+	Receiver Value
+	Args     []Value
+}
+
+// CallClosure calls a function value.
+type CallClosure struct {
+	Dest   *Temp
+	Callee Value
+	Args   []Value
+}
+
+// CallBuiltin calls a function of the runtime.
+type CallBuiltin struct {
+	Dest *Temp
+	Name string
+	Args []Value
+}
+
+// MakeClosure creates a function value for a nested function, which captures the
+// variables of the function creating it.
+type MakeClosure struct {
+	Dest *Temp
+	Fn   *Function
+}
+
+// BindMethod creates a function value calling a method on a receiver.
+type BindMethod struct {
+	Dest     *Temp
+	Method   string
+	Receiver Value
+}
+
+// MakeArray creates an array.
+type MakeArray struct {
+	Dest   *Temp
+	Values []Value
+}
+
+// MakeMap creates a map from keys and values in insertion order.
+type MakeMap struct {
+	Dest   *Temp
+	Keys   []Value
+	Values []Value
+}
+
+// MakeStruct creates a struct. Fields holds a value for every field, in declaration order.
+type MakeStruct struct {
+	Dest   *Temp
+	Fields []string
+	Values []Value
+}
+
+// MakeRange creates a range.
+type MakeRange struct {
+	Dest       *Temp
+	Start, End Value
+}
+
+// Index reads an element of an array, a byte of a string or a value of a map.
+type Index struct {
+	Dest      *Temp
+	Container Value
+	Index     Value
+}
+
+// SetIndex writes an element of an array or a value of a map.
+type SetIndex struct {
+	Container Value
+	Index     Value
+	Value     Value
+}
+
+// Field reads a field of a struct.
+type Field struct {
+	Dest   *Temp
+	Object Value
+	Name   string
+}
+
+// SetField writes a field of a struct.
+type SetField struct {
+	Object Value
+	Name   string
+	Value  Value
+}
+
+func (i *Load) Result() *Temp          { return i.Dest }
+func (i *Store) Result() *Temp         { return nil }
+func (i *BinOp) Result() *Temp         { return i.Dest }
+func (i *UnOp) Result() *Temp          { return i.Dest }
+func (i *Zero) Result() *Temp          { return i.Dest }
+func (i *Convert) Result() *Temp       { return i.Dest }
+func (i *ToString) Result() *Temp      { return i.Dest }
+func (i *ToInterface) Result() *Temp   { return i.Dest }
+func (i *Call) Result() *Temp          { return i.Dest }
+func (i *CallMethod) Result() *Temp    { return i.Dest }
+func (i *CallInterface) Result() *Temp { return i.Dest }
+func (i *CallClosure) Result() *Temp   { return i.Dest }
+func (i *CallBuiltin) Result() *Temp   { return i.Dest }
+func (i *MakeClosure) Result() *Temp   { return i.Dest }
+func (i *BindMethod) Result() *Temp    { return i.Dest }
+func (i *MakeArray) Result() *Temp     { return i.Dest }
+func (i *MakeMap) Result() *Temp       { return i.Dest }
+func (i *MakeStruct) Result() *Temp    { return i.Dest }
+func (i *MakeRange) Result() *Temp     { return i.Dest }
+func (i *Index) Result() *Temp         { return i.Dest }
+func (i *SetIndex) Result() *Temp      { return nil }
+func (i *Field) Result() *Temp         { return i.Dest }
+func (i *SetField) Result() *Temp      { return nil }
+
+// Terminator ends a block.
+type Terminator interface {
+	Successors() []*Block
+	format(p *Program) string
+}
+
+// Jump continues in another block.
+type Jump struct {
+	Target *Block
+}
+
+// Branch continues in Then when the condition is true and in Else otherwise.
+type Branch struct {
+	Cond Value
+	Then *Block
+	Else *Block
+}
+
+// Return returns from the function. Value is nil for functions without a result.
+type Return struct {
+	Value Value
+}
+
+// Unreachable ends a block control never reaches the end of, like a function whose
+// result is returned on every path.
+type Unreachable struct{}
+
+func (t *Jump) Successors() []*Block        { return []*Block{t.Target} }
+func (t *Branch) Successors() []*Block      { return []*Block{t.Then, t.Else} }
+func (t *Return) Successors() []*Block      { return nil }
+func (t *Unreachable) Successors() []*Block { return nil }
+
+// String dumps the program in the textual form of the IR.
+func (p *Program) String() string {
+	var sb strings.Builder
+	for _, name := range p.TypeNames {
+		fmt.Fprintf(&sb, "type %s = %s\n", name, p.typeName(p.Types[name], true))
+	}
+	if len(p.TypeNames) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, global := range p.Globals {
+		fmt.Fprintf(&sb, "global @%s: %s\n", global.Name, p.typeName(global.Type, false))
+	}
+	if len(p.Globals) > 0 {
+		sb.WriteString("\n")
+	}
+	for i, fn := range p.Functions {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(p.function(fn))
+	}
+	return sb.String()
+}
+
+func (p *Program) function(fn *Function) string {
+	p.current = fn
+	var sb strings.Builder
+	params := make([]string, len(fn.Params))
+	for i, param := range fn.Params {
+		params[i] = fmt.Sprintf("$%s: %s", param.Name, p.typeName(param.Type, false))
+	}
+	fmt.Fprintf(&sb, "fn @%s(%s)", fn.Name, strings.Join(params, ", "))
+	if fn.Result != nil {
+		if _, ok := fn.Result.(ast.VoidType); !ok {
+			fmt.Fprintf(&sb, " -> %s", p.typeName(fn.Result, false))
+		}
+	}
+	sb.WriteString(" {\n")
+	for _, local := range fn.Locals[len(fn.Params):] {
+		captured := ""
+		if local.Captured {
+			captured = " captured"
+		}
+		fmt.Fprintf(&sb, "  local $%s: %s%s\n", local.Name, p.typeName(local.Type, false), captured)
+	}
+	for _, block := range fn.Blocks {
+		fmt.Fprintf(&sb, "%s:\n", block)
+		for _, instr := range block.Instrs {
+			if dest := instr.Result(); dest != nil {
+				fmt.Fprintf(&sb, "  %s: %s = %s\n", dest.format(p), p.typeName(dest.DType, false), instr.format(p))
+			} else {
+				fmt.Fprintf(&sb, "  %s\n", instr.format(p))
+			}
+		}
+		fmt.Fprintf(&sb, "  %s\n", block.Term.format(p))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// typeName formats a type. Named types are shown by name unless their definition is asked for.
+func (p *Program) typeName(dtype ast.DataType, definition bool) string {
+	if t, ok := dtype.(ast.UserDefinedType); ok && !definition {
+		return p.Types.StructName(t.AliasName)
+	}
+	if t, ok := dtype.(ast.InterfaceType); ok {
+		methods := make([]string, len(t.Methods))
+		for i, method := range t.Methods {
+			methods[i] = method.Identifier.Name + p.Types.Name(method.FunctionType)[len("fn"):]
+		}
+		return "interface { " + strings.Join(methods, ", ") + " }"
+	}
+	return p.Types.Name(dtype)
+}
+
+func (p *Program) values(values []Value) string {
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		formatted[i] = value.format(p)
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func (v *Var) format(fn *Function) string {
+	switch {
+	case v.Owner == nil:
+		return "@" + v.Name
+	case v.Owner != fn:
+		return "$" + v.Owner.Name + "." + v.Name
+	}
+	return "$" + v.Name
+}
+
+func (i *Load) format(p *Program) string {
+	return "load " + i.Var.format(p.current)
+}
+
+func (i *Store) format(p *Program) string {
+	return fmt.Sprintf("store %s, %s", i.Var.format(p.current), i.Value.format(p))
+}
+
+func (i *BinOp) format(p *Program) string {
+	return fmt.Sprintf("%s %s, %s", i.Op, i.Left.format(p), i.Right.format(p))
+}
+
+func (i *UnOp) format(p *Program) string {
+	return fmt.Sprintf("%s %s", i.Op, i.Operand.format(p))
+}
+
+func (i *Zero) format(p *Program) string {
+	return "zero"
+}
+
+func (i *Convert) format(p *Program) string {
+	return fmt.Sprintf("convert %s", i.Value.format(p))
+}
+
+func (i *ToString) format(p *Program) string {
+	return fmt.Sprintf("tostring %s", i.Value.format(p))
+}
+
+func (i *ToInterface) format(p *Program) string {
+	return fmt.Sprintf("tointerface %s", i.Value.format(p))
+}
+
+func (i *Call) format(p *Program) string {
+	return fmt.Sprintf("call @%s(%s)", i.Fn.Name, p.values(i.Args))
+}
+
+func (i *CallMethod) format(p *Program) string {
+	return fmt.Sprintf("callmethod @%s(%s)", i.Fn.Name, p.values(append([]Value{i.Receiver}, i.Args...)))
+}
+
+func (i *CallInterface) format(p *Program) string {
+	return fmt.Sprintf("callinterface %s.%s(%s)", i.Receiver.format(p), i.Method, p.values(i.Args))
+}
+
+func (i *CallClosure) format(p *Program) string {
+	return fmt.Sprintf("callclosure %s(%s)", i.Callee.format(p), p.values(i.Args))
+}
+
+func (i *CallBuiltin) format(p *Program) string {
+	return fmt.Sprintf("callbuiltin %s(%s)", i.Name, p.values(i.Args))
+}
+
+func (i *MakeClosure) format(p *Program) string {
+	return "closure @" + i.Fn.Name
+}
+
+func (i *BindMethod) format(p *Program) string {
+	return fmt.Sprintf("bindmethod %s.%s", i.Receiver.format(p), i.Method)
+}
+
+func (i *MakeArray) format(p *Program) string {
+	return fmt.Sprintf("array [%s]", p.values(i.Values))
+}
+
+func (i *MakeMap) format(p *Program) string {
+	entries := make([]string, len(i.Keys))
+	for j := range i.Keys {
+		entries[j] = i.Keys[j].format(p) + " => " + i.Values[j].format(p)
+	}
+	return fmt.Sprintf("map {%s}", strings.Join(entries, ", "))
+}
+
+func (i *MakeStruct) format(p *Program) string {
+	fields := make([]string, len(i.Fields))
+	for j, field := range i.Fields {
+		fields[j] = field + ": " + i.Values[j].format(p)
+	}
+	return fmt.Sprintf("struct {%s}", strings.Join(fields, ", "))
+}
+
+func (i *MakeRange) format(p *Program) string {
+	return fmt.Sprintf("range %s, %s", i.Start.format(p), i.End.format(p))
+}
+
+func (i *Index) format(p *Program) string {
+	return fmt.Sprintf("index %s, %s", i.Container.format(p), i.Index.format(p))
+}
+
+func (i *SetIndex) format(p *Program) string {
+	return fmt.Sprintf("setindex %s, %s, %s", i.Container.format(p), i.Index.format(p), i.Value.format(p))
+}
+
+func (i *Field) format(p *Program) string {
+	return fmt.Sprintf("field %s.%s", i.Object.format(p), i.Name)
+}
+
+func (i *SetField) format(p *Program) string {
+	return fmt.Sprintf("setfield %s.%s, %s", i.Object.format(p), i.Name, i.Value.format(p))
+}
+
+func (t *Jump) format(p *Program) string {
+	return "jump " + t.Target.String()
+}
+
+func (t *Branch) format(p *Program) string {
+	return fmt.Sprintf("branch %s, %s, %s", t.Cond.format(p), t.Then, t.Else)
+}
+
+func (t *Return) format(p *Program) string {
+	if t.Value == nil {
+		return "ret"
+	}
+	return "ret " + t.Value.format(p)
+}
+
+func (t *Unreachable) format(p *Program) string {
+	return "unreachable"
+}
@@ -0,0 +1,163 @@
+package ir
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"walrus/compiler/internal/modules"
+	"walrus/compiler/internal/parser"
+	"walrus/compiler/internal/typechecker"
+	"walrus/compiler/report"
+)
+
+// lower type checks a program and lowers it.
+func lower(t *testing.T, code string) *Program {
+	t.Helper()
+
+	tmpfile, e := os.CreateTemp("", "test*.wal")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.Remove(tmpfile.Name())
+
+	if _, e := tmpfile.Write([]byte(code)); e != nil {
+		t.Fatal(e)
+	}
+	if e := tmpfile.Close(); e != nil {
+		t.Fatal(e)
+	}
+
+	defer report.ClearReports()
+
+	tree, e := parser.NewParser(tmpfile.Name(), false).Parse()
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	info := typechecker.Analyze(tree, tmpfile.Name())
+	if report.GetReports().HasErrors() {
+		t.Fatalf("Expected program to type check, got %v", report.GetReports()[0].Message)
+	}
+
+	return Lower([]modules.Module{{FilePath: tmpfile.Name(), Tree: tree}}, []*typechecker.TypeInfo{info})
+}
+
+func TestLower(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected []string
+	}{
+		{
+			name:     "Globals and constants",
+			code:     `let a := 1 + 2 * 3; let b := a;`,
+			expected: []string{"global @a: i32", "store @a, 7", "%1: i32 = load @a", "store @b, %1"},
+		},
+		{
+			name:     "An integer with a float gives a float",
+			code:     `let a := 23; let b := a / 2.0;`,
+			expected: []string{"%1: i32 = load @a", "%2: f32 = convert %1", "%3: f32 = div %2, 2.0", "store @b, %3"},
+		},
+		{
+			name:     "Conditionals branch to blocks",
+			code:     `let x := 1; if x > 0 { x = 2; } else { x = 3; }`,
+			expected: []string{"%2: bool = gt %1, 0", "branch %2, b1, b2", "b1:\n  store @x, 2\n  jump b3", "b2:\n  store @x, 3\n  jump b3", "b3:\n  ret"},
+		},
+		{
+			name:     "Loops",
+			code:     `for let i := 0; i < 3; i++ { }`,
+			expected: []string{"local $i: i32", "b1:\n  %1: i32 = load $i\n  %2: bool = lt %1, 3\n  branch %2, b2, b4", "%4: i32 = add %3, 1\n  store $i, %4\n  jump b1"},
+		},
+		{
+			name:     "Do-while loops enter the body first",
+			code:     `let n := 0; do { n++; } while n < 3;`,
+			expected: []string{"b0:\n  store @n, 0\n  jump b1\nb1:\n  %1: i32 = load @n", "%4: bool = lt %3, 3\n  branch %4, b1, b3"},
+		},
+		{
+			name:     "Break and continue jump out of the loop",
+			code:     `outer: for let i := 0; i < 3; i++ { for { if i == 1 { continue outer; } break outer; } }`,
+			expected: []string{"branch %2, b2, b8", "branch %4, b5, b7", "b5:\n  jump b6\nb6:\n  %5: i32 = load $i", "b7:\n  jump b8\nb8:\n  ret"},
+		},
+		{
+			name:     "Methods and interfaces",
+			code:     `type Point struct { x: i32 }; type Shape interface { fn area() -> i32 }; impl Point { fn area() -> i32 { ret this.x; } fn twice() -> i32 { ret area() * 2; } } let p := @Point{x: 2}; let s : Shape = p; let a := s.area(); let b := p.twice();`,
+			expected: []string{"type Point = struct { x: i32 }", "type Shape = interface { area() -> i32 }", "fn @Point.area($this: Point) -> i32 {", "%2: i32 = callmethod @Point.area(%1)", "%3: Shape = tointerface %2", "callinterface %4.area()", "callmethod @Point.twice(%6)"},
+		},
+		{
+			name:     "Closures capture variables",
+			code:     `fn counter() -> fn() -> i32 { let n := 0; ret fn() -> i32 { n++; ret n; }; }`,
+			expected: []string{"local $n: i32 captured", "%1: fn() -> i32 = closure @counter.lambda1", "fn @counter.lambda1() -> i32 {", "%1: i32 = load $counter.n"},
+		},
+		{
+			name:     "Values are converted",
+			code:     `let f := 1.5; let n := 2; let b := f < n; let s := "n: " + 1; let c := 300 as i8;`,
+			expected: []string{"%3: f64 = convert %1", "%4: f64 = convert %2", "lt %3, %4", "%6: str = tostring 1", "concat \"n: \", %6", "store @c, 44"},
+		},
+		{
+			name:     "Compound assignments evaluate the container once",
+			code:     `let m := $map[str]i32{"a" => 1}; m["a"] += 2;`,
+			expected: []string{"%2: map[str]i32 = load @m\n  %3: i32 = index %2, \"a\"\n  %4: i32 = add %3, 2\n  setindex %2, \"a\", %4"},
+		},
+		{
+			name:     "Functions and builtins are called directly",
+			code:     `fn greet(who: str) { print("hi " + who); } greet("you"); let p := print;`,
+			expected: []string{"call @greet(\"you\")", "callbuiltin print(%2)", "store @p, builtin print"},
+		},
+		{
+			name:     "Code after a return is removed",
+			code:     `fn f() -> i32 { ret 1; let x := 2; }`,
+			expected: []string{"fn @f() -> i32 {\n  local $x: i32\nb0:\n  ret 1\n}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dump := lower(t, tt.code).String()
+			for _, expected := range tt.expected {
+				if !strings.Contains(dump, expected) {
+					t.Errorf("Expected IR to contain %q, got\n%s", expected, dump)
+				}
+			}
+		})
+	}
+}
+
+func TestLoweredFunctionsAreWellFormed(t *testing.T) {
+	program := lower(t, `
+		type Point struct { x: i32, y: i32 };
+		impl Point { fn sum() -> i32 { if this.x > this.y { ret this.x; } else { ret this.y; } } }
+		fn fib(n: i32) -> i32 { if n < 2 { ret n; } ret fib(n - 1) + fib(n - 2); }
+		let total := 0;
+		for let i := 0; i < 5; i++ { if i == 2 { total += 10; } else if i > 3 { total -= 1; } }
+		let k := 0;
+		for k < 3 { k++; }
+		for { if k == 3 { break; } continue; }
+		while k > 0 { k--; }
+		do { k++; } while k < 2;
+		let p := @Point{x: 1, y: 2};
+		print("" + p.sum() + fib(10));
+	`)
+
+	for _, fn := range program.Functions {
+		defined := make(map[*Temp]bool)
+		for i, block := range fn.Blocks {
+			if block.ID != i {
+				t.Errorf("%s: expected block %d to have ID %d, got %d", fn.Name, i, i, block.ID)
+			}
+			if block.Term == nil {
+				t.Errorf("%s: block %s has no terminator", fn.Name, block)
+			}
+			for _, instr := range block.Instrs {
+				dest := instr.Result()
+				if dest == nil {
+					continue
+				}
+				if defined[dest] {
+					t.Errorf("%s: temporary %%%d is assigned twice", fn.Name, dest.ID)
+				}
+				defined[dest] = true
+			}
+		}
+	}
+}
@@ -0,0 +1,539 @@
+package ir
+
+import (
+	//Standard packages
+	"fmt"
+	"strconv"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/builtins"
+	"walrus/compiler/internal/codegen"
+	"walrus/compiler/internal/modules"
+	"walrus/compiler/internal/typechecker"
+	"walrus/compiler/internal/values"
+	"walrus/compiler/report"
+)
+
+// scope maps the names of a block to variable slots, or to the top level functions and
+// methods they call.
+type scope struct {
+	parent    *scope
+	vars      map[string]*Var
+	functions map[string]*Function
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{parent: parent, vars: make(map[string]*Var), functions: make(map[string]*Function)}
+}
+
+// lookup resolves a name to a variable or a function. Both are nil for builtins.
+func (s *scope) lookup(name string) (*Var, *Function) {
+	for current := s; current != nil; current = current.parent {
+		if v, ok := current.vars[name]; ok {
+			return v, nil
+		}
+		if fn, ok := current.functions[name]; ok {
+			return nil, fn
+		}
+	}
+	return nil, nil
+}
+
+// lowerer lowers a type checked program into the IR, with the types the typechecker
+// resolved for the module being lowered.
+type lowerer struct {
+	filePath  string
+	info      *typechecker.TypeInfo
+	types     values.TypeTable
+	program   *Program
+	functions map[string]*Function   // top level functions by name
+	methods   map[string]*Function   // methods by struct name and method name
+	implement map[string][]*Function // methods of every struct in declaration order
+	names     map[*Function]map[string]int
+	used      map[string]int // names of the lifted functions
+	fn        *Function
+	block     *Block // block being lowered, nil after a terminator
+	loops     []loop // loops around the statement being lowered, the innermost last
+	lambdas   int
+}
+
+// loop holds the blocks the 'break' and 'continue' statements of a loop jump to.
+type loop struct {
+	label string
+	next  *Block // the block starting the next iteration
+	exit  *Block
+}
+
+// Lower lowers a type checked program, from its modules in the order they are linked and
+// the type info of each of them.
+func Lower(program []modules.Module, infos []*typechecker.TypeInfo) *Program {
+	l := &lowerer{
+		types:     make(values.TypeTable),
+		functions: make(map[string]*Function),
+		methods:   make(map[string]*Function),
+		implement: make(map[string][]*Function),
+		names:     make(map[*Function]map[string]int),
+		used:      make(map[string]int),
+	}
+	l.program = &Program{Types: l.types, Main: &Function{Name: "main", Result: codegen.VoidType()}}
+	l.program.Functions = append(l.program.Functions, l.program.Main)
+
+	global := newScope(nil)
+
+	// types, methods and functions are visible to the whole program
+	l.each(program, infos, func(node ast.Node) {
+		switch t := node.(type) {
+		case ast.TypeDeclStmt:
+			switch t.UDTypeValue.(type) {
+			case ast.GenericType:
+				l.unsupported(t, "generic types cannot be lowered yet")
+			case ast.EnumType:
+				l.unsupported(t, "enum types cannot be lowered yet")
+			}
+			l.types[t.UDTypeName.Name] = t.UDTypeValue
+			l.program.TypeNames = append(l.program.TypeNames, t.UDTypeName.Name)
+		case ast.ImplStmt:
+			structName := l.types.StructName(t.ImplFor.Name)
+			for _, method := range t.Methods {
+				fn := &Function{Name: structName + "." + method.Identifier.Name, Receiver: structName}
+				l.methods[fn.Name] = fn
+				l.implement[structName] = append(l.implement[structName], fn)
+				l.used[fn.Name]++
+				l.program.Functions = append(l.program.Functions, fn)
+			}
+		case ast.FunctionDeclStmt:
+			fn := &Function{Name: t.Identifier.Name}
+			l.functions[fn.Name] = fn
+			l.used[fn.Name]++
+			l.program.Functions = append(l.program.Functions, fn)
+			global.functions[t.Identifier.Name] = fn
+		}
+	})
+
+	l.fn = l.program.Main
+	l.block = l.newBlock()
+	l.each(program, infos, func(node ast.Node) {
+		switch t := node.(type) {
+		case ast.TypeDeclStmt, ast.ImplStmt, ast.FunctionDeclStmt:
+			// lowered once every global variable is declared
+		case ast.VarDeclStmt:
+			for _, variable := range t.Variables {
+				l.varDecl(variable, global, true)
+			}
+		default:
+			l.statement(node, global)
+		}
+	})
+	l.finish(nil)
+
+	l.each(program, infos, func(node ast.Node) {
+		switch t := node.(type) {
+		case ast.ImplStmt:
+			l.implStmt(t, global)
+		case ast.FunctionDeclStmt:
+			l.function(l.functions[t.Identifier.Name], t.FunctionLiteral, global, nil)
+		}
+	})
+
+	return l.program
+}
+
+// each calls fn for the top level statements of every module but its imports, with the type
+// info of the module.
+func (l *lowerer) each(program []modules.Module, infos []*typechecker.TypeInfo, fn func(node ast.Node)) {
+	for i, module := range program {
+		l.filePath, l.info = module.FilePath, infos[i]
+		for _, node := range module.Tree.(ast.ProgramStmt).Contents {
+			if _, ok := node.(ast.ImportStmt); !ok {
+				fn(node)
+			}
+		}
+	}
+}
+
+// typeOf returns the type the typechecker resolved for an expression, or nil.
+func (l *lowerer) typeOf(node ast.Node) ast.DataType {
+	dtype, _ := l.info.DataTypeOf(node)
+	return dtype
+}
+
+// unsupported reports a node the IR cannot represent yet.
+func (l *lowerer) unsupported(node ast.Node, msg string) {
+	report.Add(l.filePath, node.StartPos().Line, node.EndPos().Line, node.StartPos().Column, node.EndPos().Column, msg).SetLevel(report.CRITICAL_ERROR)
+}
+
+// declare creates the slot of a variable. Variables shadowing a variable of the same function
+// get a numbered name.
+func (l *lowerer) declare(name string, dtype ast.DataType, s *scope, global bool) *Var {
+	owner := l.fn
+	if global {
+		owner = nil
+	}
+	names, ok := l.names[owner]
+	if !ok {
+		names = make(map[string]int)
+		l.names[owner] = names
+	}
+	unique := name
+	if n := names[name]; n > 0 {
+		unique += "." + strconv.Itoa(n)
+	}
+	names[name]++
+
+	v := &Var{Name: unique, Type: dtype, Owner: owner}
+	if global {
+		l.program.Globals = append(l.program.Globals, v)
+	} else {
+		l.fn.Locals = append(l.fn.Locals, v)
+	}
+	s.vars[name] = v
+	return v
+}
+
+// lookup resolves a name, marking the variables of enclosing functions as captured.
+func (l *lowerer) lookup(name string, s *scope) (*Var, *Function) {
+	v, fn := s.lookup(name)
+	if v != nil && v.Owner != nil && v.Owner != l.fn {
+		v.Captured = true
+	}
+	return v, fn
+}
+
+func (l *lowerer) newBlock() *Block {
+	block := &Block{ID: len(l.fn.Blocks)}
+	l.fn.Blocks = append(l.fn.Blocks, block)
+	return block
+}
+
+func (l *lowerer) temp(dtype ast.DataType) *Temp {
+	l.fn.temps++
+	return &Temp{ID: l.fn.temps, DType: dtype}
+}
+
+// emit appends an instruction to the current block. Instructions following a terminator
+// are unreachable, they go to a block that is removed once the function is lowered.
+func (l *lowerer) emit(instr Instr) {
+	if l.block == nil {
+		l.block = l.newBlock()
+	}
+	l.block.Instrs = append(l.block.Instrs, instr)
+}
+
+func (l *lowerer) terminate(term Terminator) {
+	if l.block == nil {
+		l.block = l.newBlock()
+	}
+	l.block.Term = term
+	l.block = nil
+}
+
+// jump ends the current block with a jump, unless control already left it.
+func (l *lowerer) jump(target *Block) {
+	if l.block != nil {
+		l.terminate(&Jump{Target: target})
+	}
+}
+
+// finish terminates the last block of a function and removes the unreachable blocks.
+// The blocks are laid out in reverse postorder, so a block comes before the blocks it
+// dominates and the body of a branch before the blocks following it. Blocks and
+// temporaries are numbered in that order.
+func (l *lowerer) finish(result ast.DataType) {
+	if l.block != nil {
+		if codegen.IsVoid(result) {
+			l.terminate(&Return{})
+		} else {
+			l.terminate(&Unreachable{})
+		}
+	}
+
+	visited := make(map[*Block]bool)
+	var postorder []*Block
+	var visit func(block *Block)
+	visit = func(block *Block) {
+		visited[block] = true
+		successors := block.Term.Successors()
+		for i := len(successors) - 1; i >= 0; i-- {
+			if !visited[successors[i]] {
+				visit(successors[i])
+			}
+		}
+		postorder = append(postorder, block)
+	}
+	visit(l.fn.Blocks[0])
+
+	blocks := make([]*Block, 0, len(postorder))
+	temps := 0
+	for i := len(postorder) - 1; i >= 0; i-- {
+		block := postorder[i]
+		block.ID = len(blocks)
+		blocks = append(blocks, block)
+		for _, instr := range block.Instrs {
+			if dest := instr.Result(); dest != nil {
+				temps++
+				dest.ID = temps
+			}
+		}
+	}
+	l.fn.Blocks = blocks
+	l.fn.temps = temps
+}
+
+// implStmt lowers the methods of a struct. A method receives its struct as the parameter
+// 'this', the other methods of the struct are called on it.
+func (l *lowerer) implStmt(node ast.ImplStmt, s *scope) {
+	structName := l.types.StructName(node.ImplFor.Name)
+
+	receiver := newScope(s)
+	for _, method := range l.implement[structName] {
+		receiver.functions[strings.TrimPrefix(method.Name, structName+".")] = method
+	}
+
+	this := ast.UserDefinedType{TypeName: builtins.USER_DEFINED, AliasName: structName, Location: node.ImplFor.Location}
+	for _, method := range node.Methods {
+		l.function(l.methods[structName+"."+method.Identifier.Name], method.FunctionLiteral, receiver, this)
+	}
+}
+
+// function lowers the body of a function into fn.
+func (l *lowerer) function(fn *Function, node ast.FunctionLiteral, s *scope, this ast.DataType) {
+	if len(node.TypeParams) > 0 {
+		l.unsupported(node, "generic functions cannot be lowered yet")
+	}
+
+	previousFn, previousBlock, previousLoops := l.fn, l.block, l.loops
+	defer func() { l.fn, l.block, l.loops = previousFn, previousBlock, previousLoops }()
+
+	l.fn = fn
+	l.loops = nil
+	fn.Result = codegen.ReturnType(codegen.FunctionTypeOf(node))
+
+	fnScope := newScope(s)
+	if this != nil {
+		fn.Params = append(fn.Params, l.declare("this", this, fnScope, false))
+	}
+	for _, param := range node.Params {
+		fn.Params = append(fn.Params, l.declare(param.Identifier.Name, param.Type, fnScope, false))
+	}
+
+	l.block = l.newBlock()
+	l.blockStmt(node.Body, fnScope)
+	l.finish(fn.Result)
+}
+
+// closure lifts a nested function out of the function being lowered and creates a function
+// value for it.
+func (l *lowerer) closure(node ast.FunctionLiteral, name string, s *scope) Value {
+	if name == "" {
+		l.lambdas++
+		name = "lambda" + strconv.Itoa(l.lambdas)
+	}
+	if l.fn != l.program.Main {
+		name = l.fn.Name + "." + name
+	}
+	if n := l.used[name]; n > 0 {
+		l.used[name]++
+		name += "." + strconv.Itoa(n)
+	}
+	l.used[name]++
+
+	fn := &Function{Name: name, Parent: l.fn}
+	l.program.Functions = append(l.program.Functions, fn)
+	l.function(fn, node, s, nil)
+
+	dest := l.temp(codegen.FunctionTypeOf(node))
+	l.emit(&MakeClosure{Dest: dest, Fn: fn})
+	return dest
+}
+
+func (l *lowerer) blockStmt(node ast.BlockStmt, s *scope) {
+	for _, item := range node.Contents {
+		l.statement(item, s)
+	}
+}
+
+func (l *lowerer) statement(node ast.Node, s *scope) {
+	switch t := node.(type) {
+	case ast.VarDeclStmt:
+		for _, variable := range t.Variables {
+			l.varDecl(variable, s, false)
+		}
+	case ast.FunctionDeclStmt:
+		v := l.declare(t.Identifier.Name, codegen.FunctionTypeOf(t.FunctionLiteral), s, false)
+		l.emit(&Store{Var: v, Value: l.closure(t.FunctionLiteral, t.Identifier.Name, s)})
+	case ast.IfStmt:
+		l.ifStmt(t, s)
+	case ast.ForStmt:
+		l.forStmt(t, s)
+	case ast.WhileStmt:
+		l.whileStmt(t, s)
+	case ast.ReturnStmt:
+		switch {
+		case t.Value == nil:
+			l.terminate(&Return{})
+		case codegen.IsVoid(l.fn.Result):
+			// a return at the top level of the program only ends it
+			l.expr(t.Value, s)
+			l.terminate(&Return{})
+		default:
+			l.terminate(&Return{Value: l.valueAs(t.Value, l.fn.Result, s)})
+		}
+	case ast.BreakStmt:
+		if target, ok := l.loop(t, t.Label.Name); ok {
+			l.jump(target.exit)
+		}
+	case ast.ContinueStmt:
+		if target, ok := l.loop(t, t.Label.Name); ok {
+			l.jump(target.next)
+		}
+	case ast.TypeDeclStmt:
+		l.unsupported(t, "types can only be declared at the top level of a program")
+	case ast.ImplStmt:
+		l.unsupported(t, "methods can only be implemented at the top level of a program")
+	case ast.ForEachStmt:
+		l.unsupported(t, "foreach loops cannot be lowered yet")
+	case ast.MatchStmt:
+		l.unsupported(t, "match statements cannot be lowered yet")
+	case ast.SafeStmt:
+		l.unsupported(t, "safe statements cannot be lowered yet")
+	default:
+		l.expr(t, s)
+	}
+}
+
+// varDecl declares a variable and stores its initial value.
+func (l *lowerer) varDecl(variable ast.VarDeclStmtVar, s *scope, global bool) {
+	if variable.Destructure != nil {
+		l.unsupported(variable.Value, "destructuring cannot be lowered yet")
+		return
+	}
+	dtype := variable.ExplicitType
+	if dtype == nil {
+		dtype = l.typeOf(variable.Value)
+	}
+	if dtype == nil {
+		l.unsupported(variable.Identifier, fmt.Sprintf("cannot infer the type of '%s'", variable.Identifier.Name))
+	}
+
+	var value Value
+	if variable.Value == nil {
+		value = l.zero(dtype)
+	} else {
+		value = l.valueAs(variable.Value, dtype, s)
+	}
+	v := l.declare(variable.Identifier.Name, dtype, s, global)
+	l.emit(&Store{Var: v, Value: value})
+}
+
+func (l *lowerer) ifStmt(node ast.IfStmt, s *scope) {
+	condition := l.expr(node.Condition, s)
+	then := l.newBlock()
+	var alternate *Block
+	if node.AlternateBlock != nil {
+		alternate = l.newBlock()
+	}
+	merge := l.newBlock()
+	if alternate == nil {
+		l.terminate(&Branch{Cond: condition, Then: then, Else: merge})
+	} else {
+		l.terminate(&Branch{Cond: condition, Then: then, Else: alternate})
+	}
+
+	l.block = then
+	l.blockStmt(node.Block, newScope(s))
+	l.jump(merge)
+
+	if alternate != nil {
+		l.block = alternate
+		switch t := node.AlternateBlock.(type) {
+		case ast.IfStmt:
+			l.ifStmt(t, s)
+		case ast.BlockStmt:
+			l.blockStmt(t, newScope(s))
+		}
+		l.jump(merge)
+	}
+
+	l.block = merge
+}
+
+// forStmt lowers a loop into a block testing the condition, the body, a block running the
+// increment and the block following the loop.
+func (l *lowerer) forStmt(node ast.ForStmt, s *scope) {
+	loopScope := newScope(s)
+	switch t := node.Init.(type) {
+	case nil:
+	case ast.VarDeclStmt:
+		for _, variable := range t.Variables {
+			l.varDecl(variable, loopScope, false)
+		}
+	default:
+		l.expr(t, loopScope)
+	}
+
+	condition := l.newBlock()
+	body := l.newBlock()
+	increment := l.newBlock()
+	exit := l.newBlock()
+
+	l.jump(condition)
+	l.block = condition
+	if node.Condition != nil {
+		l.terminate(&Branch{Cond: l.expr(node.Condition, loopScope), Then: body, Else: exit})
+	} else {
+		l.jump(body)
+	}
+
+	l.block = body
+	l.loops = append(l.loops, loop{label: node.Label.Name, next: increment, exit: exit})
+	l.blockStmt(node.Block, newScope(loopScope))
+	l.loops = l.loops[:len(l.loops)-1]
+	l.jump(increment)
+
+	l.block = increment
+	if node.Increment != nil {
+		l.expr(node.Increment, loopScope)
+	}
+	l.jump(condition)
+
+	l.block = exit
+}
+
+// whileStmt lowers a 'while' loop, or a 'do-while' loop, which enters its body before the
+// block checking the condition.
+func (l *lowerer) whileStmt(node ast.WhileStmt, s *scope) {
+	condition := l.newBlock()
+	body := l.newBlock()
+	exit := l.newBlock()
+
+	if node.IsDo {
+		l.jump(body)
+	} else {
+		l.jump(condition)
+	}
+
+	l.block = condition
+	l.terminate(&Branch{Cond: l.expr(node.Condition, s), Then: body, Else: exit})
+
+	l.block = body
+	l.loops = append(l.loops, loop{label: node.Label.Name, next: condition, exit: exit})
+	l.blockStmt(node.Block, newScope(s))
+	l.loops = l.loops[:len(l.loops)-1]
+	l.jump(condition)
+
+	l.block = exit
+}
+
+// loop finds the loop a 'break' or a 'continue' jumps out of, the innermost one or the one
+// with the label.
+func (l *lowerer) loop(node ast.Node, label string) (loop, bool) {
+	for i := len(l.loops) - 1; i >= 0; i-- {
+		if label == "" || l.loops[i].label == label {
+			return l.loops[i], true
+		}
+	}
+	l.unsupported(node, "'break' and 'continue' must be inside a loop")
+	return loop{}, false
+}
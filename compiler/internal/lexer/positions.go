@@ -24,7 +24,8 @@ func (p *Position) Advance(toSkip string) *Position {
 		} else {
 			p.Column++
 		}
-		p.Index++
 	}
+	// a character can take several bytes
+	p.Index += len(toSkip)
 	return p
 }
@@ -35,6 +35,12 @@ func TestAdvance(t *testing.T) {
 			toSkip:   "a\nb\nc",
 			expected: Position{Line: 3, Column: 2, Index: 5},
 		},
+		{
+			name:     "advance multi-byte characters",
+			initial:  Position{Line: 1, Column: 1, Index: 0},
+			toSkip:   "é→",
+			expected: Position{Line: 1, Column: 3, Index: 5},
+		},
 	}
 
 	for _, tt := range tests {
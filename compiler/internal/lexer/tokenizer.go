@@ -3,9 +3,13 @@ package lexer
 import (
 	//Standard packages
 	"fmt"
+	"math/big"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	//Walrus packages
 	"walrus/compiler/colors"
@@ -27,6 +31,8 @@ type Lexer struct {
 	sourceCode []byte
 	patterns   []regexPattern
 	FilePath   string
+	// the braces opened in each interpolation of a string the lexer is in, the innermost last
+	interpolations []int
 }
 
 func (lex *Lexer) advance(match string) {
@@ -70,9 +76,10 @@ func createLexer(filePath *string) *Lexer {
 			{regexp.MustCompile(`\s+`), skipHandler},                          // whitespace
 			{regexp.MustCompile(`\/\/.*`), skipHandler},                       // single line comments
 			{regexp.MustCompile(`\/\*[\s\S]*?\*\/`), skipHandler},             // multi line comments
-			{regexp.MustCompile(`"[^"]*"`), stringHandler},                    // string literals
+			{regexp.MustCompile(`"`), stringHandler},                          // string literals
+			{regexp.MustCompile("`[^`]*`"), rawStringHandler},                 // raw string literals
 			{regexp.MustCompile(`'[^']'`), byteHandler},                       // byte literals
-			{regexp.MustCompile(`[0-9]+(?:\.[0-9]+)?`), numberHandler},        // decimal numbers
+			{numberPattern, numberHandler},                                    // numbers
 			{regexp.MustCompile(`[a-zA-Z_][a-zA-Z0-9_]*`), identifierHandler}, // identifiers
 			{regexp.MustCompile(`@`), defaultHandler(AT_TOKEN, "@")},
 			{regexp.MustCompile(`\$`), defaultHandler(DOLLAR_TOKEN, "$")},
@@ -86,7 +93,12 @@ func createLexer(filePath *string) *Lexer {
 			{regexp.MustCompile(`\*=`), defaultHandler(MUL_EQUALS_TOKEN, "*=")},
 			{regexp.MustCompile(`/=`), defaultHandler(DIV_EQUALS_TOKEN, "/=")},
 			{regexp.MustCompile(`%=`), defaultHandler(MOD_EQUALS_TOKEN, "%=")},
-			{regexp.MustCompile(`\^=`), defaultHandler(EXP_EQUALS_TOKEN, "^=")},
+			{regexp.MustCompile(`\*\*=`), defaultHandler(EXP_EQUALS_TOKEN, "**=")},
+			{regexp.MustCompile(`&=`), defaultHandler(BIT_AND_EQUALS_TOKEN, "&=")},
+			{regexp.MustCompile(`\|=`), defaultHandler(BIT_OR_EQUALS_TOKEN, "|=")},
+			{regexp.MustCompile(`\^=`), defaultHandler(BIT_XOR_EQUALS_TOKEN, "^=")},
+			{regexp.MustCompile(`<<=`), defaultHandler(SHIFT_LEFT_EQUALS_TOKEN, "<<=")},
+			{regexp.MustCompile(`>>=`), defaultHandler(SHIFT_RIGHT_EQUALS_TOKEN, ">>=")},
 			{regexp.MustCompile(`\*\*`), defaultHandler(EXP_TOKEN, "**")},
 			{regexp.MustCompile(`\.\.`), defaultHandler(RANGE_TOKEN, "..")},
 			{regexp.MustCompile(`&&`), defaultHandler(AND_TOKEN, "&&")},
@@ -94,6 +106,9 @@ func createLexer(filePath *string) *Lexer {
 			{regexp.MustCompile(`&`), defaultHandler(BIT_AND_TOKEN, "&")},
 			{regexp.MustCompile(`\|`), defaultHandler(BIT_OR_TOKEN, "|")},
 			{regexp.MustCompile(`\^`), defaultHandler(BIT_XOR_TOKEN, "^")},
+			{regexp.MustCompile(`~`), defaultHandler(BIT_NOT_TOKEN, "~")},
+			{regexp.MustCompile(`<<`), defaultHandler(SHIFT_LEFT_TOKEN, "<<")},
+			{regexp.MustCompile(`>>`), defaultHandler(SHIFT_RIGHT_TOKEN, ">>")},
 			{regexp.MustCompile(`!`), defaultHandler(NOT_TOKEN, "!")},
 			{regexp.MustCompile(`\-`), defaultHandler(MINUS_TOKEN, "-")},
 			{regexp.MustCompile(`\+`), defaultHandler(PLUS_TOKEN, "+")},
@@ -101,7 +116,7 @@ func createLexer(filePath *string) *Lexer {
 			{regexp.MustCompile(`/`), defaultHandler(DIV_TOKEN, "/")},
 			{regexp.MustCompile(`%`), defaultHandler(MOD_TOKEN, "%")},
 			{regexp.MustCompile(`:=`), defaultHandler(WALRUS_TOKEN, ":=")},
-			{regexp.MustCompile(`<`), defaultHandler(LESS_EQUAL_TOKEN, "<=")},
+			{regexp.MustCompile(`<=`), defaultHandler(LESS_EQUAL_TOKEN, "<=")},
 			{regexp.MustCompile(`<`), defaultHandler(LESS_TOKEN, "<")},
 			{regexp.MustCompile(`>=`), defaultHandler(GREATER_EQUAL_TOKEN, ">=")},
 			{regexp.MustCompile(`>`), defaultHandler(GREATER_TOKEN, ">")},
@@ -117,6 +132,8 @@ func createLexer(filePath *string) *Lexer {
 			{regexp.MustCompile(`\}`), defaultHandler(CLOSE_CURLY, "}")},
 			{regexp.MustCompile(","), defaultHandler(COMMA_TOKEN, ",")},
 			{regexp.MustCompile(`\.`), defaultHandler(DOT_TOKEN, ".")},
+			{regexp.MustCompile(`\?\.`), defaultHandler(OPTIONAL_TOKEN, "?.")},
+			{regexp.MustCompile(`\?`), defaultHandler(QUESTION_TOKEN, "?")},
 		},
 	}
 	return lex
@@ -165,43 +182,234 @@ func identifierHandler(lex *Lexer, regex *regexp.Regexp) {
 	}
 }
 
+// numberPattern matches a number literal: hex '0xff', octal '0o17', binary '0b1010', or decimal
+// with a fraction and an exponent like '1.5e-3', all with '_' between digits, and the letters
+// after it, which are its type suffix like 'u8' in '10u8'.
+var numberPattern = regexp.MustCompile(`^(0[xX][0-9a-fA-F_]+|0[oO][0-7_]+|0[bB][01_]+|[0-9][0-9_]*(\.[0-9][0-9_]*)?([eE][+-]?[0-9_]+)?)([a-zA-Z0-9_]*)`)
+
+// numberSuffixes are the type suffixes of number literals.
+var numberSuffixes = map[string]builtins.TOKEN_KIND{
+	"i8": INT8_TOKEN, "i16": INT16_TOKEN, "i32": INT32_TOKEN, "i64": INT64_TOKEN, "i128": INT128_TOKEN,
+	"u8": UINT8_TOKEN, "u16": UINT16_TOKEN, "u32": UINT32_TOKEN, "u64": UINT64_TOKEN, "u128": UINT128_TOKEN,
+	"f32": FLOAT32_TOKEN, "f64": FLOAT64_TOKEN,
+}
+
 // numberHandler processes numeric tokens from the lexer input.
 // It uses a regular expression to find a numeric match in the lexer's remainder,
-// advances the lexer's position, and determines whether the number is a float or an integer.
-// Depending on the type, it pushes the appropriate token (FLOAT or INT) to the lexer's token stack.
+// advances the lexer's position, and determines the type of the number from its suffix,
+// or is untyped without a suffix: a number with a fraction or an exponent is a float, others
+// are integers.
+// The value of the token is the number in decimal, without its prefix, '_' and suffix.
 //
 // Parameters:
 //
 //	lex - A pointer to the Lexer instance.
 //	regex - A compiled regular expression used to find numeric matches in the lexer's input.
 func numberHandler(lex *Lexer, regex *regexp.Regexp) {
-	match := regex.FindString(lex.remainder())
+	parts := regex.FindStringSubmatch(lex.remainder())
+	match, number, suffix := parts[0], parts[1], parts[4]
+	isFloat := parts[2] != "" || parts[3] != ""
 	start := lex.Position
 	lex.advance(match)
 	end := lex.Position
-	//find the number is a float or an integer
-	if strings.Contains(match, ".") {
-		lex.push(NewToken(FLOAT32_TOKEN, match, start, end))
-	} else {
-		lex.push(NewToken(INT32_TOKEN, match, start, end))
+
+	fail := func(msg string, hint string) {
+		report.Add(lex.FilePath, start.Line, end.Line, start.Column, end.Column, msg).Hint(hint).SetLevel(report.SYNTAX_ERROR)
+	}
+
+	if !validSeparators(number) {
+		fail(fmt.Sprintf("invalid number literal '%s'", match), "'_' can only be used between digits")
+	}
+
+	kind := UNTYPED_INT_TOKEN
+	if isFloat {
+		kind = UNTYPED_FLOAT_TOKEN
+	}
+	if suffix != "" {
+		suffixKind, ok := numberSuffixes[suffix]
+		if !ok {
+			fail(fmt.Sprintf("invalid suffix '%s' for number literal", suffix), "the suffix of a number is its type, like '10u8' or '1.5f64'")
+		}
+		if isFloat && suffixKind != FLOAT32_TOKEN && suffixKind != FLOAT64_TOKEN {
+			fail(fmt.Sprintf("float literal '%s' cannot have the integer suffix '%s'", number, suffix), "")
+		}
+		kind = suffixKind
+	}
+
+	number = strings.ReplaceAll(number, "_", "")
+	if isFloat {
+		lex.push(NewToken(kind, number, start, end))
+		return
+	}
+
+	base := 10
+	if len(number) > 1 && number[0] == '0' && strings.ContainsRune("xXoObB", rune(number[1])) {
+		// big.Int reads the prefix with base 0
+		base = 0
 	}
+	value, ok := new(big.Int).SetString(number, base)
+	if !ok {
+		fail(fmt.Sprintf("invalid number literal '%s'", match), "")
+	}
+	if (kind == FLOAT32_TOKEN || kind == FLOAT64_TOKEN) && base == 0 {
+		fail(fmt.Sprintf("invalid number literal '%s'", match), "a float is written in decimal, like '255.0f32'")
+	}
+
+	lex.push(NewToken(kind, value.String(), start, end))
 }
 
-// stringHandler processes a string literal in the lexer, using the provided regular expression to match the string.
-// It excludes the quotes from the matched string, updates the lexer's position, and pushes a new token.
+// validSeparators reports whether every '_' of a number is between two of its digits.
+func validSeparators(number string) bool {
+	isDigit := func(c byte) bool {
+		return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+	}
+	decimal := !strings.HasPrefix(strings.ToLower(number), "0x")
+	for i := 0; i < len(number); i++ {
+		if number[i] != '_' {
+			continue
+		}
+		if i == 0 || i == len(number)-1 || !isDigit(number[i-1]) || !isDigit(number[i+1]) {
+			return false
+		}
+		// 'e' is the exponent of a decimal number, not a digit
+		if decimal && (number[i-1] == 'e' || number[i-1] == 'E' || number[i+1] == 'e' || number[i+1] == 'E') {
+			return false
+		}
+	}
+	return true
+}
+
+// stringHandler processes a string literal in the lexer, from its opening quote. See readString.
 //
 // Parameters:
 //
 //	lex   - A pointer to the Lexer instance.
-//	regex - A regular expression used to find the string literal in the lexer's remainder.
+//	regex - A regular expression matching the opening quote.
 func stringHandler(lex *Lexer, regex *regexp.Regexp) {
+	start := lex.Position
+	lex.advance(regex.FindString(lex.remainder()))
+	lex.readString(start, true)
+}
+
+// readString reads the text of a string literal up to its closing quote, and pushes a STR token
+// with the escape sequences replaced by the characters they stand for. A '{' starts an
+// interpolation: the text before it is pushed as an INTERPOLATION_START token, the tokens of
+// the expression follow, and the closing '}' goes on reading the string, see interpolationBrace.
+// The text after an interpolation ends with an INTERPOLATION_MIDDLE or INTERPOLATION_END token.
+//
+// Parameters:
+//
+//	start - The position of the opening quote, or of the brace closing the interpolation.
+//	first - Whether the text starts the literal.
+func (lex *Lexer) readString(start Position, first bool) {
+	var text strings.Builder
+
+	for !lex.atEOF() {
+		switch lex.at() {
+		case '"':
+			lex.advance(`"`)
+			kind := STR_TOKEN
+			if !first {
+				kind = INTERPOLATION_END_TOKEN
+			}
+			lex.push(NewToken(kind, text.String(), start, lex.Position))
+			return
+		case '{':
+			lex.advance("{")
+			kind := INTERPOLATION_START_TOKEN
+			if !first {
+				kind = INTERPOLATION_MIDDLE_TOKEN
+			}
+			lex.push(NewToken(kind, text.String(), start, lex.Position))
+			lex.interpolations = append(lex.interpolations, 0)
+			return
+		case '\\':
+			lex.readEscape(&text)
+		default:
+			_, size := utf8.DecodeRune(lex.sourceCode[lex.Position.Index:])
+			char := string(lex.sourceCode[lex.Position.Index : lex.Position.Index+size])
+			text.WriteString(char)
+			lex.advance(char)
+		}
+	}
+
+	report.Add(lex.FilePath, start.Line, lex.Position.Line, start.Column, lex.Position.Column, "unterminated string literal").Hint("close the string with '\"'").SetLevel(report.SYNTAX_ERROR)
+}
+
+// escapes maps the character after a backslash in a string literal to the text it stands for.
+var escapes = map[byte]string{'n': "\n", 't': "\t", 'r': "\r", '"': "\"", '\\': "\\", '{': "{", '}': "}"}
+
+var unicodeEscape = regexp.MustCompile(`^\\u\{[0-9a-fA-F]{1,6}\}`)
+
+// readEscape reads an escape sequence of a string literal and writes the character it stands
+// for: '\n', '\t', '\r', '\"', '\\', '\{', '\}', or a unicode code point in hex like '\u{1F600}'.
+func (lex *Lexer) readEscape(text *strings.Builder) {
+	start := lex.Position
+	rest := lex.sourceCode[lex.Position.Index:]
+
+	if len(rest) > 1 {
+		if char, ok := escapes[rest[1]]; ok {
+			text.WriteString(char)
+			lex.advance(string(rest[:2]))
+			return
+		}
+	}
+
+	if len(rest) > 1 && rest[1] == 'u' {
+		match := unicodeEscape.Find(rest)
+		if match == nil {
+			lex.advance(`\u`)
+			report.Add(lex.FilePath, start.Line, lex.Position.Line, start.Column, lex.Position.Column, "invalid unicode escape sequence").Hint("write the code point in hex between braces, like '\\u{1F600}'").SetLevel(report.SYNTAX_ERROR)
+			return
+		}
+		lex.advance(string(match))
+		code, _ := strconv.ParseUint(string(match[3:len(match)-1]), 16, 32)
+		if code > unicode.MaxRune || (code >= 0xD800 && code <= 0xDFFF) {
+			report.Add(lex.FilePath, start.Line, lex.Position.Line, start.Column, lex.Position.Column, fmt.Sprintf("'%s' is not a valid unicode code point", match)).SetLevel(report.SYNTAX_ERROR)
+			return
+		}
+		text.WriteRune(rune(code))
+		return
+	}
+
+	_, size := utf8.DecodeRune(rest[1:])
+	sequence := string(rest[:1+size])
+	lex.advance(sequence)
+	report.Add(lex.FilePath, start.Line, lex.Position.Line, start.Column, lex.Position.Column, fmt.Sprintf("unknown escape sequence '%s'", sequence)).Hint("write '\\\\' for a backslash").SetLevel(report.SYNTAX_ERROR)
+}
+
+// interpolationBrace keeps count of the braces in the interpolation the lexer is in. The brace
+// closing the interpolation goes back to reading its string, and it reports true.
+func (lex *Lexer) interpolationBrace() bool {
+	n := len(lex.interpolations)
+	if n == 0 {
+		return false
+	}
+
+	switch lex.at() {
+	case '{':
+		lex.interpolations[n-1]++
+	case '}':
+		if lex.interpolations[n-1] > 0 {
+			lex.interpolations[n-1]--
+			return false
+		}
+		lex.interpolations = lex.interpolations[:n-1]
+		start := lex.Position
+		lex.advance("}")
+		lex.readString(start, false)
+		return true
+	}
+	return false
+}
+
+// rawStringHandler processes a raw string literal between backticks. Its text is taken as
+// written, over several lines, without escape sequences or interpolations.
+func rawStringHandler(lex *Lexer, regex *regexp.Regexp) {
 	match := regex.FindString(lex.remainder())
-	//exclude the quotes
-	stringLiteral := match[1 : len(match)-1]
 	start := lex.Position
 	lex.advance(match)
-	end := lex.Position
-	lex.push(NewToken(STR_TOKEN, stringLiteral, start, end))
+	lex.push(NewToken(STR_TOKEN, match[1:len(match)-1], start, lex.Position))
 }
 
 // byteHandler processes a byte literal in the lexer.
@@ -237,6 +445,10 @@ func Tokenize(filename string, debug bool) []Token {
 
 	for !lex.atEOF() {
 
+		if lex.interpolationBrace() {
+			continue
+		}
+
 		matched := false
 
 		for _, pattern := range lex.patterns {
@@ -257,6 +469,10 @@ func Tokenize(filename string, debug bool) []Token {
 		}
 	}
 
+	if len(lex.interpolations) > 0 {
+		report.Add(filename, lex.Position.Line, lex.Position.Line, lex.Position.Column, lex.Position.Column, "unterminated string interpolation").Hint("close the interpolation with '}'").SetLevel(report.SYNTAX_ERROR)
+	}
+
 	lex.push(NewToken(EOF_TOKEN, "eof", lex.Position, lex.Position))
 
 	//litter.Dump(lex.Tokens)
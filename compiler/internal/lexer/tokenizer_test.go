@@ -41,14 +41,113 @@ func TestTokenize(t *testing.T) {
 				NewToken(EOF_TOKEN, "eof", Position{Line: 1, Column: 8, Index: 7}, Position{Line: 1, Column: 8, Index: 7}),
 			},
 		},
+		{
+			name:  "String escapes",
+			input: `"a\n\"\u{e9}"`,
+			expected: []Token{
+				NewToken(STR_TOKEN, "a\n\"é", Position{Line: 1, Column: 1, Index: 0}, Position{Line: 1, Column: 14, Index: 13}),
+				NewToken(EOF_TOKEN, "eof", Position{Line: 1, Column: 14, Index: 13}, Position{Line: 1, Column: 14, Index: 13}),
+			},
+		},
+		{
+			name:  "Raw string literal",
+			input: "`a\\n\nb`",
+			expected: []Token{
+				NewToken(STR_TOKEN, "a\\n\nb", Position{Line: 1, Column: 1, Index: 0}, Position{Line: 2, Column: 3, Index: 7}),
+				NewToken(EOF_TOKEN, "eof", Position{Line: 2, Column: 3, Index: 7}, Position{Line: 2, Column: 3, Index: 7}),
+			},
+		},
+		{
+			name:  "String interpolation",
+			input: `"a{x}b{y}"`,
+			expected: []Token{
+				NewToken(INTERPOLATION_START_TOKEN, "a", Position{Line: 1, Column: 1, Index: 0}, Position{Line: 1, Column: 4, Index: 3}),
+				NewToken(IDENTIFIER_TOKEN, "x", Position{Line: 1, Column: 4, Index: 3}, Position{Line: 1, Column: 5, Index: 4}),
+				NewToken(INTERPOLATION_MIDDLE_TOKEN, "b", Position{Line: 1, Column: 5, Index: 4}, Position{Line: 1, Column: 8, Index: 7}),
+				NewToken(IDENTIFIER_TOKEN, "y", Position{Line: 1, Column: 8, Index: 7}, Position{Line: 1, Column: 9, Index: 8}),
+				NewToken(INTERPOLATION_END_TOKEN, "", Position{Line: 1, Column: 9, Index: 8}, Position{Line: 1, Column: 11, Index: 10}),
+				NewToken(EOF_TOKEN, "eof", Position{Line: 1, Column: 11, Index: 10}, Position{Line: 1, Column: 11, Index: 10}),
+			},
+		},
+		{
+			name:  "Braces and strings inside an interpolation",
+			input: `"{f("}")}"`,
+			expected: []Token{
+				NewToken(INTERPOLATION_START_TOKEN, "", Position{Line: 1, Column: 1, Index: 0}, Position{Line: 1, Column: 3, Index: 2}),
+				NewToken(IDENTIFIER_TOKEN, "f", Position{Line: 1, Column: 3, Index: 2}, Position{Line: 1, Column: 4, Index: 3}),
+				NewToken(OPEN_PAREN, "(", Position{Line: 1, Column: 4, Index: 3}, Position{Line: 1, Column: 5, Index: 4}),
+				NewToken(STR_TOKEN, "}", Position{Line: 1, Column: 5, Index: 4}, Position{Line: 1, Column: 8, Index: 7}),
+				NewToken(CLOSE_PAREN, ")", Position{Line: 1, Column: 8, Index: 7}, Position{Line: 1, Column: 9, Index: 8}),
+				NewToken(INTERPOLATION_END_TOKEN, "", Position{Line: 1, Column: 9, Index: 8}, Position{Line: 1, Column: 11, Index: 10}),
+				NewToken(EOF_TOKEN, "eof", Position{Line: 1, Column: 11, Index: 10}, Position{Line: 1, Column: 11, Index: 10}),
+			},
+		},
 		{
 			name:  "Number literal",
 			input: "123",
 			expected: []Token{
-				NewToken(INT32_TOKEN, "123", Position{Line: 1, Column: 1, Index: 0}, Position{Line: 1, Column: 4, Index: 3}),
+				NewToken(UNTYPED_INT_TOKEN, "123", Position{Line: 1, Column: 1, Index: 0}, Position{Line: 1, Column: 4, Index: 3}),
 				NewToken(EOF_TOKEN, "eof", Position{Line: 1, Column: 4, Index: 3}, Position{Line: 1, Column: 4, Index: 3}),
 			},
 		},
+		{
+			name:  "Number literals with bases, separators, exponents and suffixes",
+			input: "0xff_ff 0o17 0b1010i64 1_000u8 1.5e3f64 10f32 2E-2",
+			expected: []Token{
+				NewToken(UNTYPED_INT_TOKEN, "65535", Position{Line: 1, Column: 1, Index: 0}, Position{Line: 1, Column: 8, Index: 7}),
+				NewToken(UNTYPED_INT_TOKEN, "15", Position{Line: 1, Column: 9, Index: 8}, Position{Line: 1, Column: 13, Index: 12}),
+				NewToken(INT64_TOKEN, "10", Position{Line: 1, Column: 14, Index: 13}, Position{Line: 1, Column: 23, Index: 22}),
+				NewToken(UINT8_TOKEN, "1000", Position{Line: 1, Column: 24, Index: 23}, Position{Line: 1, Column: 31, Index: 30}),
+				NewToken(FLOAT64_TOKEN, "1.5e3", Position{Line: 1, Column: 32, Index: 31}, Position{Line: 1, Column: 40, Index: 39}),
+				NewToken(FLOAT32_TOKEN, "10", Position{Line: 1, Column: 41, Index: 40}, Position{Line: 1, Column: 46, Index: 45}),
+				NewToken(UNTYPED_FLOAT_TOKEN, "2E-2", Position{Line: 1, Column: 47, Index: 46}, Position{Line: 1, Column: 51, Index: 50}),
+				NewToken(EOF_TOKEN, "eof", Position{Line: 1, Column: 51, Index: 50}, Position{Line: 1, Column: 51, Index: 50}),
+			},
+		},
+		{
+			name:  "128 bit number suffixes",
+			input: "0xffi128 7u128",
+			expected: []Token{
+				NewToken(INT128_TOKEN, "255", Position{Line: 1, Column: 1, Index: 0}, Position{Line: 1, Column: 9, Index: 8}),
+				NewToken(UINT128_TOKEN, "7", Position{Line: 1, Column: 10, Index: 9}, Position{Line: 1, Column: 15, Index: 14}),
+				NewToken(EOF_TOKEN, "eof", Position{Line: 1, Column: 15, Index: 14}, Position{Line: 1, Column: 15, Index: 14}),
+			},
+		},
+		{
+			name:  "Relational operators",
+			input: "< <=",
+			expected: []Token{
+				NewToken(LESS_TOKEN, "<", Position{Line: 1, Column: 1, Index: 0}, Position{Line: 1, Column: 2, Index: 1}),
+				NewToken(LESS_EQUAL_TOKEN, "<=", Position{Line: 1, Column: 3, Index: 2}, Position{Line: 1, Column: 5, Index: 4}),
+				NewToken(EOF_TOKEN, "eof", Position{Line: 1, Column: 5, Index: 4}, Position{Line: 1, Column: 5, Index: 4}),
+			},
+		},
+		{
+			name:  "Bitwise and shift operators",
+			input: "<<= << >>= >> **= ** ~ ^=",
+			expected: []Token{
+				NewToken(SHIFT_LEFT_EQUALS_TOKEN, "<<=", Position{Line: 1, Column: 1, Index: 0}, Position{Line: 1, Column: 4, Index: 3}),
+				NewToken(SHIFT_LEFT_TOKEN, "<<", Position{Line: 1, Column: 5, Index: 4}, Position{Line: 1, Column: 7, Index: 6}),
+				NewToken(SHIFT_RIGHT_EQUALS_TOKEN, ">>=", Position{Line: 1, Column: 8, Index: 7}, Position{Line: 1, Column: 11, Index: 10}),
+				NewToken(SHIFT_RIGHT_TOKEN, ">>", Position{Line: 1, Column: 12, Index: 11}, Position{Line: 1, Column: 14, Index: 13}),
+				NewToken(EXP_EQUALS_TOKEN, "**=", Position{Line: 1, Column: 15, Index: 14}, Position{Line: 1, Column: 18, Index: 17}),
+				NewToken(EXP_TOKEN, "**", Position{Line: 1, Column: 19, Index: 18}, Position{Line: 1, Column: 21, Index: 20}),
+				NewToken(BIT_NOT_TOKEN, "~", Position{Line: 1, Column: 22, Index: 21}, Position{Line: 1, Column: 23, Index: 22}),
+				NewToken(BIT_XOR_EQUALS_TOKEN, "^=", Position{Line: 1, Column: 24, Index: 23}, Position{Line: 1, Column: 26, Index: 25}),
+				NewToken(EOF_TOKEN, "eof", Position{Line: 1, Column: 26, Index: 25}, Position{Line: 1, Column: 26, Index: 25}),
+			},
+		},
+		{
+			name:  "Optional chaining and nullable types",
+			input: "x?.y?",
+			expected: []Token{
+				NewToken(IDENTIFIER_TOKEN, "x", Position{Line: 1, Column: 1, Index: 0}, Position{Line: 1, Column: 2, Index: 1}),
+				NewToken(OPTIONAL_TOKEN, "?.", Position{Line: 1, Column: 2, Index: 1}, Position{Line: 1, Column: 4, Index: 3}),
+				NewToken(IDENTIFIER_TOKEN, "y", Position{Line: 1, Column: 4, Index: 3}, Position{Line: 1, Column: 5, Index: 4}),
+				NewToken(QUESTION_TOKEN, "?", Position{Line: 1, Column: 5, Index: 4}, Position{Line: 1, Column: 6, Index: 5}),
+				NewToken(EOF_TOKEN, "eof", Position{Line: 1, Column: 6, Index: 5}, Position{Line: 1, Column: 6, Index: 5}),
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -16,24 +16,38 @@ const (
 	ELSE_TOKEN       builtins.TOKEN_KIND = "else"
 	FOR_TOKEN        builtins.TOKEN_KIND = "for"
 	FOREACH_TOKEN    builtins.TOKEN_KIND = "foreach"
+	WHILE_TOKEN      builtins.TOKEN_KIND = "while"
+	DO_TOKEN         builtins.TOKEN_KIND = "do"
+	MATCH_TOKEN      builtins.TOKEN_KIND = "match"
+	SAFE_TOKEN       builtins.TOKEN_KIND = "safe"
+	OTHERWISE_TOKEN  builtins.TOKEN_KIND = "otherwise"
+	NULL_TOKEN       builtins.TOKEN_KIND = builtins.NULL
+	OK_TOKEN         builtins.TOKEN_KIND = builtins.OK
+	ERR_TOKEN        builtins.TOKEN_KIND = builtins.ERR
 	IDENTIFIER_TOKEN builtins.TOKEN_KIND = "identifier"
 	PRIVATE_TOKEN    builtins.TOKEN_KIND = "priv"
 	IMPL_TOKEN       builtins.TOKEN_KIND = "impl"
 	RETURN_TOKEN     builtins.TOKEN_KIND = "ret"
+	BREAK_TOKEN      builtins.TOKEN_KIND = "break"
+	CONTINUE_TOKEN   builtins.TOKEN_KIND = "continue"
 	IN_TOKEN         builtins.TOKEN_KIND = "in"
 	AT_TOKEN         builtins.TOKEN_KIND = "@"
 	DOLLAR_TOKEN     builtins.TOKEN_KIND = "$"
 	AS_TOKEN         builtins.TOKEN_KIND = "as"
 	TYPEOF_TOKEN     builtins.TOKEN_KIND = "typeof"
+	IMPORT_TOKEN     builtins.TOKEN_KIND = "import"
+	EXPORT_TOKEN     builtins.TOKEN_KIND = "export"
 	//data types
 	INT8_TOKEN      builtins.TOKEN_KIND = builtins.INT8
 	INT16_TOKEN     builtins.TOKEN_KIND = builtins.INT16
 	INT32_TOKEN     builtins.TOKEN_KIND = builtins.INT32
 	INT64_TOKEN     builtins.TOKEN_KIND = builtins.INT64
+	INT128_TOKEN    builtins.TOKEN_KIND = builtins.INT128
 	UINT8_TOKEN     builtins.TOKEN_KIND = builtins.UINT8
 	UINT16_TOKEN    builtins.TOKEN_KIND = builtins.UINT16
 	UINT32_TOKEN    builtins.TOKEN_KIND = builtins.UINT32
 	UINT64_TOKEN    builtins.TOKEN_KIND = builtins.UINT64
+	UINT128_TOKEN   builtins.TOKEN_KIND = builtins.UINT128
 	FLOAT32_TOKEN   builtins.TOKEN_KIND = builtins.FLOAT32
 	FLOAT64_TOKEN   builtins.TOKEN_KIND = builtins.FLOAT64
 	STR_TOKEN       builtins.TOKEN_KIND = builtins.STRING
@@ -42,10 +56,18 @@ const (
 	STRUCT_TOKEN    builtins.TOKEN_KIND = builtins.STRUCT
 	FUNCTION_TOKEN  builtins.TOKEN_KIND = builtins.FUNCTION
 	INTERFACE_TOKEN builtins.TOKEN_KIND = builtins.INTERFACE
+	ENUM_TOKEN      builtins.TOKEN_KIND = builtins.ENUM
 	MAP_TOKEN       builtins.TOKEN_KIND = builtins.MAP
 
 	//array range operator
 	RANGE_TOKEN builtins.TOKEN_KIND = ".."
+	//the text of an interpolated string "text{expr}text{expr}text" around its expressions
+	INTERPOLATION_START_TOKEN  builtins.TOKEN_KIND = "\"{"
+	INTERPOLATION_MIDDLE_TOKEN builtins.TOKEN_KIND = "}{"
+	INTERPOLATION_END_TOKEN    builtins.TOKEN_KIND = "}\""
+	//number literals without a type suffix, which take the type of the place they are stored in
+	UNTYPED_INT_TOKEN   builtins.TOKEN_KIND = "untyped int"
+	UNTYPED_FLOAT_TOKEN builtins.TOKEN_KIND = "untyped float"
 	//increment and decrement
 	PLUS_PLUS_TOKEN   builtins.TOKEN_KIND = "++"
 	MINUS_MINUS_TOKEN builtins.TOKEN_KIND = "--"
@@ -53,9 +75,12 @@ const (
 	AND_TOKEN builtins.TOKEN_KIND = "&&"
 	OR_TOKEN  builtins.TOKEN_KIND = "||"
 	//bitwise operators
-	BIT_AND_TOKEN builtins.TOKEN_KIND = "&"
-	BIT_OR_TOKEN  builtins.TOKEN_KIND = "|"
-	BIT_XOR_TOKEN builtins.TOKEN_KIND = "^"
+	BIT_AND_TOKEN     builtins.TOKEN_KIND = "&"
+	BIT_OR_TOKEN      builtins.TOKEN_KIND = "|"
+	BIT_XOR_TOKEN     builtins.TOKEN_KIND = "^"
+	BIT_NOT_TOKEN     builtins.TOKEN_KIND = "~"
+	SHIFT_LEFT_TOKEN  builtins.TOKEN_KIND = "<<"
+	SHIFT_RIGHT_TOKEN builtins.TOKEN_KIND = ">>"
 	//unary operators
 	NOT_TOKEN builtins.TOKEN_KIND = "!"
 	//arithmetic operators
@@ -73,15 +98,20 @@ const (
 	NOT_EQUAL_TOKEN     builtins.TOKEN_KIND = "!="
 	DOUBLE_EQUAL_TOKEN  builtins.TOKEN_KIND = "=="
 	//assignment
-	WALRUS_TOKEN       builtins.TOKEN_KIND = ":="
-	COLON_TOKEN        builtins.TOKEN_KIND = ":"
-	EQUALS_TOKEN       builtins.TOKEN_KIND = "="
-	PLUS_EQUALS_TOKEN  builtins.TOKEN_KIND = "+="
-	MINUS_EQUALS_TOKEN builtins.TOKEN_KIND = "-="
-	MUL_EQUALS_TOKEN   builtins.TOKEN_KIND = "*="
-	DIV_EQUALS_TOKEN   builtins.TOKEN_KIND = "/="
-	MOD_EQUALS_TOKEN   builtins.TOKEN_KIND = "%="
-	EXP_EQUALS_TOKEN   builtins.TOKEN_KIND = "^="
+	WALRUS_TOKEN             builtins.TOKEN_KIND = ":="
+	COLON_TOKEN              builtins.TOKEN_KIND = ":"
+	EQUALS_TOKEN             builtins.TOKEN_KIND = "="
+	PLUS_EQUALS_TOKEN        builtins.TOKEN_KIND = "+="
+	MINUS_EQUALS_TOKEN       builtins.TOKEN_KIND = "-="
+	MUL_EQUALS_TOKEN         builtins.TOKEN_KIND = "*="
+	DIV_EQUALS_TOKEN         builtins.TOKEN_KIND = "/="
+	MOD_EQUALS_TOKEN         builtins.TOKEN_KIND = "%="
+	EXP_EQUALS_TOKEN         builtins.TOKEN_KIND = "**="
+	BIT_AND_EQUALS_TOKEN     builtins.TOKEN_KIND = "&="
+	BIT_OR_EQUALS_TOKEN      builtins.TOKEN_KIND = "|="
+	BIT_XOR_EQUALS_TOKEN     builtins.TOKEN_KIND = "^="
+	SHIFT_LEFT_EQUALS_TOKEN  builtins.TOKEN_KIND = "<<="
+	SHIFT_RIGHT_EQUALS_TOKEN builtins.TOKEN_KIND = ">>="
 	//delimiters
 	OPEN_PAREN       builtins.TOKEN_KIND = "("
 	CLOSE_PAREN      builtins.TOKEN_KIND = ")"
@@ -94,6 +124,8 @@ const (
 	SEMI_COLON_TOKEN builtins.TOKEN_KIND = ";"
 	ARROW_TOKEN      builtins.TOKEN_KIND = "->"
 	FAT_ARROW_TOKEN  builtins.TOKEN_KIND = "=>"
+	QUESTION_TOKEN   builtins.TOKEN_KIND = "?"
+	OPTIONAL_TOKEN   builtins.TOKEN_KIND = "?."
 	EOF_TOKEN        builtins.TOKEN_KIND = "eof"
 )
 
@@ -104,17 +136,30 @@ var keyWordsMap map[string]builtins.TOKEN_KIND = map[string]builtins.TOKEN_KIND{
 	"else":      ELSE_TOKEN,
 	"for":       FOR_TOKEN,
 	"foreach":   FOREACH_TOKEN,
+	"while":     WHILE_TOKEN,
+	"do":        DO_TOKEN,
+	"match":     MATCH_TOKEN,
+	"safe":      SAFE_TOKEN,
+	"otherwise": OTHERWISE_TOKEN,
+	"null":      NULL_TOKEN,
+	"ok":        OK_TOKEN,
+	"err":       ERR_TOKEN,
 	"type":      TYPE_TOKEN,
 	"typeof":    TYPEOF_TOKEN,
 	"priv":      PRIVATE_TOKEN,
 	"interface": INTERFACE_TOKEN,
 	"impl":      IMPL_TOKEN,
 	"struct":    STRUCT_TOKEN,
+	"enum":      ENUM_TOKEN,
 	"fn":        FUNCTION_TOKEN,
 	"map":       MAP_TOKEN,
 	"ret":       RETURN_TOKEN,
+	"break":     BREAK_TOKEN,
+	"continue":  CONTINUE_TOKEN,
 	"in":        IN_TOKEN,
 	"as":        AS_TOKEN,
+	"import":    IMPORT_TOKEN,
+	"export":    EXPORT_TOKEN,
 }
 
 func IsKeyword(token string) bool {
@@ -134,9 +179,9 @@ type Token struct {
 func (t *Token) Debug(filename string) {
 	colors.GREY.Printf("%s:%d:%d ", filename, t.Start.Line, t.Start.Column)
 	if t.Value == string(t.Kind) {
-		fmt.Printf("'%s'\n", t.Value)
+		fmt.Fprintf(colors.Output(), "'%s'\n", t.Value)
 	} else {
-		fmt.Printf("'%s' ('%v')\n", t.Value, t.Kind)
+		fmt.Fprintf(colors.Output(), "'%s' ('%v')\n", t.Value, t.Kind)
 	}
 }
 
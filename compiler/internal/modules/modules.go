@@ -0,0 +1,201 @@
+package modules
+
+import (
+	//Standard packages
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/parser"
+	"walrus/compiler/report"
+)
+
+// Module is a parsed file of a program.
+type Module struct {
+	FilePath string
+	Tree     ast.Node
+	Imports  map[string]string // import path as written -> file it resolves to
+}
+
+const (
+	visiting = iota + 1
+	done
+)
+
+type loader struct {
+	debug   bool
+	modules []Module
+	states  map[string]int
+	stack   []string // files being loaded, the importer last
+}
+
+// Load parses the file and every file it imports. The modules are returned in dependency
+// order, every module after the modules it imports and the entry file last.
+// Missing files and import cycles are reported against the importing file.
+func Load(filePath string, debug bool) ([]Module, error) {
+	return LoadAll([]string{filePath}, debug)
+}
+
+// LoadAll is Load for several files, like the files of a project. Every file is parsed once,
+// however many of the files import it, and the modules are returned in dependency order.
+func LoadAll(filePaths []string, debug bool) ([]Module, error) {
+	l := &loader{
+		debug:  debug,
+		states: make(map[string]int),
+	}
+	for _, filePath := range filePaths {
+		filePath = filepath.Clean(filePath)
+		if l.states[filePath] == done {
+			continue
+		}
+		if e := l.load(filePath); e != nil {
+			return nil, e
+		}
+	}
+	return l.modules, nil
+}
+
+// Resolve returns the file an import path of the importer refers to. Relative paths are
+// resolved from the folder of the importer and the '.wal' extension can be left out.
+func Resolve(importer, path string) string {
+	if !strings.HasSuffix(path, ".wal") {
+		path += ".wal"
+	}
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(filepath.Dir(importer), path)
+}
+
+func (l *loader) load(filePath string) error {
+
+	l.states[filePath] = visiting
+	l.stack = append(l.stack, filePath)
+
+	tree, e := parser.NewParser(filePath, l.debug).Parse()
+	if e != nil {
+		return e
+	}
+
+	module := Module{
+		FilePath: filePath,
+		Tree:     tree,
+		Imports:  make(map[string]string),
+	}
+
+	program, ok := tree.(ast.ProgramStmt)
+	if !ok {
+		return fmt.Errorf("'%s' is not a program", filePath)
+	}
+
+	for _, node := range program.Contents {
+		importStmt, ok := node.(ast.ImportStmt)
+		if !ok {
+			continue
+		}
+
+		resolved := Resolve(filePath, importStmt.Path)
+		module.Imports[importStmt.Path] = resolved
+
+		switch l.states[resolved] {
+		case done:
+			continue
+		case visiting:
+			report.Add(filePath, importStmt.Start.Line, importStmt.End.Line, importStmt.Start.Column, importStmt.End.Column, fmt.Sprintf("import cycle: %s", l.cycle(resolved))).SetLevel(report.NORMAL_ERROR)
+			continue
+		}
+
+		if info, e := os.Stat(resolved); e != nil || info.IsDir() {
+			report.Add(filePath, importStmt.Start.Line, importStmt.End.Line, importStmt.Start.Column, importStmt.End.Column, fmt.Sprintf("cannot find module '%s'", importStmt.Path)).SetLevel(report.NORMAL_ERROR)
+			continue
+		}
+
+		if e := l.load(resolved); e != nil {
+			return e
+		}
+	}
+
+	l.stack = l.stack[:len(l.stack)-1]
+	l.states[filePath] = done
+	l.modules = append(l.modules, module)
+
+	return nil
+}
+
+// cycle describes the chain of imports from the file back to itself.
+func (l *loader) cycle(filePath string) string {
+	start := 0
+	for i, file := range l.stack {
+		if file == filePath {
+			start = i
+			break
+		}
+	}
+	chain := append([]string{}, l.stack[start:]...)
+	return strings.Join(append(chain, filePath), " -> ")
+}
+
+// Link joins the modules into a single program for the backends. The top level declarations
+// of every module are kept in dependency order and the import statements are dropped.
+// The modules share one top level, so a name declared at the top level of two modules is
+// reported. The type checker renames the private names of checked modules which clash, so
+// only exported names are left to clash.
+func Link(modules []Module) ast.Node {
+
+	contents := make([]ast.Node, 0)
+	declared := make(map[string]string) // name -> file declaring it
+
+	for _, module := range modules {
+		program := module.Tree.(ast.ProgramStmt)
+		for _, node := range program.Contents {
+			if _, ok := node.(ast.ImportStmt); ok {
+				continue
+			}
+			for _, identifier := range topLevelNames(node) {
+				file, ok := declared[identifier.Name]
+				if ok && file != module.FilePath {
+					report.Add(module.FilePath, identifier.Start.Line, identifier.End.Line, identifier.Start.Column, identifier.End.Column, fmt.Sprintf("'%s' is already declared in '%s'", identifier.Name, file)).Hint("exported names must be unique across the files of a program").SetLevel(report.NORMAL_ERROR)
+					continue
+				}
+				declared[identifier.Name] = module.FilePath
+			}
+			contents = append(contents, node)
+		}
+	}
+
+	entry := modules[len(modules)-1].Tree.(ast.ProgramStmt)
+
+	return ast.ProgramStmt{
+		Contents: contents,
+		Location: entry.Location,
+	}
+}
+
+// topLevelNames returns the names a top level statement declares.
+func topLevelNames(node ast.Node) []ast.IdentifierExpr {
+	switch t := node.(type) {
+	case ast.VarDeclStmt:
+		names := make([]ast.IdentifierExpr, 0, len(t.Variables))
+		for _, variable := range t.Variables {
+			if variable.Destructure == nil {
+				names = append(names, variable.Identifier)
+				continue
+			}
+			for _, name := range variable.Destructure.Names {
+				if name.Name != "_" {
+					names = append(names, name)
+				}
+			}
+		}
+		return names
+	case ast.FunctionDeclStmt:
+		return []ast.IdentifierExpr{t.Identifier}
+	case ast.TypeDeclStmt:
+		return []ast.IdentifierExpr{t.UDTypeName}
+	default:
+		return nil
+	}
+}
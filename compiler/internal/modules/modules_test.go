@@ -0,0 +1,169 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/report"
+)
+
+// writeFiles writes the files in a temporary folder and returns the folder.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, code := range files {
+		path := filepath.Join(dir, name)
+		if e := os.MkdirAll(filepath.Dir(path), 0755); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.WriteFile(path, []byte(code), 0644); e != nil {
+			t.Fatal(e)
+		}
+	}
+	return dir
+}
+
+func TestLoadOrdersModulesByDependency(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"main.wal":      `import "lib/a"; import "b.wal"; let x := 1;`,
+		"lib/a.wal":     `import "../b"; export let a := 1;`,
+		"b.wal":         `export let b := 2;`,
+		"lib/other.wal": `let unused := 3;`,
+	})
+	defer report.ClearReports()
+
+	loaded, e := Load(filepath.Join(dir, "main.wal"), false)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if report.GetReports().HasErrors() {
+		t.Fatalf("Expected no errors, got %v", report.GetReports()[0].Message)
+	}
+
+	expected := []string{"b.wal", "lib/a.wal", "main.wal"}
+	if len(loaded) != len(expected) {
+		t.Fatalf("Expected %d modules, got %d", len(expected), len(loaded))
+	}
+	for i, module := range loaded {
+		if module.FilePath != filepath.Join(dir, expected[i]) {
+			t.Errorf("Expected module %d to be %s, got %s", i, expected[i], module.FilePath)
+		}
+	}
+
+	if got := loaded[2].Imports["lib/a"]; got != filepath.Join(dir, "lib/a.wal") {
+		t.Errorf("Expected 'lib/a' to resolve to lib/a.wal, got %s", got)
+	}
+}
+
+func TestLoadAllParsesEveryFileOnce(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"main.wal":  `import "lib/a"; import "b"; let x := 1;`,
+		"lib/a.wal": `import "../b"; export let a := 1;`,
+		"b.wal":     `export let b := 2;`,
+		"tool.wal":  `import "b"; let y := 3;`,
+	})
+	defer report.ClearReports()
+
+	var paths []string
+	for _, file := range []string{"main.wal", "tool.wal", "lib/a.wal", "b.wal"} {
+		paths = append(paths, filepath.Join(dir, file))
+	}
+
+	loaded, e := LoadAll(paths, false)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if report.GetReports().HasErrors() {
+		t.Fatalf("Expected no errors, got %v", report.GetReports()[0].Message)
+	}
+
+	expected := []string{"b.wal", "lib/a.wal", "main.wal", "tool.wal"}
+	if len(loaded) != len(expected) {
+		t.Fatalf("Expected %d modules, got %d", len(expected), len(loaded))
+	}
+	for i, module := range loaded {
+		if module.FilePath != filepath.Join(dir, expected[i]) {
+			t.Errorf("Expected module %d to be %s, got %s", i, expected[i], module.FilePath)
+		}
+	}
+}
+
+func TestLoadReportsAgainstTheImporter(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		file     string
+		expected string
+	}{
+		{
+			name:     "Missing file",
+			files:    map[string]string{"main.wal": `import "lib/a"; let x := 1;`, "lib/a.wal": `import "nope";`},
+			file:     "lib/a.wal",
+			expected: "cannot find module 'nope'",
+		},
+		{
+			name:     "Import cycle",
+			files:    map[string]string{"main.wal": `import "a";`, "a.wal": `import "b";`, "b.wal": `import "a";`},
+			file:     "b.wal",
+			expected: "import cycle: ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeFiles(t, tt.files)
+			defer report.ClearReports()
+
+			if _, e := Load(filepath.Join(dir, "main.wal"), false); e != nil {
+				t.Fatal(e)
+			}
+
+			reports := report.GetReports()
+			if len(reports) != 1 {
+				t.Fatalf("Expected 1 report, got %d", len(reports))
+			}
+			if reports[0].FilePath != filepath.Join(dir, tt.file) {
+				t.Errorf("Expected the report on %s, got %s", tt.file, reports[0].FilePath)
+			}
+			if !strings.HasPrefix(reports[0].Message, tt.expected) {
+				t.Errorf("Expected %q, got %q", tt.expected, reports[0].Message)
+			}
+		})
+	}
+}
+
+func TestLink(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"main.wal": `import "a"; fn helper() {} let x := 1;`,
+		"a.wal":    `export fn helper() {} let y := 2;`,
+	})
+	defer report.ClearReports()
+
+	loaded, e := Load(filepath.Join(dir, "main.wal"), false)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	program := Link(loaded).(ast.ProgramStmt)
+
+	if len(program.Contents) != 4 {
+		t.Fatalf("Expected 4 top level statements, got %d", len(program.Contents))
+	}
+	for _, node := range program.Contents {
+		if _, ok := node.(ast.ImportStmt); ok {
+			t.Errorf("Expected import statements to be dropped")
+		}
+	}
+	if _, ok := program.Contents[0].(ast.FunctionDeclStmt); !ok {
+		t.Errorf("Expected the imported module first, got %T", program.Contents[0])
+	}
+
+	reports := report.GetReports()
+	if len(reports) != 1 || reports[0].FilePath != filepath.Join(dir, "main.wal") {
+		t.Fatalf("Expected the duplicate 'helper' to be reported on main.wal, got %d reports", len(reports))
+	}
+}
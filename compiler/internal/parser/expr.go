@@ -76,7 +76,7 @@ func parsePrimaryExpr(p *Parser) ast.Node {
 	}
 
 	switch primaryToken.Kind {
-	case lexer.INT8_TOKEN, lexer.INT16_TOKEN, lexer.INT32_TOKEN, lexer.INT64_TOKEN, lexer.UINT8_TOKEN, lexer.UINT16_TOKEN, lexer.UINT32_TOKEN, lexer.UINT64_TOKEN:
+	case lexer.INT8_TOKEN, lexer.INT16_TOKEN, lexer.INT32_TOKEN, lexer.INT64_TOKEN, lexer.INT128_TOKEN, lexer.UINT8_TOKEN, lexer.UINT16_TOKEN, lexer.UINT32_TOKEN, lexer.UINT64_TOKEN, lexer.UINT128_TOKEN:
 		return ast.IntegerLiteralExpr{
 			Value:    rawValue,
 			BitSize:  builtins.GetBitSize(builtins.PARSER_TYPE(primaryToken.Kind)),
@@ -90,6 +90,23 @@ func parsePrimaryExpr(p *Parser) ast.Node {
 			BitSize:  builtins.GetBitSize(builtins.PARSER_TYPE(primaryToken.Kind)),
 			Location: loc,
 		}
+	case lexer.UNTYPED_INT_TOKEN:
+		// an i32 until it is stored in a place of another number type
+		return ast.IntegerLiteralExpr{
+			Value:     rawValue,
+			BitSize:   32,
+			IsSigned:  true,
+			IsUntyped: true,
+			Location:  loc,
+		}
+	case lexer.UNTYPED_FLOAT_TOKEN:
+		// an f32 until it is stored in a place of another float type
+		return ast.FloatLiteralExpr{
+			Value:     rawValue,
+			BitSize:   32,
+			IsUntyped: true,
+			Location:  loc,
+		}
 
 	case lexer.STR_TOKEN:
 		return ast.StringLiteralExpr{
@@ -101,6 +118,11 @@ func parsePrimaryExpr(p *Parser) ast.Node {
 			Name:     rawValue,
 			Location: loc,
 		}
+	case lexer.NULL_TOKEN:
+		return ast.NullLiteralExpr{
+			Value:    rawValue,
+			Location: loc,
+		}
 	default:
 		msg := fmt.Sprintf("Cannot create primary expression from %s\n", primaryToken.Value)
 		report.Add(p.FilePath, p.currentToken().Start.Line, p.currentToken().End.Line, p.currentToken().Start.Column, p.currentToken().End.Column, msg).SetLevel(report.SYNTAX_ERROR)
@@ -111,6 +133,7 @@ func parsePrimaryExpr(p *Parser) ast.Node {
 
 // parseGroupingExpr parses a grouping expression enclosed in parentheses.
 // It expects an opening parenthesis, followed by an expression, and a closing parenthesis.
+// Values separated by commas in the parentheses are a tuple, like '(a, b)'.
 // Returns the parsed expression node.
 //
 // Parameters:
@@ -119,8 +142,13 @@ func parsePrimaryExpr(p *Parser) ast.Node {
 // Returns:
 // - ast.Node: The parsed expression node.
 func parseGroupingExpr(p *Parser) ast.Node {
-	p.expect(lexer.OPEN_PAREN)
+	start := p.expect(lexer.OPEN_PAREN).Start
 	expr := parseExpr(p, DEFAULT_BP)
+	if p.currentTokenKind() == lexer.COMMA_TOKEN {
+		tuple := parseTupleValues(p, start, expr)
+		tuple.End = p.expect(lexer.CLOSE_PAREN).End
+		return tuple
+	}
 	p.expect(lexer.CLOSE_PAREN)
 	return expr
 }
@@ -205,7 +233,7 @@ func parseUnaryExpr(p *Parser) ast.Node {
 	operator := p.eat()
 
 	switch operator.Kind {
-	case lexer.MINUS_TOKEN, lexer.NOT_TOKEN:
+	case lexer.MINUS_TOKEN, lexer.NOT_TOKEN, lexer.BIT_NOT_TOKEN:
 		break
 	default:
 		report.Add(p.FilePath, operator.Start.Line, operator.End.Line, operator.Start.Column, operator.End.Column, fmt.Sprintf("invalid unary operator '%s'", operator.Value)).SetLevel(report.SYNTAX_ERROR)
@@ -238,6 +266,11 @@ func parseBinaryExpr(p *Parser, left ast.Node, bp BINDING_POWER) ast.Node {
 
 	op := p.eat()
 
+	// '**' is right associative, so the right operand takes the following '**' too
+	if op.Kind == lexer.EXP_TOKEN {
+		bp--
+	}
+
 	right := parseExpr(p, bp)
 
 	return ast.BinaryExpr{
@@ -1,6 +1,9 @@
 package parser
 
 import (
+	//Standard packages
+	"fmt"
+
 	//Walrus packages
 	"walrus/compiler/internal/ast"
 	"walrus/compiler/internal/lexer"
@@ -41,8 +44,8 @@ func parseLambdaFunction(p *Parser) ast.Node {
 //
 // The function expects the parser to be positioned at the start of the function
 // declaration (i.e., the 'fn' token). It advances the parser, consumes the
-// function name, parses the function signature (parameters and return type),
-// and then parses the function body block.
+// function name and its type parameters if it is generic, parses the function
+// signature (parameters and return type), and then parses the function body block.
 //
 // Parameters:
 //   - p: A pointer to the Parser instance.
@@ -56,6 +59,8 @@ func parseFunctionDeclStmt(p *Parser) ast.Node {
 
 	nameToken := p.expect(lexer.IDENTIFIER_TOKEN)
 
+	typeParams := parseTypeParams(p)
+
 	params, returnType := parseFunctionSignature(p)
 
 	block := parseBlock(p)
@@ -69,6 +74,7 @@ func parseFunctionDeclStmt(p *Parser) ast.Node {
 			},
 		},
 		FunctionLiteral: ast.FunctionLiteral{
+			TypeParams: typeParams,
 			Params:     params,
 			ReturnType: returnType,
 			Body:       block,
@@ -108,6 +114,11 @@ func parseFunctionSignature(p *Parser) ([]ast.FunctionParam, ast.DataType) {
 		}
 		// if : then the param is not optional
 		// if ?: then the param is optional
+		optional := p.currentTokenKind() == lexer.QUESTION_TOKEN
+		if optional {
+			p.eat()
+		}
+
 		currentToken := p.currentToken()
 
 		if currentToken.Kind != lexer.COLON_TOKEN {
@@ -117,8 +128,18 @@ func parseFunctionSignature(p *Parser) ([]ast.FunctionParam, ast.DataType) {
 		p.eat()
 
 		paramType := parseType(p, DEFAULT_BP)
+		end := paramType.EndPos()
 
+		// an optional param takes its default value when the call leaves it out
 		var defaultValue ast.Node
+		if token := p.currentToken(); optional || token.Kind == lexer.EQUALS_TOKEN {
+			if !optional {
+				report.Add(p.FilePath, token.Start.Line, token.End.Line, token.Start.Column, token.End.Column, fmt.Sprintf("parameter '%s' with a default value must be optional", param.Name)).Hint(fmt.Sprintf("write '%s?:' instead of '%s:'", param.Name, param.Name)).SetLevel(report.SYNTAX_ERROR)
+			}
+			p.expectError(lexer.EQUALS_TOKEN, fmt.Errorf("optional parameter '%s' must have a default value", param.Name))
+			defaultValue = parseExpr(p, DEFAULT_BP)
+			end = defaultValue.EndPos()
+		}
 
 		params = append(params, ast.FunctionParam{
 			Identifier:   param,
@@ -126,7 +147,7 @@ func parseFunctionSignature(p *Parser) ([]ast.FunctionParam, ast.DataType) {
 			DefaultValue: defaultValue,
 			Location: ast.Location{
 				Start: param.Start,
-				End:   paramType.EndPos(),
+				End:   end,
 			},
 		})
 
@@ -0,0 +1,113 @@
+package parser
+
+import (
+	//Standard packages
+	"errors"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/builtins"
+	"walrus/compiler/internal/lexer"
+)
+
+// closingAngles maps the tokens starting with '>' to what is left of them once the '>'
+// closing a type parameter list is taken, so 'Box<Box<i32>>' closes both lists.
+var closingAngles = map[builtins.TOKEN_KIND]builtins.TOKEN_KIND{
+	lexer.SHIFT_RIGHT_TOKEN:        lexer.GREATER_TOKEN,
+	lexer.SHIFT_RIGHT_EQUALS_TOKEN: lexer.GREATER_EQUAL_TOKEN,
+	lexer.GREATER_EQUAL_TOKEN:      lexer.EQUALS_TOKEN,
+}
+
+// expectClosingAngle expects the '>' closing a type parameter or type argument list. A
+// token starting with '>' is split, and the rest of it stays the current token.
+func expectClosingAngle(p *Parser) lexer.Token {
+
+	token := p.currentToken()
+
+	rest, ok := closingAngles[token.Kind]
+	if !ok {
+		return p.expectError(lexer.GREATER_TOKEN, errors.New("expected '>' to close the type parameters"))
+	}
+
+	end := token.Start
+	end.Advance(">")
+
+	p.tokens[p.index] = lexer.NewToken(rest, string(rest), end, token.End)
+
+	return lexer.NewToken(lexer.GREATER_TOKEN, string(lexer.GREATER_TOKEN), token.Start, end)
+}
+
+// parseTypeParams parses the type parameters of a generic function or type, if any. A
+// parameter may be constrained by an interface:
+//
+//	fn largest<T: Sized>(items: []T) -> T { ... }
+func parseTypeParams(p *Parser) []ast.TypeParam {
+
+	if p.currentTokenKind() != lexer.LESS_TOKEN {
+		return nil
+	}
+
+	p.eat() // eat < token
+
+	var params []ast.TypeParam
+
+	for p.hasToken() && p.currentTokenKind() != lexer.GREATER_TOKEN {
+
+		name := p.expectError(lexer.IDENTIFIER_TOKEN, errors.New("expected a type parameter name"))
+
+		param := ast.TypeParam{
+			Identifier: ast.IdentifierExpr{
+				Name: name.Value,
+				Location: ast.Location{
+					Start: name.Start,
+					End:   name.End,
+				},
+			},
+			Location: ast.Location{
+				Start: name.Start,
+				End:   name.End,
+			},
+		}
+
+		if p.currentTokenKind() == lexer.COLON_TOKEN {
+			p.eat() // eat : token
+			param.Constraint = parseType(p, DEFAULT_BP)
+			param.End = param.Constraint.EndPos()
+		}
+
+		params = append(params, param)
+
+		if p.currentTokenKind() != lexer.GREATER_TOKEN {
+			p.expect(lexer.COMMA_TOKEN)
+		}
+	}
+
+	expectClosingAngle(p)
+
+	return params
+}
+
+// parseTypeArgs parses the type arguments of a generic type, like '<i32, str>' in
+// 'Pair<i32, str>'. It returns the arguments and the end of the list.
+func parseTypeArgs(p *Parser) ([]ast.DataType, lexer.Position) {
+
+	p.eat() // eat < token
+
+	var args []ast.DataType
+
+	for p.hasToken() && p.currentTokenKind() != lexer.GREATER_TOKEN {
+		if _, ok := closingAngles[p.currentTokenKind()]; ok {
+			break
+		}
+
+		args = append(args, parseType(p, DEFAULT_BP))
+
+		if _, ok := closingAngles[p.currentTokenKind()]; !ok && p.currentTokenKind() != lexer.GREATER_TOKEN {
+			p.expect(lexer.COMMA_TOKEN)
+		}
+	}
+
+	end := expectClosingAngle(p).End
+
+	return args, end
+}
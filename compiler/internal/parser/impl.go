@@ -19,6 +19,14 @@ func parseImplStmt(p *Parser) ast.Node {
 
 	typeName := p.expectError(lexer.IDENTIFIER_TOKEN, errMsg)
 
+	// a generic struct names its type parameters, like 'impl Box<T>'
+	var typeParams []ast.IdentifierExpr
+	if p.currentTokenKind() == lexer.LESS_TOKEN {
+		for _, param := range parseTypeParams(p) {
+			typeParams = append(typeParams, param.Identifier)
+		}
+	}
+
 	p.expect(lexer.OPEN_CURLY)
 
 	methods := make([]ast.MethodToImplement, 0)
@@ -36,6 +44,8 @@ func parseImplStmt(p *Parser) ast.Node {
 
 		fnName := p.expect(lexer.IDENTIFIER_TOKEN)
 
+		methodTypeParams := parseTypeParams(p)
+
 		params, ret := parseFunctionSignature(p)
 
 		body := parseBlock(p)
@@ -51,6 +61,7 @@ func parseImplStmt(p *Parser) ast.Node {
 					},
 				},
 				FunctionLiteral: ast.FunctionLiteral{
+					TypeParams: methodTypeParams,
 					Params:     params,
 					ReturnType: ret,
 					Body:       body,
@@ -75,7 +86,8 @@ func parseImplStmt(p *Parser) ast.Node {
 				End:   typeName.End,
 			},
 		},
-		Methods: methods,
+		TypeParams: typeParams,
+		Methods:    methods,
 		Location: ast.Location{
 			Start: start,
 			End:   end,
@@ -12,10 +12,16 @@ const (
 	DEFAULT_BP BINDING_POWER = iota
 	ASSIGNMENT_BP
 	CASTING_BP
-	LOGICAL_BP
+	LOGICAL_OR_BP
+	LOGICAL_AND_BP
 	RELATIONAL_BP
+	BITWISE_OR_BP
+	BITWISE_XOR_BP
+	BITWISE_AND_BP
+	SHIFT_BP
 	ADDITIVE_BP
 	MULTIPLICATIVE_BP
+	EXPONENT_BP
 	UNARY_BP
 	CALL_BP
 	PRIMARY_BP
@@ -62,12 +68,18 @@ func bindLookupHandlers() {
 	led(lexer.DIV_EQUALS_TOKEN, ASSIGNMENT_BP, parseVarAssignmentExpr)
 	led(lexer.MOD_EQUALS_TOKEN, ASSIGNMENT_BP, parseVarAssignmentExpr)
 	led(lexer.EXP_EQUALS_TOKEN, ASSIGNMENT_BP, parseVarAssignmentExpr)
+	led(lexer.BIT_AND_EQUALS_TOKEN, ASSIGNMENT_BP, parseVarAssignmentExpr)
+	led(lexer.BIT_OR_EQUALS_TOKEN, ASSIGNMENT_BP, parseVarAssignmentExpr)
+	led(lexer.BIT_XOR_EQUALS_TOKEN, ASSIGNMENT_BP, parseVarAssignmentExpr)
+	led(lexer.SHIFT_LEFT_EQUALS_TOKEN, ASSIGNMENT_BP, parseVarAssignmentExpr)
+	led(lexer.SHIFT_RIGHT_EQUALS_TOKEN, ASSIGNMENT_BP, parseVarAssignmentExpr)
 
 	led(lexer.OPEN_BRACKET, MEMBER_BP, parseIndexable)
 
 	led(lexer.RANGE_TOKEN, PRIMARY_BP, parseRange)
 
 	led(lexer.DOT_TOKEN, MEMBER_BP, parsePropertyExpr)
+	led(lexer.OPTIONAL_TOKEN, MEMBER_BP, parsePropertyExpr)
 	led(lexer.OPEN_PAREN, CALL_BP, parseCallExpr)
 
 	//arithmetics
@@ -76,7 +88,18 @@ func bindLookupHandlers() {
 	led(lexer.MUL_TOKEN, MULTIPLICATIVE_BP, parseBinaryExpr)
 	led(lexer.DIV_TOKEN, MULTIPLICATIVE_BP, parseBinaryExpr)
 	led(lexer.MOD_TOKEN, MULTIPLICATIVE_BP, parseBinaryExpr)
-	led(lexer.EXP_TOKEN, MULTIPLICATIVE_BP, parseBinaryExpr)
+	led(lexer.EXP_TOKEN, EXPONENT_BP, parseBinaryExpr) // right associative
+
+	//bitwise
+	led(lexer.SHIFT_LEFT_TOKEN, SHIFT_BP, parseBinaryExpr)
+	led(lexer.SHIFT_RIGHT_TOKEN, SHIFT_BP, parseBinaryExpr)
+	led(lexer.BIT_AND_TOKEN, BITWISE_AND_BP, parseBinaryExpr)
+	led(lexer.BIT_XOR_TOKEN, BITWISE_XOR_BP, parseBinaryExpr)
+	led(lexer.BIT_OR_TOKEN, BITWISE_OR_BP, parseBinaryExpr)
+
+	//logical
+	led(lexer.AND_TOKEN, LOGICAL_AND_BP, parseBinaryExpr)
+	led(lexer.OR_TOKEN, LOGICAL_OR_BP, parseBinaryExpr)
 
 	led(lexer.DOUBLE_EQUAL_TOKEN, RELATIONAL_BP, parseBinaryExpr)
 	led(lexer.NOT_EQUAL_TOKEN, RELATIONAL_BP, parseBinaryExpr)
@@ -90,6 +113,7 @@ func bindLookupHandlers() {
 	//Postfix
 	led(lexer.PLUS_PLUS_TOKEN, UNARY_BP, parsePostfixExpr)   // a++
 	led(lexer.MINUS_MINUS_TOKEN, UNARY_BP, parsePostfixExpr) // a--
+	led(lexer.QUESTION_TOKEN, MEMBER_BP, parsePropagateExpr) // a?
 
 	nud(lexer.IDENTIFIER_TOKEN, parsePrimaryExpr)  // identifier
 	nud(lexer.INT8_TOKEN, parsePrimaryExpr)        // int literal, 8 bit
@@ -103,15 +127,26 @@ func bindLookupHandlers() {
 	nud(lexer.UINT32_TOKEN, parsePrimaryExpr)      // uint literal, 32 bit
 	nud(lexer.UINT64_TOKEN, parsePrimaryExpr)      // uint literal, 64 bit
 	nud(lexer.STR_TOKEN, parsePrimaryExpr)         // string literal
+	nud(lexer.NULL_TOKEN, parsePrimaryExpr)        // null literal
 	nud(lexer.OPEN_BRACKET, parseArrayExpr)        // array literal [1,2,3]
 	nud(lexer.OPEN_PAREN, parseGroupingExpr)       // grouping expression a + (b+c)
 	nud(lexer.FUNCTION_TOKEN, parseLambdaFunction) // anonymous function
 	nud(lexer.AT_TOKEN, parseStructLiteral)
 	nud(lexer.DOLLAR_TOKEN, parseMapLiteral)
+	nud(lexer.OK_TOKEN, parseResultExpr)  // ok(value)
+	nud(lexer.ERR_TOKEN, parseResultExpr) // err(value)
+
+	nud(lexer.INTERPOLATION_START_TOKEN, parseInterpolatedString) // "a {x} b"
+
+	nud(lexer.INT128_TOKEN, parsePrimaryExpr)        // int literal, 128 bit
+	nud(lexer.UINT128_TOKEN, parsePrimaryExpr)       // uint literal, 128 bit
+	nud(lexer.UNTYPED_INT_TOKEN, parsePrimaryExpr)   // int literal without a suffix
+	nud(lexer.UNTYPED_FLOAT_TOKEN, parsePrimaryExpr) // float literal without a suffix
 
 	//Unary
-	nud(lexer.MINUS_TOKEN, parseUnaryExpr) // unary minus : -a
-	nud(lexer.NOT_TOKEN, parseUnaryExpr)   // unary not : !a
+	nud(lexer.MINUS_TOKEN, parseUnaryExpr)   // unary minus : -a
+	nud(lexer.NOT_TOKEN, parseUnaryExpr)     // unary not : !a
+	nud(lexer.BIT_NOT_TOKEN, parseUnaryExpr) // bitwise not : ~a
 	//Increment and Decrement
 	//Prefix
 	nud(lexer.PLUS_PLUS_TOKEN, parsePrefixExpr)   // ++a
@@ -127,7 +162,15 @@ func bindLookupHandlers() {
 	stmt(lexer.IF_TOKEN, parseIfStmt)                 // if statement
 	stmt(lexer.FOR_TOKEN, parseForStmt)               // for statement
 	stmt(lexer.FOREACH_TOKEN, parseForStmt)           // foreach statement
+	stmt(lexer.WHILE_TOKEN, parseWhileStmt)           // while statement
+	stmt(lexer.DO_TOKEN, parseWhileStmt)              // do-while statement
+	stmt(lexer.MATCH_TOKEN, parseMatchStmt)           // match statement
+	stmt(lexer.SAFE_TOKEN, parseSafeStmt)             // safe statement
 	stmt(lexer.FUNCTION_TOKEN, parseFunctionDeclStmt) // function declaration
 	stmt(lexer.RETURN_TOKEN, parseReturnStmt)         // return statement
+	stmt(lexer.BREAK_TOKEN, parseLoopControlStmt)     // break statement
+	stmt(lexer.CONTINUE_TOKEN, parseLoopControlStmt)  // continue statement
 	stmt(lexer.IMPL_TOKEN, parseImplStmt)
+	stmt(lexer.IMPORT_TOKEN, parseImportStmt) // import statement
+	stmt(lexer.EXPORT_TOKEN, parseExportStmt) // exported declaration
 }
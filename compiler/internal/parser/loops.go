@@ -1,9 +1,6 @@
 package parser
 
 import (
-	//Standard packages
-	"fmt"
-
 	//Walrus packages
 	"walrus/compiler/internal/ast"
 	"walrus/compiler/internal/lexer"
@@ -48,19 +45,23 @@ func parseForStmt(p *Parser) ast.Node {
 			}
 		}
 
-		// check if there is an init expression
-		init = parseNode(p)
+		// a 'let' declaration or an expression followed by ';' is the init clause of a traditional loop
+		if p.currentTokenKind() == lexer.LET_TOKEN {
+			init = parseVarDeclStmt(p)
+		} else {
+			cond = parseExpr(p, DEFAULT_BP)
+			if p.currentTokenKind() == lexer.SEMI_COLON_TOKEN {
+				p.eat()
+				init = cond
+				cond = nil
+			}
+		}
 
-		// check if there no opening brace, then there is a condition and increment
-		if p.currentTokenKind() != lexer.OPEN_CURLY {
-			fmt.Printf("Current token: %v\n", p.currentTokenKind())
+		// if there is an init clause, then there is a condition and increment
+		if init != nil {
 			cond = parseExpr(p, DEFAULT_BP)
 			p.expect(lexer.SEMI_COLON_TOKEN)
 			incr = parseExpr(p, DEFAULT_BP)
-		} else {
-			// condition-only loop
-			cond = init
-			init = nil
 		}
 
 		// parse the block
@@ -95,8 +96,6 @@ func parseForStmt(p *Parser) ast.Node {
 		// parse the array expression
 		array := parseExpr(p, ASSIGNMENT_BP)
 
-		p.expect(lexer.OPEN_CURLY)
-
 		// parse the block
 		block := parseBlock(p)
 
@@ -116,3 +115,110 @@ func parseForStmt(p *Parser) ast.Node {
 
 	return nil
 }
+
+// parseWhileStmt parses a 'while' loop, `while i < 10 { }`, and a 'do-while' loop,
+// `do { } while i < 10;`, whose block runs once before the condition is checked.
+func parseWhileStmt(p *Parser) ast.Node {
+	keyword := p.eat() // advance past the 'while|do' keyword
+
+	if keyword.Kind == lexer.DO_TOKEN {
+		block := parseBlock(p)
+		p.expect(lexer.WHILE_TOKEN)
+		cond := parseExpr(p, DEFAULT_BP)
+		end := p.expect(lexer.SEMI_COLON_TOKEN).End
+
+		return ast.WhileStmt{
+			Condition: cond,
+			Block:     block,
+			IsDo:      true,
+			Location: ast.Location{
+				Start: keyword.Start,
+				End:   end,
+			},
+		}
+	}
+
+	cond := parseExpr(p, DEFAULT_BP)
+	block := parseBlock(p)
+
+	return ast.WhileStmt{
+		Condition: cond,
+		Block:     block,
+		Location: ast.Location{
+			Start: keyword.Start,
+			End:   block.EndPos(),
+		},
+	}
+}
+
+// parseLabeledLoop parses a loop with a label, like 'outer: for { }' or 'outer: while x { }'.
+// The label names the loop for the 'break' and 'continue' statements of the loops nested in it.
+func parseLabeledLoop(p *Parser) ast.Node {
+
+	name := p.eat()
+	p.expect(lexer.COLON_TOKEN)
+
+	label := ast.IdentifierExpr{
+		Name: name.Value,
+		Location: ast.Location{
+			Start: name.Start,
+			End:   name.End,
+		},
+	}
+
+	var node ast.Node
+	switch p.currentTokenKind() {
+	case lexer.WHILE_TOKEN, lexer.DO_TOKEN:
+		node = parseWhileStmt(p)
+	default:
+		node = parseForStmt(p)
+	}
+
+	switch loop := node.(type) {
+	case ast.WhileStmt:
+		loop.Label = label
+		loop.Start = name.Start
+		return loop
+	case ast.ForStmt:
+		loop.Label = label
+		loop.Start = name.Start
+		return loop
+	case ast.ForEachStmt:
+		loop.Label = label
+		loop.Start = name.Start
+		return loop
+	}
+
+	return nil
+}
+
+// parseLoopControlStmt parses 'break;' and 'continue;', with an optional label naming the
+// loop they exit or continue, like 'break outer;'.
+func parseLoopControlStmt(p *Parser) ast.Node {
+
+	keyword := p.eat()
+
+	var label ast.IdentifierExpr
+	if p.currentTokenKind() == lexer.IDENTIFIER_TOKEN {
+		name := p.eat()
+		label = ast.IdentifierExpr{
+			Name: name.Value,
+			Location: ast.Location{
+				Start: name.Start,
+				End:   name.End,
+			},
+		}
+	}
+
+	end := p.expect(lexer.SEMI_COLON_TOKEN).End
+
+	location := ast.Location{
+		Start: keyword.Start,
+		End:   end,
+	}
+
+	if keyword.Kind == lexer.CONTINUE_TOKEN {
+		return ast.ContinueStmt{Label: label, Location: location}
+	}
+	return ast.BreakStmt{Label: label, Location: location}
+}
@@ -0,0 +1,70 @@
+package parser
+
+import (
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/lexer"
+)
+
+// parseMatchStmt parses a match statement. Every arm lists one or more patterns
+// separated by commas, then '=>' and the block to run:
+//
+//	match value {
+//	    1, 2 => { ... }
+//	    3..10 => { ... }
+//	    _ => { ... }
+//	}
+func parseMatchStmt(p *Parser) ast.Node {
+
+	start := p.eat().Start // eat match token
+
+	value := parseExpr(p, ASSIGNMENT_BP)
+
+	p.expect(lexer.OPEN_CURLY)
+
+	arms := make([]ast.MatchArm, 0)
+
+	for p.hasToken() && p.currentTokenKind() != lexer.CLOSE_CURLY {
+		arms = append(arms, parseMatchArm(p))
+	}
+
+	end := p.expect(lexer.CLOSE_CURLY).End
+
+	return ast.MatchStmt{
+		Value: value,
+		Arms:  arms,
+		Location: ast.Location{
+			Start: start,
+			End:   end,
+		},
+	}
+}
+
+// parseMatchArm parses the patterns and the block of one arm. A comma may follow the block.
+func parseMatchArm(p *Parser) ast.MatchArm {
+
+	start := p.currentToken().Start
+
+	patterns := []ast.Node{parseExpr(p, DEFAULT_BP)}
+	for p.currentTokenKind() == lexer.COMMA_TOKEN {
+		p.eat()
+		patterns = append(patterns, parseExpr(p, DEFAULT_BP))
+	}
+
+	p.expect(lexer.FAT_ARROW_TOKEN)
+
+	block := parseBlock(p)
+
+	if p.hasToken() && p.currentTokenKind() == lexer.COMMA_TOKEN {
+		p.eat()
+	}
+
+	return ast.MatchArm{
+		Patterns: patterns,
+		Block:    block,
+		Location: ast.Location{
+			Start: start,
+			End:   block.End,
+		},
+	}
+}
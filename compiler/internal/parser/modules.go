@@ -0,0 +1,67 @@
+package parser
+
+import (
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/lexer"
+	"walrus/compiler/report"
+)
+
+// parseImportStmt parses an import statement like `import "path/to/file.wal";`.
+//
+// Parameters:
+//   - p: A pointer to the Parser instance.
+//
+// Returns:
+//   - ast.Node: An ast.ImportStmt holding the path as written.
+func parseImportStmt(p *Parser) ast.Node {
+
+	start := p.eat().Start // eat import token
+
+	path := p.expect(lexer.STR_TOKEN)
+
+	end := p.expect(lexer.SEMI_COLON_TOKEN).End
+
+	return ast.ImportStmt{
+		Path: path.Value,
+		Location: ast.Location{
+			Start: start,
+			End:   end,
+		},
+	}
+}
+
+// parseExportStmt parses a declaration marked with the export keyword. Variables,
+// constants, functions and types can be exported.
+//
+// Parameters:
+//   - p: A pointer to the Parser instance.
+//
+// Returns:
+//   - ast.Node: The declaration, with IsExported set.
+func parseExportStmt(p *Parser) ast.Node {
+
+	exportToken := p.eat() // eat export token
+
+	switch p.currentTokenKind() {
+	case lexer.LET_TOKEN, lexer.CONST_TOKEN:
+		decl := parseVarDeclStmt(p).(ast.VarDeclStmt)
+		decl.IsExported = true
+		decl.Start = exportToken.Start
+		return decl
+	case lexer.FUNCTION_TOKEN:
+		decl := parseFunctionDeclStmt(p).(ast.FunctionDeclStmt)
+		decl.IsExported = true
+		decl.Start = exportToken.Start
+		return decl
+	case lexer.TYPE_TOKEN:
+		decl := parseUserDefinedTypes(p).(ast.TypeDeclStmt)
+		decl.IsExported = true
+		decl.Start = exportToken.Start
+		return decl
+	default:
+		token := p.currentToken()
+		report.Add(p.FilePath, token.Start.Line, token.End.Line, token.Start.Column, token.End.Column, "only variables, constants, functions and types can be exported").SetLevel(report.SYNTAX_ERROR)
+		return nil
+	}
+}
@@ -34,6 +34,14 @@ func (p *Parser) hasToken() bool {
 	return p.index < len(p.tokens) && p.currentTokenKind() != lexer.EOF_TOKEN
 }
 
+// nextTokenKind returns the kind of the token after the current one.
+func (p *Parser) nextTokenKind() builtins.TOKEN_KIND {
+	if p.index+1 >= len(p.tokens) {
+		return lexer.EOF_TOKEN
+	}
+	return p.tokens[p.index+1].Kind
+}
+
 func (p *Parser) eat() lexer.Token {
 	token := p.currentToken()
 	p.index++
@@ -79,6 +87,11 @@ func parseNode(p *Parser) ast.Node {
 		return stmt_fn(p)
 	}
 
+	// 'name: for ...' labels a loop
+	if p.currentTokenKind() == lexer.IDENTIFIER_TOKEN && p.nextTokenKind() == lexer.COLON_TOKEN {
+		return parseLabeledLoop(p)
+	}
+
 	// if not a statement, then it must be an expression
 	expr := parseExpr(p, DEFAULT_BP)
 
@@ -0,0 +1,59 @@
+package parser
+
+import (
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/builtins"
+	"walrus/compiler/internal/lexer"
+)
+
+// parseResultType parses 'T!E', the type of the values of T and the errors of type E.
+func parseResultType(p *Parser, left ast.DataType, bp BINDING_POWER) ast.DataType {
+
+	p.expect(lexer.NOT_TOKEN)
+
+	errType := parseType(p, bp)
+
+	return ast.ResultType{
+		TypeName: builtins.PARSER_TYPE(builtins.RESULT),
+		OkType:   left,
+		ErrType:  errType,
+		Location: ast.Location{
+			Start: left.StartPos(),
+			End:   errType.EndPos(),
+		},
+	}
+}
+
+// parseResultExpr parses 'ok(value)' and 'err(value)'.
+func parseResultExpr(p *Parser) ast.Node {
+
+	keyword := p.eat()
+
+	p.expect(lexer.OPEN_PAREN)
+	value := parseExpr(p, DEFAULT_BP)
+	end := p.expect(lexer.CLOSE_PAREN).End
+
+	return ast.ResultExpr{
+		IsErr: keyword.Kind == lexer.ERR_TOKEN,
+		Value: value,
+		Location: ast.Location{
+			Start: keyword.Start,
+			End:   end,
+		},
+	}
+}
+
+// parsePropagateExpr parses 'value?', which returns the error of a result from the function.
+func parsePropagateExpr(p *Parser, left ast.Node, bp BINDING_POWER) ast.Node {
+
+	end := p.expect(lexer.QUESTION_TOKEN).End
+
+	return ast.PropagateExpr{
+		Value: left,
+		Location: ast.Location{
+			Start: left.StartPos(),
+			End:   end,
+		},
+	}
+}
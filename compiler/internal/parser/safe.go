@@ -0,0 +1,50 @@
+package parser
+
+import (
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/lexer"
+)
+
+// parseSafeStmt parses a safe statement. The first block runs when the variable is not
+// null, the 'otherwise' block, which may be left out, runs when it is:
+//
+//	safe name {
+//	    ...
+//	} otherwise {
+//	    ...
+//	}
+func parseSafeStmt(p *Parser) ast.Node {
+
+	start := p.eat().Start // eat safe token
+
+	identifier := p.expect(lexer.IDENTIFIER_TOKEN)
+
+	safeBlock := parseBlock(p)
+
+	end := safeBlock.End
+
+	var unsafeBlock ast.BlockStmt
+
+	if p.hasToken() && p.currentTokenKind() == lexer.OTHERWISE_TOKEN {
+		p.eat() // eat otherwise token
+		unsafeBlock = parseBlock(p)
+		end = unsafeBlock.End
+	}
+
+	return ast.SafeStmt{
+		Value: ast.IdentifierExpr{
+			Name: identifier.Value,
+			Location: ast.Location{
+				Start: identifier.Start,
+				End:   identifier.End,
+			},
+		},
+		SafeBlock:   safeBlock,
+		UnsafeBlock: unsafeBlock,
+		Location: ast.Location{
+			Start: start,
+			End:   end,
+		},
+	}
+}
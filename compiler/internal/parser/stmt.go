@@ -2,6 +2,7 @@ package parser
 
 import (
 	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/builtins"
 	"walrus/compiler/internal/lexer"
 )
 
@@ -11,8 +12,22 @@ func parseUserDefinedTypes(p *Parser) ast.Node {
 
 	typeName := p.expect(lexer.IDENTIFIER_TOKEN)
 
+	typeParams := parseTypeParams(p)
+
 	udType := parseTypeDefinition(p)
 
+	if typeParams != nil {
+		udType = ast.GenericType{
+			TypeName:   builtins.PARSER_TYPE(builtins.GENERIC),
+			TypeParams: typeParams,
+			TypeDef:    udType,
+			Location: ast.Location{
+				Start: typeParams[0].Start,
+				End:   udType.EndPos(),
+			},
+		}
+	}
+
 	p.expect(lexer.SEMI_COLON_TOKEN)
 
 	return ast.TypeDeclStmt{
@@ -80,6 +95,10 @@ func parseReturnStmt(p *Parser) ast.Node {
 
 	if p.currentTokenKind() != lexer.SEMI_COLON_TOKEN {
 		value = parseExpr(p, ASSIGNMENT_BP)
+		// 'ret a, b;' returns the tuple '(a, b)'
+		if p.currentTokenKind() == lexer.COMMA_TOKEN {
+			value = parseTupleValues(p, value.StartPos(), value)
+		}
 	}
 
 	end := p.expect(lexer.SEMI_COLON_TOKEN).End
@@ -0,0 +1,58 @@
+package parser
+
+import (
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/lexer"
+	"walrus/compiler/report"
+)
+
+// parseInterpolatedString parses a string with values in it, like "a {x} b {y}". The lexer
+// gives the text before each value with the token opening the value, and the text after the
+// last value with the token closing the string.
+func parseInterpolatedString(p *Parser) ast.Node {
+
+	start := p.currentToken().Start
+
+	var parts []ast.Node
+	for {
+		text := p.eat()
+		parts = appendText(parts, text)
+
+		if text.Kind == lexer.INTERPOLATION_END_TOKEN {
+			return ast.InterpolatedStringExpr{
+				Parts: parts,
+				Location: ast.Location{
+					Start: start,
+					End:   text.End,
+				},
+			}
+		}
+
+		if kind := p.currentTokenKind(); kind == lexer.INTERPOLATION_MIDDLE_TOKEN || kind == lexer.INTERPOLATION_END_TOKEN {
+			token := p.currentToken()
+			report.Add(p.FilePath, text.End.Line, token.Start.Line, text.End.Column-1, token.Start.Column+1, "empty interpolation in string").Hint("put a value between the braces, or escape the brace with '\\{'").SetLevel(report.SYNTAX_ERROR)
+		}
+
+		parts = append(parts, parseExpr(p, DEFAULT_BP))
+
+		if kind := p.currentTokenKind(); kind != lexer.INTERPOLATION_MIDDLE_TOKEN && kind != lexer.INTERPOLATION_END_TOKEN {
+			token := p.currentToken()
+			report.Add(p.FilePath, token.Start.Line, token.End.Line, token.Start.Column, token.End.Column, "expected '}' to close the interpolation").SetLevel(report.SYNTAX_ERROR)
+		}
+	}
+}
+
+// appendText adds the text of a part of an interpolated string, if it has any.
+func appendText(parts []ast.Node, text lexer.Token) []ast.Node {
+	if text.Value == "" {
+		return parts
+	}
+	return append(parts, ast.StringLiteralExpr{
+		Value: text.Value,
+		Location: ast.Location{
+			Start: text.Start,
+			End:   text.End,
+		},
+	})
+}
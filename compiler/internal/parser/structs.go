@@ -55,6 +55,12 @@ func parseStructLiteral(p *Parser) ast.Node {
 		},
 	}
 
+	// the type arguments of a generic struct may be written, like @Box<i32>{...}
+	var typeArgs []ast.DataType
+	if structName != "" && p.currentTokenKind() == lexer.LESS_TOKEN {
+		typeArgs, _ = parseTypeArgs(p)
+	}
+
 	p.expect(lexer.OPEN_CURLY)
 
 	//parse the values
@@ -90,6 +96,7 @@ func parseStructLiteral(p *Parser) ast.Node {
 
 	structVal := ast.StructLiteral{
 		Identifier: identidier,
+		TypeArgs:   typeArgs,
 		Properties: props,
 		Location: ast.Location{
 			Start: identidier.StartPos(),
@@ -101,7 +108,7 @@ func parseStructLiteral(p *Parser) ast.Node {
 }
 
 // parsePropertyExpr parses a property access expression from the parser.
-// It expects the current token to be a dot (.) followed by an identifier.
+// It expects the current token to be a dot (.) or a "?." followed by an identifier.
 // The function constructs and returns an AST node representing the property access.
 //
 // Parameters:
@@ -113,7 +120,8 @@ func parseStructLiteral(p *Parser) ast.Node {
 // - An AST node representing the property access expression.
 func parsePropertyExpr(p *Parser, left ast.Node, bp BINDING_POWER) ast.Node {
 
-	p.expect(lexer.DOT_TOKEN)
+	// '?.' gives null instead of failing when the object is null
+	optional := p.eat().Kind == lexer.OPTIONAL_TOKEN
 
 	identifier := p.expect(lexer.IDENTIFIER_TOKEN)
 
@@ -128,6 +136,7 @@ func parsePropertyExpr(p *Parser, left ast.Node, bp BINDING_POWER) ast.Node {
 	return ast.StructPropertyAccessExpr{
 		Object:   left,
 		Property: property,
+		Optional: optional,
 		Location: ast.Location{
 			Start: left.StartPos(),
 			End:   property.End,
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/builtins"
+	"walrus/compiler/internal/lexer"
+	"walrus/compiler/report"
+)
+
+// parseTupleType parses '(T, U, ...)'. A single type in parentheses is only grouped.
+func parseTupleType(p *Parser) ast.DataType {
+
+	start := p.expect(lexer.OPEN_PAREN).Start
+
+	elements := []ast.DataType{parseType(p, DEFAULT_BP)}
+	for p.currentTokenKind() == lexer.COMMA_TOKEN {
+		p.eat()
+		elements = append(elements, parseType(p, DEFAULT_BP))
+	}
+
+	end := p.expect(lexer.CLOSE_PAREN).End
+
+	if len(elements) == 1 {
+		return elements[0]
+	}
+
+	return ast.TupleType{
+		TypeName: builtins.PARSER_TYPE(builtins.TUPLE),
+		Elements: elements,
+		Location: ast.Location{
+			Start: start,
+			End:   end,
+		},
+	}
+}
+
+// parseTupleValues parses the values after the first one of a tuple, each following a comma.
+// It returns the tuple starting at start, which ends with the last value.
+func parseTupleValues(p *Parser, start lexer.Position, first ast.Node) ast.TupleLiteral {
+
+	values := []ast.Node{first}
+	for p.currentTokenKind() == lexer.COMMA_TOKEN {
+		p.eat()
+		values = append(values, parseExpr(p, DEFAULT_BP))
+	}
+
+	return ast.TupleLiteral{
+		Values: values,
+		Location: ast.Location{
+			Start: start,
+			End:   values[len(values)-1].EndPos(),
+		},
+	}
+}
+
+// parseDestructuring parses the names a declaration unpacks its value into: '(a, b)' for a
+// tuple or '{a, b}' for a struct.
+func parseDestructuring(p *Parser) *ast.Destructuring {
+
+	open := p.eat()
+
+	isStruct := open.Kind == lexer.OPEN_CURLY
+	closing := lexer.CLOSE_PAREN
+	if isStruct {
+		closing = lexer.CLOSE_CURLY
+	}
+
+	var names []ast.IdentifierExpr
+	for p.currentTokenKind() != closing {
+		name := p.expect(lexer.IDENTIFIER_TOKEN)
+		names = append(names, ast.IdentifierExpr{
+			Name: name.Value,
+			Location: ast.Location{
+				Start: name.Start,
+				End:   name.End,
+			},
+		})
+		if p.currentTokenKind() != closing {
+			p.expect(lexer.COMMA_TOKEN)
+		}
+	}
+
+	end := p.expect(closing).End
+
+	if len(names) == 0 {
+		report.Add(p.FilePath, open.Start.Line, end.Line, open.Start.Column, end.Column, "nothing to unpack the value into").Hint("write the names, like 'let (a, b) := ...'").SetLevel(report.SYNTAX_ERROR)
+	}
+
+	return &ast.Destructuring{
+		Names:    names,
+		IsStruct: isStruct,
+		Location: ast.Location{
+			Start: open.Start,
+			End:   end,
+		},
+	}
+}
@@ -34,8 +34,26 @@ func bindTypeLookups() {
 	typeNUD(lexer.FUNCTION_TOKEN, parseFunctionType)
 	typeNUD(lexer.MAP_TOKEN, parseMapType)
 	typeNUD(lexer.STRUCT_TOKEN, parseStructType)
+	typeNUD(lexer.OPEN_PAREN, parseTupleType)
 
 	typeLED(lexer.RANGE_TOKEN, PRIMARY_BP, parseRangeType)
+	typeLED(lexer.QUESTION_TOKEN, MEMBER_BP, parseMaybeType)
+	typeLED(lexer.NOT_TOKEN, MEMBER_BP, parseResultType)
+}
+
+// parseMaybeType parses 'T?', the type of the values of T and null.
+func parseMaybeType(p *Parser, left ast.DataType, bp BINDING_POWER) ast.DataType {
+
+	end := p.expect(lexer.QUESTION_TOKEN).End
+
+	return ast.MaybeType{
+		TypeName:  builtins.PARSER_TYPE(builtins.MAYBE),
+		MaybeType: left,
+		Location: ast.Location{
+			Start: left.StartPos(),
+			End:   end,
+		},
+	}
 }
 
 func parseRangeType(p *Parser, left ast.DataType, bp BINDING_POWER) ast.DataType {
@@ -178,9 +196,9 @@ func getFunctionTypeSignature(p *Parser) (builtins.PARSER_TYPE, []ast.FunctionTy
 	return builtins.PARSER_TYPE(builtins.FUNCTION), params, returnType
 }
 
-// Parses the builtin types like int, float, bool, char, str, null.
+// Parses the builtin types like int, float, bool, char, str.
 // If the type is not a builtin type, then it is a user defined type
-// Type must be a single token identifier
+// Type must be a single token identifier, followed by the type arguments of a generic type
 func parseDataType(p *Parser) ast.DataType {
 
 	identifier := p.eat()
@@ -200,7 +218,7 @@ func parseDataType(p *Parser) ast.DataType {
 	}
 
 	switch v := value; builtins.TOKEN_KIND(v) {
-	case lexer.INT8_TOKEN, lexer.INT16_TOKEN, lexer.INT32_TOKEN, lexer.INT64_TOKEN, lexer.UINT8_TOKEN, lexer.UINT16_TOKEN, lexer.UINT32_TOKEN, lexer.UINT64_TOKEN:
+	case lexer.INT8_TOKEN, lexer.INT16_TOKEN, lexer.INT32_TOKEN, lexer.INT64_TOKEN, lexer.INT128_TOKEN, lexer.UINT8_TOKEN, lexer.UINT16_TOKEN, lexer.UINT32_TOKEN, lexer.UINT64_TOKEN, lexer.UINT128_TOKEN:
 		return ast.IntegerType{
 			TypeName: builtins.PARSER_TYPE(v),
 			BitSize:  builtins.GetBitSize(builtins.PARSER_TYPE(v)),
@@ -224,9 +242,15 @@ func parseDataType(p *Parser) ast.DataType {
 			Location: loc,
 		}
 	default:
+		var typeArgs []ast.DataType
+		// an instance of a generic type, like 'Box<i32>'
+		if p.currentTokenKind() == lexer.LESS_TOKEN {
+			typeArgs, loc.End = parseTypeArgs(p)
+		}
 		return ast.UserDefinedType{
 			TypeName:  builtins.PARSER_TYPE(builtins.USER_DEFINED),
 			AliasName: value,
+			TypeArgs:  typeArgs,
 			Location:  loc,
 		}
 	}
@@ -289,6 +313,8 @@ func parseTypeDefinition(p *Parser) ast.DataType {
 		return parseStructType(p)
 	case builtins.INTERFACE:
 		return parseInterfaceType(p)
+	case builtins.ENUM:
+		return parseEnumType(p)
 	default:
 		return parseType(p, DEFAULT_BP)
 	}
@@ -427,3 +453,105 @@ func parseInterfaceType(p *Parser) ast.DataType {
 		},
 	}
 }
+
+// parseEnumType parses an enum type definition. The variants are separated by commas and
+// may carry fields:
+//
+//	type Shape enum { Circle(r: f64), Rect(w: f64, h: f64), Empty };
+func parseEnumType(p *Parser) ast.DataType {
+
+	identifier := p.eat() // eat enum token
+
+	p.expect(lexer.OPEN_CURLY)
+
+	variants := make([]ast.EnumVariant, 0)
+
+	for p.hasToken() && p.currentTokenKind() != lexer.CLOSE_CURLY {
+
+		name := p.expectError(lexer.IDENTIFIER_TOKEN, errors.New("expected a variant name"))
+
+		variant := ast.EnumVariant{
+			Name: ast.IdentifierExpr{
+				Name: name.Value,
+				Location: ast.Location{
+					Start: name.Start,
+					End:   name.End,
+				},
+			},
+			Location: ast.Location{
+				Start: name.Start,
+				End:   name.End,
+			},
+		}
+
+		if p.currentTokenKind() == lexer.OPEN_PAREN {
+			variant.Fields, variant.End = parseVariantFields(p)
+		}
+
+		variants = append(variants, variant)
+
+		if p.currentTokenKind() != lexer.CLOSE_CURLY {
+			p.expect(lexer.COMMA_TOKEN)
+		}
+	}
+
+	end := p.expect(lexer.CLOSE_CURLY).End
+
+	if len(variants) == 0 {
+		report.Add(p.FilePath, identifier.Start.Line, identifier.End.Line, identifier.Start.Column, identifier.End.Column, "enum is empty").SetLevel(report.SYNTAX_ERROR)
+	}
+
+	return ast.EnumType{
+		TypeName: builtins.PARSER_TYPE(builtins.ENUM),
+		Variants: variants,
+		Location: ast.Location{
+			Start: identifier.Start,
+			End:   end,
+		},
+	}
+}
+
+// parseVariantFields parses the fields of an enum variant, like '(w: f64, h: f64)'. It
+// returns the fields and the end of the list.
+func parseVariantFields(p *Parser) ([]ast.FunctionTypeParam, lexer.Position) {
+
+	p.expect(lexer.OPEN_PAREN)
+
+	var fields []ast.FunctionTypeParam
+
+	for p.hasToken() && p.currentTokenKind() != lexer.CLOSE_PAREN {
+
+		iden := p.expect(lexer.IDENTIFIER_TOKEN)
+
+		p.expect(lexer.COLON_TOKEN)
+
+		fieldType := parseType(p, DEFAULT_BP)
+
+		fields = append(fields, ast.FunctionTypeParam{
+			Identifier: ast.IdentifierExpr{
+				Name: iden.Value,
+				Location: ast.Location{
+					Start: iden.Start,
+					End:   iden.End,
+				},
+			},
+			Type: fieldType,
+			Location: ast.Location{
+				Start: iden.Start,
+				End:   fieldType.EndPos(),
+			},
+		})
+
+		if p.currentTokenKind() != lexer.CLOSE_PAREN {
+			p.expect(lexer.COMMA_TOKEN)
+		}
+	}
+
+	end := p.expect(lexer.CLOSE_PAREN).End
+
+	if len(fields) == 0 {
+		report.Add(p.FilePath, end.Line, end.Line, end.Column-1, end.Column, "variant without fields is written without parentheses").SetLevel(report.SYNTAX_ERROR)
+	}
+
+	return fields, end
+}
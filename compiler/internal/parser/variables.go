@@ -44,8 +44,15 @@ func parseVarDeclStmt(p *Parser) ast.Node {
 	var variables []ast.VarDeclStmtVar
 
 	for {
-		// parse the variable name
-		identifier := p.expect(lexer.IDENTIFIER_TOKEN)
+		// parse the variable name, or the names the value is unpacked into
+		var identifier lexer.Token
+		var destructure *ast.Destructuring
+		if kind := p.currentTokenKind(); kind == lexer.OPEN_PAREN || kind == lexer.OPEN_CURLY {
+			destructure = parseDestructuring(p)
+			identifier.Start = destructure.Start
+		} else {
+			identifier = p.expect(lexer.IDENTIFIER_TOKEN)
+		}
 
 		// parse the explicit type if present. This will be nil if no type is specified.
 		var explicitType ast.DataType
@@ -69,6 +76,12 @@ func parseVarDeclStmt(p *Parser) ast.Node {
 			value = parseExpr(p, DEFAULT_BP)
 		}
 
+		//an unpacked value, like a constant, must be there
+		if destructure != nil && value == nil {
+			msg := "nothing to unpack, a value must be given"
+			report.Add(p.FilePath, p.currentToken().Start.Line, p.currentToken().End.Line, p.currentToken().Start.Column, p.currentToken().End.Column, msg).SetLevel(report.SYNTAX_ERROR)
+		}
+
 		//if const, we must have a value
 		if isConst && value == nil {
 			msg := "constants must have value when declared"
@@ -85,6 +98,7 @@ func parseVarDeclStmt(p *Parser) ast.Node {
 			},
 			Value:        value,
 			ExplicitType: explicitType,
+			Destructure:  destructure,
 			Location: ast.Location{
 				Start: identifier.Start,
 				End:   p.currentToken().Start,
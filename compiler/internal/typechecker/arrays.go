@@ -46,6 +46,8 @@ func evaluateIndexableAccess(indexable ast.Indexable, e *TypeEnvironment) Tc {
 			report.Add(e.filePath, indexable.Start.Line, indexable.End.Line, indexable.Index.StartPos().Column, indexable.Index.EndPos().Column, fmt.Sprintf("cannot access index of type %s", INTERFACE_TYPE)).SetLevel(report.NORMAL_ERROR)
 		}
 		indexedValueType = t.ValueType
+	case Tuple:
+		indexedValueType = checkTupleIndex(indexable, t, index, e)
 	default:
 		report.Add(e.filePath, indexable.Start.Line, indexable.End.Line, indexable.Container.StartPos().Column, indexable.Container.EndPos().Column, fmt.Sprintf("cannot access index of type %s", tcToString(container))).SetLevel(report.CRITICAL_ERROR)
 	}
@@ -82,6 +84,21 @@ func evaluateArrayExpr(array ast.ArrayLiteral, env *TypeEnvironment) Tc {
 	}
 }
 
+// checkArrayLiteralAs checks an array literal stored in a place of an array type, like the
+// value of 'let arr: []i64 = [1, 2]'. Every value is checked as a value of the element type.
+func checkArrayLiteralAs(array ast.ArrayLiteral, expected Array, env *TypeEnvironment) Tc {
+	for _, value := range array.Values {
+		v := checkValueAs(value, expected.ArrayType, env)
+		err := validateTypeCompatibility(expected.ArrayType, v)
+		if err != nil {
+			report.Add(env.filePath, value.StartPos().Line, value.EndPos().Line, value.StartPos().Column, value.EndPos().Column, err.Error()).SetLevel(report.NORMAL_ERROR)
+		}
+	}
+
+	env.info.recordType(array, expected)
+	return expected
+}
+
 // checkRange checks the type of an array range expression.
 func checkRange(arrayRange ast.RangeExpr, env *TypeEnvironment) Tc {
 	start := parseNodeValue(arrayRange.Start, env)
@@ -14,7 +14,13 @@ func checkBlock(block ast.BlockStmt, env *TypeEnvironment) Block {
 
 	for i, stmt := range block.Contents {
 		val := checkAST(stmt, env)
-		if _, ok := val.(ReturnType); ok {
+		if _, ok := val.(LoopJump); ok {
+			//'break' and 'continue' leave the block too, without returning
+			if i < len(block.Contents)-1 {
+				report.Add(env.filePath, stmt.StartPos().Line, stmt.EndPos().Line, stmt.StartPos().Column, stmt.EndPos().Column, "unreachable code").SetLevel(report.NORMAL_ERROR)
+			}
+			return blockInfo
+		} else if _, ok := val.(ReturnType); ok {
 			//if has any more statements after return
 			if i < len(block.Contents)-1 {
 				report.Add(env.filePath, stmt.StartPos().Line, stmt.EndPos().Line, stmt.StartPos().Column, stmt.EndPos().Column, "unreachable code").SetLevel(report.NORMAL_ERROR)
@@ -0,0 +1,504 @@
+package typechecker
+
+import (
+	//Standard packages
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/builtins"
+	"walrus/compiler/internal/lexer"
+	"walrus/compiler/report"
+)
+
+// constantError is an error found while folding an expression, like a division by zero. It is
+// reported where the expression must be constant and for untyped constants, which are made of
+// literals only, see reportConstantError. Anywhere else the expression is computed when the
+// program runs.
+type constantError struct {
+	location ast.Location
+	message  string
+	hint     string
+}
+
+// foldConstant computes the value of an expression known while checking, from literals,
+// constants and the operators, casts and typeof applied to them, and records it with the
+// expression. The values of the operands are already recorded, since they are checked first.
+// A value is a *big.Int for an integer, a float64 for a float, a string or a bool.
+func foldConstant(node ast.Node, dtype Tc, env *TypeEnvironment) {
+	env.info.recordConstantError(node, nil)
+	value, ok := evaluateConstant(node, unwrapType(dtype), env)
+	env.info.recordConstant(node, value, ok)
+}
+
+// reportConstantError reports why an expression that must be constant, like the value of a
+// 'const', a match pattern or an untyped constant, could not be folded. The error is reported
+// once, even when the expression is both.
+func reportConstantError(node ast.Node, env *TypeEnvironment) {
+	err, ok := env.info.constantErrorOf(node)
+	if !ok {
+		return
+	}
+	env.info.recordConstantError(node, nil)
+	r := report.Add(env.filePath, err.location.Start.Line, err.location.End.Line, err.location.Start.Column, err.location.End.Column, err.message)
+	if err.hint != "" {
+		r.Hint(err.hint)
+	}
+	r.SetLevel(report.NORMAL_ERROR)
+}
+
+// operandConstant returns the folded value of an operand. The reason an operand could not be
+// folded is recorded with the node using it too.
+func operandConstant(node, operand ast.Node, env *TypeEnvironment) (any, bool) {
+	value, ok := env.info.constantOf(operand)
+	if err, found := env.info.constantErrorOf(operand); !ok && found {
+		env.info.recordConstantError(node, &err)
+	}
+	return value, ok
+}
+
+// evaluateConstant folds an expression of the given underlying type. Division by zero and
+// values overflowing their type are recorded as errors, and are not constant.
+func evaluateConstant(node ast.Node, dtype Tc, env *TypeEnvironment) (any, bool) {
+	switch t := node.(type) {
+	case ast.IntegerLiteralExpr:
+		value, ok := new(big.Int).SetString(t.Value, 10)
+		return literalConstant(value, ok, dtype)
+	case ast.FloatLiteralExpr:
+		value, err := strconv.ParseFloat(t.Value, 64)
+		return literalConstant(value, err == nil, dtype)
+	case ast.ByteLiteralExpr:
+		return big.NewInt(int64(t.Value[0])), true
+	case ast.StringLiteralExpr:
+		return t.Value, true
+	case ast.IdentifierExpr:
+		scope, err := env.resolveVar(t.Name)
+		if err != nil || !scope.constants[t.Name] {
+			return nil, false
+		}
+		value, ok := scope.values[t.Name]
+		return value, ok
+	case ast.UnaryExpr:
+		if _, value, ok := integerLiteral(t); ok {
+			// a negated literal is folded with its sign, like it is checked
+			return literalConstant(value, true, dtype)
+		}
+		arg, ok := operandConstant(t, t.Argument, env)
+		if !ok {
+			return nil, false
+		}
+		return unaryConstant(t, arg, dtype, env)
+	case ast.BinaryExpr:
+		left, ok := operandConstant(t, t.Left, env)
+		if !ok {
+			return nil, false
+		}
+		right, ok := operandConstant(t, t.Right, env)
+		if !ok {
+			return nil, false
+		}
+		return binaryConstant(t, left, right, dtype, env)
+	case ast.TypeCastExpr:
+		value, ok := operandConstant(t, t.Expression, env)
+		if !ok {
+			return nil, false
+		}
+		return castConstant(value, dtype)
+	case ast.TypeofExpr:
+		if _, ok := env.info.constantOf(t.Expression); !ok {
+			return nil, false
+		}
+		value, ok := env.info.TypeOf(t.Expression)
+		if !ok {
+			return nil, false
+		}
+		return tcToString(unwrapType(value)), true
+	}
+	return nil, false
+}
+
+// literalConstant gives a number literal the type it was checked with. An integer stored in
+// a float becomes a float. A literal that does not fit its type is already reported, and is
+// not constant.
+func literalConstant(value any, ok bool, dtype Tc) (any, bool) {
+	if !ok {
+		return nil, false
+	}
+	switch target := dtype.(type) {
+	case Int:
+		i, isInt := value.(*big.Int)
+		if !isInt {
+			return nil, false
+		}
+		min, max := intRange(target)
+		return i, i.Cmp(min) >= 0 && i.Cmp(max) <= 0
+	case Float:
+		f := toFloatConstant(value)
+		if target.BitSize == 32 {
+			f = float64(float32(f))
+		}
+		return f, fitsFloat(f, target)
+	}
+	return nil, false
+}
+
+// unaryConstant folds the '-', '~' and '!' operators.
+func unaryConstant(node ast.UnaryExpr, arg any, dtype Tc, env *TypeEnvironment) (any, bool) {
+	switch v := arg.(type) {
+	case *big.Int:
+		intType, ok := dtype.(Int)
+		if !ok {
+			return nil, false
+		}
+		switch node.Operator.Kind {
+		case lexer.MINUS_TOKEN:
+			return checkConstantFits(node, new(big.Int).Neg(v), intType, env)
+		case lexer.BIT_NOT_TOKEN:
+			// every bit is flipped, which keeps the value in its type
+			return wrapConstant(new(big.Int).Not(v), intType), true
+		}
+	case float64:
+		if node.Operator.Kind == lexer.MINUS_TOKEN {
+			return -v, true
+		}
+	case bool:
+		if node.Operator.Kind == lexer.NOT_TOKEN {
+			return !v, true
+		}
+	}
+	return nil, false
+}
+
+// binaryConstant folds the arithmetic, bitwise, comparison and logical operators, and the
+// concatenation of two strings. Like at runtime, the result has the type of the left operand.
+func binaryConstant(node ast.BinaryExpr, left, right any, dtype Tc, env *TypeEnvironment) (any, bool) {
+	op := node.Binop.Kind
+	switch op {
+	case lexer.DOUBLE_EQUAL_TOKEN, lexer.NOT_EQUAL_TOKEN, lexer.LESS_TOKEN, lexer.LESS_EQUAL_TOKEN, lexer.GREATER_TOKEN, lexer.GREATER_EQUAL_TOKEN:
+		return compareConstants(op, left, right)
+	case lexer.AND_TOKEN, lexer.OR_TOKEN:
+		l, lok := left.(bool)
+		r, rok := right.(bool)
+		if !lok || !rok {
+			return nil, false
+		}
+		if op == lexer.AND_TOKEN {
+			return l && r, true
+		}
+		return l || r, true
+	}
+
+	switch l := left.(type) {
+	case string:
+		r, ok := right.(string)
+		if !ok || op != lexer.PLUS_TOKEN {
+			return nil, false
+		}
+		return l + r, true
+	case *big.Int:
+		r, rok := right.(*big.Int)
+		intType, ok := dtype.(Int)
+		if !rok || !ok {
+			return nil, false
+		}
+		return intConstant(node, l, r, intType, env)
+	case float64:
+		r, rok := right.(float64)
+		floatType, ok := dtype.(Float)
+		if !rok || !ok {
+			return nil, false
+		}
+		return floatConstant(node, l, r, floatType, env)
+	}
+	return nil, false
+}
+
+// intConstant folds an operator on two integers.
+func intConstant(node ast.BinaryExpr, l, r *big.Int, intType Int, env *TypeEnvironment) (any, bool) {
+	result := new(big.Int)
+	switch node.Binop.Kind {
+	case lexer.PLUS_TOKEN:
+		result.Add(l, r)
+	case lexer.MINUS_TOKEN:
+		result.Sub(l, r)
+	case lexer.MUL_TOKEN:
+		result.Mul(l, r)
+	case lexer.DIV_TOKEN, lexer.MOD_TOKEN:
+		if r.Sign() == 0 {
+			recordDivisionByZero(node, env)
+			return nil, false
+		}
+		if node.Binop.Kind == lexer.DIV_TOKEN {
+			result.Quo(l, r)
+		} else {
+			result.Rem(l, r)
+		}
+	case lexer.EXP_TOKEN:
+		if r.Sign() < 0 {
+			return nil, false
+		}
+		// any base but -1, 0 and 1 raised to 128 or more overflows every integer type
+		if l.CmpAbs(big.NewInt(1)) > 0 && r.Cmp(big.NewInt(128)) >= 0 {
+			env.info.recordConstantError(node, &constantError{location: node.Location, message: fmt.Sprintf("constant %s ** %s overflows '%s'", l, r, tcToString(intType))})
+			return nil, false
+		}
+		result.Exp(l, r, nil)
+	case lexer.BIT_AND_TOKEN:
+		result.And(l, r)
+	case lexer.BIT_OR_TOKEN:
+		result.Or(l, r)
+	case lexer.BIT_XOR_TOKEN:
+		result.Xor(l, r)
+	case lexer.SHIFT_LEFT_TOKEN, lexer.SHIFT_RIGHT_TOKEN:
+		if r.Sign() < 0 {
+			return nil, false
+		}
+		// a shift by the bit size or more shifts every bit out, which overflows to the left
+		if node.Binop.Kind == lexer.SHIFT_LEFT_TOKEN && l.Sign() != 0 && r.Cmp(big.NewInt(int64(intType.BitSize))) >= 0 {
+			env.info.recordConstantError(node, &constantError{location: node.Location, message: fmt.Sprintf("constant %s << %s overflows '%s'", l, r, tcToString(intType))})
+			return nil, false
+		}
+		count := uint(intType.BitSize)
+		if r.Cmp(big.NewInt(int64(intType.BitSize))) < 0 {
+			count = uint(r.Uint64())
+		}
+		if node.Binop.Kind == lexer.SHIFT_LEFT_TOKEN {
+			result.Lsh(l, count)
+		} else {
+			result.Rsh(l, count)
+		}
+	default:
+		return nil, false
+	}
+	return checkConstantFits(node, result, intType, env)
+}
+
+// floatConstant folds an arithmetic operator on two floats.
+func floatConstant(node ast.BinaryExpr, l, r float64, floatType Float, env *TypeEnvironment) (any, bool) {
+	var result float64
+	switch node.Binop.Kind {
+	case lexer.PLUS_TOKEN:
+		result = l + r
+	case lexer.MINUS_TOKEN:
+		result = l - r
+	case lexer.MUL_TOKEN:
+		result = l * r
+	case lexer.DIV_TOKEN, lexer.MOD_TOKEN:
+		if r == 0 {
+			recordDivisionByZero(node, env)
+			return nil, false
+		}
+		if node.Binop.Kind == lexer.DIV_TOKEN {
+			result = l / r
+		} else {
+			result = math.Mod(l, r)
+		}
+	case lexer.EXP_TOKEN:
+		result = math.Pow(l, r)
+	default:
+		return nil, false
+	}
+	if math.IsNaN(result) {
+		return nil, false
+	}
+	if !fitsFloat(result, floatType) {
+		env.info.recordConstantError(node, &constantError{location: node.Location, message: fmt.Sprintf("constant %g overflows '%s'", result, tcToString(floatType))})
+		return nil, false
+	}
+	if floatType.BitSize == 32 {
+		result = float64(float32(result))
+	}
+	return result, true
+}
+
+// compareConstants folds the comparison of two numbers, two strings or two booleans. An
+// integer compared with a float is compared as a float.
+func compareConstants(op builtins.TOKEN_KIND, left, right any) (any, bool) {
+	cmp := 0
+	switch l := left.(type) {
+	case *big.Int:
+		if r, ok := right.(*big.Int); ok {
+			cmp = l.Cmp(r)
+			break
+		}
+		return compareConstants(op, toFloatConstant(l), right)
+	case float64:
+		r, ok := right.(*big.Int)
+		if ok {
+			return compareConstants(op, l, toFloatConstant(r))
+		}
+		f, ok := right.(float64)
+		if !ok {
+			return nil, false
+		}
+		switch {
+		case l < f:
+			cmp = -1
+		case l > f:
+			cmp = 1
+		}
+	case string, bool:
+		if op != lexer.DOUBLE_EQUAL_TOKEN && op != lexer.NOT_EQUAL_TOKEN {
+			return nil, false
+		}
+		if left != right {
+			cmp = 1
+		}
+	default:
+		return nil, false
+	}
+
+	switch op {
+	case lexer.DOUBLE_EQUAL_TOKEN:
+		return cmp == 0, true
+	case lexer.NOT_EQUAL_TOKEN:
+		return cmp != 0, true
+	case lexer.LESS_TOKEN:
+		return cmp < 0, true
+	case lexer.LESS_EQUAL_TOKEN:
+		return cmp <= 0, true
+	case lexer.GREATER_TOKEN:
+		return cmp > 0, true
+	default:
+		return cmp >= 0, true
+	}
+}
+
+// castConstant folds a cast with 'as'. Like at runtime, an integer cast to a smaller type
+// wraps around and a float cast to an integer drops its fraction.
+func castConstant(value any, dtype Tc) (any, bool) {
+	switch target := dtype.(type) {
+	case Int:
+		switch v := value.(type) {
+		case *big.Int:
+			return wrapConstant(v, target), true
+		case float64:
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return nil, false
+			}
+			i, _ := big.NewFloat(math.Trunc(v)).Int(nil)
+			return wrapConstant(i, target), true
+		}
+	case Float:
+		switch value.(type) {
+		case *big.Int, float64:
+			f := toFloatConstant(value)
+			if !fitsFloat(f, target) {
+				return nil, false
+			}
+			if target.BitSize == 32 {
+				f = float64(float32(f))
+			}
+			return f, true
+		}
+	case Str:
+		v, ok := value.(string)
+		return v, ok
+	case Bool:
+		v, ok := value.(bool)
+		return v, ok
+	}
+	return nil, false
+}
+
+// toFloatConstant converts a number to a float.
+func toFloatConstant(value any) float64 {
+	switch v := value.(type) {
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(v).Float64()
+		return f
+	case float64:
+		return v
+	}
+	return 0
+}
+
+// fitsFloat reports whether a float is in the range of a float type.
+func fitsFloat(value float64, floatType Float) bool {
+	if floatType.BitSize == 32 {
+		return math.Abs(value) <= math.MaxFloat32
+	}
+	return !math.IsInf(value, 0)
+}
+
+// wrapConstant wraps an integer around the range of an integer type, keeping its low bits.
+func wrapConstant(value *big.Int, intType Int) *big.Int {
+	modulus := new(big.Int).Lsh(big.NewInt(1), uint(intType.BitSize))
+	result := new(big.Int).Mod(value, modulus)
+	if _, max := intRange(intType); result.Cmp(max) > 0 {
+		result.Sub(result, modulus)
+	}
+	return result
+}
+
+// checkConstantFits records an error for a folded integer out of the range of its type.
+func checkConstantFits(node ast.Node, value *big.Int, intType Int, env *TypeEnvironment) (any, bool) {
+	min, max := intRange(intType)
+	if value.Cmp(min) >= 0 && value.Cmp(max) <= 0 {
+		return value, true
+	}
+	env.info.recordConstantError(node, &constantError{
+		location: ast.Location{Start: node.StartPos(), End: node.EndPos()},
+		message:  fmt.Sprintf("constant %s overflows '%s'", value, tcToString(intType)),
+		hint:     fmt.Sprintf("the values of '%s' go from %s to %s", tcToString(intType), min, max),
+	})
+	return nil, false
+}
+
+// recordDivisionByZero records an error for a division or a remainder by a constant zero.
+func recordDivisionByZero(node ast.BinaryExpr, env *TypeEnvironment) {
+	location := ast.Location{Start: node.Right.StartPos(), End: node.Right.EndPos()}
+	env.info.recordConstantError(node, &constantError{location: location, message: "constant division by zero"})
+}
+
+// constantKey formats a folded value as a key of a match pattern.
+func constantKey(value any) string {
+	switch v := value.(type) {
+	case *big.Int:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		return fmt.Sprintf("%q", v)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
+
+// constantLiteral writes a folded value of the given type as a literal at the given location,
+// and records the type and the value of the literal.
+func constantLiteral(value any, dtype Tc, location ast.Location, info *TypeInfo) ast.Node {
+	switch t := unwrapType(dtype).(type) {
+	case Maybe:
+		dtype = t.MaybeType
+	case Result:
+		dtype = t.OkType
+	}
+
+	var literal ast.Node
+	switch v := value.(type) {
+	case *big.Int:
+		if t, ok := unwrapType(dtype).(Int); ok {
+			literal = ast.IntegerLiteralExpr{Value: v.String(), BitSize: t.BitSize, IsSigned: t.IsSigned, Location: location}
+		}
+	case float64:
+		if t, ok := unwrapType(dtype).(Float); ok {
+			literal = ast.FloatLiteralExpr{Value: strconv.FormatFloat(v, 'g', -1, 64), BitSize: t.BitSize, Location: location}
+		}
+	case string:
+		literal = ast.StringLiteralExpr{Value: v, Location: location}
+	case bool:
+		// true and false are builtin constants
+		literal = ast.IdentifierExpr{Name: strconv.FormatBool(v), Location: location}
+	}
+	if literal == nil {
+		return nil
+	}
+	info.recordType(literal, dtype)
+	info.recordConstant(literal, value, true)
+	return literal
+}
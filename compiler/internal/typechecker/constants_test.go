@@ -0,0 +1,137 @@
+package typechecker
+
+import (
+	"testing"
+
+	"walrus/compiler/internal/ast"
+)
+
+func TestConstants(t *testing.T) {
+	tree, info := analyze(t, `
+		const a := 10;
+		const b := a * 3 + 2;
+		const c := -a / 3;
+		const d := a % 3;
+		const e := 1 << 4 | 1;
+		const f := ~0u8;
+		const g := 300 as u8;
+		const h := b > a && !false;
+		const i := "wal" + "rus";
+		const j := typeof b;
+		const k := 2 ** 10;
+		const l: i64 = 5_000_000_000;
+		const m := 7.5 as i32;
+		const n := 1.5 * 2.0;
+		let v := 1;
+		const w := v + 1;
+	`)
+
+	expected := map[string]string{
+		"a": "10",
+		"b": "32",
+		"c": "-3",
+		"d": "1",
+		"e": "17",
+		"f": "255",
+		"g": "44",
+		"h": "true",
+		"i": `"walrus"`,
+		"j": `"i32"`,
+		"k": "1024",
+		"l": "5000000000",
+		"m": "7",
+		"n": "3",
+		"w": "",
+	}
+
+	for _, node := range tree.(ast.ProgramStmt).Contents {
+		variable := node.(ast.VarDeclStmt).Variables[0]
+		want, ok := expected[variable.Identifier.Name]
+		if !ok {
+			continue
+		}
+		t.Run(variable.Identifier.Name, func(t *testing.T) {
+			value, _ := info.constantOf(variable.Value)
+			if got := constantKey(value); got != want {
+				t.Errorf(exp, want, got)
+			}
+		})
+	}
+}
+
+func TestConstantPatterns(t *testing.T) {
+	analyze(t, `
+		const yes := true;
+		const limit := 10;
+		let flag := false;
+		match flag {
+			yes => { }
+			false => { }
+		}
+		let x := 5;
+		match x {
+			limit => { }
+			limit + 1 => { }
+			_ => { }
+		}
+	`)
+}
+
+func TestFoldingOutsideOfConstants(t *testing.T) {
+	// only constants and untyped constants must be folded, other values are computed when
+	// the program runs
+	analyze(t, `
+		const zero := 0;
+		let a := 10 / zero;
+		const max := 2147483647;
+		let b := max + 1;
+		let z: u8 = 200u8 + 100u8;
+		fn f(x: i64) -> i64 { ret x; }
+		let c := f(2147483647 + 1);
+	`)
+}
+
+func TestDefaultParameters(t *testing.T) {
+	analyze(t, `
+		const size := 10;
+		fn f(a: i32, b?: i32 = size - 1, s?: str = "x", n?: i64? = -1) -> i32 { ret a + b; }
+		let x := f(1);
+		let y := f(1, 2);
+		let z := f(1, 2, "y", null);
+		type P struct { x: i32 };
+		impl P { fn get(d?: i32 = 7) -> i32 { ret this.x + d; } }
+		let p := @P{x: 1};
+		let w := p.get() + p.get(2);
+	`)
+}
+
+func TestConstantErrors(t *testing.T) {
+	tests := []errorCase{
+		{"Division by zero", `const a := 10 / 0;`, "constant division by zero", "1:17"},
+		{"Remainder by a zero constant", `const z := 0; const a := 5 % z;`, "constant division by zero", "1:30"},
+		{"Float division by zero", `const a := 1.5 / 0.0;`, "constant division by zero", "1:18"},
+		{"Overflow", `const a := 2147483647 + 1;`, "constant 2147483648 overflows 'i32'", "1:12"},
+		{"Overflow of a constant", `const a := 200u8; const b := a * 2u8;`, "constant 400 overflows 'u8'", "1:30"},
+		{"Shift overflow", `const a := 1u8 << 7u8 << 1u8;`, "constant 256 overflows 'u8'", "1:12"},
+		{"Shift by the bit size", `const a := 1u8 << 8;`, "constant 1 << 8 overflows 'u8'", "1:12"},
+		{"Large shift overflow", `const a := 1 << 40;`, "constant 1 << 40 overflows 'i32'", "1:12"},
+		{"Negation overflow", `const m := -128i8; const a := -m;`, "constant 128 overflows 'i8'", "1:31"},
+		{"Exponent overflow", `const a := 2 ** 40;`, "constant 1099511627776 overflows 'i32'", "1:12"},
+		{"Large exponent overflow", `const a := 2 ** 1000;`, "constant 2 ** 1000 overflows 'i32'", "1:12"},
+		{"Float overflow", `const a := 3e38 * 10.0;`, "constant 3.000000005497756e+39 overflows 'f32'", "1:12"},
+		{"Division by zero in a pattern", `let x := 2; match x { 1 / 0 => { } _ => { } }`, "constant division by zero", "1:27"},
+		{"Overflow in a pattern", `let x := 2; match x { 2147483647 + 1 => { } _ => { } }`, "constant 2147483648 overflows 'i32'", "1:23"},
+		{"Duplicate constant pattern", `const one := 1; let x := 2; match x { one => { } 1 => { } _ => { } }`, "duplicate pattern '1'", "1:50"},
+		{"Default that is not constant", `fn f(a: i32, b?: i32 = a) {}`, "default value of parameter 'b' must be a constant", "1:24"},
+		{"Default of another type", `fn f(b?: i32 = "x") {}`, "default value of parameter 'b'. ", "1:16"},
+		{"Default too large", `fn f(b?: u8 = 200 + 100) {}`, "constant 300 overflows 'u8'", "1:15"},
+		{"Required after optional", `fn f(a?: i32 = 1, b: i32) {}`, "parameter 'b' must be optional, it follows an optional parameter", "1:19"},
+		{"Too few arguments", `fn f(a: i32, b?: i32 = 1) {} f();`, "function expects 1 to 2 arguments, got 0", "1:30"},
+		{"Too many arguments", `fn f(a: i32, b?: i32 = 1) {} f(1, 2, 3);`, "function expects 1 to 2 arguments, got 3", "1:30"},
+		{"Default of a required parameter", `fn f(a: i32 = 1) {}`, "parameter 'a' with a default value must be optional", "1:13"},
+		{"Optional parameter without a default", `fn f(a?: i32) {}`, "optional parameter 'a' must have a default value", "1:13"},
+		{"Duplicate folded pattern", `let x := 2; match x { 2 + 2 => { } 4 => { } _ => { } }`, "duplicate pattern '4'", "1:36"},
+	}
+
+	checkErrors(t, tests)
+}
@@ -0,0 +1,175 @@
+package typechecker
+
+import (
+	//Standard packages
+	"fmt"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/report"
+)
+
+// checkEnumTypeDecl checks the variants of an enum declaration. Variant names must be
+// unique in the enum, and field names unique in their variant.
+func checkEnumTypeDecl(name string, enumType ast.EnumType, env *TypeEnvironment) Enum {
+
+	enum := Enum{
+		DataType: ENUM_TYPE,
+		EnumName: name,
+		Variants: make([]EnumVariant, 0, len(enumType.Variants)),
+	}
+
+	for _, variant := range enumType.Variants {
+		if _, ok := enum.variant(variant.Name.Name); ok {
+			report.Add(env.filePath, variant.Name.Start.Line, variant.Name.End.Line, variant.Name.Start.Column, variant.Name.End.Column, fmt.Sprintf("variant '%s' is already declared in enum '%s'", variant.Name.Name, name)).SetLevel(report.NORMAL_ERROR)
+			continue
+		}
+
+		fields := make([]FnParam, 0, len(variant.Fields))
+		seen := make(map[string]bool)
+		for _, field := range variant.Fields {
+			if seen[field.Identifier.Name] {
+				report.Add(env.filePath, field.Identifier.Start.Line, field.Identifier.End.Line, field.Identifier.Start.Column, field.Identifier.End.Column, fmt.Sprintf("field '%s' is already declared in variant '%s'", field.Identifier.Name, variant.Name.Name)).SetLevel(report.NORMAL_ERROR)
+			}
+			seen[field.Identifier.Name] = true
+			fields = append(fields, FnParam{
+				Name: field.Identifier.Name,
+				Type: evaluateTypeName(field.Type, env),
+			})
+		}
+
+		enum.Variants = append(enum.Variants, EnumVariant{
+			Name:   variant.Name.Name,
+			Fields: fields,
+		})
+	}
+
+	return enum
+}
+
+// enumOf returns the enum named by the object of a property access, like 'Shape' in
+// 'Shape.Circle'.
+func enumOf(object ast.Node) (Enum, bool) {
+	ident, ok := object.(ast.IdentifierExpr)
+	if !ok || !isTypeDefined(ident.Name) {
+		return Enum{}, false
+	}
+	def, _ := getTypeDefinition(ident.Name)
+	enum, ok := def.(Enum)
+	return enum, ok
+}
+
+// checkVariantAccess checks the access of a variant of an enum. A variant without fields
+// is a value of the enum, a variant with fields is a function creating one.
+func checkVariantAccess(expr ast.StructPropertyAccessExpr, enum Enum, env *TypeEnvironment) Tc {
+
+	env.info.recordType(expr.Object, enum)
+
+	prop := expr.Property
+
+	variant, ok := enum.variant(prop.Name)
+	if !ok {
+		report.Add(env.filePath, prop.Start.Line, prop.End.Line, prop.Start.Column, prop.End.Column, fmt.Sprintf("'%s' is not a variant of enum '%s'", prop.Name, enum.EnumName)).SetLevel(report.CRITICAL_ERROR)
+	}
+
+	if len(variant.Fields) == 0 {
+		return enum
+	}
+
+	return Fn{
+		DataType:      FUNCTION_TYPE,
+		Params:        variant.Fields,
+		Returns:       enum,
+		FunctionScope: *NewTypeENV(env, FUNCTION_SCOPE, prop.Name, env.filePath),
+	}
+}
+
+// checkVariantPattern checks a pattern naming a variant of an enum, like 'Shape.Empty', or
+// a variant with its fields bound to names, like 'Shape.Rect(w, _)'. The names are declared
+// in the scope of the arm. It returns the key of the variant.
+func checkVariantPattern(access ast.StructPropertyAccessExpr, fields []ast.Node, hasFields bool, value Tc, armEnv *TypeEnvironment) string {
+
+	enum, _ := enumOf(access.Object)
+	pattern := ast.Node(access)
+
+	variantType := checkVariantAccess(access, enum, armEnv)
+	if err := validateTypeCompatibility(value, enum); err != nil {
+		report.Add(armEnv.filePath, pattern.StartPos().Line, pattern.EndPos().Line, pattern.StartPos().Column, pattern.EndPos().Column, fmt.Sprintf("pattern of type '%s' cannot match a value of type '%s'", enum.EnumName, tcToString(value))).SetLevel(report.NORMAL_ERROR)
+		return ""
+	}
+
+	variant, _ := enum.variant(access.Property.Name)
+	key := enum.EnumName + "." + variant.Name
+
+	if !hasFields {
+		if _, ok := variantType.(Fn); ok {
+			report.Add(armEnv.filePath, pattern.StartPos().Line, pattern.EndPos().Line, pattern.StartPos().Column, pattern.EndPos().Column, fmt.Sprintf("variant '%s' has fields", variant.Name)).Hint(fmt.Sprintf("bind them to names, or ignore them with '_', like '%s'", variantPattern(key, variant))).SetLevel(report.NORMAL_ERROR)
+		}
+		return key
+	}
+
+	if len(fields) != len(variant.Fields) {
+		report.Add(armEnv.filePath, pattern.StartPos().Line, pattern.EndPos().Line, pattern.StartPos().Column, pattern.EndPos().Column, fmt.Sprintf("variant '%s' has %d fields, got %d", variant.Name, len(variant.Fields), len(fields))).Hint(fmt.Sprintf("write '%s'", variantPattern(key, variant))).SetLevel(report.NORMAL_ERROR)
+	}
+
+	for i, field := range fields {
+		ident, ok := field.(ast.IdentifierExpr)
+		if !ok {
+			report.Add(armEnv.filePath, field.StartPos().Line, field.EndPos().Line, field.StartPos().Column, field.EndPos().Column, "the fields of a variant pattern are bound to names").Hint("match the field inside the arm").SetLevel(report.NORMAL_ERROR)
+			continue
+		}
+		if ident.Name == "_" || i >= len(variant.Fields) {
+			continue
+		}
+		if err := armEnv.declareVar(ident.Name, variant.Fields[i].Type, false, false); err != nil {
+			report.Add(armEnv.filePath, ident.Start.Line, ident.End.Line, ident.Start.Column, ident.End.Column, err.Error()).SetLevel(report.NORMAL_ERROR)
+			continue
+		}
+		armEnv.declaredAt(ident.Name, ident.Location)
+	}
+
+	return key
+}
+
+// bindsNames reports whether a pattern binds the fields of a variant, or the value of a
+// result, to names other than '_'.
+func bindsNames(pattern ast.Node) bool {
+	var names []ast.Node
+	switch t := pattern.(type) {
+	case ast.FunctionCallExpr:
+		names = t.Arguments
+	case ast.ResultExpr:
+		names = []ast.Node{t.Value}
+	}
+	for _, name := range names {
+		if ident, ok := name.(ast.IdentifierExpr); ok && ident.Name != "_" {
+			return true
+		}
+	}
+	return false
+}
+
+// variantPattern writes a pattern binding every field of a variant to its name.
+func variantPattern(key string, variant EnumVariant) string {
+	names := make([]string, len(variant.Fields))
+	for i, field := range variant.Fields {
+		names[i] = field.Name
+	}
+	if len(names) == 0 {
+		return key
+	}
+	return key + "(" + strings.Join(names, ", ") + ")"
+}
+
+// missingVariants lists the variants of an enum not matched by any arm.
+func missingVariants(enum Enum, seen map[string]bool) []string {
+	var missing []string
+	for _, variant := range enum.Variants {
+		key := enum.EnumName + "." + variant.Name
+		if !seen[key] {
+			missing = append(missing, variantPattern(key, variant))
+		}
+	}
+	return missing
+}
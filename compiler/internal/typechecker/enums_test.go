@@ -0,0 +1,59 @@
+package typechecker
+
+import (
+	"testing"
+)
+
+const figures = `
+	type Figure enum { Circle(r: f32), Rect(w: f32, h: f32), Empty };
+`
+
+func TestEnums(t *testing.T) {
+	analyze(t, figures+`
+		fn area(f: Figure) -> f32 {
+			match f {
+				Figure.Circle(r) => { ret r * r * 3.0; }
+				Figure.Rect(w, h) => { ret w * h; }
+				Figure.Empty => { ret 0.0; }
+			}
+		}
+		fn isEmpty(f: Figure) -> bool {
+			match f {
+				Figure.Empty => { ret true; }
+				_ => { ret false; }
+			}
+		}
+		let c := Figure.Circle(1.0);
+		let e: Figure = Figure.Empty;
+		let make := Figure.Rect;
+		let r: Figure = make(2.0, 3.0);
+		let total: f32 = area(c) + area(r);
+		let same := e == Figure.Empty;
+		match r {
+			Figure.Rect(_, h) => { let height: f32 = h; }
+			Figure.Circle(_), Figure.Empty => { }
+		}
+	`)
+}
+
+func TestEnumErrors(t *testing.T) {
+	tests := []errorCase{
+		{"Unknown variant", figures + `let f := Figure.Square;`, "'Square' is not a variant of enum 'Figure'", "3:17"},
+		{"Wrong field type", figures + `let f := Figure.Circle("r");`, "cannot assign value of type 'str' to type 'f32'", "3:24"},
+		{"Wrong field count", figures + `let f := Figure.Rect(1.0);`, "function expects 2 arguments, got 1", "3:10"},
+		{"Duplicate variant", `type E enum { A, A };`, "variant 'A' is already declared in enum 'E'", "1:18"},
+		{"Duplicate field", `type E enum { A(x: i32, x: i32) };`, "field 'x' is already declared in variant 'A'", "1:25"},
+		{"Enum needs a value", figures + `let f: Figure;`, "variable 'f' of enum type 'Figure' must have a value", "3:5"},
+		{"Not exhaustive", figures + `let f := Figure.Empty; match f { Figure.Empty => { } }`, "match on 'Figure' is not exhaustive", "3:24"},
+		{"Duplicate variant pattern", figures + `let f := Figure.Empty; match f { Figure.Empty => { } Figure.Empty => { } _ => { } }`, "duplicate pattern 'Figure.Empty'", "3:54"},
+		{"Fields not bound", figures + `let f := Figure.Empty; match f { Figure.Circle => { } _ => { } }`, "variant 'Circle' has fields", "3:34"},
+		{"Wrong pattern field count", figures + `let f := Figure.Empty; match f { Figure.Rect(w) => { } _ => { } }`, "variant 'Rect' has 2 fields, got 1", "3:34"},
+		{"Field pattern is a name", figures + `let f := Figure.Empty; match f { Figure.Circle(1.0) => { } _ => { } }`, "the fields of a variant pattern are bound to names", "3:48"},
+		{"Bindings alone in the arm", figures + `let f := Figure.Empty; match f { Figure.Circle(r), Figure.Empty => { } _ => { } }`, "a pattern binding names must be alone in its arm", "3:34"},
+		{"Pattern of another type", figures + `let x := 1; match x { Figure.Empty => { } _ => { } }`, "pattern of type 'Figure' cannot match a value of type 'i32'", "3:23"},
+		{"Bindings are scoped", figures + `let f := Figure.Empty; match f { Figure.Circle(r) => { } _ => { } } let x := r;`, "'r' was not declared in this scope", "3:78"},
+		{"Generic enum", `type Option<T> enum { Some(value: T), None };`, "enum types cannot be generic yet", "1:13"},
+	}
+
+	checkErrors(t, tests)
+}
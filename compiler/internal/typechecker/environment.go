@@ -3,6 +3,7 @@ package typechecker
 import (
 	"fmt"
 	"walrus/compiler/colors"
+	"walrus/compiler/internal/ast"
 )
 
 type SCOPE_TYPE int
@@ -17,45 +18,19 @@ const (
 	LOOP_SCOPE
 )
 
-var typeDefinitions = map[string]Tc{
-	string(INT8_TYPE):    NewInt(8, true),
-	string(INT16_TYPE):   NewInt(16, true),
-	string(INT32_TYPE):   NewInt(32, true),
-	string(INT64_TYPE):   NewInt(64, true),
-	string(UINT8_TYPE):   NewInt(8, false),
-	string(UINT16_TYPE):  NewInt(16, false),
-	string(UINT32_TYPE):  NewInt(32, false),
-	string(UINT64_TYPE):  NewInt(64, false),
-	string(BYTE_TYPE):    NewInt(8, false),
-	string(STRING_TYPE):  NewStr(),
-	string(FLOAT32_TYPE): NewFloat(32),
-	string(FLOAT64_TYPE): NewFloat(64),
-	string(BOOLEAN_TYPE): NewBool(),
-	string(VOID_TYPE):    NewVoid(),
-}
-
-var builtinValues = make(map[string]bool)
-
-type TypeEnvironment struct {
-	parent     *TypeEnvironment
-	scopeType  SCOPE_TYPE
-	scopeName  string
-	variables  map[string]Tc
-	constants  map[string]bool
-	isOptional map[string]bool
-	filePath   string
-}
-
-func ClearTypes() {
-	typeDefinitions = map[string]Tc{
+// builtinTypes returns the types every file starts with.
+func builtinTypes() map[string]Tc {
+	return map[string]Tc{
 		string(INT8_TYPE):    NewInt(8, true),
 		string(INT16_TYPE):   NewInt(16, true),
 		string(INT32_TYPE):   NewInt(32, true),
 		string(INT64_TYPE):   NewInt(64, true),
+		string(INT128_TYPE):  NewInt(128, true),
 		string(UINT8_TYPE):   NewInt(8, false),
 		string(UINT16_TYPE):  NewInt(16, false),
 		string(UINT32_TYPE):  NewInt(32, false),
 		string(UINT64_TYPE):  NewInt(64, false),
+		string(UINT128_TYPE): NewInt(128, false),
 		string(BYTE_TYPE):    NewInt(8, false),
 		string(STRING_TYPE):  NewStr(),
 		string(FLOAT32_TYPE): NewFloat(32),
@@ -63,21 +38,55 @@ func ClearTypes() {
 		string(BOOLEAN_TYPE): NewBool(),
 		string(VOID_TYPE):    NewVoid(),
 	}
+}
+
+// typeDefinitions holds the types visible in the file being checked: the builtin types, the
+// types the file declares and the types it imports.
+var typeDefinitions = builtinTypes()
+
+var builtinValues = make(map[string]bool)
+
+type TypeEnvironment struct {
+	parent       *TypeEnvironment
+	scopeType    SCOPE_TYPE
+	scopeName    string
+	variables    map[string]Tc
+	constants    map[string]bool
+	values       map[string]any // folded values of the constants, see foldConstant
+	isOptional   map[string]bool
+	declarations map[string]ast.Location
+	typeParams   map[string]TypeParam // type parameters of a generic function or type
+	label        string               // the label of a loop scope, empty if the loop has none
+	hasJumps     bool                 // whether a 'break' or a 'continue' of a loop scope leaves its body
+	untypedAs    Tc                   // the type untyped number literals take while a constant expression is checked
+	filePath     string
+	info         *TypeInfo // shared by every scope of the program
+}
+
+func ClearTypes() {
+	typeDefinitions = builtinTypes()
 
 	builtinValues = make(map[string]bool)
+
+	clearModules()
 }
 
 func (t *TypeEnvironment) ClearEnv() {
 	t.variables = make(map[string]Tc)
 	t.constants = make(map[string]bool)
+	t.values = make(map[string]any)
 	t.isOptional = make(map[string]bool)
+	t.declarations = make(map[string]ast.Location)
+	t.typeParams = make(map[string]TypeParam)
 }
 
 func ProgramEnv(filepath string) *TypeEnvironment {
 	env := NewTypeENV(nil, GLOBAL_SCOPE, "global", filepath)
 	initVar(env, "true", NewBool(), true, false)
 	initVar(env, "false", NewBool(), true, false)
+	env.values["true"], env.values["false"] = true, false
 	initVar(env, "PI", NewFloat(32), true, false)
+	initVar(env, "print", NewFn([]FnParam{{Name: "value", Type: NewStr()}}, NewVoid(), env), true, false)
 	return env
 }
 
@@ -93,15 +102,49 @@ func initVar(env *TypeEnvironment, name string, typeVar Tc, isConst bool, isOpti
 	colors.BRIGHT_BROWN.Printf("Initialized builtin value '%s'\n", name)
 }
 
+// isBuiltinFn reports whether name is a builtin function visible from the scope.
+func isBuiltinFn(name string, env *TypeEnvironment) bool {
+	if !builtinValues[name] {
+		return false
+	}
+	scope, err := env.resolveVar(name)
+	if err != nil {
+		return false
+	}
+	_, isFn := scope.variables[name].(Fn)
+	return isFn
+}
+
+// shadowBuiltins removes the builtin functions a top level function of the program is named
+// after. The builtin is replaced in the whole file rather than from the declaration on, as the
+// backends declare top level functions before running any statement.
+func shadowBuiltins(program ast.ProgramStmt, env *TypeEnvironment) {
+	for _, node := range program.Contents {
+		if fn, ok := node.(ast.FunctionDeclStmt); ok && isBuiltinFn(fn.Identifier.Name, env) {
+			builtinValues[fn.Identifier.Name] = false
+			delete(env.variables, fn.Identifier.Name)
+			delete(env.constants, fn.Identifier.Name)
+		}
+	}
+}
+
 func NewTypeENV(parent *TypeEnvironment, scope SCOPE_TYPE, scopeName string, filePath string) *TypeEnvironment {
+	info := NewTypeInfo()
+	if parent != nil {
+		info = parent.info
+	}
 	return &TypeEnvironment{
-		parent:     parent,
-		scopeType:  scope,
-		scopeName:  scopeName,
-		filePath:   filePath,
-		variables:  make(map[string]Tc),
-		constants:  make(map[string]bool),
-		isOptional: make(map[string]bool),
+		parent:       parent,
+		scopeType:    scope,
+		scopeName:    scopeName,
+		filePath:     filePath,
+		variables:    make(map[string]Tc),
+		constants:    make(map[string]bool),
+		values:       make(map[string]any),
+		isOptional:   make(map[string]bool),
+		declarations: make(map[string]ast.Location),
+		typeParams:   make(map[string]TypeParam),
+		info:         info,
 	}
 }
 
@@ -135,6 +178,21 @@ func (t *TypeEnvironment) resolveFunctionEnv() (*TypeEnvironment, error) {
 	return t.parent.resolveFunctionEnv()
 }
 
+// resolveLoopEnv finds the scope of the innermost loop, or of the loop with the label. A
+// loop outside the function does not count, 'break' and 'continue' cannot leave a function.
+func (t *TypeEnvironment) resolveLoopEnv(label string) (*TypeEnvironment, error) {
+	if t.scopeType == LOOP_SCOPE && (label == "" || t.label == label) {
+		return t, nil
+	}
+	if t.scopeType == FUNCTION_SCOPE || t.parent == nil {
+		if label == "" {
+			return nil, fmt.Errorf("no loop found in this scope")
+		}
+		return nil, fmt.Errorf("no loop labeled '%s' found in this scope", label)
+	}
+	return t.parent.resolveLoopEnv(label)
+}
+
 func (t *TypeEnvironment) resolveVar(name string) (*TypeEnvironment, error) {
 
 	if t.isDeclared(name) {
@@ -157,7 +215,10 @@ func (t *TypeEnvironment) declareVar(name string, typeVar Tc, isConst bool, isOp
 	}
 
 	if ok, hasVal := builtinValues[name]; hasVal && ok {
-		return fmt.Errorf("cannot redeclare builtin value '%s'", name)
+		//builtin functions can be shadowed like any other name, builtin constants cannot
+		if !isBuiltinFn(name, t) {
+			return fmt.Errorf("cannot redeclare builtin value '%s'", name)
+		}
 	}
 
 	//should not be declared
@@ -172,6 +233,12 @@ func (t *TypeEnvironment) declareVar(name string, typeVar Tc, isConst bool, isOp
 	return nil
 }
 
+// declaredAt records where a name of this scope is declared, so identifiers referring to it
+// can be linked to the declaration.
+func (t *TypeEnvironment) declaredAt(name string, location ast.Location) {
+	t.declarations[name] = location
+}
+
 func declareType(name string, typeType Tc) error {
 	if _, ok := typeDefinitions[name]; ok {
 		return fmt.Errorf("type '%s' is already defined", name)
@@ -194,6 +261,17 @@ func (t *TypeEnvironment) getStructType() (Struct, error) {
 	return t.parent.getStructType()
 }
 
+// resolveTypeParam finds a type parameter declared in this scope or in a parent scope.
+func (t *TypeEnvironment) resolveTypeParam(name string) (TypeParam, bool) {
+	if param, ok := t.typeParams[name]; ok {
+		return param, true
+	}
+	if t.parent == nil {
+		return TypeParam{}, false
+	}
+	return t.parent.resolveTypeParam(name)
+}
+
 func (t *TypeEnvironment) isDeclared(name string) bool {
 	if _, ok := t.variables[name]; ok {
 		return true
@@ -203,7 +281,7 @@ func (t *TypeEnvironment) isDeclared(name string) bool {
 
 func getTypeDefinition(name string) (Tc, error) {
 	if typ, ok := typeDefinitions[name]; !ok {
-		return nil, fmt.Errorf("unknown type '%s'", name)
+		return nil, unknownType(name)
 	} else {
 		return unwrapType(typ), nil
 	}
@@ -32,7 +32,7 @@ func checkIncrementalExpr(node ast.IncrementalInterface, env *TypeEnvironment) T
 func logCastSuccess(originalType Tc, toCast Tc) {
 	colors.ORANGE.Print("casted type ")
 	colors.PURPLE.Print(tcToString(originalType))
-	fmt.Print(" to ")
+	fmt.Fprint(colors.Output(), " to ")
 	colors.PURPLE.Println(tcToString(toCast))
 }
 
@@ -81,7 +81,7 @@ func isCastable(src, dest Tc) error {
 			return nil
 		}
 	default:
-		fmt.Printf("Came to default case: src '%s' dest '%s'\n", srcStr, destStr)
+		fmt.Fprintf(colors.Output(), "Came to default case: src '%s' dest '%s'\n", srcStr, destStr)
 		if srcStr == destStr {
 			return nil
 		}
@@ -99,7 +99,7 @@ func isCastableStruct(src Struct, dest Tc) error {
 	tName := tcToString(src)
 	dName := tcToString(dest)
 
-	fmt.Printf("checking cast from %s to %s\n", tName, dName)
+	fmt.Fprintf(colors.Output(), "checking cast from %s to %s\n", tName, dName)
 
 	errMsg := fmt.Sprintf("cannot cast struct '%s' to '%s'", tName, dName)
 
@@ -115,7 +115,7 @@ func isCastableStruct(src Struct, dest Tc) error {
 		//check if the struct implements the interface
 		return checkMethodsImplementations(src, destInterface)
 	} else {
-		fmt.Printf("dest type: %T\n", dest)
+		fmt.Fprintf(colors.Output(), "dest type: %T\n", dest)
 		return errors.New(errMsg)
 	}
 }
@@ -124,11 +124,11 @@ func checkMissingFields(src Struct, dest Struct) error {
 	//fmt.Printf("checking missing fields in %s\n", targetType)
 	errs := make([]error, 0)
 	for key, val := range dest.StructScope.variables {
-		fmt.Printf("checking field %s\n", key)
+		fmt.Fprintf(colors.Output(), "checking field %s\n", key)
 		if dVal, ok := src.StructScope.variables[key]; !ok {
 			errs = append(errs, fmt.Errorf("field '%s' is missing in struct '%s'", key, src.StructName))
 		} else if err := validateTypeCompatibility(val, dVal); err != nil {
-			fmt.Printf("uncompatible: %s\n", err.Error())
+			fmt.Fprintf(colors.Output(), "uncompatible: %s\n", err.Error())
 			errs = append(errs, err)
 		}
 	}
@@ -152,10 +152,24 @@ func checkUnaryExpr(node ast.UnaryExpr, env *TypeEnvironment) Tc {
 	op := node.Operator
 	arg := node.Argument
 	//evaluate argument. must be evaluated to number or boolean for ! (not)
-	typeVal := parseNodeValue(arg, env)
+	var typeVal Tc
+	if _, ok := arg.(ast.IntegerLiteralExpr); ok && op.Kind == lexer.MINUS_TOKEN {
+		// a negated literal is checked with its sign
+		typeVal = checkIntegerLiteral(node, env)
+		env.info.recordType(arg, typeVal)
+	} else {
+		typeVal = parseNodeValue(arg, env)
+	}
 
 	switch t := typeVal.(type) {
-	case Int, Float:
+	case Invalid:
+	case Int:
+		//allow - and ~ only
+		if op.Kind != lexer.MINUS_TOKEN && op.Kind != lexer.BIT_NOT_TOKEN {
+
+			report.Add(env.filePath, op.Start.Line, op.End.Line, op.Start.Column, op.End.Column, "invalid unary operation with numeric types").SetLevel(report.NORMAL_ERROR)
+		}
+	case Float:
 		//allow - only
 		if op.Kind != lexer.MINUS_TOKEN {
 
@@ -176,11 +190,10 @@ func checkUnaryExpr(node ast.UnaryExpr, env *TypeEnvironment) Tc {
 func checkBinaryExpr(node ast.BinaryExpr, env *TypeEnvironment) Tc {
 	op := node.Binop
 
-	left := parseNodeValue(node.Left, env)
-	right := parseNodeValue(node.Right, env)
-
-	leftType := tcToString(left)
-	rightType := tcToString(right)
+	left, right := checkOperands(node, env)
+	if isInvalid(left) || isInvalid(right) {
+		return NewInvalid()
+	}
 
 	var errLineStart, errLineEnd, errStart, errEnd int
 	var errMsg string
@@ -189,24 +202,13 @@ func checkBinaryExpr(node ast.BinaryExpr, env *TypeEnvironment) Tc {
 	case lexer.PLUS_TOKEN:
 		return checkAdditionAndConcat(node, left, right, env)
 	case lexer.MINUS_TOKEN, lexer.MUL_TOKEN, lexer.DIV_TOKEN, lexer.MOD_TOKEN, lexer.EXP_TOKEN:
-		//must have to be numeric type on both side
-		if leftType != builtins.INT32 && leftType != builtins.FLOAT32 {
-			errMsg = fmt.Sprintf("cannot perform numeric operation between type '%s' and '%s'. left hand side expression must be evaluated to a numeric type.", leftType, rightType)
-			errLineStart = node.Left.StartPos().Line
-			errLineEnd = node.Left.EndPos().Line
-			errStart = node.Left.StartPos().Column
-			errEnd = node.Left.EndPos().Column
-		} else if rightType != builtins.INT32 && rightType != builtins.FLOAT32 {
-			errMsg = fmt.Sprintf("cannot perform numeric operation between type '%s' and '%s'. right hand side expression must be evaluated to a numeric type.", leftType, rightType)
-			errLineStart = node.Right.StartPos().Line
-			errLineEnd = node.Right.EndPos().Line
-			errStart = node.Right.StartPos().Column
-			errEnd = node.Right.EndPos().Column
-		} else {
-			return left
-		}
+		return checkArithmetic(node, left, right, env)
 	case lexer.DOUBLE_EQUAL_TOKEN, lexer.NOT_EQUAL_TOKEN, lexer.LESS_EQUAL_TOKEN, lexer.LESS_TOKEN, lexer.GREATER_EQUAL_TOKEN, lexer.GREATER_TOKEN:
 		return checkComparison(node, left, right, env)
+	case lexer.AND_TOKEN, lexer.OR_TOKEN:
+		return checkLogical(node, left, right, env)
+	case lexer.BIT_AND_TOKEN, lexer.BIT_OR_TOKEN, lexer.BIT_XOR_TOKEN, lexer.SHIFT_LEFT_TOKEN, lexer.SHIFT_RIGHT_TOKEN:
+		return checkBitwise(op, node.Left, node.Right, left, right, env)
 	default:
 		errMsg = "invalid operator"
 		errLineStart = op.Start.Line
@@ -219,6 +221,27 @@ func checkBinaryExpr(node ast.BinaryExpr, env *TypeEnvironment) Tc {
 	return left
 }
 
+// checkOperands checks the operands of a binary expression. An untyped constant takes the type
+// of the other operand, like '1' in 'x + 1' where 'x' is an i64. Two untyped constants take
+// one type, see untypedDefault.
+func checkOperands(node ast.BinaryExpr, env *TypeEnvironment) (Tc, Tc) {
+	if env.untypedAs == nil && node.Binop.Kind != lexer.AND_TOKEN && node.Binop.Kind != lexer.OR_TOKEN {
+		leftUntyped, rightUntyped := isUntypedConstant(node.Left), isUntypedConstant(node.Right)
+		switch {
+		case leftUntyped && rightUntyped:
+			env.untypedAs = untypedDefault(node)
+			defer func() { env.untypedAs = nil }()
+		case leftUntyped:
+			right := parseNodeValue(node.Right, env)
+			return checkValueAs(node.Left, right, env), right
+		case rightUntyped:
+			left := parseNodeValue(node.Left, env)
+			return left, checkValueAs(node.Right, left, env)
+		}
+	}
+	return parseNodeValue(node.Left, env), parseNodeValue(node.Right, env)
+}
+
 func checkComparison(node ast.BinaryExpr, left Tc, right Tc, env *TypeEnvironment) Tc {
 
 	leftType := tcToString(left)
@@ -234,6 +257,10 @@ func checkComparison(node ast.BinaryExpr, left Tc, right Tc, env *TypeEnvironmen
 			return boolean
 		} else if leftType == rightType {
 			return boolean
+		} else if isNullComparison(left, right) || isNullComparison(right, left) {
+			return boolean
+		} else if isResultComparison(left, right) || isResultComparison(right, left) {
+			return boolean
 		}
 	} else {
 		// ( >=, >, <=, < ) allow only numeric types
@@ -247,37 +274,133 @@ func checkComparison(node ast.BinaryExpr, left Tc, right Tc, env *TypeEnvironmen
 	return left
 }
 
+// isNullComparison reports whether a value that may be null is compared with null.
+func isNullComparison(value, null Tc) bool {
+	_, isMaybe := unwrapType(value).(Maybe)
+	_, isNull := null.(Null)
+	return isMaybe && isNull
+}
+
+// isResultComparison reports whether a result is compared with a value or an error it can hold.
+func isResultComparison(value, other Tc) bool {
+	_, isResult := unwrapType(value).(Result)
+	return isResult && validateTypeCompatibility(value, other) == nil
+}
+
 func checkAdditionAndConcat(node ast.BinaryExpr, left Tc, right Tc, env *TypeEnvironment) Tc {
+	//only string concat, int and floats are allowed.
+	if isNumberType(unwrapType(left)) {
+		return checkArithmetic(node, left, right, env)
+	} else if tcToString(left) == builtins.STRING {
+		// we concat the type
+		return left
+	}
+
+	report.Add(env.filePath, node.Start.Line, node.End.Line, node.StartPos().Column, node.EndPos().Column, "invalid expression").SetLevel(report.NORMAL_ERROR)
+	return left
+}
+
+// checkArithmetic checks the arithmetic operators, which take two numbers of the same type,
+// like two i64 or two f64, and give a number of that type. An integer and a float can be
+// mixed, like 'a + 2.4' where 'a' is an i32, and give the float type.
+func checkArithmetic(node ast.BinaryExpr, left Tc, right Tc, env *TypeEnvironment) Tc {
 
 	leftType := tcToString(left)
 	rightType := tcToString(right)
 
-	var errLineStart, errLineEnd, errStart, errEnd int
-	var errMsg string
-	//only string concat, int and floats are allowed.
-	if leftType == builtins.INT32 || leftType == builtins.FLOAT32 {
-		//right has to be int or float
-		if rightType != builtins.INT32 && rightType != builtins.FLOAT32 {
-			errMsg = fmt.Sprintf("cannot perform numeric operation between type '%s' and '%s'. right hand side expression must be evaluated to a numeric type.", leftType, rightType)
-			errLineStart = node.Right.StartPos().Line
-			errLineEnd = node.Right.EndPos().Line
-			errStart = node.Right.StartPos().Column
-			errEnd = node.Right.EndPos().Column
-		} else {
-			//return the type if left
+	//must have to be numeric type on both side
+	if !isNumberType(unwrapType(left)) {
+		report.Add(env.filePath, node.Left.StartPos().Line, node.Left.EndPos().Line, node.Left.StartPos().Column, node.Left.EndPos().Column, fmt.Sprintf("cannot perform numeric operation between type '%s' and '%s'. left hand side expression must be evaluated to a numeric type.", leftType, rightType)).SetLevel(report.NORMAL_ERROR)
+	} else if !isNumberType(unwrapType(right)) {
+		report.Add(env.filePath, node.Right.StartPos().Line, node.Right.EndPos().Line, node.Right.StartPos().Column, node.Right.EndPos().Column, fmt.Sprintf("cannot perform numeric operation between type '%s' and '%s'. right hand side expression must be evaluated to a numeric type.", leftType, rightType)).SetLevel(report.NORMAL_ERROR)
+	} else if float, ok := mixedFloat(left, right); ok {
+		return float
+	} else if leftType != rightType {
+		report.Add(env.filePath, node.Left.StartPos().Line, node.Right.EndPos().Line, node.Left.StartPos().Column, node.Right.EndPos().Column, fmt.Sprintf("mismatched types '%s' and '%s' for operator '%s'", leftType, rightType, node.Binop.Value)).SetLevel(report.NORMAL_ERROR)
+	}
+
+	return left
+}
+
+// mixedFloat returns the float operand of an integer and a float.
+func mixedFloat(left, right Tc) (Tc, bool) {
+	_, leftInt := unwrapType(left).(Int)
+	_, rightInt := unwrapType(right).(Int)
+	_, leftFloat := unwrapType(left).(Float)
+	_, rightFloat := unwrapType(right).(Float)
+	switch {
+	case leftInt && rightFloat:
+		return right, true
+	case leftFloat && rightInt:
+		return left, true
+	}
+	return nil, false
+}
+
+// checkInterpolatedString checks the values of a string like "hello {name}". Any value can be
+// put in a string, like it can be added to one, except a void value.
+func checkInterpolatedString(node ast.InterpolatedStringExpr, env *TypeEnvironment) Tc {
+	for _, part := range node.Parts {
+		value := parseNodeValue(part, env)
+		if _, ok := value.(Void); ok {
+			report.Add(env.filePath, part.StartPos().Line, part.EndPos().Line, part.StartPos().Column, part.EndPos().Column, "cannot interpolate a void value").SetLevel(report.NORMAL_ERROR)
+		}
+	}
+	return NewStr()
+}
+
+// checkLogical checks '&&' and '||', which take booleans only.
+func checkLogical(node ast.BinaryExpr, left Tc, right Tc, env *TypeEnvironment) Tc {
+
+	for _, operand := range []struct {
+		node  ast.Node
+		value Tc
+	}{{node.Left, left}, {node.Right, right}} {
+		if _, ok := unwrapType(operand.value).(Bool); !ok {
+			report.Add(env.filePath, operand.node.StartPos().Line, operand.node.EndPos().Line, operand.node.StartPos().Column, operand.node.EndPos().Column, fmt.Sprintf("operator '%s' needs boolean operands, got '%s'", node.Binop.Value, tcToString(operand.value))).SetLevel(report.NORMAL_ERROR)
+		}
+	}
+
+	return NewBool()
+}
+
+// checkBitwise checks the bitwise and shift operators, which take integers only. Both
+// operands of '&', '|' and '^' have the same type, the count of a shift can be any integer.
+// The result has the type of the left operand.
+func checkBitwise(op lexer.Token, leftNode, rightNode ast.Node, left Tc, right Tc, env *TypeEnvironment) Tc {
+
+	for _, operand := range []struct {
+		node  ast.Node
+		value Tc
+	}{{leftNode, left}, {rightNode, right}} {
+		if !isIntType(unwrapType(operand.value)) {
+			report.Add(env.filePath, operand.node.StartPos().Line, operand.node.EndPos().Line, operand.node.StartPos().Column, operand.node.EndPos().Column, fmt.Sprintf("operator '%s' needs integer operands, got '%s'", op.Value, tcToString(operand.value))).SetLevel(report.NORMAL_ERROR)
 			return left
 		}
-	} else if leftType == builtins.STRING {
-		// we concat the type
-		return left
-	} else {
-		errMsg = "invalid expression"
-		errLineStart = node.Start.Line
-		errLineEnd = node.End.Line
-		errStart = node.StartPos().Column
-		errEnd = node.EndPos().Column
 	}
 
-	report.Add(env.filePath, errLineStart, errLineEnd, errStart, errEnd, errMsg).SetLevel(report.NORMAL_ERROR)
+	if op.Kind != lexer.SHIFT_LEFT_TOKEN && op.Kind != lexer.SHIFT_RIGHT_TOKEN && tcToString(left) != tcToString(right) {
+		report.Add(env.filePath, leftNode.StartPos().Line, rightNode.EndPos().Line, leftNode.StartPos().Column, rightNode.EndPos().Column, fmt.Sprintf("mismatched types '%s' and '%s' for operator '%s'", tcToString(left), tcToString(right), op.Value)).SetLevel(report.NORMAL_ERROR)
+	}
+
 	return left
 }
+
+// bitwiseAssignment returns the operator applied by a bitwise compound assignment like '&='.
+func bitwiseAssignment(op lexer.Token) (lexer.Token, bool) {
+	switch op.Kind {
+	case lexer.BIT_AND_EQUALS_TOKEN:
+		op.Kind, op.Value = lexer.BIT_AND_TOKEN, "&"
+	case lexer.BIT_OR_EQUALS_TOKEN:
+		op.Kind, op.Value = lexer.BIT_OR_TOKEN, "|"
+	case lexer.BIT_XOR_EQUALS_TOKEN:
+		op.Kind, op.Value = lexer.BIT_XOR_TOKEN, "^"
+	case lexer.SHIFT_LEFT_EQUALS_TOKEN:
+		op.Kind, op.Value = lexer.SHIFT_LEFT_TOKEN, "<<"
+	case lexer.SHIFT_RIGHT_EQUALS_TOKEN:
+		op.Kind, op.Value = lexer.SHIFT_RIGHT_TOKEN, ">>"
+	default:
+		return op, false
+	}
+	return op, true
+}
@@ -1,6 +1,9 @@
 package typechecker
 
 import (
+	//Standard packages
+	"fmt"
+
 	//Walrus packages
 	"walrus/compiler/internal/ast"
 	"walrus/compiler/report"
@@ -11,9 +14,9 @@ func checkForStmt(forStmt ast.ForStmt, env *TypeEnvironment) Tc {
 	// for loop can be infinite loop or have a start, end and step
 
 	forLoopEnv := NewTypeENV(env, LOOP_SCOPE, "for loop", env.filePath)
+	declareLoopLabel(forStmt.Label, forLoopEnv)
 
-	if forStmt.Init != nil || forStmt.Condition != nil || forStmt.Increment != nil {
-
+	if forStmt.Init != nil {
 		//must be a variable declaration, or an assignment
 		switch t := forStmt.Init.(type) {
 		case ast.VarDeclStmt:
@@ -23,26 +26,150 @@ func checkForStmt(forStmt ast.ForStmt, env *TypeEnvironment) Tc {
 		default:
 			report.Add(env.filePath, forStmt.StartPos().Line, forStmt.EndPos().Line, forStmt.StartPos().Column, forStmt.EndPos().Column, "for loop initialization must be a variable declaration or assignment").SetLevel(report.CRITICAL_ERROR)
 		}
+	}
 
+	if forStmt.Condition != nil {
 		cond := parseNodeValue(forStmt.Condition, forLoopEnv)
 
 		//must be a boolean if !cond -> error, if !cond.Type == bool -> error
 		if _, ok := cond.(Bool); !ok {
-			report.Add(env.filePath, forStmt.StartPos().Line, forStmt.EndPos().Line, forStmt.StartPos().Column, forStmt.EndPos().Column, "for loop condition must be a boolean expression").SetLevel(report.CRITICAL_ERROR)
+			report.Add(env.filePath, forStmt.Condition.StartPos().Line, forStmt.Condition.EndPos().Line, forStmt.Condition.StartPos().Column, forStmt.Condition.EndPos().Column, "for loop condition must be a boolean expression").SetLevel(report.CRITICAL_ERROR)
 		}
+	}
 
-		incr := parseNodeValue(forStmt.Increment, forLoopEnv)
+	if forStmt.Increment != nil {
+		parseNodeValue(forStmt.Increment, forLoopEnv)
 
-		//must be assignment
-		if _, ok := incr.(ast.IncrementalInterface); !ok {
-			report.Add(env.filePath, forStmt.StartPos().Line, forStmt.EndPos().Line, forStmt.StartPos().Column, forStmt.EndPos().Column, "for loop increment must be incremental assignment").SetLevel(report.CRITICAL_ERROR)
+		//must be an increment, decrement or an assignment
+		switch forStmt.Increment.(type) {
+		case ast.IncrementalInterface, ast.VarAssignmentExpr:
+		default:
+			report.Add(env.filePath, forStmt.Increment.StartPos().Line, forStmt.Increment.EndPos().Line, forStmt.Increment.StartPos().Column, forStmt.Increment.EndPos().Column, "for loop increment must be incremental assignment").SetLevel(report.CRITICAL_ERROR)
 		}
 	}
 
 	//infinte loop
-	for _, stmt := range forStmt.Block.Contents {
-		checkAST(stmt, forLoopEnv)
+	checkBlock(forStmt.Block, forLoopEnv)
+
+	return NewVoid()
+}
+
+// checkWhileStmt checks a 'while' or a 'do-while' loop. The condition is in the scope around
+// the loop, the variables of the block are not declared when it is checked. The block of a
+// 'do-while' loop always runs, so the loop returns when its block does, unless a 'break' or
+// a 'continue' leaves it.
+func checkWhileStmt(node ast.WhileStmt, env *TypeEnvironment) Tc {
+
+	loopEnv := NewTypeENV(env, LOOP_SCOPE, "while loop", env.filePath)
+	declareLoopLabel(node.Label, loopEnv)
+
+	if !node.IsDo {
+		checkWhileCondition(node.Condition, env)
+		checkBlock(node.Block, loopEnv)
+		return NewVoid()
+	}
+
+	body := checkBlock(node.Block, loopEnv)
+	checkWhileCondition(node.Condition, env)
+
+	return Block{
+		IsSatisfied:     body.IsSatisfied && !loopEnv.hasJumps,
+		ProblemLocation: body.ProblemLocation,
+	}
+}
+
+// checkWhileCondition checks that the condition of a while loop is a boolean expression.
+func checkWhileCondition(cond ast.Node, env *TypeEnvironment) {
+	if _, ok := parseNodeValue(cond, env).(Bool); !ok {
+		report.Add(env.filePath, cond.StartPos().Line, cond.EndPos().Line, cond.StartPos().Column, cond.EndPos().Column, "while loop condition must be a boolean expression").SetLevel(report.NORMAL_ERROR)
+	}
+}
+
+// checkForEachStmt checks a loop over an array, a map or a range of integers. The loop
+// variables are declared in the scope of the loop: the index and the element of an array,
+// the key and the value of a map, the index and the number of a range.
+func checkForEachStmt(node ast.ForEachStmt, env *TypeEnvironment) Tc {
+
+	loopEnv := NewTypeENV(env, LOOP_SCOPE, "foreach loop", env.filePath)
+	declareLoopLabel(node.Label, loopEnv)
+
+	iterable := parseNodeValue(node.Iterable, env)
+
+	var keyType, valueType Tc
+
+	switch t := unwrapType(iterable).(type) {
+	case Array:
+		keyType = NewInt(32, true)
+		valueType = t.ArrayType
+	case Map:
+		keyType = t.KeyType
+		valueType = t.ValueType
+	case Range:
+		if _, ok := unwrapType(t.RangeStart).(Int); !ok {
+			report.Add(env.filePath, node.Iterable.StartPos().Line, node.Iterable.EndPos().Line, node.Iterable.StartPos().Column, node.Iterable.EndPos().Column, fmt.Sprintf("cannot iterate over a range of '%s'\n - only ranges of integers can be iterated", tcToString(t.RangeStart))).SetLevel(report.NORMAL_ERROR)
+		}
+		keyType = NewInt(32, true)
+		valueType = t.RangeStart
+	default:
+		report.Add(env.filePath, node.Iterable.StartPos().Line, node.Iterable.EndPos().Line, node.Iterable.StartPos().Column, node.Iterable.EndPos().Column, fmt.Sprintf("cannot iterate over '%s'\n - only arrays, maps and ranges can be iterated", tcToString(iterable))).SetLevel(report.CRITICAL_ERROR)
+	}
+
+	if node.Key != nil {
+		declareLoopVariable(node.Key, keyType, loopEnv)
 	}
+	declareLoopVariable(node.Value, valueType, loopEnv)
+
+	checkBlock(node.Block, loopEnv)
 
 	return NewVoid()
 }
+
+// declareLoopVariable declares a variable of a foreach loop in the scope of the loop.
+func declareLoopVariable(node ast.Node, value Tc, env *TypeEnvironment) {
+
+	identifier, ok := node.(ast.IdentifierExpr)
+	if !ok {
+		report.Add(env.filePath, node.StartPos().Line, node.EndPos().Line, node.StartPos().Column, node.EndPos().Column, "foreach loop variable must be an identifier").SetLevel(report.CRITICAL_ERROR)
+	}
+
+	if err := env.declareVar(identifier.Name, value, false, false); err != nil {
+		report.Add(env.filePath, identifier.Start.Line, identifier.End.Line, identifier.Start.Column, identifier.End.Column, err.Error()).SetLevel(report.CRITICAL_ERROR)
+	}
+	env.declaredAt(identifier.Name, identifier.Location)
+	env.info.recordType(identifier, value)
+}
+
+// declareLoopLabel gives the label to the scope of a loop. A loop nested in a loop with the
+// same label would hide it from 'break' and 'continue'.
+func declareLoopLabel(label ast.IdentifierExpr, loopEnv *TypeEnvironment) {
+
+	if label.Name == "" {
+		return
+	}
+
+	if _, err := loopEnv.parent.resolveLoopEnv(label.Name); err == nil {
+		report.Add(loopEnv.filePath, label.Start.Line, label.End.Line, label.Start.Column, label.End.Column, fmt.Sprintf("label '%s' is already used by an enclosing loop", label.Name)).SetLevel(report.NORMAL_ERROR)
+	}
+
+	loopEnv.label = label.Name
+}
+
+// checkLoopControl checks a 'break' or a 'continue' statement. It must be inside a loop of
+// the function, or inside the loop with its label.
+func checkLoopControl(keyword string, label ast.IdentifierExpr, location ast.Location, env *TypeEnvironment) Tc {
+
+	loopEnv, err := env.resolveLoopEnv(label.Name)
+	if err != nil {
+		if label.Name == "" {
+			report.Add(env.filePath, location.Start.Line, location.End.Line, location.Start.Column, location.End.Column, fmt.Sprintf("'%s' can only be used inside a loop", keyword)).SetLevel(report.NORMAL_ERROR)
+		} else {
+			report.Add(env.filePath, label.Start.Line, label.End.Line, label.Start.Column, label.End.Column, fmt.Sprintf("cannot %s '%s'. %s", keyword, label.Name, err.Error())).SetLevel(report.NORMAL_ERROR)
+		}
+	} else {
+		loopEnv.hasJumps = true
+	}
+
+	return LoopJump{
+		DataType: LOOP_JUMP_TYPE,
+	}
+}
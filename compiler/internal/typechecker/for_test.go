@@ -0,0 +1,132 @@
+package typechecker
+
+import (
+	"testing"
+
+	"walrus/compiler/internal/ast"
+)
+
+func TestForEachVariableTypes(t *testing.T) {
+	tree, info := analyze(t, `
+		foreach i, v in [1.5, 2.5] { }
+		foreach k, v in $map[str]bool{"a" => true} { }
+		foreach n in 1..4 { }
+	`)
+
+	contents := tree.(ast.ProgramStmt).Contents
+
+	tests := []struct {
+		name     string
+		node     ast.Node
+		expected string
+	}{
+		{"array index", contents[0].(ast.ForEachStmt).Key, "i32"},
+		{"array element", contents[0].(ast.ForEachStmt).Value, "f32"},
+		{"map key", contents[1].(ast.ForEachStmt).Key, "str"},
+		{"map value", contents[1].(ast.ForEachStmt).Value, "bool"},
+		{"range number", contents[2].(ast.ForEachStmt).Value, "i32"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc, ok := info.TypeOf(tt.node)
+			if !ok {
+				t.Fatalf("Expected a type for the loop variable")
+			}
+			if got := tcToString(tc); got != tt.expected {
+				t.Errorf(exp, tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestForEachErrors(t *testing.T) {
+	tests := []errorCase{
+		{"Not iterable", `let x := 5; foreach v in x { }`, "cannot iterate over 'i32'", "1:26"},
+		{"Range of floats", `foreach v in 1.5..2.5 { }`, "cannot iterate over a range of 'f32'", "1:14"},
+		{"Same name twice", `foreach v, v in [1] { }`, "variable 'v' is already declared in this scope", "1:12"},
+		{"Variables are scoped to the loop", `foreach v in [1] { } let x := v;`, "'v' was not declared in this scope", "1:31"},
+		{"Element type is checked", `foreach v in ["a"] { let n : i32 = v; }`, "error declaring variable 'n'", "1:36"},
+	}
+
+	checkErrors(t, tests)
+}
+
+func TestBreakAndContinue(t *testing.T) {
+	analyze(t, `
+		fn find(xs: []i32, x: i32) -> i32 {
+			let found := 0 - 1;
+			foreach i, v in xs {
+				if v != x {
+					continue;
+				}
+				found = i;
+				break;
+			}
+			ret found;
+		}
+		outer: for let i := 0; i < 3; i++ {
+			rows: foreach v in [1, 2] {
+				if v == i {
+					continue outer;
+				}
+				match v {
+					2 => { break rows; }
+					_ => { break outer; }
+				}
+			}
+		}
+	`)
+}
+
+func TestBreakAndContinueErrors(t *testing.T) {
+	tests := []errorCase{
+		{"Break outside a loop", `break;`, "'break' can only be used inside a loop", "1:1"},
+		{"Continue outside a loop", `if true { continue; }`, "'continue' can only be used inside a loop", "1:11"},
+		{"Break out of a function", `for { fn f() { break; } }`, "'break' can only be used inside a loop", "1:16"},
+		{"Unknown label", `for { break outer; }`, "cannot break 'outer'. no loop labeled 'outer' found in this scope", "1:13"},
+		{"Label of a loop after it", `outer: for { } for { continue outer; }`, "cannot continue 'outer'", "1:31"},
+		{"Label used twice", `outer: for { outer: for { } }`, "label 'outer' is already used by an enclosing loop", "1:14"},
+		{"Code after break", `for { break; let x := 1; }`, "unreachable code", "1:7"},
+		{"Code after continue", `foreach v in [1] { if v == 1 { continue; print("skipped"); } }`, "unreachable code", "1:32"},
+	}
+
+	checkErrors(t, tests)
+}
+
+func TestWhileLoops(t *testing.T) {
+	analyze(t, `
+		fn count(n: i32) -> i32 {
+			let i := 0;
+			while i < n {
+				i++;
+			}
+			ret i;
+		}
+		fn first(xs: []i32) -> i32 {
+			let i := 0;
+			do {
+				ret xs[i];
+			} while i < 0;
+		}
+		outer: while true {
+			do {
+				break outer;
+			} while false;
+		}
+	`)
+}
+
+func TestWhileLoopErrors(t *testing.T) {
+	tests := []errorCase{
+		{"While condition", `while 1 { }`, "while loop condition must be a boolean expression", "1:7"},
+		{"Do-while condition", `do { } while "yes";`, "while loop condition must be a boolean expression", "1:14"},
+		{"Condition after the block", `do { let done := true; } while !done;`, "'done' was not declared in this scope", "1:33"},
+		{"While may not run", `fn f() -> i32 { while true { ret 1; } }`, "missing return statement in function", "1:1"},
+		{"Do-while that breaks", `fn f() -> i32 { do { if true { break; } ret 1; } while true; }`, "missing return in this block", "1:30"},
+		{"Do-while that continues", `fn f(b: bool) -> i32 { do { if b { continue; } ret 1; } while b; }`, "missing return in this block", "1:34"},
+		{"Code after break", `while true { break; let x := 1; }`, "unreachable code", "1:14"},
+	}
+
+	checkErrors(t, tests)
+}
@@ -10,19 +10,23 @@ import (
 
 func checkFunctionExpr(funcNode ast.FunctionLiteral, env *TypeEnvironment) Tc {
 	name := fmt.Sprintf("_FN_%s", RandStringRunes(10))
-	return CheckAndDeclareFunction(funcNode, name, env)
+	return CheckAndDeclareFunction(funcNode, name, funcNode.Location, env)
 }
 
-func CheckAndDeclareFunction(funcNode ast.FunctionLiteral, name string, env *TypeEnvironment) Fn {
+// CheckAndDeclareFunction declares a function under the given name, declared at the given
+// location, and checks its body.
+func CheckAndDeclareFunction(funcNode ast.FunctionLiteral, name string, declaration ast.Location, env *TypeEnvironment) Fn {
 
 	fnEnv := NewTypeENV(env, FUNCTION_SCOPE, name, env.filePath)
 
+	typeParams := declareTypeParams(funcNode.TypeParams, fnEnv)
 	parameters := checkandDeclareParamaters(funcNode.Params, fnEnv)
 	//check return type
 	returnType := evaluateTypeName(funcNode.ReturnType, fnEnv)
 
 	fn := Fn{
 		DataType:      FUNCTION_TYPE,
+		TypeParams:    typeParams,
 		Params:        parameters,
 		Returns:       returnType,
 		FunctionScope: *fnEnv,
@@ -33,6 +37,7 @@ func CheckAndDeclareFunction(funcNode ast.FunctionLiteral, name string, env *Typ
 	if err != nil {
 		report.Add(env.filePath, funcNode.Start.Line, funcNode.End.Line, funcNode.Start.Column, funcNode.End.Column, "error declaring function. "+err.Error()).SetLevel(report.CRITICAL_ERROR)
 	}
+	env.declaredAt(name, declaration)
 
 	checkSatisfaction(funcNode, returnType, fnEnv)
 
@@ -106,18 +111,46 @@ func checkAndDeclareSingleParameter(param ast.FunctionParam, fnEnv *TypeEnvironm
 	}
 
 	paramType := evaluateTypeName(param.Type, fnEnv)
+	defaultValue := checkDefaultValue(param, paramType, fnEnv)
+
+	// the arguments are given in order, so the params after an optional one are optional too
+	if count := len(*parameters); param.DefaultValue == nil && count > 0 && (*parameters)[count-1].Default != nil {
+		report.Add(fnEnv.filePath, param.Identifier.Start.Line, param.Identifier.End.Line, param.Identifier.Start.Column, param.Identifier.End.Column, fmt.Sprintf("parameter '%s' must be optional, it follows an optional parameter", param.Identifier.Name)).SetLevel(report.NORMAL_ERROR)
+	}
 
 	err := fnEnv.declareVar(param.Identifier.Name, paramType, false, false)
 	if err != nil {
 		report.Add(fnEnv.filePath, param.Identifier.Start.Line, param.Identifier.End.Line, param.Identifier.Start.Column, param.Identifier.End.Column, fmt.Sprintf("error defining parameter. %s", err.Error())).SetLevel(report.CRITICAL_ERROR)
 	}
+	fnEnv.declaredAt(param.Identifier.Name, param.Identifier.Location)
 
 	*parameters = append(*parameters, FnParam{
-		Name: param.Identifier.Name,
-		Type: paramType,
+		Name:    param.Identifier.Name,
+		Type:    paramType,
+		Default: defaultValue,
 	})
 }
 
+// checkDefaultValue checks the default value of an optional parameter, which must be a constant
+// of the type of the parameter, and returns its folded value.
+func checkDefaultValue(param ast.FunctionParam, paramType Tc, fnEnv *TypeEnvironment) any {
+	if param.DefaultValue == nil {
+		return nil
+	}
+	node := param.DefaultValue
+	valueType := checkValueAs(node, paramType, fnEnv)
+	if err := validateTypeCompatibility(paramType, valueType); err != nil {
+		report.Add(fnEnv.filePath, node.StartPos().Line, node.EndPos().Line, node.StartPos().Column, node.EndPos().Column, fmt.Sprintf("default value of parameter '%s'. %s", param.Identifier.Name, err.Error())).SetLevel(report.NORMAL_ERROR)
+		return nil
+	}
+	reportConstantError(node, fnEnv)
+	value, ok := fnEnv.info.constantOf(node)
+	if !ok {
+		report.Add(fnEnv.filePath, node.StartPos().Line, node.EndPos().Line, node.StartPos().Column, node.EndPos().Column, fmt.Sprintf("default value of parameter '%s' must be a constant", param.Identifier.Name)).SetLevel(report.NORMAL_ERROR)
+	}
+	return value
+}
+
 func checkFunctionCall(callNode ast.FunctionCallExpr, env *TypeEnvironment) Tc {
 	//check if the function is declared
 	caller := parseNodeValue(callNode.Caller, env)
@@ -127,14 +160,34 @@ func checkFunctionCall(callNode ast.FunctionCallExpr, env *TypeEnvironment) Tc {
 		report.Add(env.filePath, callNode.Caller.StartPos().Line, callNode.Caller.EndPos().Line, callNode.Caller.StartPos().Column, callNode.Caller.EndPos().Column, err.Error()).SetLevel(report.CRITICAL_ERROR)
 	}
 
+	// an untyped number takes the type of its parameter, unless the parameter has a type
+	// parameter in its type, which is inferred from the arguments
+	args := make([]Tc, len(callNode.Arguments))
+	for i, arg := range callNode.Arguments {
+		if i < len(fn.Params) && !mentionsTypeParam(fn.Params[i].Type, fn.TypeParams) {
+			args[i] = checkValueAs(arg, fn.Params[i].Type, env)
+		} else {
+			args[i] = parseNodeValue(arg, env)
+		}
+	}
+
+	// the type parameters of a generic function are inferred from the arguments
+	if len(fn.TypeParams) > 0 {
+		fn = instantiateCall(callNode, fn, args, env)
+	}
+
 	fnParams := fn.Params
-	if len(callNode.Arguments) != len(fnParams) {
-		report.Add(env.filePath, callNode.Start.Line, callNode.End.Line, callNode.Start.Column, callNode.End.Column, fmt.Sprintf("function expects %d arguments, got %d", len(fnParams), len(callNode.Arguments))).SetLevel(report.NORMAL_ERROR)
+	if required := requiredParams(fn); len(callNode.Arguments) < required || len(callNode.Arguments) > len(fnParams) {
+		expected := fmt.Sprint(len(fnParams))
+		if required < len(fnParams) {
+			expected = fmt.Sprintf("%d to %d", required, len(fnParams))
+		}
+		report.Add(env.filePath, callNode.Start.Line, callNode.End.Line, callNode.Start.Column, callNode.End.Column, fmt.Sprintf("function expects %s arguments, got %d", expected, len(callNode.Arguments))).SetLevel(report.NORMAL_ERROR)
 	}
 
 	//check if the arguments match the parameters
-	for i := 0; i < len(callNode.Arguments); i++ {
-		arg := parseNodeValue(callNode.Arguments[i], env)
+	for i := 0; i < len(callNode.Arguments) && i < len(fnParams); i++ {
+		arg := args[i]
 		err := validateTypeCompatibility(fnParams[i].Type, arg)
 		if err != nil {
 			report.Add(env.filePath, callNode.Arguments[i].StartPos().Line, callNode.Arguments[i].EndPos().Line, callNode.Arguments[i].StartPos().Column, callNode.Arguments[i].EndPos().Column, err.Error()).SetLevel(report.NORMAL_ERROR)
@@ -144,6 +197,51 @@ func checkFunctionCall(callNode ast.FunctionCallExpr, env *TypeEnvironment) Tc {
 	return fn.Returns
 }
 
+// requiredParams returns the number of params of a function a call must give, the ones
+// before its optional params.
+func requiredParams(fn Fn) int {
+	for i, param := range fn.Params {
+		if param.Default != nil {
+			return i
+		}
+	}
+	return len(fn.Params)
+}
+
+// addDefaultArguments gives the calls of a checked tree leaving optional params out the
+// default values of those params, so the backends always call a function with every argument.
+// A default value is written as the literal of its constant.
+func addDefaultArguments(tree ast.Node, info *TypeInfo) ast.Node {
+	return ast.Rewrite(tree, func(node ast.Node) ast.Node {
+		call, ok := node.(ast.FunctionCallExpr)
+		if !ok {
+			return node
+		}
+		caller, ok := info.TypeOf(call.Caller)
+		if !ok {
+			return node
+		}
+		fn, err := userDefinedToFn(caller)
+		if err != nil || len(call.Arguments) >= len(fn.Params) {
+			return node
+		}
+		// the literals are placed right after the call, where no other node starts
+		arguments := append([]ast.Node{}, call.Arguments...)
+		for i, param := range fn.Params[len(call.Arguments):] {
+			location := ast.Location{Start: call.End, End: call.End}
+			location.End.Column += i + 1
+			location.End.Index += i + 1
+			literal := constantLiteral(param.Default, param.Type, location, info)
+			if literal == nil {
+				return node
+			}
+			arguments = append(arguments, literal)
+		}
+		call.Arguments = arguments
+		return call
+	})
+}
+
 func userDefinedToFn(ud Tc) (Fn, error) {
 	// if UserDefined then chain until Fn or error
 	switch t := ud.(type) {
@@ -167,7 +265,13 @@ func checkFunctionDeclStmt(funcNode ast.FunctionDeclStmt, env *TypeEnvironment)
 		report.Add(env.filePath, funcNode.Identifier.Start.Line, funcNode.Identifier.End.Line, funcNode.Identifier.Start.Column, funcNode.Identifier.End.Column, fmt.Sprintf("function '%s' is already defined in this scope", funcName)).SetLevel(report.NORMAL_ERROR)
 	}
 
-	return CheckAndDeclareFunction(funcNode.FunctionLiteral, funcName, env)
+	fn := CheckAndDeclareFunction(funcNode.FunctionLiteral, funcName, funcNode.Identifier.Location, env)
+
+	if funcNode.IsExported && canExport(funcNode, env) {
+		exportValue(funcName, env)
+	}
+
+	return fn
 }
 
 func getFunctionReturnValue(env *TypeEnvironment, returnNode ast.Node) Tc {
@@ -0,0 +1,448 @@
+package typechecker
+
+import (
+	//Standard packages
+	"fmt"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/report"
+)
+
+// declareTypeParams declares the type parameters of a generic function or type in its
+// scope. A constraint must be an interface.
+func declareTypeParams(params []ast.TypeParam, env *TypeEnvironment) []TypeParam {
+
+	var typeParams []TypeParam
+
+	for _, param := range params {
+		name := param.Identifier.Name
+		loc := param.Identifier.Location
+
+		if _, ok := env.resolveTypeParam(name); ok {
+			report.Add(env.filePath, loc.Start.Line, loc.End.Line, loc.Start.Column, loc.End.Column, fmt.Sprintf("type parameter '%s' is already declared", name)).SetLevel(report.NORMAL_ERROR)
+		} else if isTypeDefined(name) {
+			report.Add(env.filePath, loc.Start.Line, loc.End.Line, loc.Start.Column, loc.End.Column, fmt.Sprintf("type parameter '%s' has the name of a type", name)).SetLevel(report.NORMAL_ERROR)
+		}
+
+		typeParam := TypeParam{
+			DataType: TYPE_PARAM_TYPE,
+			Name:     name,
+		}
+
+		if param.Constraint != nil {
+			constraint := evaluateTypeName(param.Constraint, env)
+			if iface, ok := unwrapType(constraint).(Interface); ok {
+				typeParam.Constraint = iface
+			} else {
+				report.Add(env.filePath, param.Constraint.StartPos().Line, param.Constraint.EndPos().Line, param.Constraint.StartPos().Column, param.Constraint.EndPos().Column, fmt.Sprintf("constraint of type parameter '%s' must be an interface, got '%s'", name, tcToString(constraint))).SetLevel(report.NORMAL_ERROR)
+			}
+		}
+
+		env.typeParams[name] = typeParam
+		typeParams = append(typeParams, typeParam)
+	}
+
+	return typeParams
+}
+
+// checkGenericTypeDecl checks the definition of a generic type with its type parameters
+// declared. A generic struct or interface is named after its parameters, like 'Box<T>',
+// which is also the type of 'this' in the methods of a generic struct.
+func checkGenericTypeDecl(name string, node ast.GenericType, env *TypeEnvironment) Generic {
+
+	typeEnv := NewTypeENV(env, env.scopeType, name, env.filePath)
+
+	params := declareTypeParams(node.TypeParams, typeEnv)
+
+	args := make([]Tc, len(params))
+	for i, param := range params {
+		args[i] = param
+	}
+	instanceName := typeArgsName(name, args)
+
+	var def Tc
+
+	switch t := node.TypeDef.(type) {
+	case ast.StructType:
+		structType := checkStructTypeDecl(instanceName, t, typeEnv)
+		structType.Generic = name
+		structType.TypeArgs = args
+		def = structType
+	case ast.InterfaceType:
+		interfaceType := checkInterfaceTypeDecl(instanceName, t, typeEnv)
+		interfaceType.Generic = name
+		interfaceType.TypeArgs = args
+		def = interfaceType
+	case ast.EnumType:
+		report.Add(env.filePath, node.StartPos().Line, node.EndPos().Line, node.StartPos().Column, node.EndPos().Column, "enum types cannot be generic yet").SetLevel(report.NORMAL_ERROR)
+		def = checkEnumTypeDecl(name, t, typeEnv)
+	default:
+		def = evaluateTypeName(t, typeEnv)
+	}
+
+	return Generic{
+		DataType:   GENERIC_TYPE,
+		TypeName:   name,
+		TypeParams: params,
+		TypeDef:    def,
+	}
+}
+
+// typeArgsName names an instance of a generic type, like 'Pair<i32, str>'.
+func typeArgsName(name string, args []Tc) string {
+	names := make([]string, len(args))
+	for i, arg := range args {
+		names[i] = tcToString(arg)
+	}
+	return name + "<" + strings.Join(names, ", ") + ">"
+}
+
+// instantiateType evaluates the type arguments written for a generic type and returns
+// the instance of the type.
+func instantiateType(generic Generic, typeArgs []ast.DataType, location ast.Location, env *TypeEnvironment) Tc {
+
+	if len(typeArgs) != len(generic.TypeParams) {
+		report.Add(env.filePath, location.Start.Line, location.End.Line, location.Start.Column, location.End.Column, fmt.Sprintf("type '%s' expects %d type arguments, got %d", generic.TypeName, len(generic.TypeParams), len(typeArgs))).Hint(fmt.Sprintf("write the type arguments, like '%s'", tcToString(generic.TypeDef))).SetLevel(report.NORMAL_ERROR)
+	}
+
+	args := make([]Tc, len(generic.TypeParams))
+	for i, param := range generic.TypeParams {
+		if i >= len(typeArgs) {
+			args[i] = param
+			continue
+		}
+		args[i] = evaluateTypeName(typeArgs[i], env)
+		checkConstraint(param, args[i], ast.Location{Start: typeArgs[i].StartPos(), End: typeArgs[i].EndPos()}, env)
+	}
+
+	return instantiate(generic, args)
+}
+
+// checkConstraint reports a type argument not implementing the constraint of its type parameter.
+func checkConstraint(param TypeParam, arg Tc, location ast.Location, env *TypeEnvironment) {
+	if param.Constraint == nil {
+		return
+	}
+	if err := checkMethodsImplementations(unwrapType(arg), param.Constraint); err != nil {
+		report.Add(env.filePath, location.Start.Line, location.End.Line, location.Start.Column, location.End.Column, fmt.Sprintf("type '%s' does not implement '%s', the constraint of '%s'", tcToString(arg), tcToString(param.Constraint), param.Name)).SetLevel(report.NORMAL_ERROR)
+	}
+}
+
+// instantiating holds the instances being built, so a method returning its own type, like
+// 'fn with(value: T) -> Box<T>', refers to the instance instead of building it again.
+var instantiating = make(map[string]Tc)
+
+// instantiate replaces the type parameters of a generic type with the given arguments.
+// The members of a struct are copied into a scope of the instance, so the generic struct
+// keeps its own.
+func instantiate(generic Generic, args []Tc) Tc {
+
+	bindings := make(map[string]Tc, len(args))
+	for i, param := range generic.TypeParams {
+		if i < len(args) {
+			bindings[param.Name] = args[i]
+		}
+	}
+
+	name := typeArgsName(generic.TypeName, args)
+
+	if instance, ok := instantiating[name]; ok {
+		return instance
+	}
+
+	switch t := generic.TypeDef.(type) {
+	case Struct:
+		scope := NewTypeENV(t.StructScope.parent, STRUCT_SCOPE, name, t.StructScope.filePath)
+		instance := Struct{
+			DataType:    STRUCT_TYPE,
+			StructName:  name,
+			StructScope: *scope,
+			Generic:     generic.TypeName,
+			TypeArgs:    args,
+		}
+		// the scope shares its maps with the instance, which sees the members added below
+		instantiating[name] = instance
+		defer delete(instantiating, name)
+		for member, value := range t.StructScope.variables {
+			scope.variables[member] = substituteType(value, bindings)
+			scope.constants[member] = t.StructScope.constants[member]
+			scope.isOptional[member] = t.StructScope.isOptional[member]
+			if location, ok := t.StructScope.declarations[member]; ok {
+				scope.declarations[member] = location
+			}
+		}
+		return instance
+	case Interface:
+		// within its own methods, the instance is only known by name
+		instantiating[name] = Interface{DataType: INTERFACE_TYPE, InterfaceName: name, Generic: generic.TypeName, TypeArgs: args}
+		defer delete(instantiating, name)
+		methods := make([]InterfaceMethodType, len(t.Methods))
+		for i, method := range t.Methods {
+			methods[i] = InterfaceMethodType{
+				Name:   method.Name,
+				Method: substituteType(method.Method, bindings).(Fn),
+			}
+		}
+		return Interface{
+			DataType:      INTERFACE_TYPE,
+			InterfaceName: name,
+			Methods:       methods,
+			Generic:       generic.TypeName,
+			TypeArgs:      args,
+		}
+	default:
+		return substituteType(t, bindings)
+	}
+}
+
+// substituteType replaces the bound type parameters found in a type.
+func substituteType(value Tc, bindings map[string]Tc) Tc {
+	switch t := value.(type) {
+	case TypeParam:
+		if arg, ok := bindings[t.Name]; ok {
+			return arg
+		}
+		return t
+	case Array:
+		return Array{DataType: ARRAY_TYPE, ArrayType: substituteType(t.ArrayType, bindings)}
+	case Map:
+		return NewMap(substituteType(t.KeyType, bindings), substituteType(t.ValueType, bindings))
+	case Maybe:
+		// T? with a nullable T is still nullable once
+		inner := substituteType(t.MaybeType, bindings)
+		if maybe, ok := unwrapType(inner).(Maybe); ok {
+			return maybe
+		}
+		return NewMaybe(inner)
+	case Range:
+		return Range{DataType: RANGE_TYPE, RangeStart: substituteType(t.RangeStart, bindings), RangeEnd: substituteType(t.RangeEnd, bindings)}
+	case Tuple:
+		elements := make([]Tc, len(t.Elements))
+		for i, element := range t.Elements {
+			elements[i] = substituteType(element, bindings)
+		}
+		return NewTuple(elements)
+	case Fn:
+		params := make([]FnParam, len(t.Params))
+		for i, param := range t.Params {
+			params[i] = FnParam{Name: param.Name, Type: substituteType(param.Type, bindings)}
+		}
+		return Fn{
+			DataType:      t.DataType,
+			TypeParams:    t.TypeParams,
+			Params:        params,
+			Returns:       substituteType(t.Returns, bindings),
+			FunctionScope: t.FunctionScope,
+		}
+	case StructMethod:
+		return StructMethod{IsPrivate: t.IsPrivate, Fn: substituteType(t.Fn, bindings).(Fn)}
+	case StructProperty:
+		return StructProperty{IsPrivate: t.IsPrivate, Type: substituteType(t.Type, bindings)}
+	case Struct:
+		return substituteInstance(t, t.Generic, t.TypeArgs, bindings)
+	case Interface:
+		return substituteInstance(t, t.Generic, t.TypeArgs, bindings)
+	case UserDefined:
+		return UserDefined{DataType: t.DataType, TypeName: t.TypeName, TypeDef: substituteType(t.TypeDef, bindings)}
+	default:
+		return t
+	}
+}
+
+// substituteInstance instantiates a generic type again when its type arguments use the
+// bound type parameters, like a 'Box<T>' property of a generic struct.
+func substituteInstance(value Tc, genericName string, args []Tc, bindings map[string]Tc) Tc {
+
+	if genericName == "" {
+		return value
+	}
+
+	changed := false
+	newArgs := make([]Tc, len(args))
+	for i, arg := range args {
+		newArgs[i] = substituteType(arg, bindings)
+		changed = changed || tcToString(newArgs[i]) != tcToString(arg)
+	}
+
+	if !changed {
+		return value
+	}
+
+	def, err := getTypeDefinition(genericName)
+	generic, ok := def.(Generic)
+	if err != nil || !ok {
+		return value
+	}
+
+	return instantiate(generic, newArgs)
+}
+
+// inferTypeArgs matches the type of a parameter with the type of the value given for it,
+// and binds the type parameters found in the parameter type, like 'T' to 'i32' for a '[]T'
+// parameter given a '[]i32'. A type parameter keeps the first type bound to it.
+func inferTypeArgs(param Tc, arg Tc, typeParams []TypeParam, bindings map[string]Tc) {
+
+	arg = unwrapType(arg)
+
+	switch p := param.(type) {
+	case TypeParam:
+		if _, ok := bindings[p.Name]; ok || !hasTypeParam(typeParams, p.Name) {
+			return
+		}
+		// null does not tell which type it is
+		if _, ok := arg.(Null); ok {
+			return
+		}
+		bindings[p.Name] = arg
+	case Array:
+		if a, ok := arg.(Array); ok {
+			inferTypeArgs(p.ArrayType, a.ArrayType, typeParams, bindings)
+		}
+	case Map:
+		if a, ok := arg.(Map); ok {
+			inferTypeArgs(p.KeyType, a.KeyType, typeParams, bindings)
+			inferTypeArgs(p.ValueType, a.ValueType, typeParams, bindings)
+		}
+	case Maybe:
+		if a, ok := arg.(Maybe); ok {
+			inferTypeArgs(p.MaybeType, a.MaybeType, typeParams, bindings)
+		} else {
+			inferTypeArgs(p.MaybeType, arg, typeParams, bindings)
+		}
+	case Range:
+		if a, ok := arg.(Range); ok {
+			inferTypeArgs(p.RangeStart, a.RangeStart, typeParams, bindings)
+			inferTypeArgs(p.RangeEnd, a.RangeEnd, typeParams, bindings)
+		}
+	case Tuple:
+		if a, ok := arg.(Tuple); ok {
+			for i := 0; i < len(p.Elements) && i < len(a.Elements); i++ {
+				inferTypeArgs(p.Elements[i], a.Elements[i], typeParams, bindings)
+			}
+		}
+	case Fn:
+		if a, ok := arg.(Fn); ok {
+			for i := 0; i < len(p.Params) && i < len(a.Params); i++ {
+				inferTypeArgs(p.Params[i].Type, a.Params[i].Type, typeParams, bindings)
+			}
+			inferTypeArgs(p.Returns, a.Returns, typeParams, bindings)
+		}
+	case Struct:
+		if a, ok := arg.(Struct); ok && p.Generic != "" && p.Generic == a.Generic {
+			inferTypeArgsList(p.TypeArgs, a.TypeArgs, typeParams, bindings)
+		}
+	case Interface:
+		if a, ok := arg.(Interface); ok && p.Generic != "" && p.Generic == a.Generic {
+			inferTypeArgsList(p.TypeArgs, a.TypeArgs, typeParams, bindings)
+		}
+	case UserDefined:
+		inferTypeArgs(p.TypeDef, arg, typeParams, bindings)
+	}
+}
+
+func inferTypeArgsList(params []Tc, args []Tc, typeParams []TypeParam, bindings map[string]Tc) {
+	for i := 0; i < len(params) && i < len(args); i++ {
+		inferTypeArgs(params[i], args[i], typeParams, bindings)
+	}
+}
+
+// mentionsTypeParam reports whether a type has one of the type parameters in it, like 'T' or
+// '[]T'. Such a type binds its own type parameters when it is inferred from itself.
+func mentionsTypeParam(value Tc, typeParams []TypeParam) bool {
+	bindings := make(map[string]Tc)
+	inferTypeArgs(value, value, typeParams, bindings)
+	return len(bindings) > 0
+}
+
+func hasTypeParam(typeParams []TypeParam, name string) bool {
+	for _, param := range typeParams {
+		if param.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// bindTypeArgs returns the types inferred for the type parameters, reporting the ones that
+// could not be inferred and the ones not implementing their constraint.
+func bindTypeArgs(typeParams []TypeParam, bindings map[string]Tc, hint string, location ast.Location, env *TypeEnvironment) []Tc {
+
+	args := make([]Tc, len(typeParams))
+
+	for i, param := range typeParams {
+		arg, ok := bindings[param.Name]
+		if !ok {
+			report.Add(env.filePath, location.Start.Line, location.End.Line, location.Start.Column, location.End.Column, fmt.Sprintf("cannot infer the type of '%s'", param.Name)).Hint(hint).SetLevel(report.NORMAL_ERROR)
+			args[i] = param
+			continue
+		}
+		checkConstraint(param, arg, location, env)
+		args[i] = arg
+	}
+
+	return args
+}
+
+// instantiateCall infers the type arguments of a call to a generic function from the types
+// of its arguments, and returns the function with its type parameters replaced.
+func instantiateCall(callNode ast.FunctionCallExpr, fn Fn, args []Tc, env *TypeEnvironment) Fn {
+
+	bindings := make(map[string]Tc)
+	for i, arg := range args {
+		if i < len(fn.Params) {
+			inferTypeArgs(fn.Params[i].Type, arg, fn.TypeParams, bindings)
+		}
+	}
+
+	typeArgs := bindTypeArgs(fn.TypeParams, bindings, "pass arguments of the types the function takes", callNode.Location, env)
+	for i, param := range fn.TypeParams {
+		bindings[param.Name] = typeArgs[i]
+	}
+
+	instance := substituteType(fn, bindings).(Fn)
+	instance.TypeParams = nil
+
+	return instance
+}
+
+// checkGenericStructLiteral checks a literal of a generic struct. The type arguments are
+// inferred from the values of the properties when they are not written.
+func checkGenericStructLiteral(structLit ast.StructLiteral, generic Generic, env *TypeEnvironment) Tc {
+
+	sName := structLit.Identifier
+
+	template, ok := generic.TypeDef.(Struct)
+	if !ok {
+		report.Add(env.filePath, sName.StartPos().Line, sName.EndPos().Line, sName.StartPos().Column, sName.EndPos().Column, fmt.Sprintf("'%s' is not a struct", sName.Name)).SetLevel(report.CRITICAL_ERROR)
+	}
+
+	var values []Tc
+	var instance Tc
+	if len(structLit.TypeArgs) > 0 {
+		instance = instantiateType(generic, structLit.TypeArgs, sName.Location, env)
+		values = checkPropValues(structLit, instance.(Struct), nil, env)
+	} else {
+		values = checkPropValues(structLit, template, generic.TypeParams, env)
+		bindings := make(map[string]Tc)
+		for i, prop := range structLit.Properties {
+			if property, ok := template.StructScope.variables[prop.Prop.Name].(StructProperty); ok {
+				inferTypeArgs(property.Type, values[i], generic.TypeParams, bindings)
+			}
+		}
+		hint := fmt.Sprintf("write the type arguments, like '@%s{...}'", tcToString(template))
+		instance = instantiate(generic, bindTypeArgs(generic.TypeParams, bindings, hint, sName.Location, env))
+	}
+
+	structType := instance.(Struct)
+
+	checkPropsType(structType, structLit, values, env)
+	composeErrors(structLit, checkMissingProps(structType, structLit), env)
+
+	return UserDefined{
+		DataType: USER_DEFINED_TYPE,
+		TypeName: structType.StructName,
+		TypeDef:  structType,
+	}
+}
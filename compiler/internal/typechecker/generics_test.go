@@ -0,0 +1,73 @@
+package typechecker
+
+import (
+	"testing"
+)
+
+const boxes = `
+	type Shape interface { fn area() -> f32; };
+	type Square struct { side: f32 };
+	impl Square { fn area() -> f32 { ret this.side * this.side; } }
+	type Box<T> struct { value: T };
+	impl Box<T> {
+		fn get() -> T { ret this.value; }
+		fn with(value: T) -> Box<T> { ret @Box{value: value}; }
+	}
+`
+
+func TestGenerics(t *testing.T) {
+	analyze(t, boxes+`
+		fn identity<T>(value: T) -> T {
+			ret value;
+		}
+		fn first<T>(items: []T) -> T {
+			ret items[0];
+		}
+		fn total<S: Shape>(shapes: []S) -> f32 {
+			let sum := 0.0;
+			foreach s in shapes {
+				sum = sum + s.area();
+			}
+			ret sum;
+		}
+		fn pick<K, V>(m: map[K]V, key: K) -> V {
+			ret m[key];
+		}
+		let a: i32 = identity(1);
+		let b: str = identity("b");
+		let c: f32 = first([1.0, 2.0]);
+		let d: f32 = total([@Square{side: 2.0}]);
+		let e: str = pick($map[i32]str{1 => "one"}, 1);
+		let box := @Box{value: 5};
+		let five: i32 = box.get();
+		let six: Box<i32> = box.with(6);
+		let named := @Box<str>{value: "named"};
+		let nested: Box<Box<i32>> = @Box{value: box};
+		let inner: i32 = nested.get().get();
+		let maybe := @Box<i32?>{value: null};
+	`)
+}
+
+func TestGenericErrors(t *testing.T) {
+	tests := []errorCase{
+		{"Wrong argument", boxes + `fn f<T>(a: T, b: T) {} f(1, "b");`, "cannot assign value of type 'str' to type 'i32'", "10:29"},
+		{"Wrong return", boxes + `fn f<T>(a: T) -> T { ret a; } let x: str = f(1);`, "error declaring variable 'x'. cannot assign value of type 'i32' to type 'str'", "10:44"},
+		{"Not inferred", boxes + `fn f<T>(a: T?) {} f(null);`, "cannot infer the type of 'T'", "10:19"},
+		{"Constraint not satisfied", boxes + `fn f<S: Shape>(s: S) {} f(1);`, "type 'i32' does not implement 'Shape', the constraint of 'S'", "10:25"},
+		{"Constraint must be an interface", boxes + `fn f<T: i32>(a: T) {}`, "constraint of type parameter 'T' must be an interface, got 'i32'", "10:9"},
+		{"Type parameter declared twice", boxes + `fn f<T, T>(a: T) {}`, "type parameter 'T' is already declared", "10:9"},
+		{"Type parameter named as a type", boxes + `fn f<Square>(a: Square) {}`, "type parameter 'Square' has the name of a type", "10:6"},
+		{"Missing type arguments", boxes + `let b: Box = @Box{value: 1};`, "type 'Box' expects 1 type arguments, got 0", "10:8"},
+		{"Not generic", boxes + `let s: Square<i32> = @Square{side: 1.0};`, "type 'Square' is not generic", "10:8"},
+		{"Wrong type argument", boxes + `let b: Box<str> = @Box{value: 1};`, "error declaring variable 'b'. cannot assign value of type 'Box<i32>' to type 'Box<str>'", "10:19"},
+		{"Literal not inferred", boxes + `let b := @Box{value: null};`, "cannot infer the type of 'T'", "10:10"},
+		{"Literal with wrong value", boxes + `let b := @Box<str>{value: 1};`, "property 'value' should be 'str' but found 'i32'", "10:20"},
+		{"Unconstrained method", boxes + `fn f<T>(a: T) -> f32 { ret a.area(); }`, "'area' does not exist on type 'T'", "10:30"},
+		{"Constrained method", boxes + `fn f<S: Shape>(s: S) -> f32 { ret s.side; }`, "interface 'Shape' does not have a method 'side'", "10:37"},
+		{"Type parameter needs a value", boxes + `fn f<T>(a: T) { let x: T; }`, "variable 'x' of type parameter 'T' must have a value", "10:21"},
+		{"Impl without type parameters", boxes + `impl Box { fn f() {} }`, "type 'Box' expects 1 type arguments, got 0", "10:6"},
+		{"Impl of a non generic type", boxes + `impl Square<T> { fn f() {} }`, "type 'Square' is not generic", "10:6"},
+	}
+
+	checkErrors(t, tests)
+}
@@ -0,0 +1,89 @@
+package typechecker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"walrus/compiler/internal/modules"
+	"walrus/compiler/report"
+)
+
+// checkModules writes the files in a temporary folder, then loads and checks the program
+// starting at main.wal. It returns the reports and the folder.
+func checkModules(t *testing.T, files map[string]string) (reports report.Reports, dir string) {
+	t.Helper()
+
+	dir = t.TempDir()
+	for name, code := range files {
+		if e := os.WriteFile(filepath.Join(dir, name), []byte(code), 0644); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	defer func() {
+		// critical errors stop the checker
+		recover()
+		reports = report.GetReports()
+		report.ClearReports()
+	}()
+
+	loaded, e := modules.Load(filepath.Join(dir, "main.wal"), false)
+	if e != nil {
+		t.Fatal(e)
+	}
+	AnalyzeProgram(loaded)
+
+	return nil, dir
+}
+
+// errorCase is a program which must fail to type check with an error starting with expected,
+// reported at the position at, written "line:column".
+type errorCase struct {
+	name     string
+	code     string
+	expected string
+	at       string
+}
+
+// checkErrors checks every program of a table as the file main.wal.
+func checkErrors(t *testing.T, tests []errorCase) {
+	t.Helper()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reports, dir := checkModules(t, map[string]string{"main.wal": tt.code})
+			expectError(t, reports, filepath.Join(dir, "main.wal"), tt.expected, tt.at)
+		})
+	}
+}
+
+// expectError checks that one of the reports is an error of the file starting with expected,
+// reported at the position at.
+func expectError(t *testing.T, reports report.Reports, file, expected, at string) {
+	t.Helper()
+
+	var positions []string
+	for _, r := range reports {
+		if !r.IsError() || !strings.HasPrefix(r.Message, expected) {
+			continue
+		}
+		position := fmt.Sprintf("%d:%d", r.LineStart, r.ColStart)
+		if r.FilePath == file && position == at {
+			return
+		}
+		positions = append(positions, fmt.Sprintf("%s:%s", filepath.Base(r.FilePath), position))
+	}
+	if len(positions) > 0 {
+		t.Errorf("Expected the error %q at %s:%s, got it at %v", expected, filepath.Base(file), at, positions)
+		return
+	}
+
+	messages := make([]string, 0, len(reports))
+	for _, r := range reports {
+		messages = append(messages, r.Message)
+	}
+	t.Errorf("Expected an error starting with %q, got %v", expected, messages)
+}
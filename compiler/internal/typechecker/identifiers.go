@@ -24,5 +24,11 @@ func checkIdentifier(node ast.IdentifierExpr, env *TypeEnvironment) Tc {
 	// if we found value on that scope, return the value. Else make error (though there is no change to reach the error)
 	variable := declaredEnv.variables[name]
 
+	if location, ok := declaredEnv.declarations[name]; ok {
+		env.info.recordReference(node, &location)
+	} else {
+		env.info.recordReference(node, nil)
+	}
+
 	return unwrapType(variable)
 }
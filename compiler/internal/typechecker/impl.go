@@ -22,6 +22,16 @@ func checkImplStmt(implStmt ast.ImplStmt, env *TypeEnvironment) Tc {
 		report.Add(env.filePath, implStmt.Start.Line, implStmt.End.Line, implStmt.Start.Column, implStmt.End.Column, err.Error()).SetLevel(report.CRITICAL_ERROR)
 	}
 
+	// methods can only be added in the file declaring the type
+	if file, ok := typeModules[implStmt.ImplFor.Name]; ok && file != env.filePath {
+		report.Add(env.filePath, implStmt.ImplFor.Start.Line, implStmt.ImplFor.End.Line, implStmt.ImplFor.Start.Column, implStmt.ImplFor.End.Column, fmt.Sprintf("cannot implement type '%s' declared in '%s'", implStmt.ImplFor.Name, file)).SetLevel(report.CRITICAL_ERROR)
+	}
+
+	typeParams := checkImplTypeParams(implStmt, structValue, env)
+	if generic, ok := structValue.(Generic); ok {
+		structValue = generic.TypeDef
+	}
+
 	// type must be a struct
 	implForType, ok := structValue.(Struct)
 	if !ok {
@@ -38,6 +48,12 @@ func checkImplStmt(implStmt ast.ImplStmt, env *TypeEnvironment) Tc {
 
 		fnEnv := NewTypeENV(&implForType.StructScope, FUNCTION_SCOPE, name, implForType.StructScope.filePath)
 
+		//the type parameters of the struct go by the names given in the impl
+		for paramName, param := range typeParams {
+			fnEnv.typeParams[paramName] = param
+		}
+		methodTypeParams := declareTypeParams(method.TypeParams, fnEnv)
+
 		//check the parameters and declare them
 		params := checkandDeclareParamaters(method.Params, fnEnv)
 
@@ -48,6 +64,7 @@ func checkImplStmt(implStmt ast.ImplStmt, env *TypeEnvironment) Tc {
 			IsPrivate: method.IsPrivate,
 			Fn: Fn{
 				DataType:      FUNCTION_TYPE,
+				TypeParams:    methodTypeParams,
 				Params:        params,
 				Returns:       returnType,
 				FunctionScope: *fnEnv,
@@ -59,6 +76,7 @@ func checkImplStmt(implStmt ast.ImplStmt, env *TypeEnvironment) Tc {
 		if err != nil {
 			report.Add(env.filePath, method.Start.Line, method.End.Line, method.Start.Column, method.End.Column, fmt.Sprintf("cannot declare method '%s'\n└── %s", method.Identifier.Name, err.Error())).SetLevel(report.CRITICAL_ERROR)
 		}
+		implForType.StructScope.declaredAt(name, method.Identifier.Location)
 
 		checkSatisfaction(method.FunctionLiteral, returnType, fnEnv)
 
@@ -66,3 +84,35 @@ func checkImplStmt(implStmt ast.ImplStmt, env *TypeEnvironment) Tc {
 
 	return NewVoid()
 }
+
+// checkImplTypeParams matches the type parameters named in 'impl Box<T>' with the ones of
+// the generic struct, by position, so the methods may name them differently.
+func checkImplTypeParams(implStmt ast.ImplStmt, structValue Tc, env *TypeEnvironment) map[string]TypeParam {
+
+	typeParams := make(map[string]TypeParam)
+	implFor := implStmt.ImplFor
+
+	generic, ok := structValue.(Generic)
+	if !ok {
+		if len(implStmt.TypeParams) > 0 {
+			report.Add(env.filePath, implFor.Start.Line, implFor.End.Line, implFor.Start.Column, implFor.End.Column, fmt.Sprintf("type '%s' is not generic", implFor.Name)).SetLevel(report.NORMAL_ERROR)
+		}
+		return typeParams
+	}
+
+	if len(implStmt.TypeParams) != len(generic.TypeParams) {
+		report.Add(env.filePath, implFor.Start.Line, implFor.End.Line, implFor.Start.Column, implFor.End.Column, fmt.Sprintf("type '%s' expects %d type arguments, got %d", implFor.Name, len(generic.TypeParams), len(implStmt.TypeParams))).Hint(fmt.Sprintf("write 'impl %s'", tcToString(generic.TypeDef))).SetLevel(report.NORMAL_ERROR)
+	}
+
+	for i, param := range implStmt.TypeParams {
+		if i >= len(generic.TypeParams) {
+			break
+		}
+		if isTypeDefined(param.Name) {
+			report.Add(env.filePath, param.Start.Line, param.End.Line, param.Start.Column, param.End.Column, fmt.Sprintf("type parameter '%s' has the name of a type", param.Name)).SetLevel(report.NORMAL_ERROR)
+		}
+		typeParams[param.Name] = generic.TypeParams[i]
+	}
+
+	return typeParams
+}
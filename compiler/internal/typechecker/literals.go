@@ -0,0 +1,233 @@
+package typechecker
+
+import (
+	//Standard packages
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/lexer"
+	"walrus/compiler/report"
+)
+
+// checkIntegerLiteral checks that an integer literal fits its type. A negated literal is
+// checked with its sign, so '-128i8' fits while '128i8' does not. An untyped literal has the
+// type of the constant expression it is in, see checkValueAs.
+func checkIntegerLiteral(node ast.Node, env *TypeEnvironment) Tc {
+	literal, value, _ := integerLiteral(node)
+	var intType Tc = NewInt(literal.BitSize, literal.IsSigned)
+	if literal.IsUntyped && env.untypedAs != nil {
+		intType = env.untypedAs
+	}
+	switch target := unwrapType(intType).(type) {
+	case Int:
+		checkIntegerFits(node, value, target, env)
+	case Float:
+		checkFloatFits(node, literal.Value, target, env)
+	}
+	return intType
+}
+
+// checkFloatLiteral checks that a float literal fits its type.
+func checkFloatLiteral(node ast.FloatLiteralExpr, env *TypeEnvironment) Tc {
+	var floatType Tc = NewFloat(node.BitSize)
+	if _, ok := unwrapType(env.untypedAs).(Float); ok && node.IsUntyped {
+		floatType = env.untypedAs
+	}
+	checkFloatFits(node, node.Value, unwrapType(floatType).(Float), env)
+	return floatType
+}
+
+// checkValueAs checks a value stored in a place of the expected type, like a variable with
+// an explicit type, a parameter, a field or a return value. An untyped constant, a number
+// literal written without a type suffix or an expression of such literals like '10 + 1',
+// takes the type of the place when it is a number type, or a nullable or result type of a
+// number. Integers fit integer and float types, a constant with a float in it only fits
+// float types. The values of tuple and array literals are checked against the elements of
+// the place. Other values keep their own type.
+func checkValueAs(node ast.Node, expected Tc, env *TypeEnvironment) Tc {
+	switch t := node.(type) {
+	case ast.TupleLiteral:
+		if target, ok := unwrapType(expected).(Tuple); ok && len(target.Elements) == len(t.Values) {
+			return checkTupleLiteralAs(t, target, env)
+		}
+	case ast.ArrayLiteral:
+		if target, ok := unwrapType(expected).(Array); ok {
+			return checkArrayLiteralAs(t, target, env)
+		}
+	}
+
+	if !isUntypedConstant(node) || env.untypedAs != nil {
+		return parseNodeValue(node, env)
+	}
+
+	switch t := unwrapType(expected).(type) {
+	case Maybe:
+		expected = t.MaybeType
+	case Result:
+		expected = t.OkType
+	}
+	switch unwrapType(expected).(type) {
+	case Int:
+		if hasFloatLiteral(node) {
+			return parseNodeValue(node, env)
+		}
+	case Float:
+	default:
+		return parseNodeValue(node, env)
+	}
+	return checkUntypedAs(node, expected, env)
+}
+
+// checkUntypedAs checks an untyped constant whose literals all take the given type, and
+// records that type for every part of it. A constant overflowing the type is reported, like
+// '200 + 100' stored in an u8.
+func checkUntypedAs(node ast.Node, dtype Tc, env *TypeEnvironment) Tc {
+	env.untypedAs = dtype
+	defer func() { env.untypedAs = nil }()
+	value := parseNodeValue(node, env)
+	reportConstantError(node, env)
+	return value
+}
+
+// isUntypedConstant reports whether an expression is an untyped constant: a number literal
+// written without a type suffix, or the arithmetic and bitwise operators applied to such
+// constants, like '-(2 * 3)'. The bitwise operators take integers only.
+func isUntypedConstant(node ast.Node) bool {
+	switch t := node.(type) {
+	case ast.IntegerLiteralExpr:
+		return t.IsUntyped
+	case ast.FloatLiteralExpr:
+		return t.IsUntyped
+	case ast.UnaryExpr:
+		switch t.Operator.Kind {
+		case lexer.MINUS_TOKEN:
+			return isUntypedConstant(t.Argument)
+		case lexer.BIT_NOT_TOKEN:
+			return isUntypedConstant(t.Argument) && !hasFloatLiteral(t.Argument)
+		}
+	case ast.BinaryExpr:
+		switch t.Binop.Kind {
+		case lexer.PLUS_TOKEN, lexer.MINUS_TOKEN, lexer.MUL_TOKEN, lexer.DIV_TOKEN, lexer.MOD_TOKEN, lexer.EXP_TOKEN:
+			return isUntypedConstant(t.Left) && isUntypedConstant(t.Right)
+		case lexer.BIT_AND_TOKEN, lexer.BIT_OR_TOKEN, lexer.BIT_XOR_TOKEN, lexer.SHIFT_LEFT_TOKEN, lexer.SHIFT_RIGHT_TOKEN:
+			return isUntypedConstant(t.Left) && isUntypedConstant(t.Right) && !hasFloatLiteral(t)
+		}
+	}
+	return false
+}
+
+// hasFloatLiteral reports whether an untyped constant has a float literal in it.
+func hasFloatLiteral(node ast.Node) bool {
+	switch t := node.(type) {
+	case ast.FloatLiteralExpr:
+		return true
+	case ast.UnaryExpr:
+		return hasFloatLiteral(t.Argument)
+	case ast.BinaryExpr:
+		return hasFloatLiteral(t.Left) || hasFloatLiteral(t.Right)
+	}
+	return false
+}
+
+// untypedDefault returns the type of an untyped constant used where no type is expected, like
+// in 'let a := 1 + 2.5': an f32 when it has a float in it, an i32 otherwise.
+func untypedDefault(node ast.Node) Tc {
+	if hasFloatLiteral(node) {
+		return NewFloat(32)
+	}
+	return NewInt(32, true)
+}
+
+// typeLiterals gives the untyped number literals of a checked tree the types they were
+// checked with, so the backends run every literal with the type of its place. An integer
+// checked as a float becomes a float literal.
+func typeLiterals(tree ast.Node, info *TypeInfo) ast.Node {
+	return ast.Rewrite(tree, func(node ast.Node) ast.Node {
+		switch t := node.(type) {
+		case ast.IntegerLiteralExpr:
+			dtype, ok := info.TypeOf(t)
+			if !ok || !t.IsUntyped {
+				return node
+			}
+			switch d := unwrapType(dtype).(type) {
+			case Int:
+				t.BitSize, t.IsSigned = d.BitSize, d.IsSigned
+			case Float:
+				float := ast.FloatLiteralExpr{Value: t.Value, BitSize: d.BitSize, IsUntyped: true, Location: t.Location}
+				info.recordType(float, dtype)
+				if value, ok := info.constantOf(t); ok {
+					info.recordConstant(float, value, true)
+				}
+				return float
+			}
+			return t
+		case ast.FloatLiteralExpr:
+			dtype, ok := info.TypeOf(t)
+			if !ok || !t.IsUntyped {
+				return node
+			}
+			if d, ok := unwrapType(dtype).(Float); ok {
+				t.BitSize = d.BitSize
+			}
+			return t
+		}
+		return node
+	})
+}
+
+// checkFloatFits reports a float value out of the range of a float type.
+func checkFloatFits(node ast.Node, text string, floatType Float, env *TypeEnvironment) {
+	value, err := strconv.ParseFloat(text, 64)
+	if err == nil && (floatType.BitSize != 32 || math.Abs(value) <= math.MaxFloat32) {
+		return
+	}
+	report.Add(env.filePath, node.StartPos().Line, node.EndPos().Line, node.StartPos().Column, node.EndPos().Column, fmt.Sprintf("float literal '%s' does not fit in '%s'", text, tcToString(floatType))).SetLevel(report.NORMAL_ERROR)
+}
+
+// integerLiteral returns an integer literal and its value, negated by a '-' in front of it.
+func integerLiteral(node ast.Node) (ast.IntegerLiteralExpr, *big.Int, bool) {
+	negated := false
+	if unary, ok := node.(ast.UnaryExpr); ok && unary.Operator.Kind == lexer.MINUS_TOKEN {
+		node = unary.Argument
+		negated = true
+	}
+	literal, ok := node.(ast.IntegerLiteralExpr)
+	if !ok {
+		return literal, nil, false
+	}
+	value, ok := new(big.Int).SetString(literal.Value, 10)
+	if !ok {
+		return literal, nil, false
+	}
+	if negated {
+		value.Neg(value)
+	}
+	return literal, value, true
+}
+
+// checkIntegerFits reports a value out of the range of an integer type.
+func checkIntegerFits(node ast.Node, value *big.Int, intType Int, env *TypeEnvironment) bool {
+	if value == nil {
+		return true
+	}
+	min, max := intRange(intType)
+	if value.Cmp(min) >= 0 && value.Cmp(max) <= 0 {
+		return true
+	}
+	report.Add(env.filePath, node.StartPos().Line, node.EndPos().Line, node.StartPos().Column, node.EndPos().Column, fmt.Sprintf("integer literal '%s' does not fit in '%s'", value, tcToString(intType))).Hint(fmt.Sprintf("the values of '%s' go from %s to %s", tcToString(intType), min, max)).SetLevel(report.NORMAL_ERROR)
+	return false
+}
+
+// intRange returns the smallest and the largest value of an integer type.
+func intRange(intType Int) (*big.Int, *big.Int) {
+	if !intType.IsSigned {
+		max := new(big.Int).Lsh(big.NewInt(1), uint(intType.BitSize))
+		return big.NewInt(0), max.Sub(max, big.NewInt(1))
+	}
+	half := new(big.Int).Lsh(big.NewInt(1), uint(intType.BitSize-1))
+	return new(big.Int).Neg(half), new(big.Int).Sub(half, big.NewInt(1))
+}
@@ -0,0 +1,133 @@
+package typechecker
+
+import (
+	"testing"
+)
+
+func TestNumberLiterals(t *testing.T) {
+	analyze(t, `
+		let a: i32 = 0xff + 0o17 + 0b1010 + 1_000_000;
+		let b: u8 = 255u8;
+		let c: i8 = -128i8;
+		let d: u64 = 0xffff_ffff_ffff_ffffu64;
+		let e: f64 = 1.5e-3f64;
+		let f: f32 = 10f32;
+		let g: i64 = -9223372036854775808i64;
+		let h: f32 = 2E3;
+		let i: i128 = -170141183460469231731687303715884105728;
+		let j: u128 = 340282366920938463463374607431768211455u128;
+		let k: i64 = j as i64;
+		let l: u128 = 5u8 as u128;
+		let m: i64 = 5i64 * 2i64 + 0x10i64;
+		let n: f64 = e + e / 2f64;
+		let o: u8 = 0xf0u8 - 0b1010u8;
+		let p: i128 = 1_000i128 * -2i128;
+	`)
+}
+
+func TestNumberLiteralErrors(t *testing.T) {
+	tests := []errorCase{
+		{"Too large for the default type", `let a := 3000000000;`, "integer literal '3000000000' does not fit in 'i32'", "1:10"},
+		{"Too large for the suffix", `let a := 256u8;`, "integer literal '256' does not fit in 'u8'", "1:10"},
+		{"Too small for the suffix", `let a := -129i8;`, "integer literal '-129' does not fit in 'i8'", "1:10"},
+		{"Negative unsigned", `let a := -1u32;`, "integer literal '-1' does not fit in 'u32'", "1:10"},
+		{"Too large for the declared type", `let a: i8 = 300;`, "integer literal '300' does not fit in 'i8'", "1:13"},
+		{"Negative for the declared type", `let a: u16 = -1;`, "integer literal '-1' does not fit in 'u16'", "1:14"},
+		{"Float too large", `let a := 1e39;`, "float literal '1e39' does not fit in 'f32'", "1:10"},
+		{"Too large for u128", `let a: u128 = 340282366920938463463374607431768211456;`, "integer literal '340282366920938463463374607431768211456' does not fit in 'u128'", "1:15"},
+		{"Too small for i128", `let a := -170141183460469231731687303715884105729i128;`, "integer literal '-170141183460469231731687303715884105729' does not fit in 'i128'", "1:10"},
+		{"Mixed 128 bit types", `let a: i128 = 1u128;`, "error declaring variable 'a'. cannot assign value of type 'u128' to type 'i128'", "1:15"},
+	}
+
+	checkErrors(t, tests)
+}
+
+func TestUntypedNumbers(t *testing.T) {
+	analyze(t, `
+		type Meters i64;
+		let a: i64 = 10;
+		let b: u8 = 255;
+		let c: i8 = -128;
+		let d: f64 = 3.14;
+		let e: f32 = 1;
+		let f: Meters = 5000000000;
+		let g: u64 = 18446744073709551615;
+		let h := 7;
+		let i: i32 = h;
+		fn scale(value: f64, factor: f64) -> f64 {
+			ret value;
+		}
+		fn big() -> i64 {
+			ret 9223372036854775807;
+		}
+		let j: f64 = scale(3.14, 2);
+	`)
+}
+
+func TestUntypedConstants(t *testing.T) {
+	_, info := analyze(t, `type S struct { a: i64 };
+type Pair<T> struct { value: T, count: i64 };
+fn g(v: f64) -> f64 { ret v; }
+fn first<T>(value: T, count: i64) -> T { ret value; }
+let x: i64 = 10 + 1;
+let y: f64 = 1.5 * 2.0;
+let z: u8 = 2*3;
+let w := g(1.0 + 2.0);
+x = 5;
+x += 1;
+let s := @S{a: 1};
+let arr: []i64 = [1, 2];
+let m := $map[str]f64{"a" => 1.5};
+let n: i64? = 3000000000;
+let k := x * 2 + 1;
+let mixed := 1 + 2.5;
+let f := first("a", 5000000000);
+let p := @Pair{value: "a", count: 5000000000};
+let q: i64 = -(2 * 3);
+let bits: u16 = 1 << 15 | 1;
+let half: f64 = 1 / 2;
+fn parse() -> i64!str { ret 5000000000; }
+`)
+
+	// every number literal of a line has the type of its place
+	expected := map[int]string{5: "i64", 6: "f64", 7: "u8", 8: "f64", 9: "i64", 10: "i64", 11: "i64", 12: "i64", 13: "f64", 14: "i64", 15: "i64", 16: "f32", 17: "i64", 18: "i64", 19: "i64", 20: "u16", 21: "f64", 22: "i64"}
+	for _, typed := range info.Types {
+		if typed.Node != "IntegerLiteralExpr" && typed.Node != "FloatLiteralExpr" {
+			continue
+		}
+		line := typed.Location.Start.Line
+		if want, ok := expected[line]; ok && typed.Type != want {
+			t.Errorf("line %d: expected literal of type %s, got %s", line, want, typed.Type)
+		}
+	}
+}
+
+func TestUntypedNumberErrors(t *testing.T) {
+	tests := []errorCase{
+		{"Declared type too small", `let a: i8 = 300;`, "integer literal '300' does not fit in 'i8'", "1:13"},
+		{"Float to an integer", `let a: i64 = 1.5;`, "error declaring variable 'a'. cannot assign value of type 'f32' to type 'i64'", "1:14"},
+		{"Float too large for f32", `let a: f32 = 1e39;`, "float literal '1e39' does not fit in 'f32'", "1:14"},
+		{"Argument too large", `fn f(x: u8) -> u8 { ret x; } f(256);`, "integer literal '256' does not fit in 'u8'", "1:32"},
+		{"Negative argument", `fn f(x: u32) -> u32 { ret x; } f(-1);`, "integer literal '-1' does not fit in 'u32'", "1:34"},
+		{"Return too large", `fn f() -> i16 { ret 40000; }`, "integer literal '40000' does not fit in 'i16'", "1:21"},
+		{"Suffix keeps its type", `let a: i64 = 10i32;`, "error declaring variable 'a'. cannot assign value of type 'i32' to type 'i64'", "1:14"},
+		{"Default type without a place", `let a := 3000000000;`, "integer literal '3000000000' does not fit in 'i32'", "1:10"},
+		{"Constant too large for its place", `const a: u8 = 200 + 100;`, "constant 300 overflows 'u8'", "1:15"},
+		{"Float constant in an integer", `let a: i64 = 2 * 1.5;`, "error declaring variable 'a'. cannot assign value of type 'f32' to type 'i64'", "1:14"},
+		{"Field too large", `type S struct { a: i8 }; let s := @S{a: 128};`, "integer literal '128' does not fit in 'i8'", "1:41"},
+		{"Map value too large", `let m := $map[str]u8{"a" => 256};`, "integer literal '256' does not fit in 'u8'", "1:29"},
+		{"Assignment too large", `let a: u8 = 1; a = 256;`, "integer literal '256' does not fit in 'u8'", "1:20"},
+		{"Float operand of an integer", `let a: i64 = 1; let b: i64 = a + 1.5;`, "error declaring variable 'b'. cannot assign value of type 'f32' to type 'i64'", "1:30"},
+		{"Constant too large for a variable", `let d: u8 = 200 + 100;`, "constant 300 overflows 'u8'", "1:13"},
+		{"Constant too small for a variable", `let e: i8 = 100 + 100;`, "constant 200 overflows 'i8'", "1:13"},
+		{"Constant too large for the default type", `let c := 1 << 40;`, "constant 1 << 40 overflows 'i32'", "1:10"},
+		{"Constant too large for an argument", `fn f(x: u8) -> u8 { ret x; } f(200 + 100);`, "constant 300 overflows 'u8'", "1:32"},
+		{"Constant too large for a return", `fn f() -> i8 { ret 100 + 100; }`, "constant 200 overflows 'i8'", "1:20"},
+		{"Constant too large for a field", `type S struct { a: u8 }; let s := @S{a: 200 + 100};`, "constant 300 overflows 'u8'", "1:41"},
+		{"Constant too large for an element", `let a: []u8 = [1, 200 + 100];`, "constant 300 overflows 'u8'", "1:19"},
+		{"Constant too large for an assignment", `let a: u8 = 1; a = 200 + 100;`, "constant 300 overflows 'u8'", "1:20"},
+		{"Constant division by zero", `let a: i32 = 10 / 0;`, "constant division by zero", "1:19"},
+	}
+
+	checkErrors(t, tests)
+}
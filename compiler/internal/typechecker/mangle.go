@@ -0,0 +1,242 @@
+package typechecker
+
+import (
+	//Standard packages
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/modules"
+)
+
+// topLevelName is a variable, constant, function or type declared at the top level of a module.
+type topLevelName struct {
+	identifier ast.IdentifierExpr
+	isType     bool
+	isExported bool
+}
+
+// mangleNames gives a new name to the private top level names of a module which another
+// module of the program also declares, so the modules can be linked into a single program.
+// A clash between exported names is left to the linker, which reports it.
+func mangleNames(program []modules.Module, infos []*TypeInfo) {
+
+	declaring := make(map[string]int) // name -> number of modules declaring it
+	for _, module := range program {
+		for _, name := range topLevelNames(module.Tree) {
+			declaring[name.identifier.Name]++
+		}
+	}
+
+	for i, module := range program {
+		r := &renamer{
+			info:   infos[i],
+			values: make(map[ast.Location]string),
+			types:  make(map[string]string),
+		}
+
+		taken := make(map[string]bool)
+		for name := range declaring {
+			taken[name] = true
+		}
+		collectNames(reflect.ValueOf(module.Tree), taken)
+
+		suffix := moduleSuffix(module.FilePath)
+		for _, name := range topLevelNames(module.Tree) {
+			if name.isExported || declaring[name.identifier.Name] < 2 {
+				continue
+			}
+			mangled := name.identifier.Name + "_" + suffix
+			for taken[mangled] {
+				mangled += "_"
+			}
+			taken[mangled] = true
+
+			if name.isType {
+				r.types[name.identifier.Name] = mangled
+			} else {
+				r.values[name.identifier.Location] = mangled
+			}
+		}
+
+		infos[i].renamed = r.types
+		if len(r.values) > 0 || len(r.types) > 0 {
+			program[i].Tree = r.rename(reflect.ValueOf(module.Tree)).Interface().(ast.Node)
+		}
+	}
+}
+
+// topLevelNames returns the names declared at the top level of a module.
+func topLevelNames(tree ast.Node) []topLevelName {
+
+	program, ok := tree.(ast.ProgramStmt)
+	if !ok {
+		return nil
+	}
+
+	var names []topLevelName
+	for _, node := range program.Contents {
+		switch t := node.(type) {
+		case ast.VarDeclStmt:
+			for _, variable := range t.Variables {
+				if variable.Destructure == nil {
+					names = append(names, topLevelName{identifier: variable.Identifier, isExported: t.IsExported})
+					continue
+				}
+				for _, name := range variable.Destructure.Names {
+					if name.Name != "_" {
+						names = append(names, topLevelName{identifier: name, isExported: t.IsExported})
+					}
+				}
+			}
+		case ast.FunctionDeclStmt:
+			names = append(names, topLevelName{identifier: t.Identifier, isExported: t.IsExported})
+		case ast.TypeDeclStmt:
+			names = append(names, topLevelName{identifier: t.UDTypeName, isType: true, isExported: t.IsExported})
+		}
+	}
+	return names
+}
+
+// moduleSuffix returns the name of the file of a module as it is added to its mangled names.
+func moduleSuffix(filePath string) string {
+	name := strings.TrimSuffix(filepath.Base(filePath), ".wal")
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+var (
+	identifierType  = reflect.TypeOf(ast.IdentifierExpr{})
+	userDefinedType = reflect.TypeOf(ast.UserDefinedType{})
+)
+
+// collectNames adds the name of every identifier of a tree to names.
+func collectNames(value reflect.Value, names map[string]bool) {
+	switch value.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if !value.IsNil() {
+			collectNames(value.Elem(), names)
+		}
+	case reflect.Struct:
+		if value.Type() == identifierType {
+			names[value.Interface().(ast.IdentifierExpr).Name] = true
+			return
+		}
+		for i := 0; i < value.NumField(); i++ {
+			collectNames(value.Field(i), names)
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			collectNames(value.Index(i), names)
+		}
+	}
+}
+
+// renamer renames the mangled names of a module. Values are found by the declaration their
+// identifiers refer to, so a local name shadowing a mangled one keeps its name. Types cannot
+// be shadowed, every use of a mangled type name is renamed.
+type renamer struct {
+	info   *TypeInfo
+	values map[ast.Location]string // declaration of a value -> its new name
+	types  map[string]string       // type name -> its new name
+}
+
+// typeNames are the fields holding the name of a type rather than a value.
+var typeNames = map[reflect.Type]string{
+	reflect.TypeOf(ast.TypeDeclStmt{}):  "UDTypeName",
+	reflect.TypeOf(ast.ImplStmt{}):      "ImplFor",
+	reflect.TypeOf(ast.StructLiteral{}): "Identifier",
+	reflect.TypeOf(ast.MapType{}):       "Map",
+}
+
+// rename returns a copy of a value with the mangled names renamed.
+func (r *renamer) rename(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		// a type is used as a value in 'Shape.Circle' and in type patterns
+		if ident, ok := value.Interface().(ast.IdentifierExpr); ok {
+			if name, ok := r.types[ident.Name]; ok {
+				ident.Name = name
+				return reflect.ValueOf(ident)
+			}
+		}
+		return r.rename(value.Elem())
+	case reflect.Pointer:
+		if value.IsNil() {
+			return value
+		}
+		renamed := reflect.New(value.Type().Elem())
+		renamed.Elem().Set(r.rename(value.Elem()))
+		return renamed
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		renamed := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			setRenamed(renamed.Index(i), r.rename(value.Index(i)))
+		}
+		return renamed
+	case reflect.Struct:
+		return r.renameStruct(value)
+	}
+	return value
+}
+
+func (r *renamer) renameStruct(value reflect.Value) reflect.Value {
+
+	renamed := reflect.New(value.Type()).Elem()
+	renamed.Set(value)
+
+	switch value.Type() {
+	case identifierType:
+		ident := value.Interface().(ast.IdentifierExpr)
+		declaration, ok := r.info.DeclarationOf(ident)
+		if !ok {
+			// a declaration is not a reference to itself
+			declaration = ident.Location
+		}
+		if name, ok := r.values[declaration]; ok {
+			ident.Name = name
+		}
+		return reflect.ValueOf(ident)
+	case userDefinedType:
+		if name, ok := r.types[value.Interface().(ast.UserDefinedType).AliasName]; ok {
+			renamed.FieldByName("AliasName").SetString(name)
+		}
+	}
+
+	typeName := typeNames[value.Type()]
+	for i := 0; i < renamed.NumField(); i++ {
+		field := renamed.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		if value.Type().Field(i).Name == typeName {
+			ident := field.Interface().(ast.IdentifierExpr)
+			if name, ok := r.types[ident.Name]; ok {
+				ident.Name = name
+				field.Set(reflect.ValueOf(ident))
+			}
+			continue
+		}
+		setRenamed(field, r.rename(field))
+	}
+	return renamed
+}
+
+// setRenamed stores a renamed value in the field, element or pointer it was taken from.
+func setRenamed(to, value reflect.Value) {
+	if value.IsValid() {
+		to.Set(value)
+	}
+}
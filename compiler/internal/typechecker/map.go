@@ -13,8 +13,8 @@ func checkMapLiteral(node ast.MapLiteral, env *TypeEnvironment) Tc {
 
 	//check the key value pairs
 	for _, value := range node.Values {
-		keyType := parseNodeValue(value.Key, env)
-		valueType := parseNodeValue(value.Value, env)
+		keyType := checkValueAs(value.Key, evaluatedMapType.(Map).KeyType, env)
+		valueType := checkValueAs(value.Value, evaluatedMapType.(Map).ValueType, env)
 
 		err := validateTypeCompatibility(evaluatedMapType.(Map).KeyType, keyType)
 		if err != nil {
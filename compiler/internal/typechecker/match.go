@@ -0,0 +1,174 @@
+package typechecker
+
+import (
+	//Standard packages
+	"fmt"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/report"
+)
+
+// checkMatchStmt checks the patterns and the arms of a match. Every arm has its own scope.
+// A match must be exhaustive: it needs a '_' arm, unless it covers both 'true' and 'false',
+// both 'ok' and 'err' of a result, or every variant of an enum. Like an if statement with
+// an else branch, it satisfies a return when every arm returns.
+func checkMatchStmt(node ast.MatchStmt, env *TypeEnvironment) Block {
+
+	value := parseNodeValue(node.Value, env)
+
+	block := Block{
+		IsSatisfied:     true,
+		ProblemLocation: node.Location,
+	}
+
+	exhaustive := false
+	seen := make(map[string]bool)
+
+	for _, arm := range node.Arms {
+		if exhaustive {
+			report.Add(env.filePath, arm.Start.Line, arm.End.Line, arm.Start.Column, arm.End.Column, "unreachable match arm").Hint("the '_' arm before matches every value").SetLevel(report.NORMAL_ERROR)
+		}
+
+		armEnv := NewTypeENV(env, CONDITIONAL_SCOPE, "match arm", env.filePath)
+
+		for _, pattern := range arm.Patterns {
+			// names are bound only when the pattern is alone in its arm
+			if len(arm.Patterns) > 1 && bindsNames(pattern) {
+				report.Add(env.filePath, pattern.StartPos().Line, pattern.EndPos().Line, pattern.StartPos().Column, pattern.EndPos().Column, "a pattern binding names must be alone in its arm").SetLevel(report.NORMAL_ERROR)
+			}
+			key := checkMatchPattern(pattern, value, armEnv)
+			if key == "_" {
+				exhaustive = true
+				continue
+			}
+			if key == "" {
+				continue
+			}
+			if seen[key] {
+				report.Add(env.filePath, pattern.StartPos().Line, pattern.EndPos().Line, pattern.StartPos().Column, pattern.EndPos().Column, fmt.Sprintf("duplicate pattern '%s'", key)).SetLevel(report.NORMAL_ERROR)
+			}
+			seen[key] = true
+		}
+
+		armValue := checkBlock(arm.Block, armEnv)
+		if !armValue.IsSatisfied && block.IsSatisfied {
+			block.IsSatisfied = false
+			block.ProblemLocation = armValue.ProblemLocation
+		}
+	}
+
+	if _, ok := unwrapType(value).(Bool); ok && seen["true"] && seen["false"] {
+		exhaustive = true
+	}
+	if _, ok := unwrapType(value).(Result); ok && seen["ok(...)"] && seen["err(...)"] {
+		exhaustive = true
+	}
+
+	hint := "add a '_' arm to match the other values"
+	if enum, ok := unwrapType(value).(Enum); ok && !exhaustive {
+		missing := missingVariants(enum, seen)
+		exhaustive = len(missing) == 0
+		hint = fmt.Sprintf("add arms for %s, or a '_' arm", strings.Join(missing, ", "))
+	}
+
+	if !exhaustive {
+		// the missing arms are the problem, a match whose arms return is not also missing a return
+		report.Add(env.filePath, node.Start.Line, node.Value.EndPos().Line, node.Start.Column, node.Value.EndPos().Column, fmt.Sprintf("match on '%s' is not exhaustive", tcToString(value))).Hint(hint).SetLevel(report.NORMAL_ERROR)
+	}
+
+	return block
+}
+
+// checkMatchPattern checks a pattern against the type of the matched value. It returns '_'
+// for the default pattern, a key identifying constant, variant and result patterns so
+// duplicates can be found, and an empty string for patterns that cannot be compared. The
+// names bound by variant and result patterns are declared in env, the scope of the arm.
+func checkMatchPattern(pattern ast.Node, value Tc, env *TypeEnvironment) string {
+	switch t := pattern.(type) {
+	case ast.IdentifierExpr:
+		if t.Name == "_" {
+			return "_"
+		}
+		if isTypeDefined(t.Name) && t.Name != "null" && t.Name != "void" {
+			return checkTypePattern(t, value, env)
+		}
+	case ast.RangeExpr:
+		return checkRangePattern(t, value, env)
+	case ast.StructPropertyAccessExpr:
+		if _, ok := enumOf(t.Object); ok {
+			return checkVariantPattern(t, nil, false, value, env)
+		}
+	case ast.ResultExpr:
+		return checkResultPattern(t, value, env)
+	case ast.FunctionCallExpr:
+		if access, ok := t.Caller.(ast.StructPropertyAccessExpr); ok {
+			if _, ok := enumOf(access.Object); ok {
+				return checkVariantPattern(access, t.Arguments, true, value, env)
+			}
+		}
+	}
+
+	patternType := checkValueAs(pattern, value, env)
+	reportConstantError(pattern, env)
+	if err := validateTypeCompatibility(value, patternType); err != nil {
+		report.Add(env.filePath, pattern.StartPos().Line, pattern.EndPos().Line, pattern.StartPos().Column, pattern.EndPos().Column, fmt.Sprintf("pattern of type '%s' cannot match a value of type '%s'", tcToString(patternType), tcToString(value))).SetLevel(report.NORMAL_ERROR)
+	}
+
+	return patternKey(pattern, env)
+}
+
+// checkTypePattern checks a pattern naming a struct type. It matches the values of an
+// interface holding that struct.
+func checkTypePattern(pattern ast.IdentifierExpr, value Tc, env *TypeEnvironment) string {
+
+	if _, ok := unwrapType(value).(Interface); !ok {
+		report.Add(env.filePath, pattern.Start.Line, pattern.End.Line, pattern.Start.Column, pattern.End.Column, fmt.Sprintf("type pattern '%s' cannot match a value of type '%s'", pattern.Name, tcToString(value))).Hint("type patterns match the values of an interface").SetLevel(report.NORMAL_ERROR)
+		return ""
+	}
+
+	patternType, _ := getTypeDefinition(pattern.Name)
+	if _, ok := patternType.(Struct); !ok {
+		report.Add(env.filePath, pattern.Start.Line, pattern.End.Line, pattern.Start.Column, pattern.End.Column, fmt.Sprintf("type pattern '%s' must be a struct type", pattern.Name)).SetLevel(report.NORMAL_ERROR)
+		return ""
+	}
+
+	if err := validateTypeCompatibility(value, patternType); err != nil {
+		report.Add(env.filePath, pattern.Start.Line, pattern.End.Line, pattern.Start.Column, pattern.End.Column, fmt.Sprintf("type pattern '%s' can never match a value of type '%s'", pattern.Name, tcToString(value))).Hint(err.Error()).SetLevel(report.NORMAL_ERROR)
+	}
+
+	env.info.recordType(pattern, patternType)
+
+	return "type " + pattern.Name
+}
+
+// checkRangePattern checks a range pattern. It matches the numbers from its start up to
+// its end, which is left out.
+func checkRangePattern(pattern ast.RangeExpr, value Tc, env *TypeEnvironment) string {
+
+	rangeType := parseNodeValue(pattern, env)
+
+	if !isNumberType(unwrapType(value)) {
+		report.Add(env.filePath, pattern.StartPos().Line, pattern.EndPos().Line, pattern.StartPos().Column, pattern.EndPos().Column, fmt.Sprintf("range pattern cannot match a value of type '%s'", tcToString(value))).Hint("range patterns match numbers").SetLevel(report.NORMAL_ERROR)
+		return ""
+	}
+
+	if r, ok := rangeType.(Range); ok {
+		if err := validateTypeCompatibility(value, r.RangeStart); err != nil {
+			report.Add(env.filePath, pattern.StartPos().Line, pattern.EndPos().Line, pattern.StartPos().Column, pattern.EndPos().Column, fmt.Sprintf("pattern of type '%s' cannot match a value of type '%s'", tcToString(r), tcToString(value))).SetLevel(report.NORMAL_ERROR)
+		}
+	}
+
+	return ""
+}
+
+// patternKey identifies constant patterns, like literals, 'true', 'false', constants and
+// expressions folded from them, by their value. Other patterns have no key.
+func patternKey(pattern ast.Node, env *TypeEnvironment) string {
+	value, ok := env.info.constantOf(pattern)
+	if !ok {
+		return ""
+	}
+	return constantKey(value)
+}
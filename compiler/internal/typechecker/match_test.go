@@ -0,0 +1,67 @@
+package typechecker
+
+import (
+	"testing"
+)
+
+const shapes = `
+	type Shape interface { fn area() -> i32; };
+	type Square struct { side: i32 };
+	impl Square { fn area() -> i32 { ret this.side * this.side; } }
+	type Point struct { x: i32 };
+`
+
+func TestMatchSatisfiesReturn(t *testing.T) {
+	analyze(t, shapes+`
+		fn grade(n: i32) -> str {
+			match n {
+				100 => { ret "perfect"; }
+				90..100, 80 => { ret "good"; }
+				_ => { ret "other"; }
+			}
+		}
+		fn flag(b: bool) -> i32 {
+			match b {
+				true => { ret 1; },
+				false => { ret 0; },
+			}
+		}
+		fn name(s: Shape) -> str {
+			match s {
+				Square => { ret "square"; }
+				_ => { ret "shape"; }
+			}
+		}
+	`)
+}
+
+func TestMatchErrors(t *testing.T) {
+	tests := []errorCase{
+		{"Not exhaustive", `let x := 1; match x { 1 => { } }`, "match on 'i32' is not exhaustive", "1:13"},
+		{"One boolean", `let b := true; match b { true => { } }`, "match on 'bool' is not exhaustive", "1:16"},
+		{"Arm without return", `fn f(x: i32) -> i32 { match x { 1 => { ret 1; } _ => { } } }`, "missing return in this block", "1:54"},
+		{"Arm after default", `let x := 1; match x { _ => { } 1 => { } }`, "unreachable match arm", "1:32"},
+		{"Duplicate pattern", `let x := 1; match x { 1, 1 => { } _ => { } }`, "duplicate pattern '1'", "1:26"},
+		{"Pattern type", `let x := 1; match x { "a" => { } _ => { } }`, "pattern of type 'str' cannot match a value of type 'i32'", "1:23"},
+		{"Range of strings", `let s := "a"; match s { 1..2 => { } _ => { } }`, "range pattern cannot match a value of type 'str'", "1:25"},
+		{"Type pattern needs an interface", shapes + `let x := 1; match x { Square => { } _ => { } }`, "type pattern 'Square' cannot match a value of type 'i32'", "6:23"},
+		{"Type pattern must implement", shapes + `let s: Shape = @Square{side: 1}; match s { Point => { } _ => { } }`, "type pattern 'Point' can never match a value of type 'Shape'", "6:44"},
+		{"Arms are scoped", `let x := 1; match x { _ => { let y := 2; } } let z := y;`, "'y' was not declared in this scope", "1:55"},
+	}
+
+	checkErrors(t, tests)
+}
+
+func TestNotExhaustiveMatchReturns(t *testing.T) {
+	reports, _ := checkModules(t, map[string]string{"main.wal": `fn f(x: i32) -> i32 { match x { 1 => { ret 1; } } }`})
+
+	var errors []string
+	for _, r := range reports {
+		if r.IsError() {
+			errors = append(errors, r.Message)
+		}
+	}
+	if len(errors) != 1 || errors[0] != "match on 'i32' is not exhaustive" {
+		t.Errorf("Expected only the match to be reported, got %q", errors)
+	}
+}
@@ -0,0 +1,168 @@
+package typechecker
+
+import (
+	//Standard packages
+	"fmt"
+	"sort"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/modules"
+	"walrus/compiler/report"
+)
+
+// exportedValue is a variable, constant or function a file exports.
+type exportedValue struct {
+	value    Tc
+	isConst  bool
+	constant any // the folded value of a constant, nil when it is not known while checking
+}
+
+var (
+	moduleImports = make(map[string]map[string]string)        // file -> import path -> imported file
+	exports       = make(map[string]map[string]exportedValue) // file -> exported values
+	exportedTypes = make(map[string]map[string]bool)          // file -> exported type names
+	moduleTypes   = make(map[string]map[string]Tc)            // file -> types it declares
+	typeModules   = make(map[string]string)                   // type name -> file declaring it, for the types visible in the file being checked
+)
+
+func clearModules() {
+	moduleImports = make(map[string]map[string]string)
+	exports = make(map[string]map[string]exportedValue)
+	exportedTypes = make(map[string]map[string]bool)
+	moduleTypes = make(map[string]map[string]Tc)
+	typeModules = make(map[string]string)
+}
+
+// AnalyzeProgram checks the modules of a program, which must be in dependency order. Every
+// file has its own global scope and its own types, where the names and the types exported by
+// the files it imports are declared.
+// The untyped literals of a checked module are given their types in its tree, see typeLiterals,
+// and the private names clashing with the names of another module are renamed, see mangleNames.
+// It returns the type info of every module, in the same order.
+func AnalyzeProgram(program []modules.Module) []*TypeInfo {
+
+	defer ClearTypes()
+
+	infos := make([]*TypeInfo, 0, len(program))
+
+	for i, module := range program {
+		// the builtin values and types are declared again for every file
+		builtinValues = make(map[string]bool)
+		typeDefinitions = builtinTypes()
+		typeModules = make(map[string]string)
+		env := ProgramEnv(module.FilePath)
+		moduleImports[module.FilePath] = module.Imports
+
+		checkAST(module.Tree, env)
+		program[i].Tree = addDefaultArguments(typeLiterals(module.Tree, env.info), env.info)
+
+		infos = append(infos, env.info)
+	}
+
+	mangleNames(program, infos)
+
+	return infos
+}
+
+func checkImportStmt(node ast.ImportStmt, env *TypeEnvironment) Tc {
+
+	if env.scopeType != GLOBAL_SCOPE {
+		report.Add(env.filePath, node.Start.Line, node.End.Line, node.Start.Column, node.End.Column, "import statement must be at global scope").SetLevel(report.CRITICAL_ERROR)
+		return NewVoid()
+	}
+
+	file, ok := moduleImports[env.filePath][node.Path]
+	if !ok {
+		report.Add(env.filePath, node.Start.Line, node.End.Line, node.Start.Column, node.End.Column, fmt.Sprintf("cannot resolve module '%s'", node.Path)).SetLevel(report.NORMAL_ERROR)
+		return NewVoid()
+	}
+
+	//declare the exported values in a stable order
+	names := make([]string, 0, len(exports[file]))
+	for name := range exports[file] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		exported := exports[file][name]
+		if err := env.declareVar(name, exported.value, exported.isConst, false); err != nil {
+			report.Add(env.filePath, node.Start.Line, node.End.Line, node.Start.Column, node.End.Column, fmt.Sprintf("cannot import '%s' from '%s': %s", name, node.Path, err.Error())).SetLevel(report.NORMAL_ERROR)
+		}
+		if exported.constant != nil {
+			env.values[name] = exported.constant
+		}
+	}
+
+	types := make([]string, 0, len(exportedTypes[file]))
+	for name := range exportedTypes[file] {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+
+	for _, name := range types {
+		if typeModules[name] == file {
+			continue
+		}
+		if err := declareType(name, moduleTypes[file][name]); err != nil {
+			report.Add(env.filePath, node.Start.Line, node.End.Line, node.Start.Column, node.End.Column, fmt.Sprintf("cannot import type '%s' from '%s': %s", name, node.Path, err.Error())).SetLevel(report.NORMAL_ERROR)
+			continue
+		}
+		typeModules[name] = file
+	}
+
+	return NewVoid()
+}
+
+// canExport reports an exported declaration which is not at global scope.
+func canExport(node ast.Node, env *TypeEnvironment) bool {
+	if env.scopeType == GLOBAL_SCOPE {
+		return true
+	}
+	report.Add(env.filePath, node.StartPos().Line, node.EndPos().Line, node.StartPos().Column, node.EndPos().Column, "only global declarations can be exported").SetLevel(report.NORMAL_ERROR)
+	return false
+}
+
+// exportValue makes a variable, constant or function of the global scope visible to the
+// files importing this one.
+func exportValue(name string, env *TypeEnvironment) {
+	if exports[env.filePath] == nil {
+		exports[env.filePath] = make(map[string]exportedValue)
+	}
+	exports[env.filePath][name] = exportedValue{
+		value:    env.variables[name],
+		isConst:  env.constants[name],
+		constant: env.values[name],
+	}
+}
+
+// exportType makes a type declared in this file visible to the files importing it.
+func exportType(name string, env *TypeEnvironment) {
+	if exportedTypes[env.filePath] == nil {
+		exportedTypes[env.filePath] = make(map[string]bool)
+	}
+	exportedTypes[env.filePath][name] = true
+}
+
+// unknownType returns the error of a type which is not visible in the file being checked. It
+// tells which file declares the type when another file of the program does.
+func unknownType(name string) error {
+	files := make([]string, 0, len(moduleTypes))
+	for file := range moduleTypes {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		if _, ok := moduleTypes[file][name]; !ok {
+			continue
+		}
+		if exportedTypes[file][name] {
+			return fmt.Errorf("type '%s' is declared in '%s' which is not imported", name, file)
+		}
+		return fmt.Errorf("type '%s' is not exported by '%s'", name, file)
+	}
+
+	return fmt.Errorf("unknown type '%s'", name)
+}
@@ -0,0 +1,93 @@
+package typechecker
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestImportExportedNames(t *testing.T) {
+	reports, _ := checkModules(t, map[string]string{
+		"shapes.wal": `export type Point struct { x: i32 }; export const ONE := 1; export fn make(x: i32) -> Point { ret @Point{x: x}; } let hidden := 2;`,
+		"main.wal":   `import "shapes"; let p : Point = make(ONE); let q := @Point{x: p.x};`,
+	})
+
+	if reports.HasErrors() {
+		t.Fatalf("Expected the program to type check, got %v", reports[0].Message)
+	}
+}
+
+func TestPrivateNamesOfModules(t *testing.T) {
+	reports, _ := checkModules(t, map[string]string{
+		"shapes.wal": `type Point struct { x: i32 }; fn helper() {} export fn make() -> i32 { helper(); let p := @Point{x: 1}; ret p.x; }`,
+		"main.wal":   `import "shapes"; type Point struct { y: str }; fn helper() {} helper(); let p := @Point{y: "a"}; let n := make();`,
+	})
+
+	if reports.HasErrors() {
+		t.Fatalf("Expected the program to type check, got %v", reports[0].Message)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected string
+		at       string
+	}{
+		{
+			name:     "Unexported value",
+			files:    map[string]string{"a.wal": `let hidden := 2;`, "main.wal": `import "a"; let x := hidden;`},
+			expected: "'hidden' was not declared in this scope",
+			at:       "1:22",
+		},
+		{
+			name:     "Unexported type",
+			files:    map[string]string{"a.wal": `type Hidden struct { x: i32 }; export let h := @Hidden{x: 1};`, "main.wal": `import "a"; let x : Hidden = h;`},
+			expected: "type 'Hidden' is not exported by '",
+			at:       "1:21",
+		},
+		{
+			name:     "Type of a file not imported",
+			files:    map[string]string{"a.wal": `export type Point struct { x: i32 }; export let p := @Point{x: 1};`, "b.wal": `import "a"; export let q := p;`, "main.wal": `import "b"; let x : Point = q;`},
+			expected: "type 'Point' is declared in '",
+			at:       "1:21",
+		},
+		{
+			name:     "Constant stays constant",
+			files:    map[string]string{"a.wal": `export const ONE := 1;`, "main.wal": `import "a"; ONE = 2;`},
+			expected: "cannot assign to constant",
+			at:       "1:13",
+		},
+		{
+			name:     "Export outside global scope",
+			files:    map[string]string{"main.wal": `fn f() { export let x := 1; }`},
+			expected: "only global declarations can be exported",
+			at:       "1:10",
+		},
+		{
+			name:     "Import outside global scope",
+			files:    map[string]string{"main.wal": `fn f() { import "a"; }`},
+			expected: "import statement must be at global scope",
+			at:       "1:10",
+		},
+		{
+			name:     "Implementing an imported type",
+			files:    map[string]string{"a.wal": `export type Point struct { x: i32 };`, "main.wal": `import "a"; impl Point { fn get() -> i32 { ret this.x; } }`},
+			expected: "cannot implement type 'Point' declared in '",
+			at:       "1:18",
+		},
+		{
+			name:     "Imported constant pattern",
+			files:    map[string]string{"a.wal": `export const LIMIT := 5 * 2;`, "main.wal": `import "a"; let x := 3; match x { LIMIT => { } 10 => { } _ => { } }`},
+			expected: "duplicate pattern '10'",
+			at:       "1:48",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reports, dir := checkModules(t, tt.files)
+			expectError(t, reports, filepath.Join(dir, "main.wal"), tt.expected, tt.at)
+		})
+	}
+}
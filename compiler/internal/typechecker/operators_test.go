@@ -0,0 +1,78 @@
+package typechecker
+
+import (
+	"testing"
+)
+
+func TestOperators(t *testing.T) {
+	analyze(t, `
+		let a := 6;
+		let b := 3;
+		let masked := a & b | a ^ b;
+		let shifted := (1 as u8) << a >> 2;
+		let flipped := ~a;
+		let both := a > 1 && b > 1 || !(a == b);
+		let even := a & 1 == 0;
+		let c := 5;
+		c <<= 2;
+		c |= 1;
+		c ^= a;
+		c &= 14;
+		c >>= b;
+	`)
+}
+
+func TestArithmetic(t *testing.T) {
+	analyze(t, `
+		let a: i64 = 5i64 * 2i64 - 1i64;
+		let b: u8 = 200u8 / 3u8 % 7u8;
+		let e: f64 = 2.5f64;
+		let c: f64 = e + e * e;
+		let d: i128 = 170141183460469231731687303715884105727i128 - 1i128;
+		let f: u128 = 2u128 ** 100u128;
+		let g: str = "a" + "b";
+		let h: i32 = 23;
+		let i: f32 = h + 2.4;
+		let j: f64 = a / e;
+		let k: f32 = 7 / 2.0;
+	`)
+}
+
+func TestOperatorErrors(t *testing.T) {
+	tests := []errorCase{
+		{"Logical needs booleans", `let x := 1 && true;`, "operator '&&' needs boolean operands, got 'i32'", "1:10"},
+		{"Logical needs booleans on the right", `let x := false || "a";`, "operator '||' needs boolean operands, got 'str'", "1:19"},
+		{"Bitwise needs integers", `let x := 1.5 & 1.0;`, "operator '&' needs integer operands, got 'f32'", "1:10"},
+		{"Bitwise on booleans", `let x := true | false;`, "operator '|' needs integer operands, got 'bool'", "1:10"},
+		{"Shift needs integers", `let x := "a" << 1;`, "operator '<<' needs integer operands, got 'str'", "1:10"},
+		{"Bitwise needs one type", `let a := 1 as u8; let b := 1; let x := a ^ b;`, "mismatched types 'u8' and 'i32' for operator '^'", "1:40"},
+		{"Compound bitwise needs one type", `let a := 1 as u8; let b := 1; a |= b;`, "mismatched types 'u8' and 'i32' for operator '|'", "1:31"},
+		{"Arithmetic needs one type", `let a := 5i64 + 1u8;`, "mismatched types 'i64' and 'u8' for operator '+'", "1:10"},
+		{"Arithmetic of two float types", `let a := 1.5f64 * 2f32;`, "mismatched types 'f64' and 'f32' for operator '*'", "1:10"},
+		{"Arithmetic needs numbers", `let a := 1i64 - "a";`, "cannot perform numeric operation between type 'i64' and 'str'", "1:17"},
+		{"Complement of a float", `let x := ~1.5;`, "invalid unary operation with numeric types", "1:10"},
+	}
+
+	checkErrors(t, tests)
+}
+
+func TestInterpolatedStrings(t *testing.T) {
+	analyze(t, `
+		type Point struct { x: i32, y: i32 };
+		let name := "walrus";
+		let p := @Point{x: 1, y: 2};
+		let s: str = "hello {name}, {p.x + p.y} {p} {1.5 > 1.0}";
+		let raw: str = `+"`"+`no {name} here\n`+"`"+`;
+		let nested := "a {"b {name}"} c";
+	`)
+}
+
+func TestInterpolatedStringErrors(t *testing.T) {
+	tests := []errorCase{
+		{"Void value", `fn f() {} let s := "a {f()}";`, "cannot interpolate a void value", "1:24"},
+		{"Undeclared value", `let s := "a {b}";`, "'b' was not declared in this scope", "1:14"},
+		{"Not a string", `let s: i32 = "{1}";`, "error declaring variable 's'. cannot assign value of type 'str' to type 'i32'", "1:14"},
+	}
+
+	checkErrors(t, tests)
+}
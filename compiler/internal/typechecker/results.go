@@ -0,0 +1,110 @@
+package typechecker
+
+import (
+	//Standard packages
+	"fmt"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/report"
+)
+
+// checkResultExpr checks 'ok(value)' and 'err(value)'. A value is already a result of its
+// type, so 'ok' only makes it explicit. An error fits the results with its error type.
+func checkResultExpr(node ast.ResultExpr, env *TypeEnvironment) Tc {
+
+	value := parseNodeValue(node.Value, env)
+
+	if _, ok := value.(Void); ok {
+		report.Add(env.filePath, node.Value.StartPos().Line, node.Value.EndPos().Line, node.Value.StartPos().Column, node.Value.EndPos().Column, "cannot make a result of a void value").SetLevel(report.NORMAL_ERROR)
+	}
+
+	if !node.IsErr {
+		return value
+	}
+	return NewErr(value)
+}
+
+// checkPropagateExpr checks 'value?'. The value must be a result, and the function around it
+// must return a result with the same error type, which the error is returned as. It gives the
+// type of the values of the result.
+func checkPropagateExpr(node ast.PropagateExpr, env *TypeEnvironment) Tc {
+
+	value := parseNodeValue(node.Value, env)
+
+	result, ok := unwrapType(value).(Result)
+	if !ok {
+		report.Add(env.filePath, node.Value.StartPos().Line, node.Value.EndPos().Line, node.Value.StartPos().Column, node.Value.EndPos().Column, fmt.Sprintf("'?' needs a result, got '%s'", tcToString(value))).SetLevel(report.NORMAL_ERROR)
+		return value
+	}
+
+	if !env.isInFunctionScope() {
+		report.Add(env.filePath, node.Start.Line, node.End.Line, node.Start.Column, node.End.Column, "'?' can only be used inside a function").Hint("handle the error with a match on 'ok(...)' and 'err(...)'").SetLevel(report.NORMAL_ERROR)
+		return result.OkType
+	}
+
+	fnReturns := getFunctionReturnValue(env, node)
+	fnResult, ok := unwrapType(fnReturns).(Result)
+	if !ok {
+		report.Add(env.filePath, node.Start.Line, node.End.Line, node.Start.Column, node.End.Column, fmt.Sprintf("'?' can only be used in a function returning a result, got '%s'", tcToString(fnReturns))).Hint(fmt.Sprintf("return a result, like '-> %s!%s'", tcToString(fnReturns), tcToString(result.ErrType))).SetLevel(report.NORMAL_ERROR)
+		return result.OkType
+	}
+
+	if err := validateTypeCompatibility(fnResult.ErrType, result.ErrType); err != nil {
+		report.Add(env.filePath, node.Start.Line, node.End.Line, node.Start.Column, node.End.Column, fmt.Sprintf("cannot return an error of type '%s' from a function returning '%s'", tcToString(result.ErrType), tcToString(fnReturns))).SetLevel(report.NORMAL_ERROR)
+	}
+
+	return result.OkType
+}
+
+// checkResultPattern checks 'ok(name)' and 'err(name)' patterns. The value or the error of
+// the result is bound to the name in the scope of the arm. It returns the key of the pattern.
+func checkResultPattern(pattern ast.ResultExpr, value Tc, armEnv *TypeEnvironment) string {
+
+	key := "ok(...)"
+	if pattern.IsErr {
+		key = "err(...)"
+	}
+
+	result, ok := unwrapType(value).(Result)
+	if !ok {
+		report.Add(armEnv.filePath, pattern.Start.Line, pattern.End.Line, pattern.Start.Column, pattern.End.Column, fmt.Sprintf("pattern '%s' cannot match a value of type '%s'", key, tcToString(value))).Hint("'ok' and 'err' patterns match results").SetLevel(report.NORMAL_ERROR)
+		return ""
+	}
+
+	ident, ok := pattern.Value.(ast.IdentifierExpr)
+	if !ok {
+		report.Add(armEnv.filePath, pattern.Value.StartPos().Line, pattern.Value.EndPos().Line, pattern.Value.StartPos().Column, pattern.Value.EndPos().Column, fmt.Sprintf("the value of pattern '%s' is bound to a name", key)).Hint("match the value inside the arm").SetLevel(report.NORMAL_ERROR)
+		return key
+	}
+
+	if ident.Name == "_" {
+		return key
+	}
+
+	bound := result.OkType
+	if pattern.IsErr {
+		bound = result.ErrType
+	}
+	if err := armEnv.declareVar(ident.Name, bound, false, false); err != nil {
+		report.Add(armEnv.filePath, ident.Start.Line, ident.End.Line, ident.Start.Column, ident.End.Column, err.Error()).SetLevel(report.NORMAL_ERROR)
+		return key
+	}
+	armEnv.declaredAt(ident.Name, ident.Location)
+
+	return key
+}
+
+// checkIgnoredResult warns when a statement calls a function returning a result and drops it,
+// which drops its error too.
+func checkIgnoredResult(node ast.Node, value Tc, env *TypeEnvironment) {
+
+	if _, ok := node.(ast.FunctionCallExpr); !ok {
+		return
+	}
+	if _, ok := unwrapType(value).(Result); !ok {
+		return
+	}
+
+	report.Add(env.filePath, node.StartPos().Line, node.EndPos().Line, node.StartPos().Column, node.EndPos().Column, fmt.Sprintf("result of type '%s' is ignored", tcToString(value))).Hint("pass its error up with '?', or handle it with a match on 'ok(...)' and 'err(...)'").SetLevel(report.WARNING)
+}
@@ -0,0 +1,74 @@
+package typechecker
+
+import (
+	"strings"
+	"testing"
+)
+
+const parsers = `
+	fn parse(s: str) -> i32!str {
+		if s == "" {
+			ret err("empty");
+		}
+		ret 1;
+	}
+`
+
+func TestResults(t *testing.T) {
+	analyze(t, parsers+`
+		fn double(s: str) -> i32!str {
+			let n := parse(s)?;
+			ret ok(n * 2);
+		}
+		fn check(s: str) -> void!str {
+			parse(s)?;
+			ret;
+		}
+		fn describe(s: str) -> str {
+			match double(s) {
+				ok(n) => { ret "number " + n; }
+				err(e) => { ret "error " + e; }
+			}
+		}
+		let r: i32!str = double("2");
+		let e: i32!str = err("no");
+		let first: str = describe("");
+		let failed := e == err("no");
+		match r {
+			err(_) => { }
+			_ => { }
+		}
+	`)
+}
+
+func TestResultErrors(t *testing.T) {
+	tests := []errorCase{
+		{"Result used as its value", parsers + `let n: i32 = parse("1");`, "error declaring variable 'n'. cannot assign value of type 'i32!str' to type 'i32'", "8:14"},
+		{"Wrong error type", parsers + `fn f() -> i32!str { ret err(1); }`, "cannot return 'err(i32)' from this scope", "8:21"},
+		{"Error not inferred", parsers + `let e := err("no");`, "cannot infer the type of 'e' from an error", "8:10"},
+		{"Propagate a non result", parsers + `fn f() -> i32!str { let n := 1; ret n?; }`, "'?' needs a result, got 'i32'", "8:37"},
+		{"Propagate outside a function", parsers + `let n := parse("1")?;`, "'?' can only be used inside a function", "8:10"},
+		{"Propagate from a function without a result", parsers + `fn f() -> i32 { ret parse("1")?; }`, "'?' can only be used in a function returning a result, got 'i32'", "8:21"},
+		{"Propagate another error type", parsers + `fn f() -> i32!i32 { ret parse("1")?; }`, "cannot return an error of type 'str' from a function returning 'i32!i32'", "8:25"},
+		{"Void error type", `fn f() -> i32!void { ret 1; }`, "the error type of a result cannot be void", "1:15"},
+		{"Nested results", `fn f() -> i32!str!str { ret 1; }`, "result type 'i32!str' cannot be part of another result type", "1:11"},
+		{"Result pattern on a non result", parsers + `let x := 1; match x { ok(n) => { } _ => { } }`, "pattern 'ok(...)' cannot match a value of type 'i32'", "8:23"},
+		{"Result pattern is a name", parsers + `let r := parse("1"); match r { ok(1) => { } _ => { } }`, "the value of pattern 'ok(...)' is bound to a name", "8:35"},
+		{"Result match not exhaustive", parsers + `let r := parse("1"); match r { ok(n) => { } }`, "match on 'i32!str' is not exhaustive", "8:22"},
+	}
+
+	checkErrors(t, tests)
+}
+
+func TestIgnoredResult(t *testing.T) {
+	reports, _ := checkModules(t, map[string]string{"main.wal": parsers + `parse("1");`})
+	for _, r := range reports {
+		if r.IsError() {
+			t.Errorf("unexpected error: %s", r.Message)
+		}
+		if strings.HasPrefix(r.Message, "result of type 'i32!str' is ignored") {
+			return
+		}
+	}
+	t.Errorf("Expected a warning for the ignored result, got %v", reports)
+}
@@ -14,11 +14,14 @@ func checkReturnStmt(returnNode ast.ReturnStmt, env *TypeEnvironment) Tc {
 		report.Add(env.filePath, returnNode.StartPos().Line, returnNode.EndPos().Line, returnNode.StartPos().Column, returnNode.EndPos().Column, "return statement outside function").SetLevel(report.NORMAL_ERROR)
 	}
 
-	//check if the return type matches the function return type
-	returnType := parseNodeValue(returnNode.Value, env)
-
+	//check if the return type matches the function return type, 'ret;' returns nothing
 	fnReturns := getFunctionReturnValue(env, returnNode)
 
+	var returnType Tc = NewVoid()
+	if returnNode.Value != nil {
+		returnType = checkValueAs(returnNode.Value, fnReturns, env)
+	}
+
 	err := validateTypeCompatibility(fnReturns, returnType)
 	if err != nil {
 		report.Add(env.filePath, returnNode.StartPos().Line, returnNode.EndPos().Line, returnNode.StartPos().Column, returnNode.EndPos().Column, fmt.Sprintf("cannot return '%s' from this scope\n", tcToString(returnType))+fmt.Sprintf(" - function '%s' expects return type '%s'", env.scopeName, tcToString(fnReturns))).SetLevel(report.NORMAL_ERROR)
@@ -0,0 +1,51 @@
+package typechecker
+
+import (
+	//Standard packages
+	"fmt"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/report"
+)
+
+// checkSafeStmt checks a safe statement. The variable must have a nullable type 'T?'; in
+// the safe block it has the type T, as it cannot be null there. Like an if statement with
+// an else branch, it satisfies a return when both blocks return.
+func checkSafeStmt(node ast.SafeStmt, env *TypeEnvironment) Block {
+
+	name := node.Value.Name
+
+	value := parseNodeValue(node.Value, env)
+
+	safeEnv := NewTypeENV(env, SAFE_SCOPE, "safe", env.filePath)
+
+	if maybe, ok := value.(Maybe); ok {
+		// the narrowed variable keeps the declaration of the variable it narrows
+		declaredEnv, _ := env.resolveVar(name)
+		if err := safeEnv.declareVar(name, maybe.MaybeType, declaredEnv.constants[name], false); err != nil {
+			report.Add(env.filePath, node.Value.Start.Line, node.Value.End.Line, node.Value.Start.Column, node.Value.End.Column, err.Error()).SetLevel(report.NORMAL_ERROR)
+		}
+		if location, ok := declaredEnv.declarations[name]; ok {
+			safeEnv.declaredAt(name, location)
+		}
+	} else {
+		report.Add(env.filePath, node.Value.Start.Line, node.Value.End.Line, node.Value.Start.Column, node.Value.End.Column, fmt.Sprintf("'%s' of type '%s' cannot be null", name, tcToString(value))).Hint("safe statements check variables of a nullable type like 'i32?'").SetLevel(report.NORMAL_ERROR)
+	}
+
+	safeValue := checkBlock(node.SafeBlock, safeEnv)
+
+	otherwiseEnv := NewTypeENV(env, OTHERWISE_SCOPE, "otherwise", env.filePath)
+	otherwiseValue := checkBlock(node.UnsafeBlock, otherwiseEnv)
+
+	block := Block{
+		IsSatisfied:     safeValue.IsSatisfied && otherwiseValue.IsSatisfied,
+		ProblemLocation: safeValue.ProblemLocation,
+	}
+	// without an otherwise block, the problem is reported on the safe block like on an if without else
+	if safeValue.IsSatisfied && node.UnsafeBlock.Start.Line > 0 {
+		block.ProblemLocation = otherwiseValue.ProblemLocation
+	}
+
+	return block
+}
@@ -0,0 +1,55 @@
+package typechecker
+
+import (
+	"testing"
+)
+
+const points = `
+	type Point struct { x: i32 };
+	impl Point { fn double() -> i32 { ret this.x * 2; } }
+	type Line struct { end: Point? };
+`
+
+func TestSafeNarrowsMaybe(t *testing.T) {
+	analyze(t, points+`
+		fn describe(p: Point?) -> i32 {
+			safe p {
+				ret p.x + p.double();
+			} otherwise {
+				ret 0;
+			}
+		}
+		fn find(n: i32) -> Point? {
+			if n > 0 {
+				ret @Point{x: n};
+			}
+			ret null;
+		}
+		let line := @Line{end: null};
+		let x := line.end?.x;
+		let missing := x == null;
+		let count: i32? = 1;
+		count = null;
+		safe count {
+			count = count + 1;
+		}
+	`)
+}
+
+func TestMaybeErrors(t *testing.T) {
+	tests := []errorCase{
+		{"Null needs a type", `let x := null;`, "cannot infer the type of 'x' from null", "1:10"},
+		{"Null needs a maybe", `let x: i32 = null;`, "error declaring variable 'x'. cannot assign value of type 'null' to type 'i32'", "1:14"},
+		{"Maybe is not its type", `let x: i32? = 1; let y: i32 = x;`, "error declaring variable 'y'. cannot assign value of type 'i32?' to type 'i32'", "1:31"},
+		{"Narrowing ends with the block", `let x: i32? = 1; safe x { } let y: i32 = x;`, "error declaring variable 'y'. cannot assign value of type 'i32?' to type 'i32'", "1:42"},
+		{"Narrowed value is not null", `let x: i32? = 1; safe x { x = null; }`, "cannot assign value of type 'null' to type 'i32'", "1:31"},
+		{"Safe needs a maybe", `let x := 1; safe x { }`, "'x' of type 'i32' cannot be null", "1:18"},
+		{"Dot on a maybe", points + `let p: Point? = null; let x := p.x;`, "cannot access 'x' of a value of type 'Point?', which may be null", "5:32"},
+		{"Method through optional chaining", points + `let p: Point? = null; let f := p?.double;`, "method 'double' cannot be accessed with '?.'", "5:35"},
+		{"Assign through optional chaining", points + `let p: Point? = null; p?.x = 1;`, "cannot assign to a property accessed with '?.'", "5:23"},
+		{"Maybe of a maybe", `let x: i32?? = null;`, "type 'i32?' is already nullable", "1:8"},
+		{"Safe without otherwise", points + `fn f(p: Point?) -> i32 { safe p { ret p.x; } }`, "missing return in this block", "5:33"},
+	}
+
+	checkErrors(t, tests)
+}
@@ -30,6 +30,7 @@ func checkAnnonymousStructLiteral(structLit ast.StructLiteral, env *TypeEnvironm
 		if err != nil {
 			report.Add(env.filePath, propval.Prop.StartPos().Line, propval.Prop.EndPos().Line, propval.Prop.StartPos().Column, propval.Prop.EndPos().Column, fmt.Sprintf("error declaring property '%s': %s", propval.Prop.Name, err.Error())).SetLevel(report.CRITICAL_ERROR)
 		}
+		structEnv.declaredAt(propval.Prop.Name, propval.Prop.Location)
 
 		sname += fmt.Sprintf("%s: %s", propval.Prop.Name, tcToString(propType))
 		if i < len(structLit.Properties)-1 {
@@ -64,6 +65,13 @@ func checkStructLiteral(structLit ast.StructLiteral, env *TypeEnvironment) Tc {
 		report.Add(env.filePath, sName.StartPos().Line, sName.EndPos().Line, sName.StartPos().Column, sName.EndPos().Column, err.Error()).SetLevel(report.NORMAL_ERROR)
 	}
 
+	if generic, ok := Type.(Generic); ok {
+		return checkGenericStructLiteral(structLit, generic, env)
+	}
+	if len(structLit.TypeArgs) > 0 {
+		report.Add(env.filePath, sName.StartPos().Line, sName.EndPos().Line, sName.StartPos().Column, sName.EndPos().Column, fmt.Sprintf("type '%s' is not generic", sName.Name)).SetLevel(report.NORMAL_ERROR)
+	}
+
 	structType, ok := Type.(Struct)
 
 	if !ok {
@@ -71,7 +79,7 @@ func checkStructLiteral(structLit ast.StructLiteral, env *TypeEnvironment) Tc {
 	}
 
 	// now we match the defined props with the provided props
-	checkPropsType(structType, structLit, env)
+	checkPropsType(structType, structLit, checkPropValues(structLit, structType, nil, env), env)
 	// check if any required property is missing
 	missingProps := checkMissingProps(structType, structLit)
 	// if there are missing properties, we compose the error
@@ -127,15 +135,32 @@ func checkMissingProps(structType Struct, structLit ast.StructLiteral) []string
 	return missingProps
 }
 
-func checkPropsType(structType Struct, structLit ast.StructLiteral, env *TypeEnvironment) {
-	for _, structProp := range structLit.Properties {
+// checkPropValues checks the values of the properties of a struct literal, in order.
+// checkPropValues checks the values of the properties of a struct literal. A value takes the
+// type of its property, unless the property has a type parameter in its type, which is
+// inferred from the value.
+func checkPropValues(structLit ast.StructLiteral, structType Struct, typeParams []TypeParam, env *TypeEnvironment) []Tc {
+	values := make([]Tc, len(structLit.Properties))
+	for i, structProp := range structLit.Properties {
+		property, ok := structType.StructScope.variables[structProp.Prop.Name].(StructProperty)
+		if ok && !mentionsTypeParam(property.Type, typeParams) {
+			values[i] = checkValueAs(structProp.Value, property.Type, env)
+		} else {
+			values[i] = parseNodeValue(structProp.Value, env)
+		}
+	}
+	return values
+}
+
+func checkPropsType(structType Struct, structLit ast.StructLiteral, values []Tc, env *TypeEnvironment) {
+	for i, structProp := range structLit.Properties {
 		//check if the property is defined
 		if _, ok := structType.StructScope.variables[structProp.Prop.Name]; !ok {
 			report.Add(env.filePath, structProp.Prop.Start.Line, structProp.Prop.End.Line, structProp.Prop.Start.Column, structProp.Prop.End.Column, fmt.Sprintf("property '%s' is not defined on struct '%s'", structProp.Prop.Name, structLit.Identifier.Name)).SetLevel(report.CRITICAL_ERROR)
 		}
 
 		//check if the property type matches the defined type
-		providedType := parseNodeValue(structProp.Value, env)
+		providedType := values[i]
 
 		expectedType := structType.StructScope.variables[structProp.Prop.Name].(StructProperty).Type
 
@@ -151,11 +176,12 @@ func checkPropsType(structType Struct, structLit ast.StructLiteral, env *TypeEnv
 
 func getObject(expr ast.StructPropertyAccessExpr, env *TypeEnvironment) Tc {
 	// if obj is 'this' then we return the struct type
-	if ok := expr.Object.(ast.IdentifierExpr); ok.Name == "this" {
+	if ident, ok := expr.Object.(ast.IdentifierExpr); ok && ident.Name == "this" {
 		obj, err := env.getStructType()
 		if err != nil {
 			report.Add(env.filePath, expr.Object.StartPos().Line, expr.Object.EndPos().Line, expr.Object.StartPos().Column, expr.Object.EndPos().Column, "invalid use of 'this' outside of struct scope").SetLevel(report.CRITICAL_ERROR)
 		}
+		env.info.recordType(expr.Object, obj)
 		return obj
 	} else {
 		return parseNodeValue(expr.Object, env)
@@ -164,12 +190,60 @@ func getObject(expr ast.StructPropertyAccessExpr, env *TypeEnvironment) Tc {
 
 func checkPropertyAccess(expr ast.StructPropertyAccessExpr, env *TypeEnvironment) Tc {
 
+	// the variants of an enum are accessed through its name, like 'Shape.Circle'
+	if enum, ok := enumOf(expr.Object); ok && !expr.Optional {
+		return checkVariantAccess(expr, enum, env)
+	}
+
 	object := getObject(expr, env)
 
 	prop := expr.Property
 
+	if maybe, ok := unwrapType(object).(Maybe); ok {
+		if !expr.Optional {
+			report.Add(env.filePath, expr.Object.StartPos().Line, expr.Object.EndPos().Line, expr.Object.StartPos().Column, expr.Object.EndPos().Column, fmt.Sprintf("cannot access '%s' of a value of type '%s', which may be null", prop.Name, tcToString(object))).Hint("use '?.' or check the value with a safe statement").SetLevel(report.NORMAL_ERROR)
+			return checkStructProperty(expr, unwrapType(maybe.MaybeType), env)
+		}
+		return checkOptionalProperty(expr, unwrapType(maybe.MaybeType), env)
+	}
+
+	return checkStructProperty(expr, object, env)
+}
+
+// checkOptionalProperty checks a property accessed with '?.'. The object may be null, so
+// the property may be null too. Methods cannot be called this way.
+func checkOptionalProperty(expr ast.StructPropertyAccessExpr, object Tc, env *TypeEnvironment) Tc {
+
+	prop := expr.Property
+
+	propValue := checkStructProperty(expr, object, env)
+
+	switch t := propValue.(type) {
+	case Fn:
+		report.Add(env.filePath, prop.Start.Line, prop.End.Line, prop.Start.Column, prop.End.Column, fmt.Sprintf("method '%s' cannot be accessed with '?.'", prop.Name)).Hint("check the value with a safe statement first").SetLevel(report.NORMAL_ERROR)
+		return t
+	case Maybe:
+		return t
+	default:
+		if maybe, ok := unwrapType(t).(Maybe); ok {
+			return maybe
+		}
+		return NewMaybe(t)
+	}
+}
+
+// checkStructProperty finds a property or a method of a struct, or a method of an interface.
+func checkStructProperty(expr ast.StructPropertyAccessExpr, object Tc, env *TypeEnvironment) Tc {
+
+	prop := expr.Property
+
 	objName := tcToString(object)
 
+	// the values of a type parameter only have the methods of its constraint
+	if param, ok := object.(TypeParam); ok && param.Constraint != nil {
+		object = param.Constraint
+	}
+
 	var structEnv TypeEnvironment
 
 	//get the struct's environment
@@ -191,6 +265,9 @@ func checkPropertyAccess(expr ast.StructPropertyAccessExpr, env *TypeEnvironment
 
 	// Check if the property exists on the struct
 	if property, ok := structEnv.variables[prop.Name]; ok {
+		if location, ok := structEnv.declarations[prop.Name]; ok {
+			env.info.recordReference(prop, &location)
+		}
 		isPrivate := false
 		switch t := property.(type) {
 		case StructMethod:
@@ -234,6 +311,7 @@ func checkStructTypeDecl(name string, structType ast.StructType, env *TypeEnviro
 		if err != nil {
 			report.Add(env.filePath, propval.PropType.StartPos().Line, propval.PropType.EndPos().Line, propval.PropType.StartPos().Column, propval.PropType.EndPos().Column, fmt.Sprintf("error declaring property '%s': %s", propval.Prop.Name, err.Error())).SetLevel(report.CRITICAL_ERROR)
 		}
+		structEnv.declaredAt(propval.Prop.Name, propval.Prop.Location)
 	}
 
 	structTypeValue := Struct{
@@ -0,0 +1,184 @@
+package typechecker
+
+import (
+	//Standard packages
+	"fmt"
+	"math/big"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/report"
+)
+
+// evalTuple evaluates '(T, U, ...)'. A tuple cannot hold void values.
+func evalTuple(t ast.TupleType, env *TypeEnvironment) Tc {
+
+	elements := make([]Tc, len(t.Elements))
+	for i, element := range t.Elements {
+		elements[i] = evaluateTypeName(element, env)
+		if _, ok := unwrapType(elements[i]).(Void); ok {
+			report.Add(env.filePath, element.StartPos().Line, element.EndPos().Line, element.StartPos().Column, element.EndPos().Column, "a tuple cannot hold a void value").SetLevel(report.NORMAL_ERROR)
+		}
+	}
+
+	return NewTuple(elements)
+}
+
+// checkTupleLiteral checks '(a, b, ...)'. Its type is the tuple of the types of its values.
+func checkTupleLiteral(node ast.TupleLiteral, env *TypeEnvironment) Tc {
+
+	elements := make([]Tc, len(node.Values))
+	for i, value := range node.Values {
+		elements[i] = parseNodeValue(value, env)
+		checkTupleValue(value, elements[i], env)
+	}
+
+	return NewTuple(elements)
+}
+
+// checkTupleLiteralAs checks a tuple literal stored in a place of a tuple type with as many
+// elements. Each value is stored in its element, so untyped numbers take the element type.
+func checkTupleLiteralAs(node ast.TupleLiteral, expected Tuple, env *TypeEnvironment) Tc {
+
+	elements := make([]Tc, len(node.Values))
+	for i, value := range node.Values {
+		elements[i] = checkValueAs(value, expected.Elements[i], env)
+		checkTupleValue(value, elements[i], env)
+	}
+
+	tuple := NewTuple(elements)
+	env.info.recordType(node, tuple)
+	return tuple
+}
+
+// checkTupleValue reports a void value put in a tuple.
+func checkTupleValue(value ast.Node, valueType Tc, env *TypeEnvironment) {
+	if _, ok := valueType.(Void); ok {
+		report.Add(env.filePath, value.StartPos().Line, value.EndPos().Line, value.StartPos().Column, value.EndPos().Column, "a tuple cannot hold a void value").SetLevel(report.NORMAL_ERROR)
+	}
+}
+
+// checkTupleIndex checks 'tuple[i]'. The index must be a constant integer, so the type of
+// the element is known.
+func checkTupleIndex(indexable ast.Indexable, tuple Tuple, index Tc, env *TypeEnvironment) Tc {
+
+	value, _ := env.info.constantOf(indexable.Index)
+	position, ok := value.(*big.Int)
+	if !ok || !isIntType(index) {
+		report.Add(env.filePath, indexable.Index.StartPos().Line, indexable.Index.EndPos().Line, indexable.Index.StartPos().Column, indexable.Index.EndPos().Column, "a tuple must be indexed by a constant integer").Hint("the index decides the type of the element, unpack the tuple to use every value").SetLevel(report.NORMAL_ERROR)
+		return NewInvalid()
+	}
+
+	if position.Sign() < 0 || position.Cmp(big.NewInt(int64(len(tuple.Elements)))) >= 0 {
+		report.Add(env.filePath, indexable.Index.StartPos().Line, indexable.Index.EndPos().Line, indexable.Index.StartPos().Column, indexable.Index.EndPos().Column, fmt.Sprintf("index %s is out of range of a tuple of %d values", position, len(tuple.Elements))).SetLevel(report.NORMAL_ERROR)
+		return NewInvalid()
+	}
+
+	return tuple.Elements[position.Int64()]
+}
+
+// isTupleElement reports whether a checked node reads an element of a tuple, which cannot
+// be assigned to.
+func isTupleElement(node ast.Node, env *TypeEnvironment) bool {
+	indexable, ok := node.(ast.Indexable)
+	if !ok {
+		return false
+	}
+	container, _ := env.info.TypeOf(indexable.Container)
+	_, ok = container.(Tuple)
+	return ok
+}
+
+// checkDestructuring checks a declaration unpacking its value, like 'let (q, r) := ...' or
+// 'let {name, age} := ...', and declares the names it unpacks into.
+func checkDestructuring(variable ast.VarDeclStmtVar, node ast.VarDeclStmt, env *TypeEnvironment) {
+
+	value := parseExpectedType(variable.ExplicitType, variable.Value, env)
+
+	if variable.ExplicitType != nil {
+		providedValue := checkValueAs(variable.Value, value, env)
+		if err := validateTypeCompatibility(value, providedValue); err != nil {
+			report.Add(env.filePath, variable.Value.StartPos().Line, variable.Value.EndPos().Line, variable.Value.StartPos().Column, variable.Value.EndPos().Column, fmt.Sprintf("error unpacking value. %s", err.Error())).SetLevel(report.NORMAL_ERROR)
+		}
+	}
+
+	pattern := variable.Destructure
+
+	var types []Tc
+	if pattern.IsStruct {
+		types = unpackStruct(variable, value, env)
+	} else {
+		types = unpackTuple(variable, value, env)
+	}
+
+	for i, name := range pattern.Names {
+		if name.Name == "_" {
+			continue
+		}
+		if err := env.declareVar(name.Name, types[i], node.IsConst, false); err != nil {
+			report.Add(env.filePath, name.Start.Line, name.End.Line, name.Start.Column, name.End.Column, err.Error()).SetLevel(report.CRITICAL_ERROR)
+		}
+		env.declaredAt(name.Name, name.Location)
+		env.info.recordType(name, types[i])
+
+		if node.IsExported && canExport(node, env) {
+			exportValue(name.Name, env)
+		}
+	}
+}
+
+// unpackTuple returns the types of the names a tuple is unpacked into, one for each element.
+func unpackTuple(variable ast.VarDeclStmtVar, value Tc, env *TypeEnvironment) []Tc {
+
+	pattern := variable.Destructure
+	types := make([]Tc, len(pattern.Names))
+	for i := range types {
+		types[i] = NewInvalid()
+	}
+
+	tuple, ok := unwrapType(value).(Tuple)
+	if !ok {
+		report.Add(env.filePath, variable.Value.StartPos().Line, variable.Value.EndPos().Line, variable.Value.StartPos().Column, variable.Value.EndPos().Column, fmt.Sprintf("cannot unpack a value of type '%s' into '(...)'", tcToString(value))).Hint("only tuples can be unpacked by position, unpack a struct with '{...}'").SetLevel(report.NORMAL_ERROR)
+		return types
+	}
+
+	if len(tuple.Elements) != len(pattern.Names) {
+		report.Add(env.filePath, pattern.Start.Line, pattern.End.Line, pattern.Start.Column, pattern.End.Column, fmt.Sprintf("cannot unpack a tuple of %d values into %d names", len(tuple.Elements), len(pattern.Names))).Hint("skip the values you do not need with '_'").SetLevel(report.NORMAL_ERROR)
+		return types
+	}
+
+	copy(types, tuple.Elements)
+	return types
+}
+
+// unpackStruct returns the types of the fields of a struct a value is unpacked into. Every
+// name must be a field the scope can access.
+func unpackStruct(variable ast.VarDeclStmtVar, value Tc, env *TypeEnvironment) []Tc {
+
+	pattern := variable.Destructure
+	types := make([]Tc, len(pattern.Names))
+	for i := range types {
+		types[i] = NewInvalid()
+	}
+
+	structType, ok := unwrapType(value).(Struct)
+	if !ok {
+		report.Add(env.filePath, variable.Value.StartPos().Line, variable.Value.EndPos().Line, variable.Value.StartPos().Column, variable.Value.EndPos().Column, fmt.Sprintf("cannot unpack a value of type '%s' into '{...}'", tcToString(value))).Hint("only structs can be unpacked by field names, unpack a tuple with '(...)'").SetLevel(report.NORMAL_ERROR)
+		return types
+	}
+
+	for i, name := range pattern.Names {
+		if name.Name == "_" {
+			report.Add(env.filePath, name.Start.Line, name.End.Line, name.Start.Column, name.End.Column, "'_' cannot be a field name").Hint("leave out the fields you do not need").SetLevel(report.NORMAL_ERROR)
+			continue
+		}
+		if _, ok := structType.StructScope.variables[name.Name].(StructMethod); ok {
+			report.Add(env.filePath, name.Start.Line, name.End.Line, name.Start.Column, name.End.Column, fmt.Sprintf("cannot unpack method '%s', only fields can be unpacked", name.Name)).SetLevel(report.NORMAL_ERROR)
+			continue
+		}
+		access := ast.StructPropertyAccessExpr{Object: variable.Value, Property: name, Location: name.Location}
+		types[i] = checkStructProperty(access, structType, env)
+	}
+
+	return types
+}
@@ -0,0 +1,71 @@
+package typechecker
+
+import (
+	"testing"
+
+	"walrus/compiler/report"
+)
+
+const divmod = `
+	fn divmod(a: i32, b: i32) -> (i32, i32) {
+		ret a / b, a % b;
+	}
+`
+
+func TestTuples(t *testing.T) {
+	analyze(t, divmod+`
+		type Person struct { name: str, age: i32 };
+		let (q, r) := divmod(7, 2);
+		let sum: i32 = q + r;
+		let (_, rest) := divmod(9, 4);
+		let person := @Person{name: "Ann", age: 30};
+		let {name, age} := person;
+		let greeting: str = name + " " + age;
+		let t: (i64, f64) = (5000000000, 0.1);
+		let (big, small) := t;
+		let b: i64 = big;
+		let pair := (1, "one");
+		let same := pair == (1, "one");
+		let first: i32 = pair[0];
+		let empty: (i32, str);
+		const (x, y) := (1, 2);
+	`)
+}
+
+func TestTupleErrors(t *testing.T) {
+	tests := []errorCase{
+		{"Wrong tuple type", divmod + `let t: (i32, str) = divmod(1, 2);`, "error declaring variable 't'. cannot assign value of type '(i32, i32)' to type '(i32, str)'", "5:21"},
+		{"Wrong return", `fn f() -> (i32, i32) { ret 1, "a"; }`, "cannot return '(i32, str)' from this scope", "1:24"},
+		{"Too many names", divmod + `let (a, b, c) := divmod(1, 2);`, "cannot unpack a tuple of 2 values into 3 names", "5:5"},
+		{"Unpack a non tuple", `let (a, b) := 1;`, "cannot unpack a value of type 'i32' into '(...)'", "1:15"},
+		{"Unpack a non struct", divmod + `let {a, b} := divmod(1, 2);`, "cannot unpack a value of type '(i32, i32)' into '{...}'", "5:15"},
+		{"Unknown field", `type P struct { x: i32 }; let p := @P{x: 1}; let {y} := p;`, "'y' does not exist on type 'P'", "1:51"},
+		{"Skip a field", `type P struct { x: i32 }; let p := @P{x: 1}; let {_} := p;`, "'_' cannot be a field name", "1:51"},
+		{"Unpack a method", `type P struct { x: i32 }; impl P { fn get() -> i32 { ret this.x; } } let p := @P{x: 1}; let {get} := p;`, "cannot unpack method 'get', only fields can be unpacked", "1:94"},
+		{"Void element", `fn f() -> (i32, void) { ret 1, 2; }`, "a tuple cannot hold a void value", "1:17"},
+		{"Nothing to unpack into", `let () := (1, 2);`, "nothing to unpack the value into", "1:5"},
+		{"Tuple index not constant", `let t := (1, "a"); let i := 0; let x := t[i];`, "a tuple must be indexed by a constant integer", "1:43"},
+		{"Tuple index out of range", `let t := (1, "a"); let x := t[2];`, "index 2 is out of range of a tuple of 2 values", "1:31"},
+		{"Assign a tuple element", `let t := (1, "a"); t[0] = 2;`, "cannot assign to an element of a tuple", "1:20"},
+		{"Unpacked constant", divmod + `const (a, b) := divmod(1, 2); a = 3;`, "cannot assign to constant", "5:31"},
+	}
+
+	checkErrors(t, tests)
+}
+
+func TestTupleIndexErrorIsReportedOnce(t *testing.T) {
+	reports, _ := checkModules(t, map[string]string{"main.wal": `let t := (1, "a"); let x := t[2]; let y: i32 = x + 1; let (a, b, c) := t; let z: str = a;`})
+
+	var errors []string
+	for _, r := range reports {
+		if r.IsError() {
+			if r.Level != report.NORMAL_ERROR {
+				t.Errorf("Expected %q to be a normal error, got %s", r.Message, r.Level)
+			}
+			errors = append(errors, r.Message)
+		}
+	}
+	if len(errors) != 2 {
+		t.Errorf("Expected one error for the index and one for the unpacking, got %q", errors)
+	}
+}
@@ -6,11 +6,13 @@ import (
 	//Walrus packages
 	"walrus/compiler/colors"
 	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/modules"
 	"walrus/compiler/report"
 )
 
 func evaluateProgram(program ast.ProgramStmt, env *TypeEnvironment) Tc {
 	colors.PURPLE.Println("### Running type checker ###")
+	shadowBuiltins(program, env)
 	for _, item := range program.Contents {
 		checkAST(item, env)
 	}
@@ -21,16 +23,11 @@ func evaluateProgram(program ast.ProgramStmt, env *TypeEnvironment) Tc {
 	return NewVoid()
 }
 
-func Analyze(tree ast.Node, filePath string) {
+// Analyze checks the program and returns the type of every expression and the declaration
+// every identifier refers to.
+func Analyze(tree ast.Node, filePath string) *TypeInfo {
 
-	colors.PURPLE.Println("### Running type checker ###")
-
-	env := ProgramEnv(filePath)
-
-	checkAST(tree, env)
-
-	env.ClearEnv()
-	ClearTypes()
+	return AnalyzeProgram([]modules.Module{{FilePath: filePath, Tree: tree}})[0]
 }
 
 func checkAST(node ast.Node, env *TypeEnvironment) Tc {
@@ -49,12 +46,43 @@ func checkAST(node ast.Node, env *TypeEnvironment) Tc {
 		return checkIfStmt(t, env)
 	case ast.ForStmt:
 		return checkForStmt(t, env)
+	case ast.ForEachStmt:
+		return checkForEachStmt(t, env)
+	case ast.WhileStmt:
+		return checkWhileStmt(t, env)
+	case ast.BreakStmt:
+		return checkLoopControl("break", t.Label, t.Location, env)
+	case ast.ContinueStmt:
+		return checkLoopControl("continue", t.Label, t.Location, env)
+	case ast.MatchStmt:
+		return checkMatchStmt(t, env)
+	case ast.SafeStmt:
+		return checkSafeStmt(t, env)
+	case ast.ImportStmt:
+		return checkImportStmt(t, env)
 	default:
-		return parseNodeValue(t, env)
+		value := parseNodeValue(t, env)
+		checkIgnoredResult(t, value, env)
+		return value
 	}
 }
 
+// parseNodeValue checks an expression and records its type.
 func parseNodeValue(node ast.Node, env *TypeEnvironment) Tc {
+	// an untyped constant with no type to take has its default type, see checkOperands
+	untyped := env.untypedAs == nil && isUntypedConstant(node)
+	value := checkNodeValue(node, env)
+	if _, ok := node.(ast.ReturnStmt); !ok {
+		env.info.recordType(node, value)
+		foldConstant(node, value, env)
+	}
+	if untyped {
+		reportConstantError(node, env)
+	}
+	return value
+}
+
+func checkNodeValue(node ast.Node, env *TypeEnvironment) Tc {
 	switch t := node.(type) {
 	case ast.VarAssignmentExpr:
 		return checkVariableAssignment(t, env) // value
@@ -65,13 +93,21 @@ func parseNodeValue(node ast.Node, env *TypeEnvironment) Tc {
 	case ast.IdentifierExpr:
 		return checkIdentifier(t, env) // value
 	case ast.IntegerLiteralExpr:
-		return NewInt(t.BitSize, t.IsSigned) // value
+		return checkIntegerLiteral(t, env) // value
 	case ast.FloatLiteralExpr:
-		return NewFloat(t.BitSize) // value
+		return checkFloatLiteral(t, env) // value
 	case ast.StringLiteralExpr:
 		return NewStr() // value
+	case ast.InterpolatedStringExpr:
+		return checkInterpolatedString(t, env) // value
 	case ast.ByteLiteralExpr:
 		return NewInt(8, false) // value
+	case ast.NullLiteralExpr:
+		return NewNull() // value
+	case ast.ResultExpr:
+		return checkResultExpr(t, env) // value
+	case ast.PropagateExpr:
+		return checkPropagateExpr(t, env) // value
 	case ast.BinaryExpr:
 		return checkBinaryExpr(t, env) // value
 	case ast.UnaryExpr:
@@ -82,6 +118,8 @@ func parseNodeValue(node ast.Node, env *TypeEnvironment) Tc {
 		return checkRange(t, env) // value
 	case ast.ArrayLiteral:
 		return evaluateArrayExpr(t, env) // value
+	case ast.TupleLiteral:
+		return checkTupleLiteral(t, env) // value
 	case ast.Indexable:
 		return evaluateIndexableAccess(t, env) // value
 	case ast.StructLiteral:
@@ -26,6 +26,10 @@ func checkTypeDeclaration(node ast.TypeDeclStmt, env *TypeEnvironment) Tc {
 		val = checkStructTypeDecl(node.UDTypeName.Name, t, env)
 	case ast.InterfaceType:
 		val = checkInterfaceTypeDecl(node.UDTypeName.Name, t, env)
+	case ast.EnumType:
+		val = checkEnumTypeDecl(node.UDTypeName.Name, t, env)
+	case ast.GenericType:
+		val = checkGenericTypeDecl(node.UDTypeName.Name, t, env)
 	default:
 		val = evaluateTypeName(typeName, env)
 	}
@@ -39,6 +43,16 @@ func checkTypeDeclaration(node ast.TypeDeclStmt, env *TypeEnvironment) Tc {
 	err := declareType(node.UDTypeName.Name, typeVal)
 	if err != nil {
 		report.Add(env.filePath, node.Start.Line, node.End.Line, node.Start.Column, node.End.Column, err.Error()).SetLevel(report.NORMAL_ERROR)
+	} else {
+		typeModules[node.UDTypeName.Name] = env.filePath
+		if moduleTypes[env.filePath] == nil {
+			moduleTypes[env.filePath] = make(map[string]Tc)
+		}
+		moduleTypes[env.filePath][node.UDTypeName.Name] = typeVal
+	}
+
+	if node.IsExported && canExport(node, env) {
+		exportType(node.UDTypeName.Name, env)
 	}
 
 	colors.GREEN.Print("Declared Type ")
@@ -0,0 +1,261 @@
+package typechecker
+
+import (
+	//Standard packages
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/builtins"
+	"walrus/compiler/internal/values"
+)
+
+// TypedNode is the type the checker resolved for an expression.
+type TypedNode struct {
+	Node     string       `json:"node"` // kind of the node, like "BinaryExpr"
+	Location ast.Location `json:"location"`
+	Type     string       `json:"type"`
+	Tc       Tc           `json:"-"`
+}
+
+// Reference links an identifier to the declaration of the name it refers to.
+// Builtin values like 'print' have no declaration in the source.
+type Reference struct {
+	Name        string        `json:"name"`
+	Location    ast.Location  `json:"location"`
+	Declaration *ast.Location `json:"declaration,omitempty"`
+}
+
+// TypeInfo is the side table the checker fills while it checks a program: the resolved
+// type of every expression and the declaration every identifier refers to.
+type TypeInfo struct {
+	Types      []TypedNode `json:"types"`
+	References []Reference `json:"references"`
+	types      map[nodeKey]int
+	references map[ast.Location]int
+	constants  map[nodeKey]any           // folded values of constant expressions
+	errors     map[nodeKey]constantError // why expressions could not be folded
+	renamed    map[string]string         // private type name -> the name mangleNames gave it
+}
+
+// nodeKey identifies a node of the tree. Nested nodes can share a location, like a call
+// and its caller, so the kind of the node is part of the key.
+type nodeKey struct {
+	kind     string
+	location ast.Location
+}
+
+func NewTypeInfo() *TypeInfo {
+	return &TypeInfo{
+		Types:      make([]TypedNode, 0),
+		References: make([]Reference, 0),
+		types:      make(map[nodeKey]int),
+		references: make(map[ast.Location]int),
+		constants:  make(map[nodeKey]any),
+		errors:     make(map[nodeKey]constantError),
+		renamed:    make(map[string]string),
+	}
+}
+
+func keyOf(node ast.Node) nodeKey {
+	return nodeKey{
+		kind:     strings.TrimPrefix(fmt.Sprintf("%T", node), "ast."),
+		location: ast.Location{Start: node.StartPos(), End: node.EndPos()},
+	}
+}
+
+// recordType stores the type of an expression. An expression checked again, like the
+// value of a variable with an explicit type, keeps its latest type.
+func (info *TypeInfo) recordType(node ast.Node, value Tc) {
+	key := keyOf(node)
+	typed := TypedNode{Node: key.kind, Location: key.location, Type: tcToString(value), Tc: value}
+	if i, ok := info.types[key]; ok {
+		info.Types[i] = typed
+		return
+	}
+	info.types[key] = len(info.Types)
+	info.Types = append(info.Types, typed)
+}
+
+// recordConstant stores the folded value of an expression, or forgets it when the expression,
+// checked again, is not constant anymore.
+func (info *TypeInfo) recordConstant(node ast.Node, value any, isConstant bool) {
+	if !isConstant {
+		delete(info.constants, keyOf(node))
+		return
+	}
+	info.constants[keyOf(node)] = value
+}
+
+// constantOf returns the folded value of an expression, if it is constant.
+func (info *TypeInfo) constantOf(node ast.Node) (any, bool) {
+	value, ok := info.constants[keyOf(node)]
+	return value, ok
+}
+
+// recordConstantError stores why an expression could not be folded, or forgets it when err is nil.
+func (info *TypeInfo) recordConstantError(node ast.Node, err *constantError) {
+	if err == nil {
+		delete(info.errors, keyOf(node))
+		return
+	}
+	info.errors[keyOf(node)] = *err
+}
+
+// constantErrorOf returns why an expression could not be folded, if folding it found an error.
+func (info *TypeInfo) constantErrorOf(node ast.Node) (constantError, bool) {
+	err, ok := info.errors[keyOf(node)]
+	return err, ok
+}
+
+// recordReference stores the declaration an identifier resolves to.
+func (info *TypeInfo) recordReference(node ast.IdentifierExpr, declaration *ast.Location) {
+	reference := Reference{Name: node.Name, Location: node.Location, Declaration: declaration}
+	if i, ok := info.references[node.Location]; ok {
+		info.References[i] = reference
+		return
+	}
+	info.references[node.Location] = len(info.References)
+	info.References = append(info.References, reference)
+}
+
+// TypeOf returns the type resolved for an expression.
+func (info *TypeInfo) TypeOf(node ast.Node) (Tc, bool) {
+	i, ok := info.types[keyOf(node)]
+	if !ok {
+		return nil, false
+	}
+	return info.Types[i].Tc, true
+}
+
+// DeclarationOf returns where the name an identifier refers to is declared. It returns
+// false for builtins and identifiers that were not checked.
+func (info *TypeInfo) DeclarationOf(node ast.IdentifierExpr) (ast.Location, bool) {
+	i, ok := info.references[node.Location]
+	if !ok || info.References[i].Declaration == nil {
+		return ast.Location{}, false
+	}
+	return *info.References[i].Declaration, true
+}
+
+// DataTypeOf returns the type resolved for an expression as a type annotation, the way the
+// backends spell types. Types the annotations cannot spell, like the type of 'null', are left out.
+func (info *TypeInfo) DataTypeOf(node ast.Node) (ast.DataType, bool) {
+	value, ok := info.TypeOf(node)
+	if !ok {
+		return nil, false
+	}
+	dtype := info.dataType(value)
+	return dtype, dtype != nil
+}
+
+// ConstantOf returns the folded value of a constant expression. Every number literal of a
+// program that type checks is constant, the backends report any other one as an "invalid
+// numeric literal" when they generate code.
+func (info *TypeInfo) ConstantOf(node ast.Node) (values.Value, bool) {
+	value, ok := info.constantOf(node)
+	if !ok {
+		return nil, false
+	}
+	dtype, _ := info.TypeOf(node)
+	switch t := unwrapType(dtype).(type) {
+	case Int:
+		if i, ok := value.(*big.Int); ok {
+			return values.Int{Value: i, BitSize: t.BitSize, IsSigned: t.IsSigned}, true
+		}
+	case Float:
+		return values.NewFloat(toFloatConstant(value), t.BitSize), true
+	case Str:
+		if s, ok := value.(string); ok {
+			return values.NewStr(s), true
+		}
+	case Bool:
+		if b, ok := value.(bool); ok {
+			return values.NewBool(b), true
+		}
+	}
+	return nil, false
+}
+
+// dataType spells a resolved type as a type annotation. Named types are spelled by the name
+// they have in the program, which mangleNames may have changed.
+func (info *TypeInfo) dataType(value Tc) ast.DataType {
+	named := func(name string) ast.DataType {
+		if renamed, ok := info.renamed[name]; ok {
+			name = renamed
+		}
+		return ast.UserDefinedType{TypeName: builtins.USER_DEFINED, AliasName: name}
+	}
+
+	switch t := value.(type) {
+	case Int:
+		return ast.IntegerType{TypeName: builtins.PARSER_TYPE(t.DataType), BitSize: t.BitSize, IsSigned: t.IsSigned}
+	case Float:
+		return ast.FloatType{TypeName: builtins.PARSER_TYPE(t.DataType), BitSize: t.BitSize}
+	case Str:
+		return ast.StringType{TypeName: builtins.STRING}
+	case Bool:
+		return ast.BooleanType{TypeName: builtins.BOOL}
+	case Void:
+		return ast.VoidType{TypeName: builtins.VOID}
+	case UserDefined:
+		return named(t.TypeName)
+	case Struct:
+		if !strings.HasPrefix(t.StructName, "struct {") {
+			return named(t.StructName)
+		}
+		return info.structType(t)
+	case Interface:
+		return named(t.InterfaceName)
+	case Enum:
+		return named(t.EnumName)
+	case Array:
+		return ast.ArrayType{TypeName: builtins.ARRAY, ArrayType: info.dataType(t.ArrayType)}
+	case Map:
+		return ast.MapType{TypeName: builtins.MAP, Map: ast.IdentifierExpr{Name: "map"}, KeyType: info.dataType(t.KeyType), ValueType: info.dataType(t.ValueType)}
+	case Range:
+		return ast.RangeType{TypeName: builtins.RANGE, RangeStart: info.dataType(t.RangeStart), RangeEnd: info.dataType(t.RangeEnd)}
+	case Maybe:
+		return ast.MaybeType{TypeName: builtins.MAYBE, MaybeType: info.dataType(t.MaybeType)}
+	case Result:
+		return ast.ResultType{TypeName: builtins.RESULT, OkType: info.dataType(t.OkType), ErrType: info.dataType(t.ErrType)}
+	case Tuple:
+		elements := make([]ast.DataType, len(t.Elements))
+		for i, element := range t.Elements {
+			elements[i] = info.dataType(element)
+		}
+		return ast.TupleType{TypeName: builtins.TUPLE, Elements: elements}
+	case Fn:
+		params := make([]ast.FunctionTypeParam, len(t.Params))
+		for i, param := range t.Params {
+			params[i] = ast.FunctionTypeParam{Identifier: ast.IdentifierExpr{Name: param.Name}, Type: info.dataType(param.Type)}
+		}
+		return ast.FunctionType{TypeName: builtins.FUNCTION, Parameters: params, ReturnType: info.dataType(t.Returns)}
+	case StructMethod:
+		return info.dataType(t.Fn)
+	case StructProperty:
+		return info.dataType(t.Type)
+	default:
+		return nil
+	}
+}
+
+// structType spells an unnamed struct type, with its properties in declaration order.
+func (info *TypeInfo) structType(t Struct) ast.StructType {
+	props := make([]ast.StructPropType, 0, len(t.StructScope.variables))
+	for name, value := range t.StructScope.variables {
+		prop := ast.StructPropType{Prop: ast.IdentifierExpr{Name: name, Location: t.StructScope.declarations[name]}, PropType: info.dataType(value)}
+		if property, ok := value.(StructProperty); ok {
+			prop.IsPrivate = property.IsPrivate
+		}
+		props = append(props, prop)
+	}
+	sort.Slice(props, func(i, j int) bool {
+		a, b := props[i].Prop.Start, props[j].Prop.Start
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+	return ast.StructType{TypeName: builtins.STRUCT, Properties: props}
+}
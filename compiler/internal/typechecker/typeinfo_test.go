@@ -0,0 +1,151 @@
+package typechecker
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/parser"
+	"walrus/compiler/internal/values"
+	"walrus/compiler/report"
+)
+
+func analyze(t *testing.T, code string) (ast.Node, *TypeInfo) {
+	t.Helper()
+
+	tmpfile, e := os.CreateTemp("", "test*.wal")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.Remove(tmpfile.Name())
+
+	if _, e := tmpfile.Write([]byte(code)); e != nil {
+		t.Fatal(e)
+	}
+	if e := tmpfile.Close(); e != nil {
+		t.Fatal(e)
+	}
+
+	defer report.ClearReports()
+
+	tree, e := parser.NewParser(tmpfile.Name(), false).Parse()
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	info := Analyze(tree, tmpfile.Name())
+	if report.GetReports().HasErrors() {
+		t.Fatalf("Expected program to type check, got %v", report.GetReports()[0].Message)
+	}
+	return tree, info
+}
+
+func TestTypeInfoTypes(t *testing.T) {
+	tree, info := analyze(t, "let x := 5;\nfn double(n: i32) -> i32 { ret n * 2; }\nlet y := double(x) as f32 + 1.5;")
+
+	contents := tree.(ast.ProgramStmt).Contents
+	value := contents[2].(ast.VarDeclStmt).Variables[0].Value.(ast.BinaryExpr)
+	call := value.Left.(ast.TypeCastExpr).Expression.(ast.FunctionCallExpr)
+
+	tests := []struct {
+		name     string
+		node     ast.Node
+		expected string
+	}{
+		{"binary expression", value, "f32"},
+		{"call", call, "i32"},
+		{"caller", call.Caller, "fn(n: i32) -> i32"},
+		{"argument", call.Arguments[0], "i32"},
+		{"literal", value.Right, "f32"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc, ok := info.TypeOf(tt.node)
+			if !ok {
+				t.Fatalf("Expected a type for %T", tt.node)
+			}
+			if got := tcToString(tc); got != tt.expected {
+				t.Errorf(exp, tt.expected, got)
+			}
+		})
+	}
+
+	for _, typed := range info.Types {
+		if typed.Node == "ReturnStmt" {
+			t.Errorf("Expected return statements to have no type, got %s", typed.Type)
+		}
+	}
+}
+
+func TestTypeInfoReferences(t *testing.T) {
+	tree, info := analyze(t, "let x := 5;\nfn show(n: i32) { print(\"\" + n + x); }")
+
+	contents := tree.(ast.ProgramStmt).Contents
+	x := contents[0].(ast.VarDeclStmt).Variables[0].Identifier
+	fn := contents[1].(ast.FunctionDeclStmt)
+	param := fn.Params[0].Identifier
+
+	call := fn.Body.Contents[0].(ast.FunctionCallExpr)
+	sum := call.Arguments[0].(ast.BinaryExpr)
+	n := sum.Left.(ast.BinaryExpr).Right.(ast.IdentifierExpr)
+
+	if location, ok := info.DeclarationOf(n); !ok || location != param.Location {
+		t.Errorf("Expected 'n' to be declared at %v, got %v", param.Location, location)
+	}
+	if location, ok := info.DeclarationOf(sum.Right.(ast.IdentifierExpr)); !ok || location != x.Location {
+		t.Errorf("Expected 'x' to be declared at %v, got %v", x.Location, location)
+	}
+	if _, ok := info.DeclarationOf(call.Caller.(ast.IdentifierExpr)); ok {
+		t.Errorf("Expected builtin 'print' to have no declaration")
+	}
+}
+
+func TestTypeInfoDataTypes(t *testing.T) {
+	tree, info := analyze(t, "type Point struct { x: i32 };\nlet p := @Point{x: 1};\nlet a := @struct{c: \"x\", b: 2};\nlet f: f64 = 3;")
+
+	contents := tree.(ast.ProgramStmt).Contents
+	valueOf := func(i int) ast.Node {
+		return contents[i].(ast.VarDeclStmt).Variables[0].Value
+	}
+
+	if dtype, ok := info.DataTypeOf(valueOf(1)); !ok || dtype.(ast.UserDefinedType).AliasName != "Point" {
+		t.Errorf("Expected the type 'Point', got %#v", dtype)
+	}
+
+	dtype, ok := info.DataTypeOf(valueOf(2))
+	structType, isStruct := dtype.(ast.StructType)
+	if !ok || !isStruct {
+		t.Fatalf("Expected a struct type, got %#v", dtype)
+	}
+	var props []string
+	for _, prop := range structType.Properties {
+		props = append(props, prop.Prop.Name+": "+string(prop.PropType.Type()))
+	}
+	if got := strings.Join(props, ", "); got != "c: str, b: i32" {
+		t.Errorf("Expected the properties in declaration order, got %q", got)
+	}
+
+	constant, ok := info.ConstantOf(valueOf(3))
+	if float, isFloat := constant.(values.Float); !ok || !isFloat || float.Value != 3 || float.BitSize != 64 {
+		t.Errorf("Expected the f64 constant 3, got %#v", constant)
+	}
+}
+
+func TestShadowedBuiltin(t *testing.T) {
+	tree, info := analyze(t, "fn print(message: str, times: i32) {}\nprint(\"hi\", 2);")
+
+	contents := tree.(ast.ProgramStmt).Contents
+	fn := contents[0].(ast.FunctionDeclStmt)
+	call := contents[1].(ast.FunctionCallExpr)
+
+	if location, ok := info.DeclarationOf(call.Caller.(ast.IdentifierExpr)); !ok || location != fn.Identifier.Location {
+		t.Errorf("Expected 'print' to be declared at %v, got %v", fn.Identifier.Location, location)
+	}
+
+	reports, _ := checkModules(t, map[string]string{"main.wal": "let PI := 3;"})
+	if !reports.HasErrors() || !strings.HasPrefix(reports[0].Message, "cannot redeclare builtin value 'PI'") {
+		t.Errorf("Expected builtin constants to stay reserved, got %v", reports)
+	}
+}
@@ -10,12 +10,14 @@ const (
 	INT16_TYPE        builtins.TC_TYPE = builtins.INT16
 	INT32_TYPE        builtins.TC_TYPE = builtins.INT32
 	INT64_TYPE        builtins.TC_TYPE = builtins.INT64
+	INT128_TYPE       builtins.TC_TYPE = builtins.INT128
 	FLOAT32_TYPE      builtins.TC_TYPE = builtins.FLOAT32
 	FLOAT64_TYPE      builtins.TC_TYPE = builtins.FLOAT64
 	UINT8_TYPE        builtins.TC_TYPE = builtins.UINT8
 	UINT16_TYPE       builtins.TC_TYPE = builtins.UINT16
 	UINT32_TYPE       builtins.TC_TYPE = builtins.UINT32
 	UINT64_TYPE       builtins.TC_TYPE = builtins.UINT64
+	UINT128_TYPE      builtins.TC_TYPE = builtins.UINT128
 	STRING_TYPE       builtins.TC_TYPE = builtins.STRING
 	BYTE_TYPE         builtins.TC_TYPE = builtins.BYTE
 	BOOLEAN_TYPE      builtins.TC_TYPE = builtins.BOOL
@@ -27,8 +29,18 @@ const (
 	RANGE_TYPE        builtins.TC_TYPE = builtins.RANGE
 	MAP_TYPE          builtins.TC_TYPE = builtins.MAP
 	USER_DEFINED_TYPE builtins.TC_TYPE = builtins.USER_DEFINED
+	MAYBE_TYPE        builtins.TC_TYPE = builtins.MAYBE
+	NULL_TYPE         builtins.TC_TYPE = builtins.NULL
+	RESULT_TYPE       builtins.TC_TYPE = builtins.RESULT
+	TUPLE_TYPE        builtins.TC_TYPE = builtins.TUPLE
+	ERR_TYPE          builtins.TC_TYPE = builtins.ERR
+	ENUM_TYPE         builtins.TC_TYPE = builtins.ENUM
+	GENERIC_TYPE      builtins.TC_TYPE = builtins.GENERIC
+	TYPE_PARAM_TYPE   builtins.TC_TYPE = builtins.TYPE_PARAM
 	BLOCK_TYPE        builtins.TC_TYPE = "block"
 	RETURN_TYPE       builtins.TC_TYPE = "return"
+	LOOP_JUMP_TYPE    builtins.TC_TYPE = "loop jump"
+	INVALID_TYPE      builtins.TC_TYPE = "invalid"
 )
 
 type Tc interface {
@@ -78,6 +90,16 @@ func (t Void) DType() builtins.TC_TYPE {
 	return t.DataType
 }
 
+// Invalid is the type of an expression whose error was already reported. It takes and is
+// taken by every type, so the error is not reported again where the expression is used.
+type Invalid struct {
+	DataType builtins.TC_TYPE
+}
+
+func (t Invalid) DType() builtins.TC_TYPE {
+	return t.DataType
+}
+
 type Map struct {
 	DataType  builtins.TC_TYPE
 	KeyType   Tc
@@ -89,12 +111,14 @@ func (t Map) DType() builtins.TC_TYPE {
 }
 
 type FnParam struct {
-	Name string
-	Type Tc
+	Name    string
+	Type    Tc
+	Default any // folded value of the default of an optional parameter, nil when it is required
 }
 
 type Fn struct {
 	DataType      builtins.TC_TYPE
+	TypeParams    []TypeParam // set on generic functions, inferred from the arguments of a call
 	Params        []FnParam
 	Returns       Tc
 	FunctionScope TypeEnvironment
@@ -136,6 +160,8 @@ type Struct struct {
 	DataType    builtins.TC_TYPE
 	StructName  string
 	StructScope TypeEnvironment
+	Generic     string // the generic type this struct is an instance of, like 'Box' for 'Box<i32>'
+	TypeArgs    []Tc
 }
 
 func (t Struct) DType() builtins.TC_TYPE {
@@ -180,6 +206,16 @@ func (t ReturnType) DType() builtins.TC_TYPE {
 	return t.DataType
 }
 
+// LoopJump is the value of a 'break' or a 'continue' statement. Like a return, nothing after
+// it runs, but it does not return from the function.
+type LoopJump struct {
+	DataType builtins.TC_TYPE
+}
+
+func (t LoopJump) DType() builtins.TC_TYPE {
+	return t.DataType
+}
+
 type InterfaceMethodType struct {
 	Name   string
 	Method Fn
@@ -189,6 +225,8 @@ type Interface struct {
 	DataType      builtins.TC_TYPE
 	InterfaceName string
 	Methods       []InterfaceMethodType
+	Generic       string // the generic type this interface is an instance of
+	TypeArgs      []Tc
 }
 
 func (t Interface) DType() builtins.TC_TYPE {
@@ -203,3 +241,95 @@ type Maybe struct {
 func (t Maybe) DType() builtins.TC_TYPE {
 	return t.DataType
 }
+
+// Null is the type of the 'null' literal, which fits any Maybe type.
+type Null struct {
+	DataType builtins.TC_TYPE
+}
+
+func (t Null) DType() builtins.TC_TYPE {
+	return t.DataType
+}
+
+// Result is the type 'T!E', of the values of T and the errors of type E.
+type Result struct {
+	DataType builtins.TC_TYPE
+	OkType   Tc
+	ErrType  Tc
+}
+
+func (t Result) DType() builtins.TC_TYPE {
+	return t.DataType
+}
+
+// Tuple is the type '(T, U, ...)', of the values holding a value of each type, in order.
+type Tuple struct {
+	DataType builtins.TC_TYPE
+	Elements []Tc
+}
+
+func (t Tuple) DType() builtins.TC_TYPE {
+	return t.DataType
+}
+
+// Err is the type of an 'err(...)' expression, which fits any Result with its error type.
+type Err struct {
+	DataType builtins.TC_TYPE
+	ErrType  Tc
+}
+
+func (t Err) DType() builtins.TC_TYPE {
+	return t.DataType
+}
+
+// Enum is a closed set of variants. A variant with fields is created by calling it with
+// their values, like 'Shape.Circle(1.0)'.
+type Enum struct {
+	DataType builtins.TC_TYPE
+	EnumName string
+	Variants []EnumVariant
+}
+
+func (t Enum) DType() builtins.TC_TYPE {
+	return t.DataType
+}
+
+// variant finds a variant of the enum by its name.
+func (t Enum) variant(name string) (EnumVariant, bool) {
+	for _, variant := range t.Variants {
+		if variant.Name == name {
+			return variant, true
+		}
+	}
+	return EnumVariant{}, false
+}
+
+type EnumVariant struct {
+	Name   string
+	Fields []FnParam
+}
+
+// TypeParam is a type parameter of a generic function or type. Its values can only be
+// used through the methods of its constraint.
+type TypeParam struct {
+	DataType   builtins.TC_TYPE
+	Name       string
+	Constraint Tc // an Interface, or nil when any type is accepted
+}
+
+func (t TypeParam) DType() builtins.TC_TYPE {
+	return t.DataType
+}
+
+// Generic is a generic type declaration. It is not a type itself, its instances like
+// 'Box<i32>' are.
+type Generic struct {
+	DataType   builtins.TC_TYPE
+	TypeName   string
+	TypeParams []TypeParam
+	TypeDef    Tc
+}
+
+func (t Generic) DType() builtins.TC_TYPE {
+	return t.DataType
+}
@@ -43,6 +43,34 @@ func NewVoid() Void {
 	return Void{DataType: VOID_TYPE}
 }
 
+func NewInvalid() Invalid {
+	return Invalid{DataType: INVALID_TYPE}
+}
+
+func NewMaybe(maybeType Tc) Maybe {
+	return Maybe{DataType: MAYBE_TYPE, MaybeType: maybeType}
+}
+
+func NewNull() Null {
+	return Null{DataType: NULL_TYPE}
+}
+
+func NewResult(okType Tc, errType Tc) Result {
+	return Result{DataType: RESULT_TYPE, OkType: okType, ErrType: errType}
+}
+
+func NewTuple(elements []Tc) Tuple {
+	return Tuple{DataType: TUPLE_TYPE, Elements: elements}
+}
+
+func NewErr(errType Tc) Err {
+	return Err{DataType: ERR_TYPE, ErrType: errType}
+}
+
 func NewMap(keyType Tc, valueType Tc) Map {
 	return Map{DataType: MAP_TYPE, KeyType: keyType, ValueType: valueType}
 }
+
+func NewFn(params []FnParam, returns Tc, env *TypeEnvironment) Fn {
+	return Fn{DataType: FUNCTION_TYPE, Params: params, Returns: returns, FunctionScope: *NewTypeENV(env, FUNCTION_SCOPE, fmt.Sprintf("_FN_%s", RandStringRunes(10)), env.filePath)}
+}
@@ -5,9 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"strings"
 	"time"
 
 	//Walrus packages
+	"walrus/compiler/colors"
 	"walrus/compiler/internal/ast"
 	"walrus/compiler/internal/builtins"
 	"walrus/compiler/internal/utils"
@@ -48,6 +50,9 @@ func checkLValue(node ast.Node, env *TypeEnvironment) error {
 	case ast.Indexable:
 		return checkLValue(t.Container, env)
 	case ast.StructPropertyAccessExpr:
+		if t.Optional {
+			return errors.New("a property accessed with '?.'")
+		}
 		return checkLValue(t.Object, env)
 	default:
 		return fmt.Errorf("invalid lvalue")
@@ -72,6 +77,12 @@ func isIntType(operand Tc) bool {
 	}
 }
 
+// isInvalid reports whether a type is the type of an expression whose error was reported.
+func isInvalid(operand Tc) bool {
+	_, ok := operand.(Invalid)
+	return ok
+}
+
 // evaluateTypeName evaluates the given DataType and returns a corresponding ValueTypeInterface.
 // It handles different types of DataType such as ArrayType, FunctionType, and others.
 //
@@ -101,6 +112,12 @@ func evaluateTypeName(dtype ast.DataType, env *TypeEnvironment) Tc {
 		return evalStruct(t, env)
 	case ast.RangeType:
 		return evalRange(t, env)
+	case ast.MaybeType:
+		return evalMaybe(t, env)
+	case ast.ResultType:
+		return evalResult(t, env)
+	case ast.TupleType:
+		return evalTuple(t, env)
 	case nil:
 		return NewVoid()
 	default:
@@ -125,6 +142,41 @@ func evalRange(r ast.RangeType, env *TypeEnvironment) Range {
 	}
 }
 
+// evalMaybe evaluates 'T?'. A type can only be made nullable once.
+func evalMaybe(m ast.MaybeType, env *TypeEnvironment) Tc {
+
+	inner := evaluateTypeName(m.MaybeType, env)
+
+	if _, ok := unwrapType(inner).(Maybe); ok {
+		report.Add(env.filePath, m.StartPos().Line, m.EndPos().Line, m.StartPos().Column, m.EndPos().Column, fmt.Sprintf("type '%s' is already nullable", tcToString(inner))).SetLevel(report.NORMAL_ERROR)
+		return inner
+	}
+	if _, ok := unwrapType(inner).(Void); ok {
+		report.Add(env.filePath, m.StartPos().Line, m.EndPos().Line, m.StartPos().Column, m.EndPos().Column, "type 'void' cannot be nullable").SetLevel(report.NORMAL_ERROR)
+	}
+
+	return NewMaybe(inner)
+}
+
+// evalResult evaluates 'T!E'. A result cannot hold another result, and 'void!E' is the
+// result of a function that gives no value but may fail.
+func evalResult(r ast.ResultType, env *TypeEnvironment) Tc {
+
+	okType := evaluateTypeName(r.OkType, env)
+	errType := evaluateTypeName(r.ErrType, env)
+
+	for _, inner := range []Tc{okType, errType} {
+		if _, ok := unwrapType(inner).(Result); ok {
+			report.Add(env.filePath, r.StartPos().Line, r.EndPos().Line, r.StartPos().Column, r.EndPos().Column, fmt.Sprintf("result type '%s' cannot be part of another result type", tcToString(inner))).SetLevel(report.NORMAL_ERROR)
+		}
+	}
+	if _, ok := unwrapType(errType).(Void); ok {
+		report.Add(env.filePath, r.ErrType.StartPos().Line, r.ErrType.EndPos().Line, r.ErrType.StartPos().Column, r.ErrType.EndPos().Column, "the error type of a result cannot be void").SetLevel(report.NORMAL_ERROR)
+	}
+
+	return NewResult(okType, errType)
+}
+
 func evalStruct(s ast.StructType, env *TypeEnvironment) Struct {
 
 	name := "struct { "
@@ -158,6 +210,7 @@ func evalDefaultType(defaultType ast.DataType, env *TypeEnvironment) Tc {
 
 // evalUD evaluates a user-defined type and returns a type-checked user-defined type.
 // It takes an analyzed user-defined type of type ast.UserDefinedType and a type environment.
+// The name may be a type parameter in scope, or a generic type given its type arguments.
 // It returns a type-checked user-defined type (Tc) with the evaluated user-defined type.
 //
 // Parameters:
@@ -168,10 +221,23 @@ func evalDefaultType(defaultType ast.DataType, env *TypeEnvironment) Tc {
 // - Tc: a type-checked user-defined type with the evaluated user-defined type.
 func evalUD(analyzedUD ast.UserDefinedType, env *TypeEnvironment) Tc {
 	typename := analyzedUD.AliasName
+	location := ast.Location{Start: analyzedUD.StartPos(), End: analyzedUD.EndPos()}
+	if param, ok := env.resolveTypeParam(typename); ok {
+		if len(analyzedUD.TypeArgs) > 0 {
+			report.Add(env.filePath, location.Start.Line, location.End.Line, location.Start.Column, location.End.Column, fmt.Sprintf("type parameter '%s' cannot take type arguments", typename)).SetLevel(report.NORMAL_ERROR)
+		}
+		return param
+	}
 	val, err := getTypeDefinition(typename) // need to get the most deep type
 	if err != nil || val == nil {
 		report.Add(env.filePath, analyzedUD.StartPos().Line, analyzedUD.EndPos().Line, analyzedUD.StartPos().Column, analyzedUD.EndPos().Column, err.Error()).SetLevel(report.CRITICAL_ERROR)
 	}
+	if generic, ok := val.(Generic); ok {
+		return instantiateType(generic, analyzedUD.TypeArgs, location, env)
+	}
+	if len(analyzedUD.TypeArgs) > 0 {
+		report.Add(env.filePath, location.Start.Line, location.End.Line, location.Start.Column, location.End.Column, fmt.Sprintf("type '%s' is not generic", typename)).SetLevel(report.NORMAL_ERROR)
+	}
 	return val
 }
 
@@ -295,9 +361,53 @@ func validateTypeCompatibility(expectedType, providedType Tc) error {
 	unwrappedExpected := unwrapType(expectedType)
 	unwrappedProvided := unwrapType(providedType)
 
-	switch unwrappedExpected.(type) {
+	if isInvalid(unwrappedExpected) || isInvalid(unwrappedProvided) {
+		return nil
+	}
+
+	switch t := unwrappedExpected.(type) {
 	case Interface:
 		return checkMethodsImplementations(unwrappedProvided, unwrappedExpected)
+	case Maybe:
+		// a T? takes null, another T? and any value a T takes
+		switch p := unwrappedProvided.(type) {
+		case Null:
+			return nil
+		case Maybe:
+			if _, ok := unwrapType(t.MaybeType).(Interface); !ok {
+				break
+			}
+			return validateTypeCompatibility(t.MaybeType, p.MaybeType)
+		default:
+			if err := validateTypeCompatibility(t.MaybeType, unwrappedProvided); err == nil {
+				return nil
+			}
+		}
+	case Result:
+		// a T!E takes an error of type E, another T!E and any value a T takes
+		switch p := unwrappedProvided.(type) {
+		case Err:
+			if err := validateTypeCompatibility(t.ErrType, p.ErrType); err == nil {
+				return nil
+			}
+		case Result:
+			// compared by name below
+		default:
+			if err := validateTypeCompatibility(t.OkType, unwrappedProvided); err == nil {
+				return nil
+			}
+		}
+	case Tuple:
+		// a tuple takes a tuple of as many values, each taken by its element
+		if p, ok := unwrappedProvided.(Tuple); ok && len(p.Elements) == len(t.Elements) {
+			compatible := true
+			for i, element := range t.Elements {
+				compatible = compatible && validateTypeCompatibility(element, p.Elements[i]) == nil
+			}
+			if compatible {
+				return nil
+			}
+		}
 	}
 
 	expectedStr := tcToString(unwrappedExpected)
@@ -311,8 +421,8 @@ func validateTypeCompatibility(expectedType, providedType Tc) error {
 }
 
 // tcToString converts a type-checker (Tc) value to its string representation.
-// It handles various types including Array, Struct, Interface, Fn, Map, Maybe,
-// and UserDefined. For each type, it returns a formatted string that represents
+// It handles various types including Array, Struct, Interface, Fn, Map, Maybe, Result,
+// type parameters and UserDefined. For each type, it returns a formatted string that represents
 // the type. If the type is nil, it returns "void". For other types, it returns
 // the string representation of the type's DType.
 //
@@ -334,11 +444,27 @@ func tcToString(val Tc) string {
 	case Map:
 		return fmt.Sprintf("map[%s]%s", tcToString(t.KeyType), tcToString(t.ValueType))
 	case Maybe:
-		return fmt.Sprintf("maybe{%s}", tcToString(t.MaybeType))
+		return tcToString(t.MaybeType) + "?"
+	case Result:
+		return tcToString(t.OkType) + "!" + tcToString(t.ErrType)
+	case Err:
+		return "err(" + tcToString(t.ErrType) + ")"
+	case Tuple:
+		elements := make([]string, len(t.Elements))
+		for i, element := range t.Elements {
+			elements[i] = tcToString(element)
+		}
+		return "(" + strings.Join(elements, ", ") + ")"
 	case UserDefined:
 		return tcToString(unwrapType(t.TypeDef))
 	case Range:
 		return fmt.Sprintf("%s..%s", tcToString(t.RangeStart), tcToString(t.RangeEnd))
+	case Enum:
+		return t.EnumName
+	case TypeParam:
+		return t.Name
+	case Generic:
+		return t.TypeName
 	default:
 		if t == nil {
 			return "void"
@@ -350,6 +476,7 @@ func tcToString(val Tc) string {
 // functionSignatureString generates a string representation of a function's signature.
 // It takes a function `fn` of type `Fn` as input and returns a string that describes
 // the function's parameters and return type in the format: "fn(param1: type1, param2: type2) -> returnType".
+// A generic function lists its type parameters first, like "fn<T>(value: T) -> T".
 // If the function has no return type, the return type part is omitted.
 //
 // Parameters:
@@ -371,7 +498,18 @@ func functionSignatureString(fn Fn) string {
 	if ReturnStr != "" {
 		ReturnStr = " -> " + ReturnStr
 	}
-	return fmt.Sprintf("fn(%s)%s", ParamStrs, ReturnStr)
+	TypeParamStrs := ""
+	if len(fn.TypeParams) > 0 {
+		names := make([]string, len(fn.TypeParams))
+		for i, param := range fn.TypeParams {
+			names[i] = param.Name
+			if param.Constraint != nil {
+				names[i] += ": " + tcToString(param.Constraint)
+			}
+		}
+		TypeParamStrs = "<" + strings.Join(names, ", ") + ">"
+	}
+	return fmt.Sprintf("fn%s(%s)%s", TypeParamStrs, ParamStrs, ReturnStr)
 }
 
 // checkMethodsImplementations checks if the provided type `src` implements the interface `dest`.
@@ -402,6 +540,13 @@ func checkMethodsImplementations(src, dest Tc) error {
 		handleStructDest(t, interfaceType, &errs)
 	case Interface:
 		handleInterfaceDest(t, interfaceType, &errs)
+	case TypeParam:
+		// a type parameter implements what its constraint implements
+		constraint, ok := t.Constraint.(Interface)
+		if !ok {
+			return fmt.Errorf("type parameter '%s' must be constrained by an interface implementing '%s'", t.Name, expectedTypeName)
+		}
+		handleInterfaceDest(constraint, interfaceType, &errs)
 	default:
 		return fmt.Errorf("type '%s' must be a struct or interface", tcToString(src))
 	}
@@ -484,7 +629,7 @@ func handleInterfaceDest(src Interface, destInterface Interface, errs *[]error)
 		if method, found := utils.Some(src.Methods, func(m InterfaceMethodType) bool {
 			return m.Name == interfaceMethod.Name
 		}); found {
-			fmt.Printf("checking method %s\n", method.Name)
+			fmt.Fprintf(colors.Output(), "checking method %s\n", method.Name)
 			//check parameters
 			for i, param := range interfaceMethod.Method.Params {
 				if err := validateTypeCompatibility(param.Type, method.Method.Params[i].Type); err != nil {
@@ -499,7 +644,7 @@ func handleInterfaceDest(src Interface, destInterface Interface, errs *[]error)
 
 		} else {
 			//method not found
-			fmt.Printf("method %s not found\n", interfaceMethod.Name)
+			fmt.Fprintf(colors.Output(), "method %s not found\n", interfaceMethod.Name)
 			*errs = append(*errs, fmt.Errorf("missing method '%s' on '%s'", interfaceMethod.Name, src.InterfaceName))
 		}
 	}
@@ -30,7 +30,16 @@ func checkVariableAssignment(node ast.VarAssignmentExpr, env *TypeEnvironment) T
 	}
 
 	expectedType := parseNodeValue(Assignee, env)
-	providedType := parseNodeValue(valueToAssign, env)
+	providedType := checkValueAs(valueToAssign, expectedType, env)
+
+	if isTupleElement(Assignee, env) {
+		report.Add(env.filePath, Assignee.StartPos().Line, Assignee.EndPos().Line, Assignee.StartPos().Column, Assignee.EndPos().Column, "cannot assign to an element of a tuple").Hint("tuples cannot be changed, assign a new tuple instead").SetLevel(report.NORMAL_ERROR)
+	}
+
+	if op, ok := bitwiseAssignment(node.Operator); ok {
+		// the count of a shift can be any integer, so these follow the rules of the operator
+		return checkBitwise(op, Assignee, valueToAssign, expectedType, providedType, env)
+	}
 
 	err := validateTypeCompatibility(expectedType, providedType)
 	if err != nil {
@@ -51,7 +60,8 @@ func checkVariableAssignment(node ast.VarAssignmentExpr, env *TypeEnvironment) T
 // - ValueTypeInterface: The type of the declared variable.
 //
 // The function performs the following steps:
-//  1. Retrieves the variable to be declared from the AST node.
+//  1. Retrieves the variable to be declared from the AST node. A variable unpacking its
+//     value, like 'let (q, r) := ...', is checked by checkDestructuring instead.
 //  2. Prints a message indicating the variable being declared.
 //  3. Determines the expected type of the variable, either from an explicit type
 //     specified in the declaration or by inferring it from the assigned value.
@@ -66,18 +76,43 @@ func checkVariableDeclaration(node ast.VarDeclStmt, env *TypeEnvironment) Tc {
 
 	for _, varToDecl := range varsToDecl {
 
+		if varToDecl.Destructure != nil {
+			checkDestructuring(varToDecl, node, env)
+			continue
+		}
+
 		colors.BLUE.Print("Declaring variable ")
 		colors.RED.Println(varToDecl.Identifier.Name)
 
 		expectedTypeInterface := parseExpectedType(varToDecl.ExplicitType, varToDecl.Value, env)
 
+		// null alone does not tell which nullable type the variable has
+		if _, ok := expectedTypeInterface.(Null); ok {
+			report.Add(env.filePath, varToDecl.Value.StartPos().Line, varToDecl.Value.EndPos().Line, varToDecl.Value.StartPos().Column, varToDecl.Value.EndPos().Column, fmt.Sprintf("cannot infer the type of '%s' from null", varToDecl.Identifier.Name)).Hint(fmt.Sprintf("write the type, like 'let %s: i32? = null'", varToDecl.Identifier.Name)).SetLevel(report.NORMAL_ERROR)
+		}
+
+		// nor does an error alone tell the type of the values of the result
+		if errType, ok := expectedTypeInterface.(Err); ok {
+			report.Add(env.filePath, varToDecl.Value.StartPos().Line, varToDecl.Value.EndPos().Line, varToDecl.Value.StartPos().Column, varToDecl.Value.EndPos().Column, fmt.Sprintf("cannot infer the type of '%s' from an error", varToDecl.Identifier.Name)).Hint(fmt.Sprintf("write the type, like 'let %s: i32!%s = err(...)'", varToDecl.Identifier.Name, tcToString(errType.ErrType))).SetLevel(report.NORMAL_ERROR)
+		}
+
 		// do not allow void type
 		if _, ok := expectedTypeInterface.(Void); ok {
 			report.Add(env.filePath, varToDecl.Identifier.StartPos().Line, varToDecl.Identifier.EndPos().Line, varToDecl.Identifier.StartPos().Column, varToDecl.Identifier.EndPos().Column, "cannot declare variable of type void\n - a variable must be a non void type").SetLevel(report.CRITICAL_ERROR)
 		}
 
+		// a type parameter has no zero value to start with
+		if param, ok := expectedTypeInterface.(TypeParam); ok && varToDecl.Value == nil {
+			report.Add(env.filePath, varToDecl.Identifier.StartPos().Line, varToDecl.Identifier.EndPos().Line, varToDecl.Identifier.StartPos().Column, varToDecl.Identifier.EndPos().Column, fmt.Sprintf("variable '%s' of type parameter '%s' must have a value", varToDecl.Identifier.Name, param.Name)).SetLevel(report.NORMAL_ERROR)
+		}
+
+		// nor has an enum, no variant is its zero value
+		if enum, ok := unwrapType(expectedTypeInterface).(Enum); ok && varToDecl.Value == nil {
+			report.Add(env.filePath, varToDecl.Identifier.StartPos().Line, varToDecl.Identifier.EndPos().Line, varToDecl.Identifier.StartPos().Column, varToDecl.Identifier.EndPos().Column, fmt.Sprintf("variable '%s' of enum type '%s' must have a value", varToDecl.Identifier.Name, enum.EnumName)).SetLevel(report.NORMAL_ERROR)
+		}
+
 		if varToDecl.Value != nil && varToDecl.ExplicitType != nil {
-			providedValue := parseNodeValue(varToDecl.Value, env)
+			providedValue := checkValueAs(varToDecl.Value, expectedTypeInterface, env)
 			err := validateTypeCompatibility(expectedTypeInterface, providedValue)
 			if err != nil {
 				report.Add(env.filePath, varToDecl.Value.StartPos().Line, varToDecl.Value.EndPos().Line, varToDecl.Value.StartPos().Column, varToDecl.Value.EndPos().Column, fmt.Sprintf("error declaring variable '%s'. %s", varToDecl.Identifier.Name, err.Error())).SetLevel(report.NORMAL_ERROR)
@@ -88,16 +123,29 @@ func checkVariableDeclaration(node ast.VarDeclStmt, env *TypeEnvironment) Tc {
 		if err != nil {
 			report.Add(env.filePath, varToDecl.Identifier.StartPos().Line, varToDecl.Identifier.EndPos().Line, varToDecl.Identifier.StartPos().Column, varToDecl.Identifier.EndPos().Column, err.Error()).SetLevel(report.CRITICAL_ERROR)
 		}
+		env.declaredAt(varToDecl.Identifier.Name, varToDecl.Identifier.Location)
+
+		// a constant keeps its value when it is known while checking
+		if node.IsConst && varToDecl.Value != nil {
+			reportConstantError(varToDecl.Value, env)
+			if value, ok := env.info.constantOf(varToDecl.Value); ok {
+				env.values[varToDecl.Identifier.Name] = value
+			}
+		}
+
+		if node.IsExported && canExport(node, env) {
+			exportValue(varToDecl.Identifier.Name, env)
+		}
 
 		if node.IsConst {
 			colors.GREEN.Print("Declared constant variable ")
 			colors.RED.Print(varToDecl.Identifier.Name)
-			fmt.Print(" of type ")
+			fmt.Fprint(colors.Output(), " of type ")
 			colors.PURPLE.Println(tcToString(expectedTypeInterface))
 		} else {
 			colors.GREEN.Print("Declared variable ")
 			colors.RED.Print(varToDecl.Identifier.Name)
-			fmt.Print(" of type ")
+			fmt.Fprint(colors.Output(), " of type ")
 			colors.PURPLE.Println(tcToString(expectedTypeInterface))
 		}
 	}
@@ -109,7 +157,7 @@ func parseExpectedType(explicitType ast.DataType, value ast.Node, env *TypeEnvir
 
 	if explicitType != nil {
 		expectedTypeInterface = evaluateTypeName(explicitType, env)
-		fmt.Print("Explicit type: ")
+		fmt.Fprint(colors.Output(), "Explicit type: ")
 		colors.PURPLE.Println(tcToString(expectedTypeInterface))
 	} else {
 		expectedTypeInterface = parseNodeValue(value, env)
@@ -0,0 +1,374 @@
+package values
+
+import (
+	//Standard packages
+	"fmt"
+	"math"
+	"math/big"
+
+	//Walrus packages
+	"walrus/compiler/internal/builtins"
+	"walrus/compiler/internal/lexer"
+)
+
+// The operations in this file work on values only, so every backend that uses these values
+// (the interpreter and the bytecode vm) behaves the same. Failed operations return an error
+// which the caller reports as a runtime error at its own location.
+
+// WrapInt truncates an integer to the given bit size, the way fixed size integers overflow.
+func WrapInt(value *big.Int, bitSize uint8, isSigned bool) *big.Int {
+	modulus := new(big.Int).Lsh(big.NewInt(1), uint(bitSize))
+	wrapped := new(big.Int).Mod(value, modulus)
+	if isSigned && wrapped.Cmp(new(big.Int).Rsh(modulus, 1)) >= 0 {
+		wrapped.Sub(wrapped, modulus)
+	}
+	return wrapped
+}
+
+// ToInt converts a numeric value to an integer of the given size. Floats are truncated.
+func ToInt(value Value, bitSize uint8, isSigned bool) (Int, error) {
+	switch t := value.(type) {
+	case Int:
+		return Int{Value: WrapInt(t.Value, bitSize, isSigned), BitSize: bitSize, IsSigned: isSigned}, nil
+	case Float:
+		if math.IsNaN(t.Value) || math.IsInf(t.Value, 0) {
+			return Int{}, fmt.Errorf("cannot convert %s to an integer", t.String())
+		}
+		truncated, _ := big.NewFloat(math.Trunc(t.Value)).Int(nil)
+		return Int{Value: WrapInt(truncated, bitSize, isSigned), BitSize: bitSize, IsSigned: isSigned}, nil
+	default:
+		return Int{}, fmt.Errorf("'%s' is not a number", TypeName(value))
+	}
+}
+
+// ToFloat converts a numeric value to a float of the given size.
+func ToFloat(value Value, bitSize uint8) (Float, error) {
+	switch t := value.(type) {
+	case Int:
+		f, _ := new(big.Float).SetInt(t.Value).Float64()
+		return NewFloat(f, bitSize), nil
+	case Float:
+		return NewFloat(t.Value, bitSize), nil
+	default:
+		return Float{}, fmt.Errorf("'%s' is not a number", TypeName(value))
+	}
+}
+
+func isNumber(value Value) bool {
+	switch value.(type) {
+	case Int, Float:
+		return true
+	default:
+		return false
+	}
+}
+
+// BinaryOperation applies a binary operator. Like the typechecker, the result of an
+// arithmetic operation has the type of the left operand.
+func BinaryOperation(op builtins.TOKEN_KIND, left, right Value) (Value, error) {
+	switch op {
+	case lexer.PLUS_TOKEN, lexer.MINUS_TOKEN, lexer.MUL_TOKEN, lexer.DIV_TOKEN, lexer.MOD_TOKEN, lexer.EXP_TOKEN:
+		if str, ok := left.(Str); ok && op == lexer.PLUS_TOKEN {
+			return NewStr(str.Value + right.String()), nil
+		}
+		return arithmetic(op, left, right)
+	case lexer.DOUBLE_EQUAL_TOKEN:
+		return NewBool(Equals(left, right)), nil
+	case lexer.NOT_EQUAL_TOKEN:
+		return NewBool(!Equals(left, right)), nil
+	case lexer.LESS_TOKEN, lexer.LESS_EQUAL_TOKEN, lexer.GREATER_TOKEN, lexer.GREATER_EQUAL_TOKEN:
+		cmp, err := Compare(left, right)
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case lexer.LESS_TOKEN:
+			return NewBool(cmp < 0), nil
+		case lexer.LESS_EQUAL_TOKEN:
+			return NewBool(cmp <= 0), nil
+		case lexer.GREATER_TOKEN:
+			return NewBool(cmp > 0), nil
+		default:
+			return NewBool(cmp >= 0), nil
+		}
+	case lexer.BIT_AND_TOKEN, lexer.BIT_OR_TOKEN, lexer.BIT_XOR_TOKEN, lexer.SHIFT_LEFT_TOKEN, lexer.SHIFT_RIGHT_TOKEN:
+		return bitwise(op, left, right)
+	case lexer.AND_TOKEN, lexer.OR_TOKEN:
+		// the interpreter and the vm skip the right operand when the left one decides the result
+		l, lok := left.(Bool)
+		r, rok := right.(Bool)
+		if !lok || !rok {
+			return nil, fmt.Errorf("operator '%s' needs boolean operands", op)
+		}
+		if op == lexer.AND_TOKEN {
+			return NewBool(l.Value && r.Value), nil
+		}
+		return NewBool(l.Value || r.Value), nil
+	default:
+		return nil, fmt.Errorf("invalid operator '%s'", op)
+	}
+}
+
+// bitwise applies the bitwise and shift operators to integers. A shift by the bit size of
+// the integer or more shifts every bit out, keeping the sign of negative numbers shifted right.
+func bitwise(op builtins.TOKEN_KIND, left, right Value) (Value, error) {
+	l, lok := left.(Int)
+	r, rok := right.(Int)
+	if !lok || !rok {
+		return nil, fmt.Errorf("operator '%s' needs integer operands", op)
+	}
+
+	result := new(big.Int)
+	switch op {
+	case lexer.BIT_AND_TOKEN:
+		result.And(l.Value, r.Value)
+	case lexer.BIT_OR_TOKEN:
+		result.Or(l.Value, r.Value)
+	case lexer.BIT_XOR_TOKEN:
+		result.Xor(l.Value, r.Value)
+	default:
+		if r.Value.Sign() < 0 {
+			return nil, fmt.Errorf("negative shift count")
+		}
+		count := uint(l.BitSize)
+		if r.Value.Cmp(big.NewInt(int64(l.BitSize))) < 0 {
+			count = uint(r.Value.Uint64())
+		}
+		if op == lexer.SHIFT_LEFT_TOKEN {
+			result.Lsh(l.Value, count)
+		} else {
+			result.Rsh(l.Value, count)
+		}
+	}
+	return Int{Value: WrapInt(result, l.BitSize, l.IsSigned), BitSize: l.BitSize, IsSigned: l.IsSigned}, nil
+}
+
+func arithmetic(op builtins.TOKEN_KIND, left, right Value) (Value, error) {
+	if r, ok := right.(Float); ok {
+		if _, ok := left.(Int); ok {
+			// an integer and a float give a float
+			l, _ := ToFloat(left, r.BitSize)
+			return arithmetic(op, l, right)
+		}
+	}
+	switch l := left.(type) {
+	case Int:
+		r, err := ToInt(right, l.BitSize, l.IsSigned)
+		if err != nil {
+			return nil, err
+		}
+		result := new(big.Int)
+		switch op {
+		case lexer.PLUS_TOKEN:
+			result.Add(l.Value, r.Value)
+		case lexer.MINUS_TOKEN:
+			result.Sub(l.Value, r.Value)
+		case lexer.MUL_TOKEN:
+			result.Mul(l.Value, r.Value)
+		case lexer.DIV_TOKEN, lexer.MOD_TOKEN:
+			if r.Value.Sign() == 0 {
+				return nil, fmt.Errorf("integer division by zero")
+			}
+			if op == lexer.DIV_TOKEN {
+				result.Quo(l.Value, r.Value)
+			} else {
+				result.Rem(l.Value, r.Value)
+			}
+		case lexer.EXP_TOKEN:
+			if r.Value.Sign() < 0 {
+				return nil, fmt.Errorf("negative integer exponent")
+			}
+			result.Exp(l.Value, r.Value, new(big.Int).Lsh(big.NewInt(1), uint(l.BitSize)))
+		}
+		return Int{Value: WrapInt(result, l.BitSize, l.IsSigned), BitSize: l.BitSize, IsSigned: l.IsSigned}, nil
+	case Float:
+		r, err := ToFloat(right, l.BitSize)
+		if err != nil {
+			return nil, err
+		}
+		var result float64
+		switch op {
+		case lexer.PLUS_TOKEN:
+			result = l.Value + r.Value
+		case lexer.MINUS_TOKEN:
+			result = l.Value - r.Value
+		case lexer.MUL_TOKEN:
+			result = l.Value * r.Value
+		case lexer.DIV_TOKEN:
+			result = l.Value / r.Value
+		case lexer.MOD_TOKEN:
+			result = math.Mod(l.Value, r.Value)
+		case lexer.EXP_TOKEN:
+			result = math.Pow(l.Value, r.Value)
+		}
+		return NewFloat(result, l.BitSize), nil
+	default:
+		return nil, fmt.Errorf("cannot perform numeric operation on type '%s'", TypeName(left))
+	}
+}
+
+// Equals compares two values. Numbers compare by value, strings and booleans by content,
+// enum values by variant and fields, errors by their values, tuples by their values, and arrays, maps, structs and functions by identity.
+func Equals(left, right Value) bool {
+	if isNumber(left) && isNumber(right) {
+		cmp, _ := Compare(left, right)
+		return cmp == 0
+	}
+	if l, ok := left.(Range); ok {
+		r, ok := right.(Range)
+		return ok && Equals(l.Start, r.Start) && Equals(l.End, r.End)
+	}
+	if l, ok := left.(Err); ok {
+		r, ok := right.(Err)
+		return ok && Equals(l.Value, r.Value)
+	}
+	if l, ok := left.(*Tuple); ok {
+		r, ok := right.(*Tuple)
+		if !ok || len(l.Values) != len(r.Values) {
+			return false
+		}
+		for i := range l.Values {
+			if !Equals(l.Values[i], r.Values[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	if l, ok := left.(*Variant); ok {
+		r, ok := right.(*Variant)
+		if !ok || l.EnumName != r.EnumName || l.Name != r.Name || len(l.Fields) != len(r.Fields) {
+			return false
+		}
+		for i := range l.Fields {
+			if !Equals(l.Fields[i], r.Fields[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return left == right
+}
+
+// Compare orders two numbers. It returns -1, 0 or 1 like big.Int.Cmp.
+func Compare(left, right Value) (int, error) {
+	l, lok := left.(Int)
+	r, rok := right.(Int)
+	if lok && rok {
+		return l.Value.Cmp(r.Value), nil
+	}
+	lf, err := ToFloat(left, 64)
+	if err != nil {
+		return 0, err
+	}
+	rf, err := ToFloat(right, 64)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case lf.Value < rf.Value:
+		return -1, nil
+	case lf.Value > rf.Value:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// UnaryOperation applies the '-', '!' and '~' prefix operators.
+func UnaryOperation(op builtins.TOKEN_KIND, value Value) (Value, error) {
+	switch t := value.(type) {
+	case Int:
+		switch op {
+		case lexer.MINUS_TOKEN:
+			return Int{Value: WrapInt(new(big.Int).Neg(t.Value), t.BitSize, t.IsSigned), BitSize: t.BitSize, IsSigned: t.IsSigned}, nil
+		case lexer.BIT_NOT_TOKEN:
+			return Int{Value: WrapInt(new(big.Int).Not(t.Value), t.BitSize, t.IsSigned), BitSize: t.BitSize, IsSigned: t.IsSigned}, nil
+		}
+	case Float:
+		if op == lexer.MINUS_TOKEN {
+			return NewFloat(-t.Value, t.BitSize), nil
+		}
+	case Bool:
+		if op == lexer.NOT_TOKEN {
+			return NewBool(!t.Value), nil
+		}
+	}
+	return nil, fmt.Errorf("invalid unary operation '%s' on type '%s'", op, TypeName(value))
+}
+
+// CastStruct copies the given fields of a struct into a new struct named structName.
+func CastStruct(value Value, structName string, fields []string) (Value, error) {
+	src, ok := value.(*Struct)
+	if !ok {
+		return nil, fmt.Errorf("cannot cast '%s' to a struct", TypeName(value))
+	}
+	result := NewStruct(structName)
+	for _, field := range fields {
+		result.SetField(field, src.Fields[field])
+	}
+	return result, nil
+}
+
+// arrayIndex converts an index value to a position in a container of the given length.
+func arrayIndex(index Value, length int) (int, error) {
+	i, ok := index.(Int)
+	if !ok {
+		return 0, fmt.Errorf("cannot use type '%s' as an index", TypeName(index))
+	}
+	if !i.Value.IsInt64() || i.Value.Int64() < 0 || i.Value.Int64() >= int64(length) {
+		return 0, fmt.Errorf("index %s out of range with length %d", i.Value.String(), length)
+	}
+	return int(i.Value.Int64()), nil
+}
+
+// Index reads an array element, a byte of a string, a map value or a tuple element.
+func Index(container, index Value) (Value, error) {
+	switch t := container.(type) {
+	case *Tuple:
+		i, err := arrayIndex(index, len(t.Values))
+		if err != nil {
+			return nil, err
+		}
+		return t.Values[i], nil
+	case *Array:
+		i, err := arrayIndex(index, len(t.Values))
+		if err != nil {
+			return nil, err
+		}
+		return t.Values[i], nil
+	case Str:
+		i, err := arrayIndex(index, len(t.Value))
+		if err != nil {
+			return nil, err
+		}
+		return NewInt(int64(t.Value[i]), 8, false), nil
+	case *Map:
+		value, ok := t.Get(index)
+		if !ok {
+			return nil, fmt.Errorf("key %s not found in map", quoted(index))
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("cannot access index of type %s", TypeName(container))
+	}
+}
+
+// SetIndex stores a value in an array element or a map entry. Strings are immutable.
+func SetIndex(container, index, value Value) error {
+	switch t := container.(type) {
+	case *Array:
+		i, err := arrayIndex(index, len(t.Values))
+		if err != nil {
+			return err
+		}
+		t.Values[i] = value
+		return nil
+	case *Map:
+		t.Set(index, value)
+		return nil
+	case Str:
+		return fmt.Errorf("cannot assign to an index of a string")
+	default:
+		return fmt.Errorf("cannot access index of type %s", TypeName(container))
+	}
+}
@@ -0,0 +1,183 @@
+package values
+
+import (
+	//Standard packages
+	"fmt"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/builtins"
+)
+
+// TypeTable maps the names of user defined types to their definitions.
+type TypeTable map[string]ast.DataType
+
+// Underlying follows user defined type names until it reaches a type definition.
+// It returns the definition and the name it was declared with, which is empty for unnamed types.
+func (types TypeTable) Underlying(dtype ast.DataType) (ast.DataType, string) {
+	name := ""
+	for {
+		switch t := dtype.(type) {
+		case ast.UserDefinedType:
+			if t.AliasName == builtins.BYTE {
+				return ast.IntegerType{TypeName: builtins.UINT8, BitSize: 8, IsSigned: false, Location: t.Location}, ""
+			}
+			def, ok := types[t.AliasName]
+			if !ok {
+				return nil, t.AliasName
+			}
+			name = t.AliasName
+			if generic, ok := def.(ast.GenericType); ok {
+				def = instantiate(generic, t.TypeArgs)
+			}
+			dtype = def
+		case ast.MapType:
+			if t.Map.Name == "map" || t.Map.Name == "" {
+				return t, name
+			}
+			def, ok := types[t.Map.Name]
+			if !ok {
+				return nil, t.Map.Name
+			}
+			name = t.Map.Name
+			dtype = def
+		default:
+			return dtype, name
+		}
+	}
+}
+
+// instantiate replaces the type parameters of a generic type definition with the given
+// type arguments. Without arguments, the parameters stay unknown type names.
+func instantiate(generic ast.GenericType, args []ast.DataType) ast.DataType {
+	bindings := make(map[string]ast.DataType, len(args))
+	for i, param := range generic.TypeParams {
+		if i < len(args) {
+			bindings[param.Identifier.Name] = args[i]
+		}
+	}
+	return substituteTypeParams(generic.TypeDef, bindings)
+}
+
+// substituteTypeParams replaces the type parameter names found in a type annotation.
+func substituteTypeParams(dtype ast.DataType, bindings map[string]ast.DataType) ast.DataType {
+	switch t := dtype.(type) {
+	case ast.UserDefinedType:
+		if arg, ok := bindings[t.AliasName]; ok {
+			return arg
+		}
+		args := make([]ast.DataType, len(t.TypeArgs))
+		for i, arg := range t.TypeArgs {
+			args[i] = substituteTypeParams(arg, bindings)
+		}
+		t.TypeArgs = args
+		return t
+	case ast.ArrayType:
+		t.ArrayType = substituteTypeParams(t.ArrayType, bindings)
+		return t
+	case ast.MapType:
+		t.KeyType = substituteTypeParams(t.KeyType, bindings)
+		t.ValueType = substituteTypeParams(t.ValueType, bindings)
+		return t
+	case ast.MaybeType:
+		t.MaybeType = substituteTypeParams(t.MaybeType, bindings)
+		return t
+	case ast.RangeType:
+		t.RangeStart = substituteTypeParams(t.RangeStart, bindings)
+		t.RangeEnd = substituteTypeParams(t.RangeEnd, bindings)
+		return t
+	case ast.TupleType:
+		elements := make([]ast.DataType, len(t.Elements))
+		for i, element := range t.Elements {
+			elements[i] = substituteTypeParams(element, bindings)
+		}
+		t.Elements = elements
+		return t
+	case ast.StructType:
+		props := make([]ast.StructPropType, len(t.Properties))
+		for i, prop := range t.Properties {
+			props[i] = prop
+			props[i].PropType = substituteTypeParams(prop.PropType, bindings)
+		}
+		t.Properties = props
+		return t
+	case ast.FunctionType:
+		params := make([]ast.FunctionTypeParam, len(t.Parameters))
+		for i, param := range t.Parameters {
+			params[i] = param
+			params[i].Type = substituteTypeParams(param.Type, bindings)
+		}
+		t.Parameters = params
+		t.ReturnType = substituteTypeParams(t.ReturnType, bindings)
+		return t
+	default:
+		return dtype
+	}
+}
+
+// StructName resolves a (possibly aliased) struct type name to the name the struct was declared with.
+func (types TypeTable) StructName(name string) string {
+	for {
+		def, ok := types[name]
+		if !ok {
+			return name
+		}
+		alias, ok := def.(ast.UserDefinedType)
+		if !ok {
+			return name
+		}
+		name = alias.AliasName
+	}
+}
+
+// Name formats a type annotation the same way the typechecker formats resolved types.
+func (types TypeTable) Name(dtype ast.DataType) string {
+	def, name := types.Underlying(dtype)
+	switch t := def.(type) {
+	case nil, ast.VoidType:
+		if name != "" {
+			return name
+		}
+		return builtins.VOID
+	case ast.StructType:
+		if name != "" {
+			return types.StructName(name)
+		}
+		props := make([]string, len(t.Properties))
+		for i, prop := range t.Properties {
+			props[i] = fmt.Sprintf("%s: %s", prop.Prop.Name, types.Name(prop.PropType))
+		}
+		return "struct { " + strings.Join(props, ", ") + " }"
+	case ast.InterfaceType, ast.EnumType:
+		return name
+	case ast.ArrayType:
+		return "[]" + types.Name(t.ArrayType)
+	case ast.MapType:
+		return fmt.Sprintf("map[%s]%s", types.Name(t.KeyType), types.Name(t.ValueType))
+	case ast.RangeType:
+		return fmt.Sprintf("%s..%s", types.Name(t.RangeStart), types.Name(t.RangeEnd))
+	case ast.MaybeType:
+		return types.Name(t.MaybeType) + "?"
+	case ast.ResultType:
+		return types.Name(t.OkType) + "!" + types.Name(t.ErrType)
+	case ast.TupleType:
+		elements := make([]string, len(t.Elements))
+		for i, element := range t.Elements {
+			elements[i] = types.Name(element)
+		}
+		return "(" + strings.Join(elements, ", ") + ")"
+	case ast.FunctionType:
+		params := make([]string, len(t.Parameters))
+		for i, param := range t.Parameters {
+			params[i] = fmt.Sprintf("%s: %s", param.Identifier.Name, types.Name(param.Type))
+		}
+		returns := ""
+		if _, ok := t.ReturnType.(ast.VoidType); !ok && t.ReturnType != nil {
+			returns = " -> " + types.Name(t.ReturnType)
+		}
+		return fmt.Sprintf("fn(%s)%s", strings.Join(params, ", "), returns)
+	default:
+		return string(t.Type())
+	}
+}
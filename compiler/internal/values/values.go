@@ -0,0 +1,368 @@
+// Package values defines the runtime values of walrus programs and the operations on them,
+// shared by the interpreter, the bytecode vm and the backends folding constants.
+package values
+
+import (
+	//Standard packages
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	//Walrus packages
+	"walrus/compiler/internal/builtins"
+)
+
+const (
+	INT_VALUE      builtins.TC_TYPE = "int"
+	FLOAT_VALUE    builtins.TC_TYPE = "float"
+	STRING_VALUE   builtins.TC_TYPE = builtins.STRING
+	BOOLEAN_VALUE  builtins.TC_TYPE = builtins.BOOL
+	VOID_VALUE     builtins.TC_TYPE = builtins.VOID
+	ARRAY_VALUE    builtins.TC_TYPE = builtins.ARRAY
+	MAP_VALUE      builtins.TC_TYPE = builtins.MAP
+	STRUCT_VALUE   builtins.TC_TYPE = builtins.STRUCT
+	ENUM_VALUE     builtins.TC_TYPE = builtins.ENUM
+	FUNCTION_VALUE builtins.TC_TYPE = builtins.FUNCTION
+	RANGE_VALUE    builtins.TC_TYPE = builtins.RANGE
+	NULL_VALUE     builtins.TC_TYPE = builtins.NULL
+	ERR_VALUE      builtins.TC_TYPE = builtins.ERR
+	TUPLE_VALUE    builtins.TC_TYPE = builtins.TUPLE
+)
+
+// Value is a runtime value. Every value kind mirrors a typechecker.Tc kind.
+type Value interface {
+	DType() builtins.TC_TYPE
+	String() string
+}
+
+type Int struct {
+	Value    *big.Int
+	BitSize  uint8
+	IsSigned bool
+}
+
+func (v Int) DType() builtins.TC_TYPE {
+	return INT_VALUE
+}
+
+func (v Int) String() string {
+	return v.Value.String()
+}
+
+type Float struct {
+	Value   float64
+	BitSize uint8
+}
+
+func (v Float) DType() builtins.TC_TYPE {
+	return FLOAT_VALUE
+}
+
+func (v Float) String() string {
+	return strconv.FormatFloat(v.Value, 'g', -1, int(v.BitSize))
+}
+
+type Str struct {
+	Value string
+}
+
+func (v Str) DType() builtins.TC_TYPE {
+	return STRING_VALUE
+}
+
+func (v Str) String() string {
+	return v.Value
+}
+
+type Bool struct {
+	Value bool
+}
+
+func (v Bool) DType() builtins.TC_TYPE {
+	return BOOLEAN_VALUE
+}
+
+func (v Bool) String() string {
+	return strconv.FormatBool(v.Value)
+}
+
+type Void struct{}
+
+func (v Void) DType() builtins.TC_TYPE {
+	return VOID_VALUE
+}
+
+func (v Void) String() string {
+	return "void"
+}
+
+// Null is the value of a nullable variable that holds nothing. A nullable variable that
+// holds something holds that value itself.
+type Null struct{}
+
+func (v Null) DType() builtins.TC_TYPE {
+	return NULL_VALUE
+}
+
+func (v Null) String() string {
+	return "null"
+}
+
+// Array is shared by reference, so assigning through an index is visible to every holder.
+type Array struct {
+	ElementType string
+	Values      []Value
+}
+
+func (v *Array) DType() builtins.TC_TYPE {
+	return ARRAY_VALUE
+}
+
+func (v *Array) String() string {
+	values := make([]string, len(v.Values))
+	for i, value := range v.Values {
+		values[i] = quoted(value)
+	}
+	return "[" + strings.Join(values, ", ") + "]"
+}
+
+type MapEntry struct {
+	Key   Value
+	Value Value
+}
+
+// Map keeps its entries in insertion order. Entries are looked up by the key's hash.
+type Map struct {
+	KeyType   string
+	ValueType string
+	Entries   map[string]*MapEntry
+	Order     []string
+}
+
+func (v *Map) DType() builtins.TC_TYPE {
+	return MAP_VALUE
+}
+
+func (v *Map) String() string {
+	values := make([]string, 0, len(v.Order))
+	for _, hash := range v.Order {
+		entry := v.Entries[hash]
+		values = append(values, fmt.Sprintf("%s => %s", quoted(entry.Key), quoted(entry.Value)))
+	}
+	return "{" + strings.Join(values, ", ") + "}"
+}
+
+func (v *Map) Get(key Value) (Value, bool) {
+	if entry, ok := v.Entries[hashKey(key)]; ok {
+		return entry.Value, true
+	}
+	return nil, false
+}
+
+func (v *Map) Set(key Value, value Value) {
+	hash := hashKey(key)
+	if entry, ok := v.Entries[hash]; ok {
+		entry.Value = value
+		return
+	}
+	v.Entries[hash] = &MapEntry{Key: key, Value: value}
+	v.Order = append(v.Order, hash)
+}
+
+// Struct is shared by reference, so methods can modify the fields through 'this'.
+type Struct struct {
+	StructName string
+	Fields     map[string]Value
+	Order      []string
+}
+
+func (v *Struct) DType() builtins.TC_TYPE {
+	return STRUCT_VALUE
+}
+
+func (v *Struct) String() string {
+	values := make([]string, len(v.Order))
+	for i, name := range v.Order {
+		values[i] = fmt.Sprintf("%s: %s", name, quoted(v.Fields[name]))
+	}
+	name := v.StructName
+	if name == "" {
+		name = "@struct"
+	} else {
+		name = "@" + name
+	}
+	return name + "{" + strings.Join(values, ", ") + "}"
+}
+
+// Err is the error held by a result. A result that holds a value holds that value itself.
+type Err struct {
+	Value Value
+}
+
+func (v Err) DType() builtins.TC_TYPE {
+	return ERR_VALUE
+}
+
+func (v Err) String() string {
+	return "err(" + quoted(v.Value) + ")"
+}
+
+// Tuple holds a value of each element of a tuple type, in order. Tuples cannot be changed.
+type Tuple struct {
+	Values []Value
+}
+
+func (v *Tuple) DType() builtins.TC_TYPE {
+	return TUPLE_VALUE
+}
+
+func (v *Tuple) String() string {
+	values := make([]string, len(v.Values))
+	for i, value := range v.Values {
+		values[i] = quoted(value)
+	}
+	return "(" + strings.Join(values, ", ") + ")"
+}
+
+// Variant is a value of an enum. Fields holds the values of the variant fields, in order.
+type Variant struct {
+	EnumName string
+	Name     string
+	Fields   []Value
+}
+
+func (v *Variant) DType() builtins.TC_TYPE {
+	return ENUM_VALUE
+}
+
+func (v *Variant) String() string {
+	name := v.EnumName + "." + v.Name
+	if len(v.Fields) == 0 {
+		return name
+	}
+	values := make([]string, len(v.Fields))
+	for i, field := range v.Fields {
+		values[i] = quoted(field)
+	}
+	return name + "(" + strings.Join(values, ", ") + ")"
+}
+
+type Range struct {
+	Start Value
+	End   Value
+}
+
+func (v Range) DType() builtins.TC_TYPE {
+	return RANGE_VALUE
+}
+
+func (v Range) String() string {
+	return fmt.Sprintf("%s..%s", v.Start.String(), v.End.String())
+}
+
+// helper value initialization functions
+func NewInt(value int64, bitSize uint8, isSigned bool) Int {
+	return Int{Value: big.NewInt(value), BitSize: bitSize, IsSigned: isSigned}
+}
+
+func NewFloat(value float64, bitSize uint8) Float {
+	if bitSize == 32 {
+		value = float64(float32(value))
+	}
+	return Float{Value: value, BitSize: bitSize}
+}
+
+func NewStr(value string) Str {
+	return Str{Value: value}
+}
+
+func NewBool(value bool) Bool {
+	return Bool{Value: value}
+}
+
+func NewVoid() Void {
+	return Void{}
+}
+
+func NewArray(elementType string, values []Value) *Array {
+	return &Array{ElementType: elementType, Values: values}
+}
+
+func NewMap(keyType, valueType string) *Map {
+	return &Map{KeyType: keyType, ValueType: valueType, Entries: make(map[string]*MapEntry)}
+}
+
+func NewStruct(name string) *Struct {
+	return &Struct{StructName: name, Fields: make(map[string]Value)}
+}
+
+// SetField sets a struct field, keeping the declaration order of new fields.
+func (v *Struct) SetField(name string, value Value) {
+	if _, ok := v.Fields[name]; !ok {
+		v.Order = append(v.Order, name)
+	}
+	v.Fields[name] = value
+}
+
+// hashKey makes a map key out of a value. The type is part of the key so 1 and "1" never collide.
+func hashKey(value Value) string {
+	return TypeName(value) + ":" + value.String()
+}
+
+// quoted returns the string form of a value, with strings wrapped in quotes.
+func quoted(value Value) string {
+	if str, ok := value.(Str); ok {
+		return strconv.Quote(str.Value)
+	}
+	return value.String()
+}
+
+func numericType(isInt bool, bitSize uint8, isSigned bool) string {
+	if !isInt {
+		return fmt.Sprintf("f%d", bitSize)
+	}
+	if isSigned {
+		return fmt.Sprintf("i%d", bitSize)
+	}
+	return fmt.Sprintf("u%d", bitSize)
+}
+
+// TypeName returns the name of the runtime type of a value, formatted like the typechecker does.
+func TypeName(value Value) string {
+	switch v := value.(type) {
+	case Int:
+		return numericType(true, v.BitSize, v.IsSigned)
+	case Float:
+		return numericType(false, v.BitSize, false)
+	case *Array:
+		return "[]" + v.ElementType
+	case *Map:
+		return fmt.Sprintf("map[%s]%s", v.KeyType, v.ValueType)
+	case *Struct:
+		if v.StructName != "" {
+			return v.StructName
+		}
+		props := make([]string, len(v.Order))
+		for i, name := range v.Order {
+			props[i] = fmt.Sprintf("%s: %s", name, TypeName(v.Fields[name]))
+		}
+		return "struct { " + strings.Join(props, ", ") + " }"
+	case *Variant:
+		return v.EnumName
+	case Err:
+		return "err(" + TypeName(v.Value) + ")"
+	case *Tuple:
+		elements := make([]string, len(v.Values))
+		for i, value := range v.Values {
+			elements[i] = TypeName(value)
+		}
+		return "(" + strings.Join(elements, ", ") + ")"
+	case Range:
+		return fmt.Sprintf("%s..%s", TypeName(v.Start), TypeName(v.End))
+	case nil:
+		return "void"
+	default:
+		return string(v.DType())
+	}
+}
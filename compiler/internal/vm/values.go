@@ -0,0 +1,91 @@
+package vm
+
+import (
+	//Standard packages
+	"fmt"
+
+	//Walrus packages
+	"walrus/compiler/internal/builtins"
+	"walrus/compiler/internal/bytecode"
+	"walrus/compiler/internal/values"
+)
+
+// Env is a runtime scope. Variables live in slots assigned by the bytecode compiler.
+type Env struct {
+	parent *Env
+	slots  []values.Value
+}
+
+func NewEnv(parent *Env, size int) *Env {
+	slots := make([]values.Value, size)
+	for i := range slots {
+		slots[i] = values.NewVoid()
+	}
+	return &Env{parent: parent, slots: slots}
+}
+
+// ancestor returns the scope depth levels up.
+func (e *Env) ancestor(depth int) *Env {
+	env := e
+	for i := 0; i < depth; i++ {
+		env = env.parent
+	}
+	return env
+}
+
+// Closure is a compiled function together with the scope it was created in.
+type Closure struct {
+	Function *bytecode.Function
+	Env      *Env
+}
+
+func (v *Closure) DType() builtins.TC_TYPE {
+	return values.FUNCTION_VALUE
+}
+
+func (v *Closure) String() string {
+	return fmt.Sprintf("<fn %s>", v.Function.Name)
+}
+
+// BoundMethod is a struct method accessed through a struct value.
+type BoundMethod struct {
+	Function *bytecode.Function
+	This     *values.Struct
+}
+
+func (v *BoundMethod) DType() builtins.TC_TYPE {
+	return values.FUNCTION_VALUE
+}
+
+func (v *BoundMethod) String() string {
+	return fmt.Sprintf("<fn %s>", v.Function.Name)
+}
+
+// Native is a builtin function implemented by the vm.
+type Native struct {
+	Name  string
+	Arity int
+	Call  func(vm *VM, args []values.Value) values.Value
+}
+
+func (v *Native) DType() builtins.TC_TYPE {
+	return values.FUNCTION_VALUE
+}
+
+func (v *Native) String() string {
+	return fmt.Sprintf("<builtin fn %s>", v.Name)
+}
+
+// Iterator walks the values a foreach loop visits. Next returns the key and the value of
+// the next iteration, or false when there are none left.
+type Iterator struct {
+	Next func() (values.Value, values.Value, bool)
+}
+
+func (v *Iterator) DType() builtins.TC_TYPE {
+	return values.VOID_VALUE
+}
+
+func (v *Iterator) String() string {
+	return "<iterator>"
+}
@@ -0,0 +1,441 @@
+package vm
+
+import (
+	//Standard packages
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+
+	//Walrus packages
+	"walrus/compiler/internal/builtins"
+	"walrus/compiler/internal/bytecode"
+	"walrus/compiler/internal/lexer"
+	"walrus/compiler/internal/values"
+	"walrus/compiler/report"
+)
+
+// maxCallDepth limits recursion so a runaway program reports an error instead of exhausting memory.
+const maxCallDepth = 10000
+
+// frame is an active function call.
+type frame struct {
+	function *bytecode.Function
+	ip       int
+	env      *Env
+	base     int // stack height below the callee
+}
+
+type VM struct {
+	program  *bytecode.Program
+	filePath string
+	out      io.Writer
+	stack    []values.Value
+	frames   []*frame
+	globals  *Env
+	methods  map[string]*bytecode.MethodTable
+}
+
+func NewVM(program *bytecode.Program, filePath string, out io.Writer) *VM {
+	vm := &VM{
+		program:  program,
+		filePath: filePath,
+		out:      out,
+		methods:  make(map[string]*bytecode.MethodTable),
+	}
+	for i := range program.Methods {
+		vm.methods[program.Methods[i].Struct] = &program.Methods[i]
+	}
+	vm.globals = NewEnv(nil, program.Main().Locals)
+	for i, name := range bytecode.Globals {
+		vm.globals.slots[i] = vm.builtinValue(name)
+	}
+	return vm
+}
+
+// builtinValue returns the value of a builtin declared by the typechecker.
+func (vm *VM) builtinValue(name string) values.Value {
+	switch name {
+	case "true":
+		return values.NewBool(true)
+	case "false":
+		return values.NewBool(false)
+	case "PI":
+		return values.NewFloat(math.Pi, 32)
+	case "print":
+		return &Native{Name: "print", Arity: 1, Call: func(vm *VM, args []values.Value) values.Value {
+			fmt.Fprintln(vm.out, args[0].String())
+			return values.NewVoid()
+		}}
+	default:
+		return values.NewVoid()
+	}
+}
+
+// Run executes a compiled program. The program output is written to out.
+// Runtime errors are reported with the RUNTIME_ERROR level, which halts the execution.
+func Run(program *bytecode.Program, filePath string, out io.Writer) {
+	vm := NewVM(program, filePath, out)
+	vm.frames = append(vm.frames, &frame{function: program.Main(), env: vm.globals})
+	vm.run()
+}
+
+// runtimeError reports an error at the location of the instruction being executed.
+func (vm *VM) runtimeError(msg string) {
+	f := vm.frames[len(vm.frames)-1]
+	span := f.function.SpanAt(f.ip - 1)
+	report.Add(vm.filePath, span.LineStart, span.LineEnd, span.ColStart, span.ColEnd, msg).SetLevel(report.RUNTIME_ERROR)
+}
+
+func (vm *VM) push(value values.Value) {
+	vm.stack = append(vm.stack, value)
+}
+
+func (vm *VM) pop() values.Value {
+	value := vm.stack[len(vm.stack)-1]
+	vm.stack = vm.stack[:len(vm.stack)-1]
+	return value
+}
+
+func (vm *VM) peek(distance int) values.Value {
+	return vm.stack[len(vm.stack)-1-distance]
+}
+
+// popN removes the top n values and returns them in the order they were pushed.
+func (vm *VM) popN(n int) []values.Value {
+	popped := make([]values.Value, n)
+	copy(popped, vm.stack[len(vm.stack)-n:])
+	vm.stack = vm.stack[:len(vm.stack)-n]
+	return popped
+}
+
+func (vm *VM) constant(index int) values.Value {
+	return vm.program.Constants[index]
+}
+
+func (vm *VM) constantString(index int) string {
+	return vm.constant(index).String()
+}
+
+var binaryOperators = map[bytecode.Opcode]builtins.TOKEN_KIND{
+	bytecode.OP_ADD:           lexer.PLUS_TOKEN,
+	bytecode.OP_SUB:           lexer.MINUS_TOKEN,
+	bytecode.OP_MUL:           lexer.MUL_TOKEN,
+	bytecode.OP_DIV:           lexer.DIV_TOKEN,
+	bytecode.OP_MOD:           lexer.MOD_TOKEN,
+	bytecode.OP_EXP:           lexer.EXP_TOKEN,
+	bytecode.OP_BIT_AND:       lexer.BIT_AND_TOKEN,
+	bytecode.OP_BIT_OR:        lexer.BIT_OR_TOKEN,
+	bytecode.OP_BIT_XOR:       lexer.BIT_XOR_TOKEN,
+	bytecode.OP_SHIFT_LEFT:    lexer.SHIFT_LEFT_TOKEN,
+	bytecode.OP_SHIFT_RIGHT:   lexer.SHIFT_RIGHT_TOKEN,
+	bytecode.OP_EQUAL:         lexer.DOUBLE_EQUAL_TOKEN,
+	bytecode.OP_NOT_EQUAL:     lexer.NOT_EQUAL_TOKEN,
+	bytecode.OP_LESS:          lexer.LESS_TOKEN,
+	bytecode.OP_LESS_EQUAL:    lexer.LESS_EQUAL_TOKEN,
+	bytecode.OP_GREATER:       lexer.GREATER_TOKEN,
+	bytecode.OP_GREATER_EQUAL: lexer.GREATER_EQUAL_TOKEN,
+}
+
+// check reports a failed operation as a runtime error.
+func (vm *VM) check(value values.Value, err error) values.Value {
+	if err != nil {
+		vm.runtimeError(err.Error())
+	}
+	return value
+}
+
+func (vm *VM) run() {
+	for {
+		f := vm.frames[len(vm.frames)-1]
+		code := f.function.Code
+
+		if f.ip >= len(code) {
+			vm.runtimeError("unexpected end of bytecode")
+		}
+
+		op := bytecode.Opcode(code[f.ip])
+		f.ip++
+		operands := make([]int, op.Operands())
+		for i := range operands {
+			operands[i] = f.function.ReadOperand(f.ip)
+			f.ip += 2
+		}
+
+		switch op {
+		case bytecode.OP_CONSTANT:
+			vm.push(vm.constant(operands[0]))
+		case bytecode.OP_VOID:
+			vm.push(values.NewVoid())
+		case bytecode.OP_POP:
+			vm.pop()
+		case bytecode.OP_DUP:
+			vm.push(vm.peek(0))
+		case bytecode.OP_DUP2:
+			vm.push(vm.peek(1))
+			vm.push(vm.peek(1))
+		case bytecode.OP_GET_VAR:
+			vm.push(f.env.ancestor(operands[0]).slots[operands[1]])
+		case bytecode.OP_SET_VAR:
+			f.env.ancestor(operands[0]).slots[operands[1]] = vm.peek(0)
+		case bytecode.OP_DEFINE_VAR:
+			f.env.slots[operands[0]] = vm.pop()
+		case bytecode.OP_ENTER_SCOPE:
+			f.env = NewEnv(f.env, operands[0])
+		case bytecode.OP_EXIT_SCOPE:
+			f.env = f.env.parent
+		case bytecode.OP_ADD, bytecode.OP_SUB, bytecode.OP_MUL, bytecode.OP_DIV, bytecode.OP_MOD, bytecode.OP_EXP,
+			bytecode.OP_BIT_AND, bytecode.OP_BIT_OR, bytecode.OP_BIT_XOR, bytecode.OP_SHIFT_LEFT, bytecode.OP_SHIFT_RIGHT,
+			bytecode.OP_EQUAL, bytecode.OP_NOT_EQUAL, bytecode.OP_LESS, bytecode.OP_LESS_EQUAL, bytecode.OP_GREATER, bytecode.OP_GREATER_EQUAL:
+			right := vm.pop()
+			left := vm.pop()
+			vm.push(vm.check(values.BinaryOperation(binaryOperators[op], left, right)))
+		case bytecode.OP_NEGATE:
+			vm.push(vm.check(values.UnaryOperation(lexer.MINUS_TOKEN, vm.pop())))
+		case bytecode.OP_NOT:
+			vm.push(vm.check(values.UnaryOperation(lexer.NOT_TOKEN, vm.pop())))
+		case bytecode.OP_BIT_NOT:
+			vm.push(vm.check(values.UnaryOperation(lexer.BIT_NOT_TOKEN, vm.pop())))
+		case bytecode.OP_CAST_INT:
+			vm.push(vm.check(values.ToInt(vm.pop(), uint8(operands[0]), operands[1] == 1)))
+		case bytecode.OP_CAST_FLOAT:
+			vm.push(vm.check(values.ToFloat(vm.pop(), uint8(operands[0]))))
+		case bytecode.OP_CAST_STRUCT:
+			layout := vm.program.Layouts[operands[0]]
+			vm.push(vm.check(values.CastStruct(vm.pop(), layout.Name, layout.Fields)))
+		case bytecode.OP_TYPEOF:
+			vm.push(values.NewStr(values.TypeName(vm.pop())))
+		case bytecode.OP_ERR:
+			vm.push(values.Err{Value: vm.pop()})
+		case bytecode.OP_IS_ERR:
+			_, ok := vm.pop().(values.Err)
+			vm.push(values.NewBool(ok))
+		case bytecode.OP_ERR_VALUE:
+			errValue, ok := vm.pop().(values.Err)
+			if !ok {
+				vm.runtimeError("cannot read the error of a value that is not an error")
+			}
+			vm.push(errValue.Value)
+		case bytecode.OP_RANGE:
+			end := vm.pop()
+			start := vm.pop()
+			vm.push(values.Range{Start: start, End: end})
+		case bytecode.OP_ARRAY:
+			elements := vm.popN(operands[1])
+			elementType := vm.constantString(operands[0])
+			if elementType == "" {
+				elementType = "void"
+				if len(elements) > 0 {
+					elementType = values.TypeName(elements[0])
+				}
+			}
+			vm.push(values.NewArray(elementType, elements))
+		case bytecode.OP_MAP:
+			entries := vm.popN(operands[2] * 2)
+			value := values.NewMap(vm.constantString(operands[0]), vm.constantString(operands[1]))
+			for i := 0; i < len(entries); i += 2 {
+				value.Set(entries[i], entries[i+1])
+			}
+			vm.push(value)
+		case bytecode.OP_STRUCT:
+			layout := vm.program.Layouts[operands[0]]
+			fields := vm.popN(len(layout.Fields))
+			value := values.NewStruct(layout.Name)
+			for i, field := range layout.Fields {
+				value.SetField(field, fields[i])
+			}
+			vm.push(value)
+		case bytecode.OP_TUPLE:
+			vm.push(&values.Tuple{Values: vm.popN(operands[0])})
+		case bytecode.OP_VARIANT:
+			fields := vm.popN(operands[2])
+			if len(fields) == 0 {
+				fields = nil
+			}
+			vm.push(&values.Variant{EnumName: vm.constantString(operands[0]), Name: vm.constantString(operands[1]), Fields: fields})
+		case bytecode.OP_IS_VARIANT:
+			variant, ok := vm.pop().(*values.Variant)
+			vm.push(values.NewBool(ok && variant.EnumName == vm.constantString(operands[0]) && variant.Name == vm.constantString(operands[1])))
+		case bytecode.OP_VARIANT_FIELD:
+			variant, ok := vm.pop().(*values.Variant)
+			if !ok || operands[0] >= len(variant.Fields) {
+				vm.runtimeError("cannot read a field of a value that is not a variant with fields")
+			}
+			vm.push(variant.Fields[operands[0]])
+		case bytecode.OP_IS_STRUCT:
+			structValue, ok := vm.pop().(*values.Struct)
+			vm.push(values.NewBool(ok && structValue.StructName == vm.constantString(operands[0])))
+		case bytecode.OP_INDEX:
+			index := vm.pop()
+			container := vm.pop()
+			vm.push(vm.check(values.Index(container, index)))
+		case bytecode.OP_SET_INDEX:
+			value := vm.pop()
+			index := vm.pop()
+			container := vm.pop()
+			if err := values.SetIndex(container, index, value); err != nil {
+				vm.runtimeError(err.Error())
+			}
+			vm.push(value)
+		case bytecode.OP_GET_PROPERTY:
+			vm.push(vm.getProperty(vm.pop(), vm.constantString(operands[0])))
+		case bytecode.OP_SET_PROPERTY:
+			value := vm.pop()
+			object, ok := vm.pop().(*values.Struct)
+			if !ok {
+				vm.runtimeError("cannot assign a property of a non struct value")
+			}
+			object.SetField(vm.constantString(operands[0]), value)
+			vm.push(value)
+		case bytecode.OP_CLOSURE:
+			vm.push(&Closure{Function: vm.program.Functions[operands[0]], Env: f.env})
+		case bytecode.OP_CALL:
+			vm.call(operands[0])
+		case bytecode.OP_RETURN:
+			result := vm.pop()
+			vm.frames = vm.frames[:len(vm.frames)-1]
+			if len(vm.frames) == 0 {
+				return
+			}
+			vm.stack = vm.stack[:f.base]
+			vm.push(result)
+		case bytecode.OP_JUMP:
+			f.ip = operands[0]
+		case bytecode.OP_JUMP_IF_FALSE:
+			condition, ok := vm.pop().(values.Bool)
+			if !ok {
+				vm.runtimeError("condition must be a boolean expression")
+			}
+			if !condition.Value {
+				f.ip = operands[0]
+			}
+		case bytecode.OP_ITER:
+			vm.push(vm.iterator(vm.pop()))
+		case bytecode.OP_ITER_NEXT:
+			key, value, ok := vm.pop().(*Iterator).Next()
+			if !ok {
+				f.ip = operands[0]
+				break
+			}
+			vm.push(key)
+			vm.push(value)
+		default:
+			vm.runtimeError(fmt.Sprintf("unknown opcode %d", op))
+		}
+	}
+}
+
+// iterator returns an iterator over the elements of an array, the entries of a map or the
+// numbers of a range, from its start up to its end, which is left out. Like the interpreter,
+// elements appended to an array by the loop are visited and entries added to a map are not.
+func (vm *VM) iterator(iterable values.Value) *Iterator {
+	switch t := iterable.(type) {
+	case *values.Array:
+		i := 0
+		return &Iterator{Next: func() (values.Value, values.Value, bool) {
+			if i >= len(t.Values) {
+				return nil, nil, false
+			}
+			i++
+			return values.NewInt(int64(i-1), 32, true), t.Values[i-1], true
+		}}
+	case *values.Map:
+		order := append([]string{}, t.Order...)
+		return &Iterator{Next: func() (values.Value, values.Value, bool) {
+			for len(order) > 0 {
+				entry, ok := t.Entries[order[0]]
+				order = order[1:]
+				if ok {
+					return entry.Key, entry.Value, true
+				}
+			}
+			return nil, nil, false
+		}}
+	case values.Range:
+		start, startOk := t.Start.(values.Int)
+		end, endOk := t.End.(values.Int)
+		if !startOk || !endOk {
+			vm.runtimeError(fmt.Sprintf("cannot iterate over '%s'", values.TypeName(iterable)))
+		}
+		n, index := new(big.Int).Set(start.Value), int64(0)
+		return &Iterator{Next: func() (values.Value, values.Value, bool) {
+			if n.Cmp(end.Value) >= 0 {
+				return nil, nil, false
+			}
+			value := values.Int{Value: new(big.Int).Set(n), BitSize: start.BitSize, IsSigned: start.IsSigned}
+			n.Add(n, big.NewInt(1))
+			index++
+			return values.NewInt(index-1, 32, true), value, true
+		}}
+	}
+	vm.runtimeError(fmt.Sprintf("cannot iterate over '%s'", values.TypeName(iterable)))
+	return nil
+}
+
+// getProperty returns a field of a struct, or one of its methods bound to the struct.
+func (vm *VM) getProperty(object values.Value, name string) values.Value {
+	structValue, ok := object.(*values.Struct)
+	if !ok {
+		vm.runtimeError(fmt.Sprintf("cannot access property '%s' of type '%s'", name, values.TypeName(object)))
+	}
+
+	if value, ok := structValue.Fields[name]; ok {
+		return value
+	}
+
+	if table, ok := vm.methods[structValue.StructName]; ok {
+		for i, method := range table.Names {
+			if method == name {
+				return &BoundMethod{Function: vm.program.Functions[table.Functions[i]], This: structValue}
+			}
+		}
+	}
+
+	vm.runtimeError(fmt.Sprintf("'%s' does not exist on type '%s'", name, values.TypeName(object)))
+	return nil
+}
+
+// call calls the function below the arguments on the stack. Methods run in a scope
+// where 'this' and the other methods of the struct are declared.
+func (vm *VM) call(argc int) {
+	callee := vm.peek(argc)
+	base := len(vm.stack) - argc - 1
+
+	var function *bytecode.Function
+	var parent *Env
+
+	switch fn := callee.(type) {
+	case *Native:
+		if argc != fn.Arity {
+			vm.runtimeError(fmt.Sprintf("function '%s' expects %d arguments, got %d", fn.Name, fn.Arity, argc))
+		}
+		args := vm.popN(argc)
+		vm.pop()
+		vm.push(fn.Call(vm, args))
+		return
+	case *Closure:
+		function, parent = fn.Function, fn.Env
+	case *BoundMethod:
+		function = fn.Function
+		table := vm.methods[fn.This.StructName]
+		parent = NewEnv(vm.globals, len(table.Names)+1)
+		parent.slots[0] = fn.This
+		for i, index := range table.Functions {
+			parent.slots[i+1] = &BoundMethod{Function: vm.program.Functions[index], This: fn.This}
+		}
+	default:
+		vm.runtimeError(fmt.Sprintf("cannot call a value of type '%s'", values.TypeName(callee)))
+	}
+
+	if argc != function.Arity {
+		vm.runtimeError(fmt.Sprintf("function '%s' expects %d arguments, got %d", function.Name, function.Arity, argc))
+	}
+	if len(vm.frames) >= maxCallDepth {
+		vm.runtimeError(fmt.Sprintf("maximum call depth of %d exceeded", maxCallDepth))
+	}
+
+	env := NewEnv(parent, function.Locals)
+	copy(env.slots, vm.stack[base+1:])
+
+	vm.frames = append(vm.frames, &frame{function: function, env: env, base: base})
+}
@@ -0,0 +1,753 @@
+package vm
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"walrus/compiler/internal/bytecode"
+	"walrus/compiler/internal/modules"
+	"walrus/compiler/internal/parser"
+	"walrus/compiler/internal/typechecker"
+	"walrus/compiler/report"
+)
+
+// run type checks, compiles and executes a program. It returns what the program printed and
+// the message of the runtime error that stopped it, if any.
+func run(t *testing.T, code string) (output string, runtimeError string) {
+	t.Helper()
+
+	tmpfile, e := os.CreateTemp("", "test*.wal")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.Remove(tmpfile.Name())
+
+	if _, e := tmpfile.Write([]byte(code)); e != nil {
+		t.Fatal(e)
+	}
+	if e := tmpfile.Close(); e != nil {
+		t.Fatal(e)
+	}
+
+	var out bytes.Buffer
+
+	defer func() {
+		if r := recover(); r != nil {
+			reports := report.GetReports()
+			runtimeError = reports[len(reports)-1].Message
+		}
+		report.ClearReports()
+		output = out.String()
+	}()
+
+	tree, e := parser.NewParser(tmpfile.Name(), false).Parse()
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	typechecker.Analyze(tree, tmpfile.Name())
+	if report.GetReports().HasErrors() {
+		t.Fatalf("Expected program to type check, got %v", report.GetReports())
+	}
+
+	program := bytecode.Compile(tree, tmpfile.Name())
+
+	// run the program after a round trip through the binary format
+	var encoded bytes.Buffer
+	if e := bytecode.Encode(&encoded, program); e != nil {
+		t.Fatal(e)
+	}
+	decoded, e := bytecode.Decode(&encoded)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	Run(decoded, tmpfile.Name(), &out)
+
+	return out.String(), ""
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected string
+	}{
+		{
+			name:     "Arithmetic",
+			code:     `print("" + (1 + 2 * 3)); print("" + (7 / 2)); print("" + (7 % 3)); print("" + (2 ** 10)); print("" + (1.5 * 2.0));`,
+			expected: "7\n3\n1\n1024\n3\n",
+		},
+		{
+			name: "Bitwise operators",
+			code: `
+				let a := 6;
+				print("" + (a & 3) + " " + (a | 3) + " " + (a ^ 3));
+				print("" + (1 << 4) + " " + (a >> 1) + " " + (~a));
+			`,
+			expected: "2 7 5\n16 3 -7\n",
+		},
+		{
+			name: "Operator precedence",
+			code: `
+				let a := 6;
+				print("" + (2 ** 3 ** 2) + " " + (1 + 2 << 1) + " " + (a & 1 == 0));
+			`,
+			expected: "512 6 true\n",
+		},
+		{
+			name: "Logical operators short circuit",
+			code: `
+				fn side() -> bool { print("side"); ret true; }
+				print("" + (false && side()) + " " + (true || side()));
+			`,
+			expected: "false true\n",
+		},
+		{
+			name: "Bitwise operators keep the type of their operands",
+			code: `
+				let c := 200 as u8;
+				let n := -128 as i8;
+				print("" + (c << 1 as u8) + " " + (~c) + " " + (n >> 100));
+			`,
+			expected: "144 55 -1\n",
+		},
+		{
+			name: "Compound bitwise assignments",
+			code: `
+				let e := 5;
+				e <<= 2; e |= 1; e ^= 3; e &= 14; e >>= 1;
+				print("" + e);
+			`,
+			expected: "3\n",
+		},
+		{
+			name:     "Integer overflow wraps",
+			code:     `let x : u8 = 255 as u8; x += 1 as u8; print("" + x); let y := 300 as i8; print("" + y);`,
+			expected: "0\n44\n",
+		},
+		{
+			name:     "Recursion",
+			code:     `fn fib(n: i32) -> i32 { if n < 2 { ret n; } ret fib(n - 1) + fib(n - 2); } print("" + fib(15));`,
+			expected: "610\n",
+		},
+		{
+			name: "Closures",
+			code: `
+				fn counter() -> fn() -> i32 {
+					let n := 0;
+					ret fn() -> i32 { n++; ret n; };
+				}
+				let c := counter();
+				c();
+				print("" + c());
+			`,
+			expected: "2\n",
+		},
+		{
+			name: "Shadowed builtin",
+			code: `
+				fn shout(message: str) -> str {
+					fn print(m: str) -> str { ret m + "!"; }
+					ret print(message);
+				}
+				print(shout("hi"));
+			`,
+			expected: "hi!\n",
+		},
+		{
+			name: "Loops",
+			code: `
+				let total := 0;
+				for let i := 0; i < 5; i++ { total += i; }
+				let k := 0;
+				for k < 3 { k++; }
+				print("" + total + " " + k);
+			`,
+			expected: "10 3\n",
+		},
+		{
+			name: "Break and continue",
+			code: `
+				let total := 0;
+				for let i := 0; i < 10; i++ {
+					if i == 5 { break; }
+					if i % 2 == 0 { continue; }
+					total += i;
+				}
+				print("" + total);
+			`,
+			expected: "4\n",
+		},
+		{
+			name: "Labeled break and continue",
+			code: `
+				let pairs := 0;
+				outer: for let i := 0; i < 3; i++ {
+					for let j := 0; j < 3; j++ {
+						if j == 2 { continue outer; }
+						if i == 2 { break outer; }
+						pairs += 1;
+					}
+				}
+				print("" + pairs);
+			`,
+			expected: "4\n",
+		},
+		{
+			name: "While loops",
+			code: `
+				let n := 0;
+				while n < 5 {
+					n++;
+					if n == 2 { continue; }
+				}
+				print("" + n);
+			`,
+			expected: "5\n",
+		},
+		{
+			name: "Do-while runs its body first",
+			code: `
+				let d := 10;
+				do { d++; } while d < 5;
+				fn digits(x: i32) -> i32 {
+					let count := 0;
+					let v := x;
+					do { count++; v /= 10; } while v > 0;
+					ret count;
+				}
+				print("" + d + " " + digits(0) + " " + digits(123));
+			`,
+			expected: "11 1 3\n",
+		},
+		{
+			name: "Return from a do-while",
+			code: `
+				fn first() -> i32 { do { ret 7; } while true; }
+				print("" + first());
+			`,
+			expected: "7\n",
+		},
+		{
+			name: "Labeled break out of a while",
+			code: `
+				let hits := 0;
+				rows: while hits < 10 {
+					do {
+						hits++;
+						if hits == 3 { break rows; }
+					} while false;
+				}
+				print("" + hits);
+			`,
+			expected: "3\n",
+		},
+		{
+			name: "String interpolation",
+			code: `
+				let name := "walrus";
+				let n := 3;
+				let f := 1.5;
+				print("hi {name}, {n + 1} \{x\}");
+				print("{n}{"-"}{n > 2}");
+				print("f={f}");
+			`,
+			expected: "hi walrus, 4 {x}\n3-true\nf=1.5\n",
+		},
+		{
+			name:     "String escapes",
+			code:     `print("tab\there \"q\" \\ \u{e9}");`,
+			expected: "tab\there \"q\" \\ é\n",
+		},
+		{
+			name:     "Raw strings",
+			code:     `let name := "walrus"; print(` + "`raw \\n {name}`" + `); print(` + "`line1\nline2`" + `);`,
+			expected: "raw \\n {name}\nline1\nline2\n",
+		},
+		{
+			name:     "Number literal bases",
+			code:     `print("" + 0xff + " " + 0o17 + " " + 0b101 + " " + 1_000_000 + " " + 1.5e3 + " " + 2.5e-1f64);`,
+			expected: "255 15 5 1000000 1500 0.25\n",
+		},
+		{
+			name: "Number literal suffixes",
+			code: `
+				let a := 255u8;
+				let b := -128i8;
+				let c := 0xffff_ffff_ffff_ffffu64;
+				let d := 10f32 / 4f32;
+				print("" + a + " " + b + " " + c + " " + d);
+			`,
+			expected: "255 -128 18446744073709551615 2.5\n",
+		},
+		{
+			name: "Untyped numbers take the declared type",
+			code: `
+				let big: i64 = 5000000000;
+				let neg: i64 = -5000000000;
+				let small: u8 = 200;
+				let half: f64 = 0.1;
+				let whole: f32 = 3;
+				print("" + big + " " + neg + " " + small + " " + half + " " + whole);
+			`,
+			expected: "5000000000 -5000000000 200 0.1 3\n",
+		},
+		{
+			name: "Untyped arguments and results",
+			code: `
+				fn wide(n: i64) -> i64 { ret n; }
+				fn limit() -> u64 { ret 18446744073709551615; }
+				fn precise(x: f64, y: f64) -> f64 { ret x; }
+				print("" + wide(5000000000) + " " + limit());
+				print("" + precise(3.141592653589793, 1) + " " + precise(0.1, 2));
+			`,
+			expected: "5000000000 18446744073709551615\n3.141592653589793 0.1\n",
+		},
+		{
+			name: "Untyped constants",
+			code: `
+				type S struct { a: i64 };
+				let x: i64 = 5000000000 + 1;
+				x += 1;
+				let y: f64 = 1.5 * 2.0;
+				let z: u8 = 2 * 100;
+				let half: f64 = 1 / 2;
+				let arr: []i64 = [5000000000, 2];
+				let m := $map[str]f64{"a" => 1};
+				let s := @S{a: 5000000000};
+				fn quarter(v: f64) -> f64 { ret v / 4; }
+				print("" + x + " " + y + " " + z + " " + half);
+				print("" + (arr[0] + arr[1]) + " " + m["a"] / 4 + " " + s.a + " " + quarter(1 + 2));
+			`,
+			expected: "5000000002 3 200 0.5\n5000000002 0.25 5000000000 0.75\n",
+		},
+		{
+			name: "Constant errors outside of constants",
+			code: `
+				fn never(n: i32) -> i32 { ret n / 0; }
+				const max := 2147483647;
+				let b := max + 1;
+				let z: u8 = 200u8 + 100u8;
+				print("" + b + " " + z);
+			`,
+			expected: "-2147483648 44\n",
+		},
+		{
+			name: "Default parameters",
+			code: `
+				const size := 10;
+				fn add(a: i32, b?: i32 = size - 1, s?: str = "x", n?: i64 = -3, on?: bool = true) -> str {
+					ret "" + a + " " + b + " " + s + " " + n + " " + on;
+				}
+				print(add(1));
+				print(add(1, 2, "y"));
+			`,
+			expected: "1 9 x -3 true\n1 2 y -3 true\n",
+		},
+		{
+			name: "Integers with floats",
+			code: `
+				let a: i32 = 23;
+				let d: i64 = 3;
+				const k := 7 / 2.0;
+				print("" + (a + 2.4) + " " + (1.5f64 * d) + " " + (a / 2.0) + " " + (10 - a * 0.5) + " " + k);
+			`,
+			expected: "25.4 4.5 11.5 -1.5 3.5\n",
+		},
+		{
+			name: "128 bit integers",
+			code: `
+				let a: i128 = -170141183460469231731687303715884105728;
+				let b := 340282366920938463463374607431768211455u128;
+				fn same(x: u128) -> u128 { ret x; }
+				print("" + a + " " + b + " " + same(18446744073709551616));
+			`,
+			expected: "-170141183460469231731687303715884105728 340282366920938463463374607431768211455 18446744073709551616\n",
+		},
+		{
+			name: "128 bit conversions",
+			code: `
+				let a: i128 = -170141183460469231731687303715884105728;
+				let b := 340282366920938463463374607431768211455u128;
+				print("" + (b as u8) + " " + (a as i64) + " " + (5 as i128));
+			`,
+			expected: "255 0 5\n",
+		},
+		{
+			name:     "Return from a loop",
+			code:     `fn find() -> i32 { for let i := 0; i < 10; i++ { if i == 4 { ret i; } } ret 0 - 1; } print("" + find());`,
+			expected: "4\n",
+		},
+		{
+			name:     "Foreach over arrays",
+			code:     `let arr := [10, 20]; foreach i, v in arr { print("" + i + ":" + v); }`,
+			expected: "0:10\n1:20\n",
+		},
+		{
+			name:     "Foreach over maps",
+			code:     `let m := $map[str]i32{"a" => 1, "b" => 2}; foreach k, v in m { print(k + v); }`,
+			expected: "a1\nb2\n",
+		},
+		{
+			name:     "Foreach over ranges",
+			code:     `let total := 0; foreach n in 2..5 { total += n; } print("" + total);`,
+			expected: "9\n",
+		},
+		{
+			name: "Return from a foreach",
+			code: `
+				fn first(xs: []i32) -> i32 {
+					foreach x in xs { ret x; }
+					ret 0 - 1;
+				}
+				print("" + first([7, 8]));
+			`,
+			expected: "7\n",
+		},
+		{
+			name: "Labeled foreach",
+			code: `
+				let sum := 0;
+				outer: foreach i in 0..5 {
+					foreach j in 0..5 {
+						if j > i { continue outer; }
+						if i == 4 { break outer; }
+						sum += j;
+					}
+				}
+				print("" + sum);
+			`,
+			expected: "10\n",
+		},
+		{
+			name: "Foreach element captured by a closure",
+			code: `
+				let f := fn() -> i32 { ret 0; };
+				foreach v in [1, 2] {
+					if v == 1 { f = fn() -> i32 { ret v; }; }
+				}
+				print("" + f());
+			`,
+			expected: "1\n",
+		},
+		{
+			name: "Match values and ranges",
+			code: `
+				fn grade(n: i32) -> str {
+					match n {
+						100 => { ret "perfect"; }
+						90..100, 80 => { ret "good"; }
+						-1 => { ret "negative"; }
+						_ => { ret "other"; }
+					}
+				}
+				print(grade(100)); print(grade(99)); print(grade(80)); print(grade(-1)); print(grade(90 - 1));
+			`,
+			expected: "perfect\ngood\ngood\nnegative\nother\n",
+		},
+		{
+			name: "Match types",
+			code: `
+				type Shape interface { fn area() -> i32; };
+				type Square struct { side: i32 };
+				impl Square { fn area() -> i32 { ret this.side * this.side; } }
+				fn name(s: Shape) -> str {
+					match s {
+						Square => { ret "square"; }
+						_ => { ret "shape"; }
+					}
+				}
+				print(name(@Square{side: 2}));
+			`,
+			expected: "square\n",
+		},
+		{
+			name: "Match in a loop",
+			code: `
+				let odd := 0;
+				foreach n in 0..6 {
+					match n % 2 {
+						0 => { continue; }
+						_ => { if n == 5 { break; } odd += n; }
+					}
+				}
+				print("" + odd);
+			`,
+			expected: "4\n",
+		},
+		{
+			name: "Enum variants",
+			code: `
+				type Figure enum { Circle(r: f32), Rect(w: f32, h: f32), Empty };
+				fn area(f: Figure) -> f32 {
+					match f {
+						Figure.Circle(r) => { ret r * r * 3.0; }
+						Figure.Rect(w, h) => { ret w * h; }
+						Figure.Empty => { ret 0.0; }
+					}
+				}
+				let make := Figure.Rect;
+				print("" + area(Figure.Circle(1.0)) + " " + area(make(2.0, 3.0)) + " " + area(Figure.Empty));
+			`,
+			expected: "3 6 0\n",
+		},
+		{
+			name: "Enum values",
+			code: `
+				type Figure enum { Circle(r: f32), Rect(w: f32, h: f32), Empty };
+				let r := Figure.Rect(2.0, 3.0);
+				print("" + r);
+				print("" + (r == Figure.Rect(2.0, 3.0)) + " " + (Figure.Circle(1.0) == Figure.Empty));
+				print(typeof Figure.Empty);
+			`,
+			expected: "Figure.Rect(2, 3)\ntrue false\nFigure\n",
+		},
+		{
+			name: "Match on results",
+			code: `
+				fn parse(s: str) -> i32!str { if s == "" { ret err("empty"); } ret 21; }
+				fn double(s: str) -> i32!str { let n := parse(s)?; ret ok(n * 2); }
+				fn describe(s: str) -> str {
+					match double(s) {
+						ok(n) => { ret "number " + n; }
+						err(e) => { ret "error " + e; }
+					}
+				}
+				print(describe("x"));
+				print(describe(""));
+			`,
+			expected: "number 42\nerror empty\n",
+		},
+		{
+			name: "Results compare to their values",
+			code: `
+				fn parse(s: str) -> i32!str { if s == "" { ret err("empty"); } ret 21; }
+				fn double(s: str) -> i32!str { let n := parse(s)?; ret ok(n * 2); }
+				let a := double("x");
+				let b := double("");
+				print("" + (a == 42) + " " + (b == err("empty")));
+				print(typeof a);
+				print(typeof b);
+			`,
+			expected: "true true\ni32\nerr(str)\n",
+		},
+		{
+			name: "Unpack tuples",
+			code: `
+				fn divmod(a: i32, b: i32) -> (i32, i32) { ret a / b, a % b; }
+				let (q, r) := divmod(7, 2);
+				let (_, rest) := divmod(9, 4);
+				print("" + q + " " + r + " " + rest);
+			`,
+			expected: "3 1 1\n",
+		},
+		{
+			name: "Unpack structs",
+			code: `
+				type Person struct { name: str, age: i32 };
+				let {name, age} := @Person{name: "Ann", age: 30};
+				print(name + " " + age);
+			`,
+			expected: "Ann 30\n",
+		},
+		{
+			name: "Tuple values",
+			code: `
+				let t: (i64, f64) = (5000000000, 0.5);
+				let pair := (1, "one");
+				let empty: (i32, str);
+				print("" + t[0] + " " + pair[1] + " " + (pair == (1, "one")));
+				print("" + empty);
+				print(typeof t);
+			`,
+			expected: "5000000000 one true\n(0, \"\")\n(i64, f64)\n",
+		},
+		{
+			name: "Safe narrows a nullable value",
+			code: `
+				fn describe(n: i32?) -> str {
+					safe n { ret "some " + (n + 1); } otherwise { ret "none"; }
+				}
+				let a: i32?;
+				print(describe(a));
+				a = 4;
+				print(describe(a));
+				let b: str? = "hi";
+				safe b { print(b + "!"); }
+			`,
+			expected: "none\nsome 5\nhi!\n",
+		},
+		{
+			name: "Nullable values compare to null",
+			code: `
+				let a: i32?;
+				let b: str? = "hi";
+				print("" + (b != null) + " " + (a == null));
+			`,
+			expected: "true true\n",
+		},
+		{
+			name: "Optional chaining",
+			code: `
+				type Inner struct { x: i32 };
+				type Outer struct { inner: Inner? };
+				let o := @Outer{inner: null};
+				print("" + (o.inner?.x == null));
+				o.inner = @Inner{x: 7};
+				print("" + o.inner?.x);
+			`,
+			expected: "true\n7\n",
+		},
+		{
+			name: "Generic structs",
+			code: `
+				type Box<T> struct { value: T };
+				impl Box<T> { fn get() -> T { ret this.value; } }
+				let b := @Box{value: 5};
+				let s := @Box<str>{value: "hi"};
+				let nested: Box<Box<i32>> = @Box{value: b};
+				print("" + b.get() + " " + s.get() + " " + nested.get().get());
+			`,
+			expected: "5 hi 5\n",
+		},
+		{
+			name: "Generic functions",
+			code: `
+				type Shape interface { fn area() -> f32; };
+				type Square struct { side: f32 };
+				impl Square { fn area() -> f32 { ret this.side * this.side; } }
+				fn first<T>(items: []T) -> T { ret items[0]; }
+				fn twice<S: Shape>(shape: S) -> f32 { ret shape.area() * 2.0; }
+				print(first(["a", "b"]));
+				print("" + twice(@Square{side: 1.5}));
+			`,
+			expected: "a\n4.5\n",
+		},
+		{
+			name: "Arrays and maps",
+			code: `
+				let arr := [1, 2, 3];
+				arr[1] = 20;
+				arr[2] += 10;
+				let m := $map[str]i32{"a" => 1};
+				m["b"] = 2;
+				print("" + arr);
+				print("" + m);
+			`,
+			expected: "[1, 20, 13]\n{\"a\" => 1, \"b\" => 2}\n",
+		},
+		{
+			name: "Struct methods",
+			code: `
+				type Point struct { x: i32, y: i32 };
+				impl Point {
+					fn sum() -> i32 { ret this.x + this.y; }
+					fn twice() -> i32 { ret sum() * 2; }
+				}
+				let p := @Point{x: 1, y: 2};
+				p.x = 5;
+				print("" + p.twice());
+				print(typeof p);
+			`,
+			expected: "14\nPoint\n",
+		},
+		{
+			name: "Zero values",
+			code: `
+				type Point struct { x: i32, y: f32 };
+				let p : Point;
+				let s : str;
+				let a : []i32;
+				print("" + p);
+				print("[" + s + "]");
+				print(typeof a);
+			`,
+			expected: "@Point{x: 0, y: 0}\n[]\n[]i32\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := run(t, tt.code)
+			if err != "" {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if output != tt.expected {
+				t.Errorf("Expected output %q, got %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestRuntimeErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected string
+	}{
+		{
+			name:     "Division by zero",
+			code:     `let a := 0; print("" + (1 / a));`,
+			expected: "integer division by zero",
+		},
+		{
+			name:     "Division by a constant zero",
+			code:     `const zero := 0; let a := 10 / zero;`,
+			expected: "integer division by zero",
+		},
+		{
+			name:     "Index out of range",
+			code:     `let arr := [1, 2, 3]; print("" + arr[3]);`,
+			expected: "index 3 out of range with length 3",
+		},
+		{
+			name:     "Unbounded recursion",
+			code:     `fn loop(n: i32) -> i32 { ret loop(n + 1); } loop(0);`,
+			expected: "maximum call depth",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := run(t, tt.code)
+			if !strings.Contains(err, tt.expected) {
+				t.Errorf("Expected error %q, got %q", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestModules(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"shapes.wal": `type Point struct { x: i32 }; fn helper() -> i32 { ret 1; } export fn fromShapes() -> i32 { let helper := @Point{x: helper()}; ret helper.x; }`,
+		"main.wal":   `import "shapes"; type Point struct { y: str }; fn helper() -> i32 { ret 2; } let p := @Point{y: "b"}; print("" + helper() + " " + p.y + " " + fromShapes());`,
+	}
+	for name, code := range files {
+		if e := os.WriteFile(filepath.Join(dir, name), []byte(code), 0644); e != nil {
+			t.Fatal(e)
+		}
+	}
+	defer report.ClearReports()
+
+	entry := filepath.Join(dir, "main.wal")
+	loaded, e := modules.Load(entry, false)
+	if e != nil {
+		t.Fatal(e)
+	}
+	typechecker.AnalyzeProgram(loaded)
+	program := modules.Link(loaded)
+	if report.GetReports().HasErrors() {
+		t.Fatalf("Expected the modules to be linked, got %v", report.GetReports())
+	}
+
+	var out bytes.Buffer
+	Run(bytecode.Compile(program, entry), entry, &out)
+	if out.String() != "2 b 1\n" {
+		t.Errorf("Expected each module to use its own private names, got %q", out.String())
+	}
+}
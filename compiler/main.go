@@ -2,28 +2,55 @@ package main
 
 import (
 	//Standard packages
+	"fmt"
 	"os"
+	"strings"
 
 	//Walrus packages
-	"walrus/compiler/analyzer"
 	"walrus/compiler/colors"
 )
 
+// usage lists the commands, the flags of a command are shown by 'walrus <command> -h'.
+var usage = []string{
+	"Usage: walrus <command> [flags] [arguments]",
+	"",
+	"Commands:",
+	"  check [file|folder]   analyze a file, or every file of a project",
+	"  build [folder]        check a project and compile its entry file",
+	"  run <file>            run a file on the interpreter, or on the vm with -vm",
+	"  tokens <file>         print the tokens of a file",
+	"  ast <file>            analyze a file and save its tree as JSON",
+	"  ir <file>             print the IR of a file",
+	"  go <file>             transpile a file to Go",
+	"  c <file>              transpile a file to C",
+	"  fmt <files>           format files, -w writes them back",
+	"  lsp                   start the language server",
+	"",
+	"walrus <file> is the same as walrus check -json <file>.",
+}
+
 func main() {
 
-	if len(os.Args) < 2 {
-		colors.GREEN.Println("Usage: walrus <file>")
+	if len(os.Args) < 2 || os.Args[1] == "help" || os.Args[1] == "-h" || os.Args[1] == "--help" {
+		colors.GREEN.Println(strings.Join(usage, "\n"))
 		return
 	}
 
-	filePath := os.Args[1]
+	name, args := os.Args[1], os.Args[2:]
 
-	r, err := analyzer.Analyze(filePath, true, false, true)
-	if len(r) > 0 {
-		r.DisplayAll()
+	command, ok := commands[name]
+	if !ok {
+		if !strings.HasSuffix(name, ".wal") {
+			colors.RED.Println(fmt.Sprintf("unknown command '%s'", name))
+			colors.GREEN.Println(strings.Join(usage, "\n"))
+			os.Exit(2)
+		}
+		//a file on its own is checked and its tree saved, like before the commands existed
+		command, args = commands["check"], []string{"-json", name}
 	}
 
-	if err != nil {
-		colors.RED.Println("Error analyzing file: ", err)
+	if err := command(args); err != nil {
+		colors.RED.Println("Error: ", err)
+		os.Exit(1)
 	}
 }
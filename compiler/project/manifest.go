@@ -0,0 +1,152 @@
+package project
+
+import (
+	//Standard packages
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FileName is the name of the project file at the root of a project.
+const FileName = "walrus.json"
+
+// Manifest describes a project. Paths are relative to the folder of the project file.
+type Manifest struct {
+	Name    string   `json:"name"`
+	Entry   string   `json:"entry,omitempty"`   // file built by 'walrus build'
+	Sources []string `json:"sources,omitempty"` // folders searched for .wal files, the project folder by default
+	Output  string   `json:"output,omitempty"`  // folder the build is written to, 'build' by default
+	Root    string   `json:"-"`                 // folder of the project file
+}
+
+// Find looks for the project file in the folder and its parents and returns its path.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		path := filepath.Join(dir, FileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no %s found", FileName)
+		}
+		dir = parent
+	}
+}
+
+// Load reads the project file, fills in the defaults and validates it.
+func Load(path string) (*Manifest, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %s", FileName, err.Error())
+	}
+
+	manifest.Root = filepath.Dir(path)
+
+	if len(manifest.Sources) == 0 {
+		manifest.Sources = []string{"."}
+	}
+	if manifest.Output == "" {
+		manifest.Output = "build"
+	}
+
+	return manifest, manifest.validate()
+}
+
+// Default returns the manifest of a folder without a project file: every .wal file under
+// the folder is a source file.
+func Default(dir string) *Manifest {
+	return &Manifest{
+		Name:    filepath.Base(dir),
+		Sources: []string{"."},
+		Output:  "build",
+		Root:    dir,
+	}
+}
+
+func (m *Manifest) validate() error {
+	if m.Name == "" {
+		return fmt.Errorf("invalid %s: missing 'name'", FileName)
+	}
+	if m.Entry != "" && !strings.HasSuffix(m.Entry, ".wal") {
+		return fmt.Errorf("invalid %s: entry '%s' must have .wal extension", FileName, m.Entry)
+	}
+	for _, source := range m.Sources {
+		if info, err := os.Stat(m.Path(source)); err != nil || !info.IsDir() {
+			return fmt.Errorf("invalid %s: source root '%s' is not a folder", FileName, source)
+		}
+	}
+	return nil
+}
+
+// Path returns the path of a file of the project.
+func (m *Manifest) Path(name string) string {
+	if filepath.IsAbs(name) {
+		return filepath.Clean(name)
+	}
+	return filepath.Join(m.Root, name)
+}
+
+// EntryPath returns the path of the entry file.
+func (m *Manifest) EntryPath() (string, error) {
+	if m.Entry == "" {
+		return "", errors.New("the project has no entry file")
+	}
+	path := m.Path(m.Entry)
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("entry file '%s' not found", m.Entry)
+	}
+	return path, nil
+}
+
+// Files returns every .wal file under the source roots, sorted. Hidden folders and the
+// output folder are skipped.
+func (m *Manifest) Files() ([]string, error) {
+
+	output := m.Path(m.Output)
+	found := make(map[string]bool)
+
+	for _, source := range m.Sources {
+		err := filepath.WalkDir(m.Path(source), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path == output || (path != m.Path(source) && strings.HasPrefix(d.Name(), ".")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(path, ".wal") {
+				found[path] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	files := make([]string, 0, len(found))
+	for file := range found {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
@@ -0,0 +1,107 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, FileName), `{"name": "demo", "entry": "main.wal"}`)
+
+	manifest, err := Load(filepath.Join(dir, FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if manifest.Root != dir {
+		t.Errorf("Expected root %s, got %s", dir, manifest.Root)
+	}
+	if len(manifest.Sources) != 1 || manifest.Sources[0] != "." {
+		t.Errorf("Expected the project folder as source root, got %v", manifest.Sources)
+	}
+	if manifest.Output != "build" {
+		t.Errorf("Expected output 'build', got %s", manifest.Output)
+	}
+	if _, err := manifest.EntryPath(); err == nil {
+		t.Errorf("Expected a missing entry file to be an error")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+	}{
+		{"Invalid JSON", `{"name": `},
+		{"Missing name", `{"entry": "main.wal"}`},
+		{"Entry without extension", `{"name": "demo", "entry": "main"}`},
+		{"Missing source root", `{"name": "demo", "sources": ["src"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile(t, filepath.Join(dir, FileName), tt.manifest)
+			if _, err := Load(filepath.Join(dir, FileName)); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, FileName), `{"name": "demo"}`)
+	nested := filepath.Join(dir, "src", "lib")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := Find(nested)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(dir, FileName) {
+		t.Errorf("Expected %s, got %s", filepath.Join(dir, FileName), path)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, FileName), `{"name": "demo", "sources": ["src", "lib"], "output": "out"}`)
+	for _, name := range []string{"src/main.wal", "src/util/math.wal", "src/notes.txt", "src/.cache/old.wal", "lib/io.wal", "other.wal"} {
+		writeFile(t, filepath.Join(dir, name), "")
+	}
+
+	manifest, err := Load(filepath.Join(dir, FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := manifest.Files()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"lib/io.wal", "src/main.wal", "src/util/math.wal"}
+	if len(files) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, files)
+	}
+	for i, file := range files {
+		if file != filepath.Join(dir, expected[i]) {
+			t.Errorf("Expected %s, got %s", expected[i], file)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package report
+
+import (
+	//Standard packages
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// jsonReport is a report as written by WriteJSON.
+type jsonReport struct {
+	File      string   `json:"file"`
+	LineStart int      `json:"lineStart"`
+	LineEnd   int      `json:"lineEnd"`
+	ColStart  int      `json:"colStart"`
+	ColEnd    int      `json:"colEnd"`
+	Level     string   `json:"level"`
+	Message   string   `json:"message"`
+	Hints     []string `json:"hints,omitempty"`
+}
+
+// WriteJSON writes the reports as a JSON array, one object per report.
+func (r Reports) WriteJSON(w io.Writer) error {
+	reports := make([]jsonReport, 0, len(r))
+	for _, report := range r {
+		reports = append(reports, jsonReport{
+			File:      report.FilePath,
+			LineStart: report.LineStart,
+			LineEnd:   report.LineEnd,
+			ColStart:  report.ColStart,
+			ColEnd:    report.ColEnd,
+			Level:     string(report.Level),
+			Message:   report.Message,
+			Hints:     report.Hints,
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(reports)
+}
+
+// The SARIF 2.1.0 log written by WriteSARIF, with the properties code scanning tools read.
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID     string          `json:"ruleId"`
+	Level      string          `json:"level"`
+	Message    sarifMessage    `json:"message"`
+	Locations  []sarifLocation `json:"locations"`
+	Properties *sarifHints     `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           sarifRegion   `json:"region"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine,omitempty"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+type sarifHints struct {
+	Hints []string `json:"hints"`
+}
+
+// sarifLevels maps the level of a report to the level of a SARIF result.
+var sarifLevels = map[REPORT_TYPE]string{
+	CRITICAL_ERROR: "error",
+	SYNTAX_ERROR:   "error",
+	NORMAL_ERROR:   "error",
+	RUNTIME_ERROR:  "error",
+	WARNING:        "warning",
+	INFO:           "note",
+}
+
+// WriteSARIF writes the reports as a SARIF 2.1.0 log, the format code scanning dashboards
+// import. Every level of report is a rule of the log.
+func (r Reports) WriteSARIF(w io.Writer) error {
+
+	rules := make([]sarifRule, 0)
+	ruleIDs := make(map[string]bool)
+	results := make([]sarifResult, 0, len(r))
+
+	for _, report := range r {
+		ruleID := strings.ReplaceAll(string(report.Level), " ", "-")
+		if !ruleIDs[ruleID] {
+			ruleIDs[ruleID] = true
+			rules = append(rules, sarifRule{ID: ruleID, ShortDescription: sarifMessage{Text: string(report.Level)}})
+		}
+
+		result := sarifResult{
+			RuleID:  ruleID,
+			Level:   sarifLevels[report.Level],
+			Message: sarifMessage{Text: report.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifact{URI: artifactURI(report.FilePath)},
+					Region: sarifRegion{
+						StartLine:   report.LineStart,
+						StartColumn: report.ColStart,
+						EndLine:     report.LineEnd,
+						EndColumn:   report.ColEnd,
+					},
+				},
+			}},
+		}
+		if len(report.Hints) > 0 {
+			result.Properties = &sarifHints{Hints: report.Hints}
+		}
+		results = append(results, result)
+	}
+
+	log := sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "walrus",
+				InformationURI: "https://github.com/itsfuad/walrus",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(log)
+}
+
+// artifactURI returns the path of a file relative to the working directory when the file is
+// inside it, with forward slashes, as code scanning tools match files of the repository.
+func artifactURI(path string) string {
+	if filepath.IsAbs(path) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(path)
+}
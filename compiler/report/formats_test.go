@@ -0,0 +1,80 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+var formatReports = Reports{
+	{FilePath: "code/main.wal", LineStart: 3, LineEnd: 3, ColStart: 5, ColEnd: 9, Message: "'x' was not declared in this scope", Level: NORMAL_ERROR},
+	{FilePath: "code/main.wal", LineStart: 1, LineEnd: 1, ColStart: 6, ColEnd: 6, Message: "User defined type name should be capitalized", Hints: []string{"Make the first letter uppercase"}, Level: INFO},
+}
+
+func TestWriteJSON(t *testing.T) {
+	var out bytes.Buffer
+	if err := formatReports.WriteJSON(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	var reports []jsonReport
+	if err := json.Unmarshal(out.Bytes(), &reports); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reports) != 2 {
+		t.Fatalf("Expected 2 reports, got %d", len(reports))
+	}
+	if reports[0].File != "code/main.wal" || reports[0].LineStart != 3 || reports[0].ColEnd != 9 || reports[0].Level != "error" {
+		t.Errorf("Unexpected report %+v", reports[0])
+	}
+	if len(reports[1].Hints) != 1 {
+		t.Errorf("Expected the hint to be kept, got %v", reports[1].Hints)
+	}
+}
+
+func TestWriteJSONWithoutReports(t *testing.T) {
+	var out bytes.Buffer
+	if err := (Reports{}).WriteJSON(&out); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "[]\n" {
+		t.Errorf("Expected an empty array, got %q", got)
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var out bytes.Buffer
+	if err := formatReports.WriteSARIF(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(out.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("Expected a SARIF 2.1.0 log with one run, got %+v", log)
+	}
+
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || run.Tool.Driver.Rules[0].ID != "error" {
+		t.Errorf("Expected a rule per level, got %+v", run.Tool.Driver.Rules)
+	}
+
+	if len(run.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(run.Results))
+	}
+	if run.Results[0].Level != "error" || run.Results[1].Level != "note" {
+		t.Errorf("Expected levels error and note, got %s and %s", run.Results[0].Level, run.Results[1].Level)
+	}
+
+	location := run.Results[0].Locations[0].PhysicalLocation
+	if location.ArtifactLocation.URI != "code/main.wal" || location.Region.StartLine != 3 || location.Region.StartColumn != 5 {
+		t.Errorf("Unexpected location %+v", location)
+	}
+	if run.Results[1].Properties == nil || run.Results[1].Properties.Hints[0] != "Make the first letter uppercase" {
+		t.Errorf("Expected the hint in the properties of the result")
+	}
+}
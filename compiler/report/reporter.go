@@ -15,6 +15,7 @@ const (
 	CRITICAL_ERROR REPORT_TYPE = "critical error" // Stops compilation immediately
 	SYNTAX_ERROR   REPORT_TYPE = "syntax error"   // Syntax error, also stops compilation
 	NORMAL_ERROR   REPORT_TYPE = "error"          // Regular error that doesn't halt compilation
+	RUNTIME_ERROR  REPORT_TYPE = "runtime error"  // Error raised while executing a program, stops execution
 
 	WARNING REPORT_TYPE = "warning" // Indicates potential issues
 	INFO    REPORT_TYPE = "info"    // Informational message
@@ -25,6 +26,7 @@ var colorMap = map[REPORT_TYPE]colors.COLOR{
 	CRITICAL_ERROR: colors.BOLD_RED,
 	SYNTAX_ERROR:   colors.RED,
 	NORMAL_ERROR:   colors.RED,
+	RUNTIME_ERROR:  colors.BOLD_RED,
 	WARNING:        colors.YELLOW,
 	INFO:           colors.BLUE,
 }
@@ -88,6 +90,8 @@ func printReport(r *Report) {
 		reportMsgType = "Syntax Error: "
 	case NORMAL_ERROR:
 		reportMsgType = "Error: "
+	case RUNTIME_ERROR:
+		reportMsgType = "Runtime Error: "
 	}
 
 	reportColor := colorMap[r.Level]
@@ -103,7 +107,7 @@ func printReport(r *Report) {
 	colors.GREY.Printf("%s> [%s:%d:%d]\n", strings.Repeat("=", numlen+1), r.FilePath, r.LineStart, r.ColStart)
 
 	// The code snippet and underline are printed in the same color.
-	fmt.Print(snippet)
+	fmt.Fprint(colors.Output(), snippet)
 	reportColor.Print(underline)
 
 	showHints(r, hLen)
@@ -149,7 +153,7 @@ func showHints(r *Report, padding int) {
 			colors.YELLOW.Printf("%s- %s\n", strings.Repeat(" ", padding), hint)
 		}
 	} else {
-		fmt.Println()
+		fmt.Fprintln(colors.Output())
 	}
 }
 
@@ -197,7 +201,7 @@ func Add(filePath string, lineStart, lineEnd int, colStart, colEnd int, msg stri
 }
 
 // SetLevel assigns a diagnostic level to the report, increments its count,
-// and triggers DisplayAll if the level is critical or denotes a syntax error.
+// and triggers DisplayAll if the level is critical, a syntax error or a runtime error.
 func (e *Report) SetLevel(level REPORT_TYPE) {
 	if level == NULL {
 		panic("call SetLevel() method with valid Error level")
@@ -206,6 +210,9 @@ func (e *Report) SetLevel(level REPORT_TYPE) {
 	if level == CRITICAL_ERROR || level == SYNTAX_ERROR {
 		panic("Critical error or syntax error detected")
 	}
+	if level == RUNTIME_ERROR {
+		panic("Runtime error detected")
+	}
 }
 
 // DisplayAll outputs all the diagnostic reports. It recovers from panics,
@@ -220,6 +227,18 @@ func (r Reports) DisplayAll() {
 	r.ShowStatus()
 }
 
+// DisplayShort outputs one line per report, the format editors and scripts read.
+func (r Reports) DisplayShort() {
+	for _, report := range r {
+		fmt.Fprintln(colors.Output(), report.Short())
+	}
+}
+
+// Short formats the report on one line, like 'main.wal:3:5: error: message'.
+func (r *Report) Short() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s", r.FilePath, r.LineStart, r.ColStart, r.Level, r.Message)
+}
+
 // ShowStatus displays a summary of compilation status along with counts of warnings and errors.
 func (r Reports) ShowStatus() {
 	warningCount := 0
@@ -228,7 +247,7 @@ func (r Reports) ShowStatus() {
 	for _, report := range r {
 		if report.Level == WARNING {
 			warningCount++
-		} else if report.Level == NORMAL_ERROR || report.Level == CRITICAL_ERROR || report.Level == SYNTAX_ERROR {
+		} else if report.IsError() {
 			probCount++
 		}
 	}
@@ -259,3 +278,18 @@ func (r Reports) ShowStatus() {
 	messageColor.Print(totalProblemsString)
 	messageColor.Println(" -------------")
 }
+
+// IsError reports whether the report level is one of the error levels.
+func (r *Report) IsError() bool {
+	return r.Level == NORMAL_ERROR || r.Level == CRITICAL_ERROR || r.Level == SYNTAX_ERROR || r.Level == RUNTIME_ERROR
+}
+
+// HasErrors reports whether any of the reports is an error.
+func (r Reports) HasErrors() bool {
+	for _, report := range r {
+		if report.IsError() {
+			return true
+		}
+	}
+	return false
+}
@@ -18,6 +18,14 @@ func TestPanicRecover(t *testing.T) {
 	Panicable(1, []int{2, 3})
 }
 
+func TestShort(t *testing.T) {
+	r := &Report{FilePath: "main.wal", LineStart: 3, ColStart: 5, Message: "'x' was not declared in this scope", Level: NORMAL_ERROR}
+	expected := "main.wal:3:5: error: 'x' was not declared in this scope"
+	if got := r.Short(); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
 func Panicable(data ...any) {
 	//panic if data is not primitive
 	for _, d := range data {
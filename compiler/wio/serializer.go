@@ -4,11 +4,23 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"walrus/compiler/colors"
 	"walrus/compiler/internal/ast"
+	"walrus/compiler/internal/bytecode"
+	"walrus/compiler/internal/typechecker"
 )
 
-func Serialize(root *ast.Node, folder, filename string) error {
+// typedTree is the JSON written by Serialize. TypeInfo is left out when the tree was not type checked.
+type typedTree struct {
+	Tree     *ast.Node             `json:"tree"`
+	TypeInfo *typechecker.TypeInfo `json:"typeInfo,omitempty"`
+}
+
+// Serialize writes the tree and the types the typechecker resolved for it to a file named
+// '<filename>.json' in the 'ast' folder.
+func Serialize(root *ast.Node, info *typechecker.TypeInfo, folder, filename string) error {
 
 	//create the folder if it does not exist
 	if _, err := os.Stat(folder + "/ast"); os.IsNotExist(err) {
@@ -17,16 +29,56 @@ func Serialize(root *ast.Node, folder, filename string) error {
 
 	file, err := os.Create(fmt.Sprintf("%s/ast/%s.json", folder, filename))
 	if err != nil {
-		fmt.Printf("Error creating file: %s", err)
+		fmt.Fprintf(colors.Output(), "Error creating file: %s", err)
 	}
 	defer file.Close()
 
 	//write the tree to a file named 'expressions.json' in 'code/ast' folder
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "    ")
-	err = encoder.Encode(root)
+	err = encoder.Encode(typedTree{Tree: root, TypeInfo: info})
+	if err != nil {
+		fmt.Fprintf(colors.Output(), "Error encoding JSON: %s", err)
+		return err
+	}
+	return nil
+}
+
+// SerializeBytecode writes the compiled program to a file named '<filename>.wbc' in the 'bytecode' folder.
+func SerializeBytecode(program *bytecode.Program, folder, filename string) error {
+
+	//create the folder if it does not exist
+	if _, err := os.Stat(folder + "/bytecode"); os.IsNotExist(err) {
+		os.Mkdir(folder+"/bytecode", os.ModePerm)
+	}
+
+	file, err := os.Create(fmt.Sprintf("%s/bytecode/%s.wbc", folder, filename))
+	if err != nil {
+		fmt.Fprintf(colors.Output(), "Error creating file: %s", err)
+		return err
+	}
+	defer file.Close()
+
+	err = bytecode.Encode(file, program)
+	if err != nil {
+		fmt.Fprintf(colors.Output(), "Error encoding bytecode: %s", err)
+		return err
+	}
+	return nil
+}
+
+// SerializeSource writes generated source code to a file named 'filename' in the 'target' folder.
+func SerializeSource(source []byte, folder, target, filename string) error {
+
+	//create the folder and its parents if they do not exist, the folder is empty for files in the working directory
+	dir := filepath.Join(folder, target)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		os.MkdirAll(dir, os.ModePerm)
+	}
+
+	err := os.WriteFile(filepath.Join(dir, filename), source, 0644)
 	if err != nil {
-		fmt.Printf("Error encoding JSON: %s", err)
+		fmt.Fprintf(colors.Output(), "Error writing file: %s", err)
 		return err
 	}
 	return nil
@@ -1,12 +1,14 @@
 package wio
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"walrus/compiler/internal/ast"
 	"walrus/compiler/internal/lexer"
+	"walrus/compiler/internal/typechecker"
 )
 
 func TestSerialize(t *testing.T) {
@@ -67,8 +69,31 @@ func TestSerialize(t *testing.T) {
 	}
 
 	// Serialize the AST node to a file
-	err = Serialize(&program, tempDir, "temp")
+	err = Serialize(&program, nil, tempDir, "temp")
 	if err != nil {
 		t.Fatal(err)
 	}
 }
+
+func TestSerializeTypeInfo(t *testing.T) {
+	tempDir := t.TempDir()
+
+	var program ast.Node = ast.ProgramStmt{}
+	if err := Serialize(&program, typechecker.NewTypeInfo(), tempDir, "typed"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "ast", "typed.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"tree", "typeInfo"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("Expected the JSON to contain %q, got %s", key, data)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"net"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"walrus/compiler/report"
@@ -249,6 +250,10 @@ func processDiagnostics(writer *bufio.Writer, uri string) {
 	log.Printf("Found %d problems\n", len(report))
 
 	for _, r := range report {
+		// problems found in imported files are not shown on this file
+		if filepath.Clean(r.FilePath) != filepath.Clean(filePath) {
+			continue
+		}
 		diagnostics = append(diagnostics, map[string]interface{}{
 			"range": map[string]interface{}{
 				"start": map[string]int{"line": r.LineStart - 1, "character": r.ColStart - 1},
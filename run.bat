@@ -1,4 +1,4 @@
 @echo off
 echo starting compiler
 cd compiler
-go run main.go code/variables.wal
\ No newline at end of file
+go run . code/variables.wal
\ No newline at end of file